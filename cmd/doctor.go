@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/app"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+)
+
+// doctorCmd diagnoses why scans of a directory fail
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [dir]",
+	Short: "Diagnose the scan environment",
+	Long: `Report the build tool executables on PATH and their versions, whether the server is
+reachable and accepts the credentials, the build files detected in the directory (default: the
+current directory) and the resolved configuration.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	// Scan flags belong to the root command, so the directory is an argument
+	cfg.TaskDir = "."
+	if len(args) > 0 {
+		cfg.TaskDir = args[0]
+	}
+	applyProjectConfig(cmd.Flags().Changed)
+
+	logger.InitLogger(cfg.LogLevel)
+	logger.SetColorMode(cfg.Color)
+	logger.SetRedaction(cfg.Redact, cfg.Password, cfg.Token)
+
+	if err := app.NewBuildScanApplication(cfg).Doctor(cmd.OutOrStdout()); err != nil {
+		logger.GetLogger().Errorf("Doctor failed: %v", err)
+		os.Exit(app.ExitCode(err))
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -8,12 +10,24 @@ import (
 	"github.com/craftslab/cleansource-sca-cli/internal/app"
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
 var (
 	// Global configuration
 	cfg *config.ScanConfig
 
+	// Dependency depth and archive compression level, applied to the configuration only when given
+	dependencyDepth int
+	archiveLevel    int
+
+	// Print the effective configuration instead of scanning
+	printConfig bool
+
+	// Print the dependency output schema instead of scanning
+	printSchema bool
+
 	// Root command
 	rootCmd = &cobra.Command{
 		Use:     "cleansource-sca-cli",
@@ -29,11 +43,26 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
+	rootCmd.PersistentFlags().BoolVar(&printConfig, "print-config", false, "Print the effective configuration as JSON, with secrets masked, and exit without scanning")
+	rootCmd.PersistentFlags().BoolVar(&printSchema, "schema", false, "Print the JSON schema of the dependency output and exit without scanning")
 	rootCmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Color, "color", config.ColorAuto, "Color log output (auto, always, never); auto colors only on a terminal")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Redact, "redact", true, "Mask passwords, tokens and URL credentials in logs (--redact=false to debug)")
 	rootCmd.PersistentFlags().StringVar(&cfg.ServerURL, "server-url", "", "Server URL")
+	rootCmd.PersistentFlags().BoolVar(&cfg.AllowInsecureHTTP, "allow-insecure-http", false, "Allow an http:// server URL, sending credentials unencrypted")
 	rootCmd.PersistentFlags().StringVar(&cfg.Username, "username", "", "Username for authentication")
 	rootCmd.PersistentFlags().StringVar(&cfg.Password, "password", "", "Password for authentication")
 	rootCmd.PersistentFlags().StringVar(&cfg.Token, "token", "", "Authentication token")
+	rootCmd.PersistentFlags().StringVar(&cfg.PasswordFile, "password-file", "", "Read the password from this file, overriding --password")
+	rootCmd.PersistentFlags().StringVar(&cfg.TokenFile, "token-file", "", "Read the authentication token from this file, overriding --token")
+	rootCmd.PersistentFlags().StringVar(&cfg.AuthMode, "auth-mode", "", "Authentication mode (cookie, token, basic); defaults to token when given, otherwise cookie")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ServerHealth, "server-health", false, "Check server health and credentials before scanning")
+	rootCmd.PersistentFlags().IntVar(&cfg.RetryCount, "retry-count", config.DefaultRetryCount, "Retries for transient server failures (network errors, 429, 5xx)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.RetryWait, "retry-wait", config.DefaultRetryWait, "Base wait before the first retry, doubled on each attempt")
+	rootCmd.PersistentFlags().DurationVar(&cfg.RetryMaxWait, "retry-max-wait", config.DefaultRetryMaxWait, "Maximum wait between retries, including Retry-After")
+	rootCmd.PersistentFlags().StringVar(&cfg.UploadRate, "upload-rate", "", "Limit upload bandwidth per second, e.g. 5MB or 512KB (default unlimited)")
+	rootCmd.PersistentFlags().IntVar(&cfg.MaxIdleConns, "max-idle-conns", config.DefaultMaxIdleConns, "Idle server connections kept open for reuse by later requests")
+	rootCmd.PersistentFlags().DurationVar(&cfg.KeepAlive, "keep-alive", config.DefaultKeepAlive, "Interval of TCP keep-alive probes on server connections")
 
 	// Scan flags
 	rootCmd.Flags().StringVar(&cfg.TaskDir, "task-dir", "", "Task directory to scan")
@@ -44,26 +73,131 @@ func init() {
 	rootCmd.Flags().StringVar(&cfg.CustomProject, "custom-project", "", "Custom project name")
 	rootCmd.Flags().StringVar(&cfg.CustomProduct, "custom-product", "", "Custom product name")
 	rootCmd.Flags().StringVar(&cfg.CustomVersion, "custom-version", "", "Custom version")
+	rootCmd.Flags().StringArrayVar(&cfg.Meta, "meta", nil, "Attach key=value metadata to the upload, e.g. --meta branch=main (repeatable)")
 	rootCmd.Flags().StringVar(&cfg.LicenseName, "license-name", "", "License name")
 	rootCmd.Flags().StringVar(&cfg.NotificationEmail, "notification-email", "", "Notification email")
 	rootCmd.Flags().StringVar(&cfg.ThreadNum, "thread-num", "30", "Thread number (1-60)")
+	rootCmd.Flags().BoolVar(&cfg.Recursive, "recursive", false, "Detect build files in subdirectories")
+	rootCmd.Flags().IntVar(&cfg.MaxDepth, "max-depth", 0, "Maximum directory depth for recursive detection and file walking (0 = default)")
+	rootCmd.Flags().BoolVar(&cfg.DedupWfp, "dedup-wfp", false, "Group identical files under a single hash entry in the WFP file")
+	rootCmd.Flags().BoolVar(&cfg.Incremental, "incremental", false, "Only rehash files added or modified since the previous run, using the fingerprint cache")
+	rootCmd.Flags().BoolVar(&cfg.ArchiveUnmatchedOnly, "archive-unmatched-only", false, "Upload a source archive of only the files the server could not match, after the fingerprint upload")
+	rootCmd.Flags().StringVar(&cfg.ArchiveFormat, "archive-format", utils.ArchiveFormatZip, "Source archive format (zip, tar.gz)")
+	rootCmd.Flags().IntVar(&archiveLevel, "compress-archive-level", utils.DefaultCompressionLevel, "Source archive compression level from 0 (stored, fastest) to 9 (smallest, slowest); -1 uses the format default")
+	rootCmd.Flags().StringVar(&cfg.FileManifest, "file-manifest", "", "Write every fingerprinted file with its size and hash to this file (CSV for .csv, otherwise JSON)")
+	rootCmd.Flags().StringVar(&cfg.WfpName, "wfp-name", config.DefaultWfpName, "Fingerprint file name written to the output directory")
+	rootCmd.Flags().StringVar(&cfg.DepsName, "deps-name", config.DefaultDepsName, "Dependency file name written to the output directory")
+	rootCmd.Flags().BoolVar(&cfg.DependencyOutputPerTool, "dependency-output-per-tool", false, "Also write the dependencies of each build tool to their own file in the output directory, e.g. dependencies.maven.json")
+	rootCmd.Flags().BoolVar(&cfg.SplitOnly, "split-only", false, "With --dependency-output-per-tool, skip the combined dependency file; dependencies are then not uploaded")
+	rootCmd.Flags().BoolVar(&cfg.SkipUnchangedWfp, "skip-unchanged-wfp", false, "Upload only the dependency file when the fingerprints are unchanged since the last successful upload")
+	rootCmd.Flags().StringArrayVar(&cfg.CombineDirs, "combine-dir", nil, "Fingerprint another directory into the same WFP file below a path prefix, as prefix=dir (repeatable)")
+	rootCmd.Flags().StringVar(&cfg.FilesFrom, "files-from", "", "Newline-delimited list of the only files to fingerprint, relative to the task directory (e.g. from git diff --name-only); the upload is marked partial")
+	rootCmd.Flags().StringVar(&cfg.Since, "since", "", "Only fingerprint files modified within this duration (e.g. 24h) or after this timestamp (e.g. 2024-05-01), uploading a partial WFP file")
+	rootCmd.Flags().Int64Var(&cfg.MinFileSize, "min-file-size", config.DefaultMinFileSize, "Smallest file in bytes to fingerprint; files over 1MB are always skipped")
+	rootCmd.Flags().BoolVar(&cfg.IncludeEmpty, "include-empty", false, "Record empty files as zero-size WFP entries, regardless of --min-file-size")
+	rootCmd.Flags().StringVar(&cfg.ReadBuffer, "read-buffer", "", "Buffer size files are read with while fingerprinting, e.g. 64KB, from 512B to 1MB (default 32KB)")
+	rootCmd.Flags().StringVar(&cfg.WfpChunkSize, "wfp-chunk-size", "", "Upload WFP files larger than this size, e.g. 50MB, in chunks split at file entries (default: one upload)")
+	rootCmd.Flags().StringVar(&cfg.WfpCache, "wfp-cache", "", "Fingerprint cache file for incremental mode (default: fingerprints.cache in the output directory)")
+	rootCmd.Flags().StringSliceVar(&cfg.LicenseFilenames, "license-filenames", nil, "License file names to collect, matched case-insensitively with any extension; NOTICE files are recorded as attributions (default LICENSE,LICENCE,COPYING,COPYING.LESSER,COPYRIGHT,UNLICENSE,NOTICE,LICENSE-MIT,LICENSE-APACHE,PATENTS)")
+	rootCmd.Flags().StringSliceVar(&cfg.SourceExts, "source-ext", nil, "File extensions to fingerprint as source, including built-in binary ones (repeatable, e.g. .myext)")
+	rootCmd.Flags().StringSliceVar(&cfg.BinaryExts, "binary-ext", nil, "Additional file extensions to skip as binary (repeatable, e.g. .dat)")
+	rootCmd.Flags().StringSliceVar(&cfg.ExcludePaths, "exclude", nil, "Paths to exclude from fingerprinting, relative to the task directory (e.g. docs/**,*.min.js)")
+	rootCmd.Flags().BoolVar(&cfg.ExcludeTests, "exclude-tests", false, "Exclude test and generated source directories (test, tests, __tests__, testdata, generated, gen) from fingerprinting")
 
 	// Build tool specific flags
+	rootCmd.Flags().StringSliceVar(&cfg.OnlyTools, "only-tool", nil, "Only run scanners for these build tools (e.g. go,maven)")
+	rootCmd.Flags().StringSliceVar(&cfg.SkipTools, "skip-tool", nil, "Skip scanners for these build tools (e.g. npm)")
+	rootCmd.Flags().DurationVar(&cfg.BuildToolTimeout, "build-tool-timeout", config.DefaultBuildToolTimeout, "Time an external build tool command may run before it is killed and static parsing is used")
 	rootCmd.Flags().StringVar(&cfg.MavenPath, "maven-path", "", "Maven executable path")
+	rootCmd.Flags().StringVar(&cfg.MavenSettings, "maven-settings", "", "Maven settings.xml with mirrors, proxies and credentials passed to mvn as -s (default ~/.m2/settings.xml)")
 	rootCmd.Flags().StringVar(&cfg.MavenBuildCommand, "maven-build-command", "", "Maven build command")
 	rootCmd.Flags().StringVar(&cfg.PipPath, "pip-path", "", "Pip executable path")
 	rootCmd.Flags().StringVar(&cfg.PipRequirementsPath, "pip-requirements-path", "", "Pip requirements file path")
+	rootCmd.Flags().StringVar(&cfg.PipConstraintsPath, "pip-constraints", "", "Pip constraints file path")
+	rootCmd.Flags().StringVar(&cfg.DotnetPath, "dotnet-path", "", "dotnet executable path; when set, dotnet list package --include-transitive resolves the restored package graph")
+	rootCmd.Flags().StringVar(&cfg.GoProxy, "go-proxy", "", "GOPROXY for go list (default: inherited from the environment)")
+	rootCmd.Flags().StringVar(&cfg.GoFlags, "go-flags", "", "GOFLAGS for go list, e.g. -mod=mod (default: inherited from the environment)")
+	rootCmd.Flags().StringVar(&cfg.ExternalScanner, "external-scanner", "", "Executable run with the scan directory that prints extra dependency roots as JSON on stdout")
+	rootCmd.Flags().BoolVar(&cfg.PreferLockfile, "prefer-lockfile", false, "When files of one ecosystem overlap (requirements.txt and Pipfile, several npm lockfiles), only use the locked source")
+	rootCmd.Flags().BoolVar(&cfg.PreferManifest, "prefer-manifest", false, "When files of one ecosystem overlap, only use the declared manifest (requirements.txt, package.json ranges)")
+	rootCmd.Flags().StringVar(&cfg.VenvPath, "venv-path", "", "Virtualenv to read installed packages from when pip cannot be invoked (default: $VIRTUAL_ENV)")
+	rootCmd.Flags().BoolVar(&cfg.ExperimentalCScan, "experimental-c-scan", false, "Heuristically detect system libraries in Makefile/CMake C projects")
+
+	// Dependency output flags
+	rootCmd.Flags().StringArrayVar(&cfg.InternalPatterns, "internal-pattern", nil, "Mark dependencies whose group or name matches this glob (e.g. com.mycorp.*, @myorg/*) or re:<regexp> as internal (repeatable)")
+	rootCmd.Flags().StringSliceVar(&cfg.ExcludeScopes, "exclude-scope", nil, "Dependency scopes to exclude from output (e.g. peer,optional)")
+	rootCmd.Flags().BoolVar(&cfg.NormalizeVersions, "normalize-versions", false, "Strip range operators and v prefixes from single versions (e.g. ^4.18.2, ~=1.0, v1.9.1), keeping the original as rawVersion")
+	rootCmd.Flags().BoolVar(&cfg.NormalizeTypes, "normalize-types", false, "Map dependency types to package URL ecosystems (e.g. jar and gradle to maven, pipenv to pypi), keeping the original as rawType")
+	rootCmd.Flags().BoolVar(&cfg.ResolveVersions, "resolve-versions", false, "Resolve npm and Python version constraints to the newest matching version in the npm registry or PyPI, keeping the constraint as rawVersion (needs network access; failed lookups keep the constraint)")
+	rootCmd.Flags().BoolVar(&cfg.ReportUnmatchedOnly, "report-unmatched-only", false, "Only output dependencies whose version is empty or unknown, to diagnose incomplete scans")
+	rootCmd.Flags().BoolVar(&cfg.FlattenDeps, "flatten-deps", false, "Output a flat deduplicated dependency list per root, with each dependency's shallowest depth, instead of nested trees")
+	rootCmd.Flags().IntVar(&dependencyDepth, "dependency-depth", -1, "Transitive dependency levels to keep (0 = direct only, -1 = unlimited)")
+	rootCmd.Flags().StringVar(&cfg.SBOMInput, "sbom-input", "", "Read dependencies from this CycloneDX or SPDX JSON file instead of running the build tool scanners")
+	rootCmd.Flags().StringVar(&cfg.Format, "format", config.FormatJSON, "Dependency output format (json, cyclonedx, spdx, csv, dot, jsonl)")
+	rootCmd.Flags().StringVar(&cfg.OutputPath, "output", "", "Write dependency output in the selected format to this file")
+	rootCmd.Flags().StringVar(&cfg.HTMLReport, "html-report", "", "Write a self-contained HTML summary to this file instead of uploading (no server needed)")
+	rootCmd.Flags().BoolVar(&cfg.ProjectsOnly, "projects-only", false, "Write the name, version, build tool, description and license of each detected project to --output as JSON, without resolving dependencies (no server needed)")
+
+	// Policy flags
+	rootCmd.Flags().StringSliceVar(&cfg.FailOn, "fail-on", nil, "Conditions that fail the scan with exit code 5 (stale-lockfile)")
+	rootCmd.Flags().BoolVar(&cfg.Strict, "strict", false, "Fail the scan with exit code 5 on any warning, such as a scanner fallback, a failed or skipped scanner or a stale lockfile, or on a dependency with an unknown version")
 }
 
 func initConfig() {
 	if cfg == nil {
 		cfg = config.NewScanConfig()
 	}
+	if rootCmd.Flags().Changed("dependency-depth") {
+		cfg.DependencyDepth = &dependencyDepth
+	}
+	if rootCmd.Flags().Changed("compress-archive-level") {
+		cfg.CompressArchiveLevel = &archiveLevel
+	}
+
+	// Secrets mounted as files keep them out of shell history and process arguments
+	if err := cfg.LoadSecretFiles(); err != nil {
+		logger.GetLogger().Errorf("Invalid configuration: %v", err)
+		os.Exit(app.ExitCode(app.NewConfigError(err)))
+	}
+
+	// Apply the project-local config at the scan root; flags take precedence
+	if cfg.TaskDir != "" {
+		applyProjectConfig(rootCmd.Flags().Changed)
+	}
+}
+
+// applyProjectConfig applies the project-local config of the task directory to the flags that
+// were not changed
+func applyProjectConfig(changed func(name string) bool) {
+	projectConfig, err := config.LoadProjectConfig(cfg.TaskDir)
+	if err != nil {
+		logger.GetLogger().Warnf("Failed to load project config: %v", err)
+		return
+	}
+	cfg.ApplyProjectConfig(projectConfig, changed)
 }
 
 func runScan(cmd *cobra.Command, args []string) {
+	if printSchema {
+		if _, err := cmd.OutOrStdout().Write(model.DependencySchema); err != nil {
+			logger.GetLogger().Errorf("Failed to print schema: %v", err)
+			os.Exit(app.ExitCode(err))
+		}
+		return
+	}
+
+	if printConfig {
+		if err := writeConfig(cmd.OutOrStdout(), cfg); err != nil {
+			logger.GetLogger().Errorf("Failed to print configuration: %v", err)
+			os.Exit(app.ExitCode(app.NewConfigError(err)))
+		}
+		return
+	}
+
 	// Initialize logger
 	logger.InitLogger(cfg.LogLevel)
+	logger.SetColorMode(cfg.Color)
+	logger.SetRedaction(cfg.Redact, cfg.Password, cfg.Token)
 	log := logger.GetLogger()
 
 	log.Info("-----        Detect Version CleanSource_SCA: 4.0.0        -----")
@@ -76,14 +210,25 @@ func runScan(cmd *cobra.Command, args []string) {
 	application := app.NewBuildScanApplication(cfg)
 	if err := application.Run(); err != nil {
 		log.Errorf("Scan failed: %v", err)
-		os.Exit(1)
+		os.Exit(app.ExitCode(err))
 	}
 
 	log.Info("------------- END OF SCAN ------------")
 }
 
+// writeConfig writes the resolved configuration, after flags, secret files and the project
+// config were applied, as indented JSON with secrets masked
+func writeConfig(w io.Writer, cfg *config.ScanConfig) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cfg.Redacted())
+}
+
 func printParamLog(cfg *config.ScanConfig) {
 	log := logger.GetLogger()
+	if cfg.ServerURL != "" {
+		log.Infof("Server URL: %s", cfg.ServerURL)
+	}
 	log.Infof("Task Directory: %s", cfg.TaskDir)
 	log.Infof("Scan Type: %s", cfg.ScanType)
 	log.Infof("Build Depend: %t", cfg.BuildDepend)
@@ -2,20 +2,24 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"runtime"
-	"sync"
-	"sync/atomic"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/sbom"
 	"github.com/craftslab/cleansource-sca-cli/internal/scanner"
 	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+	"github.com/craftslab/cleansource-sca-cli/internal/vcs"
 	"github.com/craftslab/cleansource-sca-cli/pkg/buildtools"
 	"github.com/craftslab/cleansource-sca-cli/pkg/client"
 )
@@ -29,9 +33,21 @@ type BuildScanApplication struct {
 
 // NewBuildScanApplication creates a new application instance
 func NewBuildScanApplication(cfg *config.ScanConfig) *BuildScanApplication {
+	remotingClient := client.NewRemotingClient(cfg.ServerURL)
+	remotingClient.SetRetryPolicy(client.RetryPolicy{
+		MaxRetries:  max(cfg.RetryCount, 0),
+		WaitTime:    cfg.GetRetryWait(),
+		MaxWaitTime: cfg.GetRetryMaxWait(),
+	})
+	transportPolicy := client.DefaultTransportPolicy()
+	transportPolicy.MaxIdleConns = cfg.GetMaxIdleConns()
+	transportPolicy.KeepAlive = cfg.GetKeepAlive()
+	remotingClient.SetTransportPolicy(transportPolicy)
+	remotingClient.SetUploadRate(cfg.GetUploadRate())
+
 	return &BuildScanApplication{
 		config: cfg,
-		client: client.NewRemotingClient(cfg.ServerURL),
+		client: remotingClient,
 		log:    logger.GetLogger(),
 	}
 }
@@ -40,12 +56,22 @@ func NewBuildScanApplication(cfg *config.ScanConfig) *BuildScanApplication {
 func (app *BuildScanApplication) Run() error {
 	// Validate configuration
 	if err := app.config.Validate(); err != nil {
-		return fmt.Errorf("configuration validation failed: %w", err)
+		return NewConfigError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+	if err := checkTaskDir(app.config.TaskDir); err != nil {
+		return NewConfigError(err)
 	}
 
 	// Set output path
 	app.config.SetToPath(app.config.TaskDir)
 
+	if app.config.ProjectsOnly {
+		return app.runProjectsOnly()
+	}
+	if app.config.Offline() {
+		return app.runLocalReport()
+	}
+
 	switch app.config.ScanType {
 	case "source":
 		return app.runSourceScan()
@@ -54,15 +80,48 @@ func (app *BuildScanApplication) Run() error {
 	case "binary":
 		return app.runBinaryScan()
 	default:
-		return fmt.Errorf("unsupported scan type: %s", app.config.ScanType)
+		return NewConfigError(fmt.Errorf("unsupported scan type: %s", app.config.ScanType))
+	}
+}
+
+// checkTaskDir verifies that an existing task directory is a directory that can be listed.
+// A missing one is left to the scan, which reports it once the server has been checked.
+func checkTaskDir(taskDir string) error {
+	info, err := os.Stat(taskDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access scan directory: %w", err)
 	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s", config.ErrTaskDirNotDirectory, taskDir)
+	}
+
+	dir, err := os.Open(taskDir)
+	if err == nil {
+		_, err = dir.Readdirnames(1)
+		_ = dir.Close()
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("scan directory is not readable: %w", err)
+	}
+	return nil
 }
 
 // runSourceScan handles source code scanning
 func (app *BuildScanApplication) runSourceScan() error {
+	// Check server health before any local work
+	if app.config.ServerHealth {
+		app.log.Info("Checking server health...")
+		if err := app.client.HealthCheck(); err != nil {
+			return fmt.Errorf("server health check failed: %w", err)
+		}
+	}
+
 	// Verify authentication
 	if err := app.verifyAuth(); err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+		return NewAuthError(fmt.Errorf("authentication failed: %w", err))
 	}
 
 	// Check scan directory
@@ -84,12 +143,16 @@ func (app *BuildScanApplication) runSourceScan() error {
 	}
 	app.log.Infof("Scan directory: %s, size: %d bytes", taskDir, dirSize)
 
+	// The revision is read before the scan writes any file that could show up as a change
+	revision := app.detectRevision(taskDir)
+
 	// Create scannable environment
 	env := buildtools.NewScannableEnvironment(taskDir, "")
 
-	// Generate fingerprint file
+	// Generate fingerprint file; an incremental run is only one once a previous run left its cache
 	app.log.Info("Generating fingerprint file...")
-	wfpFile, err := app.generateWfpFile(env)
+	cached := app.config.Incremental && utils.FileExists(app.config.GetWfpCachePath())
+	wfpFile, wfpScanner, err := app.generateWfpFile(env)
 	if err != nil {
 		return fmt.Errorf("failed to generate fingerprint file: %w", err)
 	}
@@ -97,13 +160,42 @@ func (app *BuildScanApplication) runSourceScan() error {
 		_ = os.Remove(name)
 	}(wfpFile) // Clean up
 
+	// Unchanged fingerprints need neither the WFP file nor the source archive uploaded again
+	var wfpUnchanged bool
+	var wfpRecord string
+	if app.config.SkipUnchangedWfp {
+		wfpUnchanged, wfpRecord = app.checkWfpUnchanged(wfpFile)
+	}
+
+	// Oversized fingerprint files follow the scan upload in chunks
+	var wfpChunks []string
+	if chunkSize := app.config.GetWfpChunkSize(); chunkSize > 0 && !wfpUnchanged {
+		wfpChunks, err = app.splitWfpFile(wfpFile, chunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to split fingerprint file: %w", err)
+		}
+		defer func(names []string) {
+			for _, name := range names {
+				_ = os.Remove(name)
+			}
+		}(wfpChunks) // Clean up
+	}
+
 	// Build dependency information if enabled
 	var buildFile string
-	if app.config.BuildDepend {
+	var dependencies []model.DependencyRoot
+	var scanWarnings []string
+	var dependencyErr error
+	if app.config.BuildDepend || app.config.SBOMInput != "" {
 		app.log.Info("Building dependency information...")
-		buildFile, err = app.buildDependencyInfo(env)
-		if err != nil {
-			app.log.Warnf("Failed to build dependency information: %v", err)
+		buildFile, dependencies, scanWarnings, dependencyErr = app.buildDependencyInfo(env)
+		// A failure with an exit code, such as an unwritable --output, ends the scan; others only degrade it
+		var scanErr *ScanError
+		if errors.As(dependencyErr, &scanErr) {
+			return dependencyErr
+		}
+		if dependencyErr != nil {
+			app.log.Warnf("Failed to build dependency information: %v", dependencyErr)
 		}
 		if buildFile != "" {
 			defer func(name string) {
@@ -112,11 +204,17 @@ func (app *BuildScanApplication) runSourceScan() error {
 		}
 	}
 
-	// Create archive if needed
+	// Subtrees the fingerprint walk could not read are reported with the scan warnings
+	for _, path := range wfpScanner.TooLongPaths() {
+		scanWarnings = append(scanWarnings, fmt.Sprintf("Left %s out of the fingerprints, its path is too long to be read", path))
+	}
+	app.summarizeWarnings(dependencies, scanWarnings)
+
+	// Create archive if needed; in unmatched-only mode it is uploaded after matching instead
 	var archiveFile string
-	if app.config.DefaultParam.IsSaveSourceFile == 1 {
+	if app.config.DefaultParam.IsSaveSourceFile == 1 && !app.config.ArchiveUnmatchedOnly && !wfpUnchanged {
 		app.log.Info("Creating source archive...")
-		archiveFile, err = utils.CreateZipArchive(taskDir, app.config.ToPath)
+		archiveFile, err = utils.CreateArchive(taskDir, app.config.ToPath, app.config.GetArchiveFormat(), app.config.GetCompressArchiveLevel())
 		if err != nil {
 			app.log.Warnf("Failed to create archive: %v", err)
 		}
@@ -127,29 +225,185 @@ func (app *BuildScanApplication) runSourceScan() error {
 		}
 	}
 
+	// Default the project name and version to the Git repository being scanned
+	app.applyVCSDefaults(revision)
+
+	// Without a build manifest, describe the project from its source files instead
+	var languages *scanner.LanguageSummary
+	if len(dependencies) == 0 {
+		languages = app.detectLanguages(taskDir)
+	}
+
 	// Upload data to server
 	app.log.Info("Uploading scan data...")
 	uploadData := &model.UploadData{
-		WfpFile:     wfpFile,
-		BuildFile:   buildFile,
-		ArchiveFile: archiveFile,
-		Config:      app.config,
-		DirSize:     dirSize,
+		WfpFile:      wfpFile,
+		BuildFile:    buildFile,
+		ArchiveFile:  archiveFile,
+		Config:       app.config,
+		DirSize:      dirSize,
+		WfpUnchanged: wfpUnchanged,
+		WfpPartial:   app.config.FilesFrom != "",
+		ScanMode:     scanMode(app.config, cached, wfpUnchanged),
+	}
+	if revision != nil {
+		uploadData.Commit = revision.Commit
+		uploadData.Dirty = revision.Dirty
+	}
+	if wfpSince := wfpScanner.Since(); !wfpSince.IsZero() {
+		uploadData.WfpSince = &wfpSince
+	}
+	if wfpUnchanged || len(wfpChunks) > 0 {
+		uploadData.WfpFile = ""
+		uploadData.WfpChunks = len(wfpChunks)
+	}
+	if licenses := app.collectLicenseFiles(taskDir); licenses != nil {
+		uploadData.LicenseFiles = licenses
+	}
+	if languages != nil {
+		uploadData.DetectedProject = languages.ProjectName
+		uploadData.Languages = languages.Dominant()
 	}
 
-	success, err := app.client.UploadData(uploadData)
+	result, err := app.client.UploadScan(uploadData)
 	if err != nil {
-		return fmt.Errorf("failed to upload data: %w", err)
+		return NewUploadError(fmt.Errorf("failed to upload data: %w", err))
+	}
+
+	if !result.Success {
+		return NewUploadError(fmt.Errorf("upload was not successful"))
 	}
 
-	if !success {
-		return fmt.Errorf("upload was not successful")
+	if len(wfpChunks) > 0 {
+		if result.TaskID == "" {
+			return NewUploadError(fmt.Errorf("server returned no task ID for the fingerprint chunks"))
+		}
+		if err := app.client.UploadWfpChunks(result.TaskID, wfpChunks); err != nil {
+			return NewUploadError(fmt.Errorf("failed to upload fingerprint chunks: %w", err))
+		}
+	}
+
+	if app.config.ArchiveUnmatchedOnly && !wfpUnchanged {
+		if err := app.uploadUnmatchedArchive(taskDir, result.TaskID, wfpScanner); err != nil {
+			return NewUploadError(err)
+		}
+	}
+
+	// Record the hash only after a successful upload, so a failed one is retried in full
+	if wfpRecord != "" && !wfpUnchanged {
+		if err := os.WriteFile(app.config.GetWfpHashPath(), []byte(wfpRecord), 0644); err != nil {
+			app.log.Warnf("Failed to save fingerprint hash: %v", err)
+		}
+	}
+
+	// Results are uploaded before policies are enforced so the server still records the scan
+	if err := app.checkFailOn(dependencies); err != nil {
+		return err
+	}
+	if err := app.checkStrict(dependencies, scanWarnings, dependencyErr); err != nil {
+		return err
 	}
 
 	app.log.Info("Scan completed successfully")
 	return nil
 }
 
+// detectRevision reads the Git revision of the task directory, logging the commit scanned and
+// whether it has uncommitted changes. It returns nil outside a Git work tree.
+func (app *BuildScanApplication) detectRevision(taskDir string) *vcs.Info {
+	info := vcs.Detect(taskDir)
+	switch {
+	case info == nil:
+		app.log.Debug("Scan directory is not in a Git work tree, no revision recorded")
+	case info.Commit == "":
+		app.log.Info("Scan directory is in a Git repository without commits")
+	case info.Dirty:
+		app.log.Warnf("Scanning Git commit %s with uncommitted changes", info.Commit)
+	default:
+		app.log.Infof("Scanning Git commit %s", info.Commit)
+	}
+	return info
+}
+
+// applyVCSDefaults derives the custom project and version from the Git repository when they
+// were not given
+func (app *BuildScanApplication) applyVCSDefaults(info *vcs.Info) {
+	if info == nil {
+		return
+	}
+	if app.config.CustomProject == "" && info.Project != "" {
+		app.config.CustomProject = info.Project
+		app.log.Infof("Using project name from Git: %s", info.Project)
+	}
+	if app.config.CustomVersion == "" && info.Version != "" {
+		app.config.CustomVersion = info.Version
+		app.log.Infof("Using version from Git: %s", info.Version)
+	}
+}
+
+// collectLicenseFiles finds the license and NOTICE files of the task directory, returning nil
+// when there are none
+func (app *BuildScanApplication) collectLicenseFiles(taskDir string) *model.FilePathCollect {
+	licenses, err := scanner.NewWfpScanner(app.config).CollectLicenseFiles(taskDir)
+	if err != nil {
+		app.log.Warnf("Failed to collect license files: %v", err)
+		return nil
+	}
+	if len(licenses.ProjectLicenseFiles) == 0 && len(licenses.NoticeFiles) == 0 {
+		return nil
+	}
+	app.log.Infof("Found %d license and %d NOTICE files", len(licenses.ProjectLicenseFiles), len(licenses.NoticeFiles))
+	return licenses
+}
+
+// detectLanguages guesses the project name and languages of a directory from its source files,
+// returning nil when it holds no recognized source files
+func (app *BuildScanApplication) detectLanguages(taskDir string) *scanner.LanguageSummary {
+	summary, err := scanner.NewWfpScanner(app.config).DetectLanguages(taskDir)
+	if err != nil {
+		app.log.Warnf("Failed to detect languages: %v", err)
+		return nil
+	}
+	if summary.SourceFiles == 0 {
+		return nil
+	}
+
+	var counts []string
+	for _, language := range summary.Languages {
+		counts = append(counts, fmt.Sprintf("%s (%d files)", language.Language, language.Files))
+	}
+	app.log.Infof("No build manifest found; project %s, dominant languages: %s, source files: %s",
+		summary.ProjectName, strings.Join(summary.Dominant(), ", "), strings.Join(counts, ", "))
+	return summary
+}
+
+// Scan modes uploaded as scanMode metadata, telling the server how to merge the results with
+// those of earlier scans of the project
+const (
+	ScanModeFull        = "full"        // Every file fingerprinted
+	ScanModeIncremental = "incremental" // Fingerprints carried over from a previous run or limited to changed files
+	ScanModeDepsOnly    = "deps-only"   // Fingerprints unchanged since the last upload, only dependencies sent
+	ScanModeBinary      = "binary"
+	ScanModeDocker      = "docker"
+)
+
+// scanMode determines the scan mode from the flags in effect. cached reports whether
+// --incremental found the fingerprint cache of a previous run, so a first run counts as full,
+// and wfpUnchanged whether --skip-unchanged-wfp left the fingerprints out of the upload.
+func scanMode(cfg *config.ScanConfig, cached, wfpUnchanged bool) string {
+	switch {
+	case cfg.ScanType == "docker":
+		return ScanModeDocker
+	case cfg.ScanType == "binary":
+		return ScanModeBinary
+	case wfpUnchanged:
+		return ScanModeDepsOnly
+	case cached || cfg.FilesFrom != "" || cfg.Since != "":
+		return ScanModeIncremental
+	}
+	return ScanModeFull
+}
+
 // runDockerScan handles Docker image scanning
 func (app *BuildScanApplication) runDockerScan() error {
 	app.log.Info("Starting Docker scan...")
@@ -166,48 +420,295 @@ func (app *BuildScanApplication) runBinaryScan() error {
 
 // verifyAuth verifies authentication with the server
 func (app *BuildScanApplication) verifyAuth() error {
-	if app.config.Token != "" {
+	switch {
+	case app.config.AuthMode == config.AuthModeBasic:
+		// Credentials are sent with every request, there is no session to establish
+		app.log.Info("Using HTTP Basic authentication...")
+		app.config.AuthType = config.AuthTypeBasic
+		app.client.SetBasicAuth(app.config.Username, app.config.Password)
+		return nil
+	case app.config.AuthMode == config.AuthModeToken || (app.config.AuthMode == "" && app.config.Token != ""):
 		app.log.Info("Verifying token...")
 		app.config.AuthType = config.AuthTypeToken
 		return app.client.VerifyToken(app.config.Token)
-	} else {
+	default:
 		app.log.Info("Logging in with username/password...")
 		app.config.AuthType = config.AuthTypeCookie
 		return app.client.Login(app.config.Username, app.config.Password)
 	}
 }
 
-// generateWfpFile generates a fingerprint file for the source code
-func (app *BuildScanApplication) generateWfpFile(env *buildtools.ScannableEnvironment) (string, error) {
+// generateWfpFile generates a fingerprint file for the source code, returning the scanner that
+// knows which files it fingerprinted
+func (app *BuildScanApplication) generateWfpFile(env *buildtools.ScannableEnvironment) (string, *scanner.WfpScanner, error) {
 	wfpScanner := scanner.NewWfpScanner(app.config)
-	return wfpScanner.GenerateWfpFile(env.GetDirectory())
+	wfpFile, err := wfpScanner.GenerateWfpFile(env.GetDirectory())
+	if err == nil && len(app.config.CombineDirs) > 0 {
+		err = app.combineWfpFiles(wfpFile)
+	}
+	return wfpFile, wfpScanner, err
 }
 
-// buildDependencyInfo builds dependency information
-func (app *BuildScanApplication) buildDependencyInfo(env *buildtools.ScannableEnvironment) (string, error) {
-	// Detect build tools and create appropriate scanner
-	buildScanner := buildtools.NewBuildScanner(env, app.config)
-	dependencies, err := buildScanner.ScanDependencies()
+// combineWfpFiles fingerprints every --combine-dir directory and adds its entries to wfpFile
+// below the directory's prefix, so several directories upload as one project
+func (app *BuildScanApplication) combineWfpFiles(wfpFile string) error {
+	parts := []scanner.WfpPart{{File: wfpFile}}
+	defer func() {
+		for _, part := range parts[1:] {
+			_ = os.Remove(part.File)
+		}
+	}()
+
+	for i, dir := range app.config.GetCombineDirs() {
+		// Only the task directory keeps the incremental cache and the file manifest
+		cfg := *app.config
+		cfg.TaskDir = dir.Dir
+		cfg.WfpName = fmt.Sprintf("%s.part%d", filepath.Base(wfpFile), i+1)
+		cfg.Incremental = false
+		cfg.FileManifest = ""
+		cfg.FilesFrom = ""
+
+		app.log.Infof("Fingerprinting %s below %s/", dir.Dir, dir.Prefix)
+		partFile, err := scanner.NewWfpScanner(&cfg).GenerateWfpFile(dir.Dir)
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint %s: %w", dir.Dir, err)
+		}
+		parts = append(parts, scanner.WfpPart{File: partFile, Prefix: dir.Prefix})
+	}
+
+	return scanner.CombineWfpFiles(wfpFile, parts)
+}
+
+// checkWfpUnchanged compares the WFP file with the record stored by the last successful upload,
+// returning whether it is unchanged and its current record. The record holds the server, project
+// and task directory next to the hash, so an upload elsewhere never counts as unchanged.
+func (app *BuildScanApplication) checkWfpUnchanged(wfpFile string) (bool, string) {
+	hash, err := utils.CalculateFileHash(wfpFile)
+	if err != nil {
+		app.log.Warnf("Failed to hash fingerprint file: %v", err)
+		return false, ""
+	}
+	record := app.wfpHashRecord(hash)
+
+	previous, err := os.ReadFile(app.config.GetWfpHashPath())
+	if err != nil || string(previous) != record {
+		return false, record
+	}
+	app.log.Info("Fingerprints are unchanged since the last upload, uploading the dependency file only")
+	return true, record
+}
+
+// wfpHashRecord returns the WFP hash followed by the upload target it was recorded for
+func (app *BuildScanApplication) wfpHashRecord(hash string) string {
+	taskDir, err := filepath.Abs(app.config.TaskDir)
 	if err != nil {
-		return "", err
+		taskDir = app.config.TaskDir
+	}
+	return fmt.Sprintf("%s\nserver=%s\nproject=%s\nproduct=%s\nversion=%s\ntaskDir=%s\n",
+		hash, app.config.ServerURL, app.config.CustomProject, app.config.CustomProduct, app.config.CustomVersion, taskDir)
+}
+
+// splitWfpFile splits the WFP file into chunks when it is larger than chunkSize, returning
+// nil when it can be uploaded whole
+func (app *BuildScanApplication) splitWfpFile(wfpFile string, chunkSize int64) ([]string, error) {
+	size, err := utils.GetFileSize(wfpFile)
+	if err != nil || size <= chunkSize {
+		return nil, err
+	}
+
+	chunks, err := scanner.SplitWfpFile(wfpFile, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	app.log.Infof("Fingerprint file of %d bytes split into %d chunks", size, len(chunks))
+	return chunks, nil
+}
+
+// buildDependencyInfo builds dependency information, also returning the scan warnings no
+// dependency root carries
+func (app *BuildScanApplication) buildDependencyInfo(env *buildtools.ScannableEnvironment) (string, []model.DependencyRoot, []string, error) {
+	// Dependencies read from --sbom-input need no build tool detection
+	var buildScanner *buildtools.BuildScanner
+	if app.config.SBOMInput == "" {
+		buildScanner = buildtools.NewBuildScanner(env, app.config)
+	}
+	dependencies, scanWarnings, err := app.scanDependencies(buildScanner)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// Scanners range over maps, so fix the order before anything is serialized
+	model.SortDependencies(dependencies)
+	app.summarizeDependencies(dependencies)
+
+	// Write the dependency output in the selected format
+	if app.config.OutputPath != "" {
+		if err := app.writeOutput(dependencies); err != nil {
+			return "", dependencies, scanWarnings, NewConfigError(fmt.Errorf("failed to write dependency output: %w", err))
+		}
+	}
+	if app.config.DependencyOutputPerTool {
+		if err := app.writeDependenciesPerTool(dependencies); err != nil {
+			app.log.Warnf("Failed to write per-tool dependency files: %v", err)
+		}
+		if app.config.SplitOnly {
+			return "", dependencies, scanWarnings, nil
+		}
 	}
 
 	// Convert to JSON and write to file
 	jsonData, err := json.MarshalIndent(dependencies, "", "  ")
 	if err != nil {
-		return "", err
+		return "", dependencies, scanWarnings, err
 	}
 
-	buildFile := filepath.Join(app.config.ToPath, "dependencies.json")
+	buildFile := app.config.GetDepsPath()
 	err = os.WriteFile(buildFile, jsonData, 0644)
 	if err != nil {
-		return "", err
+		return "", dependencies, scanWarnings, err
+	}
+
+	return buildFile, dependencies, scanWarnings, nil
+}
+
+// summarizeDependencies logs the dependency counts of the risk summary
+func (app *BuildScanApplication) summarizeDependencies(dependencies []model.DependencyRoot) {
+	summary := model.NewRiskSummary(dependencies)
+	if summary.Total == 0 {
+		return
+	}
+
+	ecosystems := make([]string, 0, len(summary.ByEcosystem))
+	for ecosystem, count := range summary.ByEcosystem {
+		ecosystems = append(ecosystems, fmt.Sprintf("%s: %d", ecosystem, count))
+	}
+	slices.Sort(ecosystems)
+	app.log.Infof("Found %d dependencies (%d direct, %d transitive, %d with unknown version; %s)",
+		summary.Total, summary.Direct, summary.Transitive, summary.Unknown, strings.Join(ecosystems, ", "))
+}
+
+// summarizeWarnings lists the warnings recorded by the scanners, which are also written to
+// dependencies.json, and the scan warnings no root carries, such as paths left out of the
+// fingerprints
+func (app *BuildScanApplication) summarizeWarnings(dependencies []model.DependencyRoot, scanWarnings []string) {
+	warnings := slices.Clone(scanWarnings)
+	for _, root := range dependencies {
+		for _, warning := range root.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%s (%s): %s", root.ProjectName, root.BuildTool, warning))
+		}
+	}
+	if len(warnings) == 0 {
+		return
+	}
+
+	app.log.Warnf("Scan finished with %d warning(s):", len(warnings))
+	for _, warning := range warnings {
+		app.log.Warnf("  - %s", warning)
+	}
+}
+
+// scanDependencies runs the build tool scanners, or reads the dependencies from --sbom-input
+// when given and runs the same processors over them; buildScanner is unused then and may be
+// nil. Scan warnings travel on the first root; without any root, such as when every scanner
+// was skipped, they are returned separately.
+func (app *BuildScanApplication) scanDependencies(buildScanner *buildtools.BuildScanner) ([]model.DependencyRoot, []string, error) {
+	if app.config.SBOMInput == "" {
+		dependencies, err := buildScanner.ScanDependencies()
+		if err != nil || len(dependencies) > 0 {
+			return dependencies, nil, err
+		}
+		return nil, buildScanner.Warnings(), nil
+	}
+
+	app.log.Infof("Reading dependencies from SBOM: %s", app.config.SBOMInput)
+	dependencies, err := sbom.ReadFile(app.config.SBOMInput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read SBOM input: %w", err)
+	}
+	return buildtools.ProcessDependencies(app.config, dependencies), nil, nil
+}
+
+// uploadUnmatchedArchive archives and uploads only the files the server could not match
+// from the fingerprints of the given task. Only paths wfpScanner fingerprinted are archived,
+// so the server cannot ask for files that were never part of the scan.
+func (app *BuildScanApplication) uploadUnmatchedArchive(taskDir, taskID string, wfpScanner *scanner.WfpScanner) error {
+	if taskID == "" {
+		return fmt.Errorf("server returned no task ID for the unmatched files archive")
+	}
+
+	paths, err := app.client.FetchUnmatchedPaths(taskID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch unmatched paths: %w", err)
+	}
+	if len(paths) == 0 {
+		app.log.Info("All files were matched, no source archive needed")
+		return nil
+	}
+	fingerprinted := wfpScanner.Fingerprinted(paths)
+	if dropped := len(paths) - len(fingerprinted); dropped > 0 {
+		app.log.Warnf("Ignoring %d unmatched path(s) that were not fingerprinted by this scan", dropped)
+	}
+	if len(fingerprinted) == 0 {
+		return nil
+	}
+	paths = fingerprinted
+
+	app.log.Infof("Creating source archive of %d unmatched files...", len(paths))
+	archiveFile, err := utils.CreateArchiveFiles(taskDir, app.config.ToPath, app.config.GetArchiveFormat(),
+		app.config.GetCompressArchiveLevel(), paths)
+	if err != nil {
+		return fmt.Errorf("failed to create unmatched files archive: %w", err)
+	}
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(archiveFile) // Clean up
+
+	if err := app.client.UploadArchive(taskID, archiveFile); err != nil {
+		return fmt.Errorf("failed to upload unmatched files archive: %w", err)
+	}
+	return nil
+}
+
+// checkFailOn returns a policy error when the dependency scan meets a --fail-on condition
+func (app *BuildScanApplication) checkFailOn(dependencies []model.DependencyRoot) error {
+	if !app.config.FailsOn(config.FailOnStaleLockfile) {
+		return nil
+	}
+	for _, root := range dependencies {
+		for _, warning := range root.Warnings {
+			if buildtools.IsStaleLockfileWarning(warning) {
+				return NewPolicyError(errors.New(warning))
+			}
+		}
+	}
+	return nil
+}
+
+// checkStrict returns a policy error in --strict mode when the dependency scan failed, recorded
+// any warning, including skipped scanners, or left a dependency version unknown
+func (app *BuildScanApplication) checkStrict(dependencies []model.DependencyRoot, scanWarnings []string, dependencyErr error) error {
+	if !app.config.Strict {
+		return nil
+	}
+	if dependencyErr != nil {
+		return NewPolicyError(fmt.Errorf("strict mode: failed to build dependency information: %w", dependencyErr))
 	}
 
-	return buildFile, nil
+	warnings := slices.Clone(scanWarnings)
+	for _, root := range dependencies {
+		warnings = append(warnings, root.Warnings...)
+	}
+	if len(warnings) > 0 {
+		return NewPolicyError(fmt.Errorf("strict mode: scan finished with %d warning(s), first: %s", len(warnings), warnings[0]))
+	}
+	if unknown := model.NewRiskSummary(dependencies).Unknown; unknown > 0 {
+		return NewPolicyError(fmt.Errorf("strict mode: %d dependencies have an unknown version", unknown))
+	}
+	return nil
 }
 
-// calculateDirSize calculates the total size of a directory using concurrent processing
+// calculateDirSize calculates the total size of the regular files in a directory. Sizes come
+// from the directory walk itself, so a single synchronous pass is all that is needed.
 func (app *BuildScanApplication) calculateDirSize(rootDir string) (int64, error) {
 	// Check if directory exists first
 	if _, err := os.Stat(rootDir); os.IsNotExist(err) {
@@ -215,44 +716,19 @@ func (app *BuildScanApplication) calculateDirSize(rootDir string) (int64, error)
 	}
 
 	var totalSize int64
-	var wg sync.WaitGroup
-	sizeChan := make(chan int64, 100)
-
-	// Limit concurrent goroutines
-	semaphore := make(chan struct{}, runtime.NumCPU()*2)
-
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
 			return nil // Continue walking even if there's an error with individual files
 		}
 
-		if info.IsDir() {
+		info, err := d.Info()
+		if err != nil {
 			return nil
 		}
-
-		wg.Add(1)
-		go func(size int64) {
-			defer wg.Done()
-			semaphore <- struct{}{}        // Acquire semaphore
-			defer func() { <-semaphore }() // Release semaphore
-
-			sizeChan <- size
-		}(info.Size())
-
+		totalSize += info.Size()
 		return nil
 	})
 
-	// Close channel when all goroutines are done
-	go func() {
-		wg.Wait()
-		close(sizeChan)
-	}()
-
-	// Sum up all sizes
-	for size := range sizeChan {
-		atomic.AddInt64(&totalSize, size)
-	}
-
 	return totalSize, err
 }
 
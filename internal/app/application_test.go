@@ -1,12 +1,24 @@
 package app
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 )
 
 // nolint: staticcheck
@@ -45,6 +57,29 @@ func TestBuildScanApplication_Run_ValidationError(t *testing.T) {
 	}
 }
 
+func TestBuildScanApplication_Run_TaskDirNotDirectory(t *testing.T) {
+	taskFile := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(taskFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cfg := &config.ScanConfig{
+		TaskDir:   taskFile,
+		ServerURL: "https://example.com",
+		Username:  "testuser",
+		Password:  "testpass",
+		ScanType:  "source",
+	}
+
+	err := NewBuildScanApplication(cfg).Run()
+	if !errors.Is(err, config.ErrTaskDirNotDirectory) {
+		t.Fatalf("Expected ErrTaskDirNotDirectory, got: %v", err)
+	}
+	if code := ExitCode(err); code != ExitConfig {
+		t.Errorf("Expected exit code %d, got %d", ExitConfig, code)
+	}
+}
+
 func TestBuildScanApplication_Run_UnsupportedScanType(t *testing.T) {
 	cfg := &config.ScanConfig{
 		TaskDir:   "/tmp/test",
@@ -171,6 +206,36 @@ func TestBuildScanApplication_calculateDirSize_LargeDirectory(t *testing.T) {
 	}
 }
 
+func TestBuildScanApplication_calculateDirSize_NestedTree(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Files of varying sizes across nested and empty directories; directories add nothing
+	var expectedSize int64
+	for d := 0; d < 5; d++ {
+		dir := filepath.Join(tempDir, fmt.Sprintf("level%d", d), "nested", "empty")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		for f := 0; f < 20; f++ {
+			content := make([]byte, d*100+f)
+			fileName := filepath.Join(filepath.Dir(dir), fmt.Sprintf("file%d.txt", f))
+			if err := os.WriteFile(fileName, content, 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+			expectedSize += int64(len(content))
+		}
+	}
+
+	app := NewBuildScanApplication(&config.ScanConfig{})
+	size, err := app.CalculateDirSize(tempDir)
+	if err != nil {
+		t.Fatalf("CalculateDirSize failed: %v", err)
+	}
+	if size != expectedSize {
+		t.Errorf("Expected size %d, got %d", expectedSize, size)
+	}
+}
+
 // Mock tests would require more complex setup, so here are basic integration tests
 
 func TestBuildScanApplication_runSourceScan_NonExistentDir(t *testing.T) {
@@ -190,6 +255,470 @@ func TestBuildScanApplication_runSourceScan_NonExistentDir(t *testing.T) {
 	}
 }
 
+// newStubServer starts a server answering the health and login endpoints with the given status codes
+func newStubServer(t *testing.T, healthStatus, loginStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(healthStatus)
+	})
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(loginStatus)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBuildScanApplication_runSourceScan_ServerHealth(t *testing.T) {
+	tests := []struct {
+		name         string
+		healthStatus int
+		loginStatus  int
+		expectedErr  string
+	}{
+		{"healthy", http.StatusOK, http.StatusOK, "scan directory does not exist"},
+		{"unhealthy", http.StatusServiceUnavailable, http.StatusOK, "server health check failed"},
+		{"invalid credentials", http.StatusOK, http.StatusUnauthorized, "authentication failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubServer(t, tt.healthStatus, tt.loginStatus)
+			cfg := &config.ScanConfig{
+				TaskDir:      "/non/existent/directory",
+				ServerURL:    server.URL,
+				Username:     "testuser",
+				Password:     "testpass",
+				ScanType:     "source",
+				ServerHealth: true,
+			}
+
+			app := NewBuildScanApplication(cfg)
+			err := app.runSourceScan()
+
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error starting with %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildScanApplication_verifyAuth_Basic(t *testing.T) {
+	server := newStubServer(t, http.StatusOK, http.StatusUnauthorized)
+	cfg := &config.ScanConfig{
+		ServerURL: server.URL,
+		Username:  "testuser",
+		Password:  "testpass",
+		AuthMode:  config.AuthModeBasic,
+	}
+
+	app := NewBuildScanApplication(cfg)
+	if err := app.verifyAuth(); err != nil {
+		t.Fatalf("Basic auth should not use the login endpoint, got: %v", err)
+	}
+	if cfg.AuthType != config.AuthTypeBasic {
+		t.Errorf("Expected AuthTypeBasic, got %d", cfg.AuthType)
+	}
+}
+
+func TestBuildScanApplication_Run_RedactsSecrets(t *testing.T) {
+	// The server echoes the rejected credentials back, as some error pages do
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write(body)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = t.TempDir()
+	cfg.ServerURL = strings.Replace(server.URL, "http://", "http://admin:url-secret@", 1)
+	cfg.Username = "testuser"
+	cfg.Password = "password-secret"
+	cfg.AllowInsecureHTTP = true
+
+	var buf bytes.Buffer
+	logger.InitLogger("debug")
+	logger.GetLogger().SetOutput(&buf)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+	logger.SetRedaction(cfg.Redact, cfg.Password, cfg.Token)
+
+	app := NewBuildScanApplication(cfg)
+	app.log.Infof("Server URL: %s", cfg.ServerURL)
+	err := app.Run()
+	if err == nil {
+		t.Fatal("Expected authentication to fail")
+	}
+	app.log.Errorf("Scan failed: %v", err)
+
+	output := buf.String()
+	for _, secret := range []string{"password-secret", "url-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Log output leaked %q:\n%s", secret, output)
+		}
+	}
+	if !strings.Contains(output, "authentication failed") {
+		t.Errorf("Expected the failure to be logged, got:\n%s", output)
+	}
+}
+
+func TestBuildScanApplication_runSourceScan_ArchiveUnmatchedOnly(t *testing.T) {
+	var archived []string
+	var uploadedArchive bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := r.FormFile("archiveFile"); err == nil {
+			t.Error("The first phase should not upload a source archive")
+		}
+		_, _ = w.Write([]byte(`{"success": true, "taskId": "task-1"}`))
+	})
+	mux.HandleFunc("/api/scan/unmatched", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"paths": ["src/unmatched.go", "generated/skip.go", "assets/logo.png"]}`))
+	})
+	mux.HandleFunc("/api/scan/archive", func(w http.ResponseWriter, r *http.Request) {
+		uploadedArchive = r.FormValue("taskId") == "task-1"
+		file, header, err := r.FormFile("archiveFile")
+		if err != nil {
+			t.Errorf("Expected archive file in the form: %v", err)
+			return
+		}
+		defer func() { _ = file.Close() }()
+		reader, err := zip.NewReader(file, header.Size)
+		if err != nil {
+			t.Errorf("Failed to read uploaded archive: %v", err)
+			return
+		}
+		for _, entry := range reader.File {
+			archived = append(archived, entry.Name)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	// generated/skip.go is excluded and assets/logo.png was never fingerprinted, so neither is archived
+	for _, name := range []string{"main.go", "src/unmatched.go", "generated/skip.go", "assets/logo.png"} {
+		fullPath := filepath.Join(taskDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = server.URL
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.BuildDepend = false
+	cfg.ArchiveUnmatchedOnly = true
+	cfg.ExcludePaths = []string{"generated/**"}
+
+	if err := NewBuildScanApplication(cfg).runSourceScan(); err != nil {
+		t.Fatalf("runSourceScan failed: %v", err)
+	}
+	if !uploadedArchive {
+		t.Fatal("Expected the unmatched files archive to be uploaded for task-1")
+	}
+	if len(archived) != 1 || archived[0] != "src/unmatched.go" {
+		t.Errorf("Expected only the unmatched file to be archived, got %v", archived)
+	}
+}
+
+func TestBuildScanApplication_runSourceScan_TooLongPathWarnings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Go prefixes long paths on Windows, the PATH_MAX limit is Unix only")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success": true, "taskId": "task-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	// Nest directories one relative step at a time until the full path exceeds PATH_MAX (4096)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+	if err := os.Chdir(taskDir); err != nil {
+		t.Fatalf("Failed to enter project directory: %v", err)
+	}
+	name := strings.Repeat("d", 200)
+	for i := 0; i < 22; i++ {
+		if err := os.Mkdir(name, 0755); err != nil {
+			t.Fatalf("Failed to create level %d: %v", i, err)
+		}
+		if err := os.Chdir(name); err != nil {
+			t.Fatalf("Failed to enter level %d: %v", i, err)
+		}
+	}
+	if err := os.Chdir(wd); err != nil {
+		t.Fatalf("Failed to restore working directory: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger.GetLogger().SetOutput(&buf)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = server.URL
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.BuildDepend = false
+	cfg.Strict = true
+
+	// The left-out subtree is a scan warning, so --strict fails even without a dependency scan
+	err = NewBuildScanApplication(cfg).runSourceScan()
+	if code := ExitCode(err); code != ExitPolicy || !strings.Contains(err.Error(), "path is too long") {
+		t.Errorf("Expected a policy error for the too long path, got exit code %d: %v", code, err)
+	}
+	if output := buf.String(); !strings.Contains(output, "Scan finished with") || !strings.Contains(output, "out of the fingerprints") {
+		t.Errorf("Expected the too long path in the warning summary, got:\n%s", output)
+	}
+}
+
+func TestBuildScanApplication_runSourceScan_OutputWriteFailure(t *testing.T) {
+	var uploaded bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		uploaded = true
+		_, _ = w.Write([]byte(`{"success": true, "taskId": "task-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	packageJSON := `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "4.17.21"}}`
+	if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+		t.Fatalf("Failed to create package.json: %v", err)
+	}
+	// A regular file where the output directory should be makes --output unwritable
+	blocker := filepath.Join(tempDir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("Failed to create blocker file: %v", err)
+	}
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = server.URL
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.OutputPath = filepath.Join(blocker, "deps.json")
+
+	err := NewBuildScanApplication(cfg).runSourceScan()
+	if code := ExitCode(err); code != ExitConfig || !strings.Contains(err.Error(), "failed to write dependency output") {
+		t.Errorf("Expected a config error for the unwritable output, got exit code %d: %v", code, err)
+	}
+	if uploaded {
+		t.Error("Expected the scan to stop before uploading")
+	}
+}
+
+func TestBuildScanApplication_runSourceScan_SkipUnchangedWfp(t *testing.T) {
+	type upload struct {
+		wfp, build, unchanged bool
+		mode                  string
+	}
+	var uploads []upload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		_, _, wfpErr := r.FormFile("wfpFile")
+		_, _, buildErr := r.FormFile("buildFile")
+		var metadata struct {
+			ScanMode string `json:"scanMode"`
+		}
+		_ = json.Unmarshal([]byte(r.FormValue("metadata")), &metadata)
+		uploads = append(uploads, upload{
+			mode:      metadata.ScanMode,
+			wfp:       wfpErr == nil,
+			build:     buildErr == nil,
+			unchanged: strings.Contains(r.FormValue("metadata"), `"wfpUnchanged":true`),
+		})
+		_, _ = w.Write([]byte(`{"success": true, "taskId": "task-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	files := map[string]string{
+		"main.js":      "console.log('demo')\n",
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "4.17.21"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(taskDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = server.URL
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.SkipUnchangedWfp = true
+
+	// The third run uploads the same fingerprints under another project
+	for run := 1; run <= 3; run++ {
+		if run == 3 {
+			cfg.CustomProject = "other-project"
+		}
+		if err := NewBuildScanApplication(cfg).runSourceScan(); err != nil {
+			t.Fatalf("Run %d failed: %v", run, err)
+		}
+	}
+
+	if len(uploads) != 3 {
+		t.Fatalf("Expected 3 uploads, got %d", len(uploads))
+	}
+	if first := uploads[0]; !first.wfp || !first.build || first.unchanged || first.mode != ScanModeFull {
+		t.Errorf("Expected the first run to upload the WFP and build files, got %+v", first)
+	}
+	if second := uploads[1]; second.wfp || !second.build || !second.unchanged || second.mode != ScanModeDepsOnly {
+		t.Errorf("Expected the unchanged second run to upload only the build file, got %+v", second)
+	}
+	if third := uploads[2]; !third.wfp || third.unchanged || third.mode != ScanModeFull {
+		t.Errorf("Expected the run for another project to upload the WFP file, got %+v", third)
+	}
+}
+
+func TestBuildScanApplication_runSourceScan_GitRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	type upload struct {
+		Commit string `json:"commit"`
+		Dirty  bool   `json:"dirty"`
+	}
+	var uploads []upload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		var metadata upload
+		_ = json.Unmarshal([]byte(r.FormValue("metadata")), &metadata)
+		uploads = append(uploads, metadata)
+		_, _ = w.Write([]byte(`{"success": true, "taskId": "task-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+	git := func(args ...string) string {
+		output, err := exec.Command("git", append([]string{"-C", taskDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q")
+	git("add", "main.go")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial")
+	commit := git("rev-parse", "HEAD")
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = server.URL
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.BuildDepend = false
+
+	scan := func() {
+		t.Helper()
+		if err := NewBuildScanApplication(cfg).runSourceScan(); err != nil {
+			t.Fatalf("runSourceScan failed: %v", err)
+		}
+	}
+	scan()
+	if err := os.WriteFile(filepath.Join(taskDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify main.go: %v", err)
+	}
+	scan()
+	if err := os.RemoveAll(filepath.Join(taskDir, ".git")); err != nil {
+		t.Fatalf("Failed to remove the repository: %v", err)
+	}
+	scan()
+
+	expected := []upload{{commit, false}, {commit, true}, {}}
+	if !slices.Equal(uploads, expected) {
+		t.Errorf("Expected uploads %+v, got %+v", expected, uploads)
+	}
+}
+
+func TestScanMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(cfg *config.ScanConfig)
+		cached       bool
+		wfpUnchanged bool
+		expected     string
+	}{
+		{name: "full scan", setup: func(cfg *config.ScanConfig) {}, expected: ScanModeFull},
+		{name: "first incremental run", setup: func(cfg *config.ScanConfig) { cfg.Incremental = true }, expected: ScanModeFull},
+		{name: "incremental run with cache", setup: func(cfg *config.ScanConfig) { cfg.Incremental = true }, cached: true, expected: ScanModeIncremental},
+		{name: "files from list", setup: func(cfg *config.ScanConfig) { cfg.FilesFrom = "changed.txt" }, expected: ScanModeIncremental},
+		{name: "since", setup: func(cfg *config.ScanConfig) { cfg.Since = "24h" }, expected: ScanModeIncremental},
+		{name: "unchanged fingerprints", setup: func(cfg *config.ScanConfig) { cfg.SkipUnchangedWfp = true }, wfpUnchanged: true, expected: ScanModeDepsOnly},
+		{name: "unchanged incremental run", setup: func(cfg *config.ScanConfig) { cfg.Incremental = true }, cached: true, wfpUnchanged: true, expected: ScanModeDepsOnly},
+		{name: "binary", setup: func(cfg *config.ScanConfig) { cfg.ScanType = "binary"; cfg.Incremental = true }, cached: true, expected: ScanModeBinary},
+		{name: "docker", setup: func(cfg *config.ScanConfig) { cfg.ScanType = "docker" }, expected: ScanModeDocker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewScanConfig()
+			tt.setup(cfg)
+			if got := scanMode(cfg, tt.cached, tt.wfpUnchanged); got != tt.expected {
+				t.Errorf("Expected scan mode %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestBuildScanApplication_runDockerScan_NotImplemented(t *testing.T) {
 	cfg := &config.ScanConfig{
 		TaskDir:   "/tmp/test",
@@ -251,3 +780,64 @@ func BenchmarkBuildScanApplication_calculateDirSize(b *testing.B) {
 		_, _ = app.calculateDirSize(tempDir)
 	}
 }
+
+func BenchmarkBuildScanApplication_calculateDirSize_10kFiles(b *testing.B) {
+	tempDir := b.TempDir()
+
+	// 100 directories of 100 files each
+	content := make([]byte, 100)
+	for d := 0; d < 100; d++ {
+		dir := filepath.Join(tempDir, fmt.Sprintf("dir%d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatalf("Failed to create directory: %v", err)
+		}
+		for f := 0; f < 100; f++ {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.txt", f)), content, 0644); err != nil {
+				b.Fatalf("Failed to create test file: %v", err)
+			}
+		}
+	}
+
+	app := NewBuildScanApplication(&config.ScanConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = app.calculateDirSize(tempDir)
+	}
+}
+
+func TestBuildScanApplication_Run_HTMLReport(t *testing.T) {
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create task directory: %v", err)
+	}
+	files := map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"express": "^4.18.2"}}`,
+		"index.js":     "require('express')\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(taskDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// No server URL or credentials: the report runs offline
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.HTMLReport = filepath.Join(tempDir, "report.html")
+
+	if err := NewBuildScanApplication(cfg).Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cfg.HTMLReport)
+	if err != nil {
+		t.Fatalf("Failed to read HTML report: %v", err)
+	}
+	for _, expected := range []string{"express", "npm", "javascript"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected report to contain %q", expected)
+		}
+	}
+}
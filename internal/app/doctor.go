@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/pkg/buildtools"
+)
+
+// authModes names the authentication types after their --auth-mode
+var authModes = map[config.AuthType]string{
+	config.AuthTypeCookie: config.AuthModeCookie,
+	config.AuthTypeToken:  config.AuthModeToken,
+	config.AuthTypeBasic:  config.AuthModeBasic,
+}
+
+// Doctor writes a diagnosis of the scan environment to w: the build tool executables on PATH
+// and their versions, whether the server is reachable and accepts the credentials, the build
+// files in the task directory and the resolved configuration. Missing executables are only
+// reported, since the scanners fall back to parsing build files; an unreachable server,
+// rejected credentials or an invalid configuration fail the diagnosis.
+func (app *BuildScanApplication) Doctor(w io.Writer) error {
+	// Detection logs the projects it finds, so it runs before the report is written
+	taskDir := app.config.TaskDir
+	var buildFiles []buildtools.BuildFile
+	_, dirErr := os.Stat(taskDir)
+	if dirErr == nil {
+		buildScanner := buildtools.NewBuildScanner(buildtools.NewScannableEnvironment(taskDir, ""), app.config)
+		buildFiles = buildScanner.DetectBuildFiles()
+	}
+
+	var problems int
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "Build tool executables:")
+	for _, status := range buildtools.ProbeExecutables(app.config) {
+		switch {
+		case status.Path == "":
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\tnot found\n", status.Tool, status.Name)
+		case status.Err != nil:
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s (version unknown: %v)\n", status.Tool, status.Name, status.Path, status.Err)
+		default:
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s (%s)\n", status.Tool, status.Name, status.Path, status.Version)
+		}
+	}
+
+	_, _ = fmt.Fprintln(tw, "\nServer:")
+	problems += app.diagnoseServer(tw)
+
+	_, _ = fmt.Fprintf(tw, "\nBuild files in %s:\n", taskDir)
+	switch {
+	case dirErr != nil:
+		_, _ = fmt.Fprintf(tw, "  cannot read directory: %v\n", dirErr)
+		problems++
+	case len(buildFiles) == 0:
+		_, _ = fmt.Fprintln(tw, "  none")
+	}
+	for _, buildFile := range buildFiles {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\n", buildFile.Name, buildFile.Tool)
+	}
+
+	_, _ = fmt.Fprintln(tw, "\nConfiguration:")
+	if err := app.config.Validate(); err != nil {
+		_, _ = fmt.Fprintf(tw, "  invalid: %v\n", err)
+		problems++
+	} else {
+		_, _ = fmt.Fprintln(tw, "  valid")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if err := writeIndentedJSON(w, app.config.Redacted()); err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("doctor found %d problems", problems)
+	}
+	return nil
+}
+
+// diagnoseServer reports whether the server is reachable and the credentials are accepted,
+// returning the number of problems found
+func (app *BuildScanApplication) diagnoseServer(w io.Writer) int {
+	if app.config.ServerURL == "" {
+		_, _ = fmt.Fprintln(w, "  not configured (--server-url)")
+		return 0
+	}
+
+	if err := app.client.HealthCheck(); err != nil {
+		_, _ = fmt.Fprintf(w, "  %s\tunreachable: %v\n", app.config.ServerURL, err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(w, "  %s\treachable\n", app.config.ServerURL)
+
+	if app.config.Username == "" && app.config.Token == "" {
+		_, _ = fmt.Fprintln(w, "  authentication\tno credentials (--token or --username)")
+		return 0
+	}
+	err := app.verifyAuth()
+	mode := authModes[app.config.AuthType]
+	switch {
+	case err != nil:
+		_, _ = fmt.Fprintf(w, "  authentication (%s)\tfailed: %v\n", mode, err)
+		return 1
+	case app.config.AuthType == config.AuthTypeBasic:
+		_, _ = fmt.Fprintf(w, "  authentication (%s)\tnot verified, credentials are sent with each request\n", mode)
+	default:
+		_, _ = fmt.Fprintf(w, "  authentication (%s)\tok\n", mode)
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestBuildScanApplication_Doctor(t *testing.T) {
+	// No build tool can be found on an empty PATH
+	t.Setenv("PATH", t.TempDir())
+
+	taskDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(`{"name": "fixture", "version": "1.0.0"}`), 0644); err != nil {
+		t.Fatalf("Failed to write package.json: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		loginStatus int
+		expected    []string
+		expectedErr string
+	}{
+		{"healthy", http.StatusOK, []string{`authentication \(cookie\)\s+ok`, `(?m)^  valid$`}, ""},
+		{"invalid credentials", http.StatusUnauthorized, []string{`authentication \(cookie\)\s+failed`}, "doctor found 1 problems"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubServer(t, http.StatusOK, tt.loginStatus)
+			cfg := &config.ScanConfig{
+				TaskDir:           taskDir,
+				ServerURL:         server.URL,
+				AllowInsecureHTTP: true,
+				Username:          "testuser",
+				Password:          "testpass",
+				ScanType:          "source",
+				ThreadNum:         "4",
+			}
+
+			var out bytes.Buffer
+			err := NewBuildScanApplication(cfg).Doctor(&out)
+			if tt.expectedErr == "" && err != nil {
+				t.Fatalf("Doctor failed: %v\n%s", err, out.String())
+			}
+			if tt.expectedErr != "" && (err == nil || err.Error() != tt.expectedErr) {
+				t.Fatalf("Expected error %q, got: %v", tt.expectedErr, err)
+			}
+
+			expected := append([]string{
+				`npm\s+npm\s+not found`,
+				`go\s+go\s+not found`,
+				`package\.json\s+npm`,
+				regexp.QuoteMeta(server.URL) + `\s+reachable`,
+			}, tt.expected...)
+			for _, pattern := range expected {
+				if !regexp.MustCompile(pattern).MatchString(out.String()) {
+					t.Errorf("Expected output to match %q, got:\n%s", pattern, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "testpass") {
+				t.Errorf("Expected the password to be masked, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestBuildScanApplication_Doctor_NoServer(t *testing.T) {
+	cfg := &config.ScanConfig{TaskDir: filepath.Join(t.TempDir(), "missing")}
+
+	var out bytes.Buffer
+	err := NewBuildScanApplication(cfg).Doctor(&out)
+	if err == nil || err.Error() != "doctor found 2 problems" {
+		t.Errorf("Expected the missing directory and server URL to be reported, got: %v", err)
+	}
+	for _, expected := range []string{"not configured (--server-url)", "cannot read directory", "invalid: server URL is required"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import "errors"
+
+// Exit codes form a stable contract for CI systems; do not renumber them
+const (
+	ExitSuccess = 0 // Scan completed successfully
+	ExitError   = 1 // Generic or unclassified error
+	ExitConfig  = 2 // Invalid configuration, flags or arguments
+	ExitAuth    = 3 // Authentication with the server failed
+	ExitUpload  = 4 // Uploading scan data to the server failed
+	ExitPolicy  = 5 // A policy or threshold was violated
+)
+
+// ScanError is an error carrying the exit code of its failure class
+type ScanError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the wrapped error
+func (e *ScanError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// NewConfigError wraps a configuration or validation error (exit code 2)
+func NewConfigError(err error) error {
+	return &ScanError{Code: ExitConfig, Err: err}
+}
+
+// NewAuthError wraps an authentication error (exit code 3)
+func NewAuthError(err error) error {
+	return &ScanError{Code: ExitAuth, Err: err}
+}
+
+// NewUploadError wraps an upload error (exit code 4)
+func NewUploadError(err error) error {
+	return &ScanError{Code: ExitUpload, Err: err}
+}
+
+// NewPolicyError wraps a policy or threshold violation (exit code 5)
+func NewPolicyError(err error) error {
+	return &ScanError{Code: ExitPolicy, Err: err}
+}
+
+// ExitCode returns the process exit code for an error returned by Run
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	var scanErr *ScanError
+	if errors.As(err, &scanErr) {
+		return scanErr.Code
+	}
+	return ExitError
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, ExitSuccess},
+		{"generic", errors.New("boom"), ExitError},
+		{"config", NewConfigError(config.ErrMissingTaskDir), ExitConfig},
+		{"auth", NewAuthError(errors.New("bad token")), ExitAuth},
+		{"upload", NewUploadError(errors.New("503")), ExitUpload},
+		{"policy", NewPolicyError(errors.New("stale lockfile")), ExitPolicy},
+		{"wrapped", fmt.Errorf("scan failed: %w", NewPolicyError(errors.New("threshold"))), ExitPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.expected {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+// newExitCodeServer starts a server answering login and upload with the given status codes
+func newExitCodeServer(t *testing.T, loginStatus, uploadStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(loginStatus)
+	})
+	mux.HandleFunc("/api/scan/upload", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(uploadStatus)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newExitCodeConfig returns a valid source scan config for a directory with one file
+func newExitCodeConfig(t *testing.T, serverURL string) *config.ScanConfig {
+	t.Helper()
+	tempDir := t.TempDir()
+	taskDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("Failed to create task directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	cfg := config.NewScanConfig()
+	cfg.TaskDir = taskDir
+	cfg.ToPath = tempDir
+	cfg.ServerURL = serverURL
+	cfg.AllowInsecureHTTP = true // httptest servers speak plain http
+	cfg.Username = "testuser"
+	cfg.Password = "testpass"
+	cfg.BuildDepend = false
+	cfg.RetryCount = 0
+	return cfg
+}
+
+func TestBuildScanApplication_Run_ExitCodes(t *testing.T) {
+	t.Run("validation error", func(t *testing.T) {
+		err := NewBuildScanApplication(&config.ScanConfig{}).Run()
+		if code := ExitCode(err); code != ExitConfig {
+			t.Errorf("Expected exit code %d for a validation error, got %d (%v)", ExitConfig, code, err)
+		}
+	})
+
+	t.Run("auth error", func(t *testing.T) {
+		server := newExitCodeServer(t, http.StatusUnauthorized, http.StatusOK)
+		err := NewBuildScanApplication(newExitCodeConfig(t, server.URL)).Run()
+		if code := ExitCode(err); code != ExitAuth {
+			t.Errorf("Expected exit code %d for an auth error, got %d (%v)", ExitAuth, code, err)
+		}
+	})
+
+	t.Run("upload error", func(t *testing.T) {
+		server := newExitCodeServer(t, http.StatusOK, http.StatusInternalServerError)
+		err := NewBuildScanApplication(newExitCodeConfig(t, server.URL)).Run()
+		if code := ExitCode(err); code != ExitUpload {
+			t.Errorf("Expected exit code %d for an upload error, got %d (%v)", ExitUpload, code, err)
+		}
+	})
+
+	t.Run("stale lockfile policy", func(t *testing.T) {
+		server := newExitCodeServer(t, http.StatusOK, http.StatusOK)
+		cfg := newExitCodeConfig(t, server.URL)
+		cfg.BuildDepend = true
+		cfg.FailOn = []string{config.FailOnStaleLockfile}
+		files := map[string]string{
+			"package.json":      `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}}`,
+			"package-lock.json": `{"lockfileVersion": 3, "packages": {"": {"name": "demo"}}}`,
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(cfg.TaskDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create %s: %v", name, err)
+			}
+		}
+
+		err := NewBuildScanApplication(cfg).Run()
+		if code := ExitCode(err); code != ExitPolicy {
+			t.Errorf("Expected exit code %d for a stale lockfile, got %d (%v)", ExitPolicy, code, err)
+		}
+	})
+
+	t.Run("strict warnings", func(t *testing.T) {
+		// A broken lockfile falls back to declared versions with a warning, which only --strict rejects
+		for _, strict := range []bool{false, true} {
+			server := newExitCodeServer(t, http.StatusOK, http.StatusOK)
+			cfg := newExitCodeConfig(t, server.URL)
+			cfg.BuildDepend = true
+			cfg.Strict = strict
+			files := map[string]string{
+				"package.json":      `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}}`,
+				"package-lock.json": `{"lockfileVersion": 3, "packages": {`,
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(cfg.TaskDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to create %s: %v", name, err)
+				}
+			}
+
+			want := ExitSuccess
+			if strict {
+				want = ExitPolicy
+			}
+			err := NewBuildScanApplication(cfg).Run()
+			if code := ExitCode(err); code != want {
+				t.Errorf("Expected exit code %d with strict=%v, got %d (%v)", want, strict, code, err)
+			}
+		}
+	})
+
+	t.Run("strict skipped scanner", func(t *testing.T) {
+		server := newExitCodeServer(t, http.StatusOK, http.StatusOK)
+		cfg := newExitCodeConfig(t, server.URL)
+		cfg.BuildDepend = true
+		cfg.Strict = true
+		cfg.ExternalScanner = "no-such-scanner"
+
+		err := NewBuildScanApplication(cfg).Run()
+		if code := ExitCode(err); code != ExitPolicy || !strings.Contains(err.Error(), "Scanner skipped") {
+			t.Errorf("Expected exit code %d for a skipped scanner, got %d (%v)", ExitPolicy, code, err)
+		}
+	})
+
+	t.Run("strict unknown version", func(t *testing.T) {
+		for _, strict := range []bool{false, true} {
+			server := newExitCodeServer(t, http.StatusOK, http.StatusOK)
+			cfg := newExitCodeConfig(t, server.URL)
+			cfg.BuildDepend = true
+			cfg.Strict = strict
+			// A git dependency has no version, which no scanner warns about
+			content := "[package]\nname = \"demo\"\nversion = \"0.1.0\"\n\n[dependencies]\nlocal-lib = { git = \"https://example.com/local-lib.git\" }\n"
+			if err := os.WriteFile(filepath.Join(cfg.TaskDir, "Cargo.toml"), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create Cargo.toml: %v", err)
+			}
+
+			want := ExitSuccess
+			if strict {
+				want = ExitPolicy
+			}
+			err := NewBuildScanApplication(cfg).Run()
+			if code := ExitCode(err); code != want {
+				t.Errorf("Expected exit code %d with strict=%v, got %d (%v)", want, strict, code, err)
+			}
+			if strict && (err == nil || !strings.Contains(err.Error(), "unknown version")) {
+				t.Errorf("Expected an unknown version violation, got %v", err)
+			}
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		server := newExitCodeServer(t, http.StatusOK, http.StatusOK)
+		err := NewBuildScanApplication(newExitCodeConfig(t, server.URL)).Run()
+		if code := ExitCode(err); code != ExitSuccess {
+			t.Errorf("Expected exit code %d for a successful scan, got %d (%v)", ExitSuccess, code, err)
+		}
+	})
+}
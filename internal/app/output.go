@@ -0,0 +1,260 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/sbom"
+)
+
+// dependencySerializer writes dependency roots in one output format
+type dependencySerializer func(w io.Writer, roots []model.DependencyRoot) error
+
+// dependencySerializers maps output formats to their serializers
+var dependencySerializers = map[string]dependencySerializer{
+	config.FormatJSON:      writeJSON,
+	config.FormatCycloneDX: writeCycloneDX,
+	config.FormatSPDX:      writeSPDX,
+	config.FormatCSV:       writeCSV,
+	config.FormatDOT:       writeDOT,
+	config.FormatJSONL:     writeJSONL,
+}
+
+// writeOutput writes dependency roots to the configured output file in the configured format
+func (app *BuildScanApplication) writeOutput(roots []model.DependencyRoot) error {
+	format := app.config.Format
+	if format == "" {
+		format = config.FormatJSON
+	}
+
+	if err := os.MkdirAll(filepath.Dir(app.config.OutputPath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(app.config.OutputPath)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	if err := writeDependencies(file, roots, format); err != nil {
+		return err
+	}
+
+	app.log.Infof("Dependency output (%s) written to: %s", format, app.config.OutputPath)
+	return nil
+}
+
+// writeDependenciesPerTool writes the dependency roots of each build tool as JSON to their own
+// file in the output directory, keeping the order of roots
+func (app *BuildScanApplication) writeDependenciesPerTool(roots []model.DependencyRoot) error {
+	var tools []string
+	rootsByTool := make(map[string][]model.DependencyRoot)
+	for _, root := range roots {
+		if _, ok := rootsByTool[root.BuildTool]; !ok {
+			tools = append(tools, root.BuildTool)
+		}
+		rootsByTool[root.BuildTool] = append(rootsByTool[root.BuildTool], root)
+	}
+
+	for _, tool := range tools {
+		data, err := json.MarshalIndent(rootsByTool[tool], "", "  ")
+		if err != nil {
+			return err
+		}
+		path := app.config.GetToolDepsPath(tool)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		app.log.Infof("Dependencies of %s written to: %s", tool, path)
+	}
+	return nil
+}
+
+// writeDependencies writes dependency roots to w using the serializer for format, in the
+// deterministic order of model.SortDependencies
+func writeDependencies(w io.Writer, roots []model.DependencyRoot, format string) error {
+	serializer, ok := dependencySerializers[format]
+	if !ok {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+	model.SortDependencies(roots)
+	return serializer(w, roots)
+}
+
+// writeIndentedJSON writes v as indented JSON
+func writeIndentedJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeJSON writes the dependency roots as JSON, as uploaded to the server
+func writeJSON(w io.Writer, roots []model.DependencyRoot) error {
+	return writeIndentedJSON(w, roots)
+}
+
+// writeCycloneDX writes the dependency roots as a CycloneDX JSON BOM
+func writeCycloneDX(w io.Writer, roots []model.DependencyRoot) error {
+	return writeIndentedJSON(w, sbom.ToCycloneDX(roots))
+}
+
+// writeSPDX writes the dependency roots as an SPDX JSON document
+func writeSPDX(w io.Writer, roots []model.DependencyRoot) error {
+	return writeIndentedJSON(w, sbom.ToSPDX(roots))
+}
+
+// writeCSV writes one row per dependency, with its depth in the tree (1 = direct)
+func writeCSV(w io.Writer, roots []model.DependencyRoot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"build_tool", "project_name", "project_version", "group", "name", "version", "type", "scope", "depth"}); err != nil {
+		return err
+	}
+
+	var writeRows func(root model.DependencyRoot, dependencies []model.Dependency, depth int) error
+	writeRows = func(root model.DependencyRoot, dependencies []model.Dependency, depth int) error {
+		for _, dep := range dependencies {
+			record := []string{
+				root.BuildTool,
+				root.ProjectName,
+				root.ProjectVersion,
+				dependencyGroup(dep),
+				dep.Name,
+				dep.Version,
+				dep.Type,
+				dep.Scope,
+				strconv.Itoa(depth),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			if err := writeRows(root, dep.Children, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := writeRows(root, root.Dependencies, 1); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// dependencyLine is a dependency in JSON-lines output, flattened with its root context
+type dependencyLine struct {
+	BuildTool      string `json:"buildTool"`
+	ProjectName    string `json:"projectName"`
+	ProjectVersion string `json:"projectVersion"`
+	Group          string `json:"group,omitempty"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Type           string `json:"type"`
+	Classifier     string `json:"classifier,omitempty"`
+	Scope          string `json:"scope,omitempty"`
+	RawScope       string `json:"rawScope,omitempty"`
+	Depth          int    `json:"depth"` // 1 = direct
+}
+
+// writeJSONL writes one JSON object per dependency and line, encoding each as the tree is
+// walked so nothing but the current line is buffered
+func writeJSONL(w io.Writer, roots []model.DependencyRoot) error {
+	encoder := json.NewEncoder(w)
+
+	var writeLines func(root model.DependencyRoot, dependencies []model.Dependency, depth int) error
+	writeLines = func(root model.DependencyRoot, dependencies []model.Dependency, depth int) error {
+		for _, dep := range dependencies {
+			line := dependencyLine{
+				BuildTool:      root.BuildTool,
+				ProjectName:    root.ProjectName,
+				ProjectVersion: root.ProjectVersion,
+				Group:          dependencyGroup(dep),
+				Name:           dep.Name,
+				Version:        dep.Version,
+				Type:           dep.Type,
+				Classifier:     dep.Classifier,
+				Scope:          dep.Scope,
+				RawScope:       dep.RawScope,
+				Depth:          depth,
+			}
+			if err := encoder.Encode(line); err != nil {
+				return err
+			}
+			if err := writeLines(root, dep.Children, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := writeLines(root, root.Dependencies, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDOT writes the dependency graph in Graphviz DOT format
+func writeDOT(w io.Writer, roots []model.DependencyRoot) error {
+	if _, err := fmt.Fprintln(w, "digraph dependencies {"); err != nil {
+		return err
+	}
+
+	edges := make(map[string]bool)
+	var writeEdges func(parent string, dependencies []model.Dependency) error
+	writeEdges = func(parent string, dependencies []model.Dependency) error {
+		for _, dep := range dependencies {
+			node := dep.Name + "@" + dep.Version
+			if group := dependencyGroup(dep); group != "" {
+				node = group + ":" + node
+			}
+			edge := fmt.Sprintf("  %s -> %s;", strconv.Quote(parent), strconv.Quote(node))
+			if edges[edge] {
+				continue
+			}
+			edges[edge] = true
+			if _, err := fmt.Fprintln(w, edge); err != nil {
+				return err
+			}
+			if err := writeEdges(node, dep.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		node := root.ProjectName + "@" + root.ProjectVersion
+		if _, err := fmt.Fprintf(w, "  %s [shape=box];\n", strconv.Quote(node)); err != nil {
+			return err
+		}
+		if err := writeEdges(node, root.Dependencies); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// dependencyGroup returns the group of a dependency, if any
+func dependencyGroup(dep model.Dependency) string {
+	if dep.ID != nil && dep.ID.Group != "" {
+		return dep.ID.Group
+	}
+	return dep.GroupID
+}
@@ -0,0 +1,414 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/sbom"
+	"github.com/craftslab/cleansource-sca-cli/pkg/buildtools"
+)
+
+// outputFixture returns dependency roots covering nested and grouped dependencies
+func outputFixture() []model.DependencyRoot {
+	return []model.DependencyRoot{
+		{
+			ProjectName:    "web-app",
+			ProjectVersion: "1.0.0",
+			BuildTool:      "npm",
+			Dependencies: []model.Dependency{
+				{
+					Name:    "express",
+					Version: "4.18.2",
+					Type:    "npm",
+					Scope:   "runtime",
+					Children: []model.Dependency{
+						{Name: "body-parser", Version: "1.20.1", Type: "npm", Scope: "runtime"},
+					},
+				},
+				{Name: "@types/node", Version: "20.1.0", Type: "npm", Scope: "development"},
+			},
+		},
+		{
+			ProjectName:    "service",
+			ProjectVersion: "2.0.0",
+			BuildTool:      "maven",
+			Dependencies: []model.Dependency{
+				{
+					ID:      &model.DependencyID{Group: "junit", Name: "junit", Version: "4.13.2", Type: "jar"},
+					Name:    "junit",
+					Version: "4.13.2",
+					Type:    "jar",
+					Scope:   "test",
+				},
+			},
+		},
+	}
+}
+
+func TestWriteDependencies_AllFormats(t *testing.T) {
+	for _, format := range config.OutputFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeDependencies(&buf, outputFixture(), format); err != nil {
+				t.Fatalf("writeDependencies(%s) failed: %v", format, err)
+			}
+			if buf.Len() == 0 {
+				t.Fatalf("Expected non-empty %s output", format)
+			}
+
+			switch format {
+			case config.FormatJSON:
+				var roots []model.DependencyRoot
+				if err := json.Unmarshal(buf.Bytes(), &roots); err != nil || len(roots) != 2 {
+					t.Errorf("Expected 2 parseable roots, got %d (%v)", len(roots), err)
+				}
+			case config.FormatCycloneDX:
+				var bom sbom.CycloneDXBOM
+				if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+					t.Fatalf("Failed to parse CycloneDX output: %v", err)
+				}
+				if bom.BOMFormat != "CycloneDX" || len(bom.Components) != 6 {
+					t.Errorf("Expected CycloneDX BOM with 6 components, got %s with %d", bom.BOMFormat, len(bom.Components))
+				}
+			case config.FormatSPDX:
+				var doc sbom.SPDXDocument
+				if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+					t.Fatalf("Failed to parse SPDX output: %v", err)
+				}
+				if doc.SPDXVersion != sbom.SPDXVersion || len(doc.Packages) != 6 {
+					t.Errorf("Expected SPDX document with 6 packages, got %s with %d", doc.SPDXVersion, len(doc.Packages))
+				}
+			case config.FormatCSV:
+				records, err := csv.NewReader(&buf).ReadAll()
+				if err != nil {
+					t.Fatalf("Failed to parse CSV output: %v", err)
+				}
+				if len(records) != 5 {
+					t.Errorf("Expected header plus 4 dependency rows, got %d rows", len(records))
+				}
+			case config.FormatDOT:
+				output := strings.TrimSpace(buf.String())
+				if !strings.HasPrefix(output, "digraph dependencies {") || !strings.HasSuffix(output, "}") {
+					t.Errorf("Expected a DOT digraph, got: %s", output)
+				}
+				if !strings.Contains(output, `"express@4.18.2" -> "body-parser@1.20.1";`) {
+					t.Errorf("Expected nested dependency edge in DOT output, got: %s", output)
+				}
+			case config.FormatJSONL:
+				lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+				if len(lines) != 4 {
+					t.Fatalf("Expected one line per dependency (4), got %d", len(lines))
+				}
+				for _, line := range lines {
+					var dep dependencyLine
+					if err := json.Unmarshal([]byte(line), &dep); err != nil {
+						t.Errorf("Expected each line to be valid JSON, got %q: %v", line, err)
+					}
+				}
+				// Output is sorted, so @types/node precedes express and its child
+				var nested dependencyLine
+				if err := json.Unmarshal([]byte(lines[2]), &nested); err != nil || nested.Name != "body-parser" || nested.Depth != 2 || nested.ProjectName != "web-app" {
+					t.Errorf("Expected body-parser at depth 2 under web-app, got %+v", nested)
+				}
+			default:
+				t.Errorf("No parser check for format %s", format)
+			}
+		})
+	}
+}
+
+func TestWriteDependencies_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeDependencies(&buf, outputFixture(), "xml")
+	if err == nil || err.Error() != "unsupported output format: xml" {
+		t.Errorf("Expected unsupported format error, got: %v", err)
+	}
+}
+
+func TestBuildScanApplication_writeOutput(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "reports", "bom.json")
+	cfg := &config.ScanConfig{Format: config.FormatCycloneDX, OutputPath: outputPath}
+	app := NewBuildScanApplication(cfg)
+
+	if err := app.writeOutput(outputFixture()); err != nil {
+		t.Fatalf("writeOutput failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(data), `"bomFormat": "CycloneDX"`) {
+		t.Errorf("Expected CycloneDX output, got: %s", data)
+	}
+}
+
+func TestBuildScanApplication_buildDependencyInfo_Deterministic(t *testing.T) {
+	taskDir := t.TempDir()
+	packageJSON := `{"name": "demo", "version": "1.0.0", "dependencies": {
+		"react": "^18.2.0", "axios": "^1.4.0", "lodash": "^4.17.21", "zod": "^3.21.4",
+		"express": "^4.18.2", "chalk": "^5.3.0", "uuid": "^9.0.0", "dayjs": "^1.11.9"
+	}, "devDependencies": {"jest": "^29.6.1", "eslint": "^8.45.0", "typescript": "^5.1.6"}}`
+	if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+		t.Fatalf("Failed to create package.json: %v", err)
+	}
+
+	var outputs [][]byte
+	for run := 0; run < 2; run++ {
+		cfg := &config.ScanConfig{TaskDir: taskDir, ToPath: t.TempDir()}
+		app := NewBuildScanApplication(cfg)
+
+		buildFile, _, _, err := app.buildDependencyInfo(buildtools.NewScannableEnvironment(taskDir, ""))
+		if err != nil {
+			t.Fatalf("buildDependencyInfo failed: %v", err)
+		}
+		data, err := os.ReadFile(buildFile)
+		if err != nil {
+			t.Fatalf("Failed to read dependency file: %v", err)
+		}
+		outputs = append(outputs, data)
+	}
+
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Errorf("Expected byte-identical dependency JSON across runs\nfirst:  %s\nsecond: %s", outputs[0], outputs[1])
+	}
+	if axios, zod := bytes.Index(outputs[0], []byte(`"axios"`)), bytes.Index(outputs[0], []byte(`"zod"`)); axios < 0 || zod < axios {
+		t.Errorf("Expected dependencies sorted by name, got: %s", outputs[0])
+	}
+	if err := model.ValidateDependencyOutput(outputs[0]); err != nil {
+		t.Errorf("Expected the dependency JSON to match the schema: %v", err)
+	}
+}
+
+func TestBuildScanApplication_buildDependencyInfo_RecordsWarnings(t *testing.T) {
+	taskDir := t.TempDir()
+	files := map[string]string{
+		"package.json":      `{"name": "demo", "version": "1.0.0", "dependencies": {"express": "^4.18.2"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(taskDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	app := NewBuildScanApplication(&config.ScanConfig{TaskDir: taskDir, ToPath: t.TempDir()})
+	buildFile, _, _, err := app.buildDependencyInfo(buildtools.NewScannableEnvironment(taskDir, ""))
+	if err != nil {
+		t.Fatalf("buildDependencyInfo failed: %v", err)
+	}
+	data, err := os.ReadFile(buildFile)
+	if err != nil {
+		t.Fatalf("Failed to read dependency file: %v", err)
+	}
+
+	// The broken lockfile falls back to declared versions, which must be visible in the output
+	var roots []model.DependencyRoot
+	if err := json.Unmarshal(data, &roots); err != nil || len(roots) != 1 {
+		t.Fatalf("Expected one parseable root, got %d (%v)", len(roots), err)
+	}
+	if len(roots[0].Warnings) != 1 || !strings.Contains(roots[0].Warnings[0], "Failed to parse package-lock.json, using declared versions") {
+		t.Errorf("Expected the lockfile fallback to be recorded as a warning, got %v", roots[0].Warnings)
+	}
+}
+
+func TestBuildScanApplication_buildDependencyInfo_SBOMInputProcessed(t *testing.T) {
+	taskDir := t.TempDir()
+	bom := `{
+	"bomFormat": "CycloneDX",
+	"specVersion": "1.5",
+	"version": 1,
+	"components": [
+		{"type": "application", "bom-ref": "app", "name": "app", "version": "1.0.0"},
+		{"type": "library", "bom-ref": "pkg:npm/express@4.18.2", "name": "express", "version": "4.18.2", "purl": "pkg:npm/express@4.18.2", "scope": "required"},
+		{"type": "library", "bom-ref": "pkg:npm/accepts@1.3.8", "name": "accepts", "version": "1.3.8", "purl": "pkg:npm/accepts@1.3.8", "scope": "required"},
+		{"type": "library", "bom-ref": "pkg:npm/jest@29.6.1", "name": "jest", "version": "29.6.1", "purl": "pkg:npm/jest@29.6.1", "scope": "excluded"}
+	],
+	"dependencies": [
+		{"ref": "app", "dependsOn": ["pkg:npm/express@4.18.2", "pkg:npm/jest@29.6.1"]},
+		{"ref": "pkg:npm/express@4.18.2", "dependsOn": ["pkg:npm/accepts@1.3.8"]}
+	]
+}`
+	files := map[string]string{
+		"bom.json":     bom,
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "4.17.21"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(taskDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	logger.GetLogger().SetOutput(&buf)
+	defer logger.GetLogger().SetOutput(os.Stdout)
+
+	depth := 0
+	cfg := &config.ScanConfig{
+		TaskDir:         taskDir,
+		ToPath:          t.TempDir(),
+		SBOMInput:       filepath.Join(taskDir, "bom.json"),
+		ExcludeScopes:   []string{"test"},
+		DependencyDepth: &depth,
+	}
+	_, roots, _, err := NewBuildScanApplication(cfg).buildDependencyInfo(buildtools.NewScannableEnvironment(taskDir, ""))
+	if err != nil {
+		t.Fatalf("buildDependencyInfo failed: %v", err)
+	}
+
+	// jest is dropped by --exclude-scope and accepts by --dependency-depth
+	if len(roots) != 1 || len(roots[0].Dependencies) != 1 {
+		t.Fatalf("Expected one root with one dependency, got %+v", roots)
+	}
+	if express := roots[0].Dependencies[0]; express.Name != "express" || len(express.Children) != 0 {
+		t.Errorf("Expected express without its transitive dependencies, got %+v", express)
+	}
+	if strings.Contains(buf.String(), "Detected") {
+		t.Errorf("Expected no build tool detection for SBOM input, got:\n%s", buf.String())
+	}
+}
+
+func TestBuildScanApplication_writeDependenciesPerTool(t *testing.T) {
+	toPath := t.TempDir()
+	roots := []model.DependencyRoot{
+		{ProjectName: "backend", ProjectVersion: "1.0.0", BuildTool: "maven", Dependencies: []model.Dependency{{Name: "guava", Version: "33.0.0-jre", Type: "jar"}}},
+		{ProjectName: "frontend", ProjectVersion: "2.0.0", BuildTool: "npm", Dependencies: []model.Dependency{{Name: "lodash", Version: "4.17.21", Type: "npm"}}},
+		{ProjectName: "admin", ProjectVersion: "2.0.0", BuildTool: "npm", Dependencies: []model.Dependency{{Name: "react", Version: "18.2.0", Type: "npm"}}},
+	}
+
+	app := NewBuildScanApplication(&config.ScanConfig{ToPath: toPath})
+	if err := app.writeDependenciesPerTool(roots); err != nil {
+		t.Fatalf("writeDependenciesPerTool failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"dependencies.maven.json": {"backend"},
+		"dependencies.npm.json":   {"frontend", "admin"},
+	}
+	for name, projects := range expected {
+		data, err := os.ReadFile(filepath.Join(toPath, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		var written []model.DependencyRoot
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		var got []string
+		for _, root := range written {
+			got = append(got, root.ProjectName)
+		}
+		if !slices.Equal(got, projects) {
+			t.Errorf("Expected %s to hold %v, got %v", name, projects, got)
+		}
+	}
+	if entries, _ := os.ReadDir(toPath); len(entries) != 2 {
+		t.Errorf("Expected exactly two per-tool files, got %d", len(entries))
+	}
+}
+
+func TestBuildScanApplication_buildDependencyInfo_SplitOnly(t *testing.T) {
+	taskDir := t.TempDir()
+	packageJSON := `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "4.17.21"}}`
+	if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+		t.Fatalf("Failed to create package.json: %v", err)
+	}
+
+	toPath := t.TempDir()
+	app := NewBuildScanApplication(&config.ScanConfig{TaskDir: taskDir, ToPath: toPath, DependencyOutputPerTool: true, SplitOnly: true})
+	buildFile, dependencies, _, err := app.buildDependencyInfo(buildtools.NewScannableEnvironment(taskDir, ""))
+	if err != nil {
+		t.Fatalf("buildDependencyInfo failed: %v", err)
+	}
+	if buildFile != "" || len(dependencies) != 1 {
+		t.Errorf("Expected no combined file and one root, got %q and %d roots", buildFile, len(dependencies))
+	}
+	if _, err := os.Stat(filepath.Join(toPath, config.DefaultDepsName)); !os.IsNotExist(err) {
+		t.Errorf("Expected no combined dependency file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(toPath, "dependencies.npm.json")); err != nil {
+		t.Errorf("Expected the npm dependency file, got %v", err)
+	}
+}
+
+func TestBuildScanApplication_OutputNames(t *testing.T) {
+	toPath := t.TempDir()
+	for _, project := range []string{"frontend", "backend"} {
+		taskDir := t.TempDir()
+		packageJSON := `{"name": "` + project + `", "version": "1.0.0", "dependencies": {"lodash": "4.17.21"}}`
+		if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+			t.Fatalf("Failed to create package.json: %v", err)
+		}
+
+		cfg := &config.ScanConfig{TaskDir: taskDir, ToPath: toPath, WfpName: project + " scan.wfp", DepsName: project + "/deps.json"}
+		app := NewBuildScanApplication(cfg)
+		env := buildtools.NewScannableEnvironment(taskDir, "")
+
+		wfpFile, _, err := app.generateWfpFile(env)
+		if err != nil {
+			t.Fatalf("generateWfpFile failed: %v", err)
+		}
+		buildFile, _, _, err := app.buildDependencyInfo(env)
+		if err != nil {
+			t.Fatalf("buildDependencyInfo failed: %v", err)
+		}
+
+		// Names are sanitized, so path separators and spaces cannot escape or clash
+		if want := filepath.Join(toPath, project+"_scan.wfp"); wfpFile != want {
+			t.Errorf("Expected fingerprint file %s, got %s", want, wfpFile)
+		}
+		if want := filepath.Join(toPath, project+"_deps.json"); buildFile != want {
+			t.Errorf("Expected dependency file %s, got %s", want, buildFile)
+		}
+	}
+
+	for _, project := range []string{"frontend", "backend"} {
+		data, err := os.ReadFile(filepath.Join(toPath, project+"_deps.json"))
+		if err != nil {
+			t.Fatalf("Expected the %s dependency file to survive the other scan: %v", project, err)
+		}
+		if !strings.Contains(string(data), `"projectName": "`+project+`"`) {
+			t.Errorf("Expected the %s dependency file to hold its own project, got %s", project, data)
+		}
+		if _, err := os.Stat(filepath.Join(toPath, project+"_scan.wfp")); err != nil {
+			t.Errorf("Expected the %s fingerprint file to survive the other scan: %v", project, err)
+		}
+	}
+}
+
+func TestBuildScanApplication_Run_ProjectsOnly(t *testing.T) {
+	taskDir := t.TempDir()
+	packageJSON := `{"name": "demo", "version": "1.0.0", "license": "MIT", "dependencies": {"express": "^4.18.2"}}`
+	if err := os.WriteFile(filepath.Join(taskDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+		t.Fatalf("Failed to create package.json: %v", err)
+	}
+
+	// No server settings are needed, nothing is uploaded
+	outputPath := filepath.Join(t.TempDir(), "out", "projects.json")
+	app := NewBuildScanApplication(&config.ScanConfig{TaskDir: taskDir, ProjectsOnly: true, OutputPath: outputPath})
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read project list: %v", err)
+	}
+	var projects []model.ProjectInfo
+	if err := json.Unmarshal(data, &projects); err != nil {
+		t.Fatalf("Expected a JSON project list, got %s: %v", data, err)
+	}
+	want := model.ProjectInfo{Name: "demo", Version: "1.0.0", License: "MIT", BuildTool: "npm"}
+	if len(projects) != 1 || projects[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, projects)
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/pkg/buildtools"
+)
+
+// runProjectsOnly writes the projects detected in the task directory to the output file as
+// JSON, without resolving dependencies or contacting the server
+func (app *BuildScanApplication) runProjectsOnly() error {
+	taskDir := app.config.TaskDir
+	if _, err := os.Stat(taskDir); os.IsNotExist(err) {
+		return fmt.Errorf("scan directory does not exist: %s", taskDir)
+	}
+
+	buildScanner := buildtools.NewBuildScanner(buildtools.NewScannableEnvironment(taskDir, ""), app.config)
+	projects, err := buildScanner.DetectProjects()
+	if err != nil {
+		return fmt.Errorf("failed to detect projects: %w", err)
+	}
+	if projects == nil {
+		projects = []model.ProjectInfo{} // An empty list rather than null
+	}
+
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(app.config.OutputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(app.config.OutputPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	app.log.Infof("%d projects written to: %s", len(projects), app.config.OutputPath)
+	return nil
+}
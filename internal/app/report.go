@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/report"
+	"github.com/craftslab/cleansource-sca-cli/internal/scanner"
+	"github.com/craftslab/cleansource-sca-cli/pkg/buildtools"
+)
+
+// runLocalReport scans the task directory without contacting the server and writes an HTML
+// summary of the detected tools, files, dependencies and warnings
+func (app *BuildScanApplication) runLocalReport() error {
+	taskDir := app.config.TaskDir
+	if _, err := os.Stat(taskDir); os.IsNotExist(err) {
+		return fmt.Errorf("scan directory does not exist: %s", taskDir)
+	}
+
+	revision := app.detectRevision(taskDir)
+	app.applyVCSDefaults(revision)
+	buildScanner := buildtools.NewBuildScanner(buildtools.NewScannableEnvironment(taskDir, ""), app.config)
+	tools := buildScanner.DetectBuildTools()
+	slices.Sort(tools)
+
+	data := &report.Data{
+		ProjectName: app.config.CustomProject,
+		TaskDir:     taskDir,
+		GeneratedAt: time.Now(),
+		Tools:       slices.Compact(tools),
+	}
+	if revision != nil {
+		data.Commit = revision.Commit
+		data.Dirty = revision.Dirty
+	}
+
+	var scanWarnings []string
+	var dependencyErr error
+	if app.config.BuildDepend || app.config.SBOMInput != "" {
+		app.log.Info("Building dependency information...")
+		var dependencies []model.DependencyRoot
+		dependencies, scanWarnings, dependencyErr = app.scanDependencies(buildScanner)
+		if dependencyErr != nil {
+			app.log.Warnf("Failed to build dependency information: %v", dependencyErr)
+		}
+		data.Dependencies = dependencies
+
+		if app.config.OutputPath != "" {
+			if err := app.writeOutput(dependencies); err != nil {
+				return NewConfigError(fmt.Errorf("failed to write dependency output: %w", err))
+			}
+		}
+		if app.config.DependencyOutputPerTool {
+			if err := app.writeDependenciesPerTool(dependencies); err != nil {
+				app.log.Warnf("Failed to write per-tool dependency files: %v", err)
+			}
+		}
+	}
+
+	summary, err := scanner.NewWfpScanner(app.config).DetectLanguages(taskDir)
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+	data.Files = summary.Files
+	data.SourceFiles = summary.SourceFiles
+	for _, language := range summary.Languages {
+		data.Languages = append(data.Languages, report.LanguageFiles{Language: language.Language, Files: language.Files})
+	}
+	if data.ProjectName == "" {
+		data.ProjectName = summary.ProjectName
+	}
+
+	if err := report.WriteHTMLFile(app.config.HTMLReport, data); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	app.log.Infof("HTML report written to: %s", app.config.HTMLReport)
+	return app.checkStrict(data.Dependencies, scanWarnings, dependencyErr)
+}
@@ -1,23 +1,135 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+const (
+	// DefaultThreadNum is the worker count used when ThreadNum is unset or invalid
+	DefaultThreadNum = 30
+	// DefaultDetectMaxDepth is the directory depth searched by recursive detection when MaxDepth is unset
+	DefaultDetectMaxDepth = 5
+	// DefaultRetryCount is the number of retries for transient server failures
+	DefaultRetryCount = 3
+	// DefaultRetryWait is the base wait before the first retry
+	DefaultRetryWait = time.Second
+	// DefaultRetryMaxWait caps the backoff and Retry-After waits between retries
+	DefaultRetryMaxWait = 30 * time.Second
+	// DefaultMaxIdleConns is the number of idle connections kept open to the server
+	DefaultMaxIdleConns = 10
+	// DefaultKeepAlive is the interval of TCP keep-alive probes on server connections
+	DefaultKeepAlive = 30 * time.Second
+	// DefaultBuildToolTimeout is how long an external build tool command may run before it is killed
+	DefaultBuildToolTimeout = 10 * time.Minute
+	// DefaultWfpCacheName is the fingerprint cache file name, placed in ToPath when no path is configured
+	DefaultWfpCacheName = "fingerprints.cache"
+	// DefaultWfpName is the fingerprint file name written to ToPath
+	DefaultWfpName = "fingerprints.wfp"
+	// DefaultDepsName is the dependency file name written to ToPath
+	DefaultDepsName = "dependencies.json"
+	// DefaultMinFileSize is the smallest file fingerprinted when MinFileSize is unset, skipping empty files
+	DefaultMinFileSize = 1
+	// MaxFingerprintFileSize is the largest file fingerprinted; bigger files are skipped
+	MaxFingerprintFileSize = 1024 * 1024
+	// DefaultReadBufferSize is the buffer files are read with while fingerprinting
+	DefaultReadBufferSize = 32 * 1024
+	// MinReadBufferSize is the smallest read buffer accepted by --read-buffer
+	MinReadBufferSize = 512
+)
+
+// DefaultLicenseFilenames are the license and attribution file names collected when none are configured
+var DefaultLicenseFilenames = []string{
+	"LICENSE", "LICENCE", "COPYING", "COPYING.LESSER", "COPYRIGHT",
+	"UNLICENSE", "NOTICE", "LICENSE-MIT", "LICENSE-APACHE", "PATENTS",
+}
+
+// Dependency output formats
+const (
+	FormatJSON      = "json"
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+	FormatCSV       = "csv"
+	FormatDOT       = "dot"
+	FormatJSONL     = "jsonl"
+)
+
+// OutputFormats lists the supported dependency output formats
+var OutputFormats = []string{FormatJSON, FormatCycloneDX, FormatSPDX, FormatCSV, FormatDOT, FormatJSONL}
+
+// ArchiveFormats lists the supported source archive formats
+var ArchiveFormats = []string{utils.ArchiveFormatZip, utils.ArchiveFormatTarGz}
+
+// Log color modes
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
 )
 
+// ColorModes lists the supported log color modes
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// Authentication modes
+const (
+	AuthModeCookie = "cookie"
+	AuthModeToken  = "token"
+	AuthModeBasic  = "basic"
+)
+
+// AuthModes lists the supported authentication modes
+var AuthModes = []string{AuthModeCookie, AuthModeToken, AuthModeBasic}
+
+// Conditions that fail the scan when given to --fail-on
+const (
+	FailOnStaleLockfile = "stale-lockfile"
+)
+
+// FailOnConditions lists the supported --fail-on conditions
+var FailOnConditions = []string{FailOnStaleLockfile}
+
+// metaKeyPattern restricts --meta keys to identifier-like names
+var metaKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,63}$`)
+
+// BuildTools lists the build tools accepted by --only-tool and --skip-tool
+var BuildTools = []string{"maven", "gradle", "pip", "pipenv", "npm", "go", "cargo", "composer", "dotnet", "cmake", "c-heuristic"}
+
 // ScanConfig represents the main configuration for the build scanner
 type ScanConfig struct {
 	// Authentication
-	ServerURL string
-	Username  string
-	Password  string
-	Token     string
-	AuthType  AuthType
+	ServerURL         string
+	Username          string
+	Password          string
+	Token             string
+	PasswordFile      string // Read into Password, overriding the inline value
+	TokenFile         string // Read into Token, overriding the inline value
+	AuthMode          string // Empty selects token when given, otherwise cookie
+	AuthType          AuthType
+	AllowInsecureHTTP bool // Permit an http:// server URL, sending credentials in plaintext
+
+	// Server communication
+	ServerHealth bool
+	RetryCount   int
+	RetryWait    time.Duration
+	RetryMaxWait time.Duration
+	UploadRate   string        // Upload bandwidth limit per second, e.g. "5MB"; empty is unlimited
+	MaxIdleConns int           // Idle connections kept open for reuse; zero or less uses the default
+	KeepAlive    time.Duration // TCP keep-alive interval; zero or less uses the default
 
 	// Project information
 	CustomProject string
 	CustomProduct string
 	CustomVersion string
+	Meta          []string // User metadata as key=value pairs, uploaded under userMeta
 
 	// Scan parameters
 	TaskDir     string
@@ -28,15 +140,122 @@ type ScanConfig struct {
 	LicenseName string
 	ThreadNum   string
 	LogLevel    string
+	Color       string // Log coloring: auto, always or never
+	Redact      bool   // Mask passwords, tokens and URL credentials in log output
+	Recursive   bool
+	MaxDepth    int
+	DedupWfp    bool
+	Incremental bool
+	WfpCache    string
+
+	// File names written to ToPath, so several scans can share it
+	WfpName  string
+	DepsName string
+
+	// Also write the dependencies of each build tool to their own file, e.g. dependencies.maven.json;
+	// SplitOnly skips the combined dependency file, which is then not uploaded
+	DependencyOutputPerTool bool
+	SplitOnly               bool
+
+	// Upload only the dependency file when the WFP file matches the last successful upload
+	SkipUnchangedWfp bool
+
+	// Further directories fingerprinted into the same WFP file as prefix=dir pairs, their paths
+	// placed below prefix
+	CombineDirs []string
+
+	// Newline-delimited list of the only files to fingerprint, relative to the task directory;
+	// the WFP file is then partial
+	FilesFrom string
+
+	// Only fingerprint files modified after this time, a duration ago such as "24h" or a
+	// timestamp; the WFP file is then partial
+	Since string
+
+	// Smallest file fingerprinted in bytes; IncludeEmpty records empty files regardless
+	MinFileSize  int64
+	IncludeEmpty bool
+
+	// Extensions fingerprinted as source even if built in as binary, and extra binary ones to skip
+	SourceExts []string
+	BinaryExts []string
+
+	// Largest WFP upload, e.g. "50MB"; bigger files are uploaded in chunks. Empty uploads whole.
+	WfpChunkSize string
+
+	// Buffer files are read with while fingerprinting, e.g. "64KB"; empty uses DefaultReadBufferSize
+	ReadBuffer string
+
+	// Listing of every fingerprinted file with its size and hash, CSV for a .csv path and JSON otherwise
+	FileManifest string
+
+	// Archive only the files the server could not match, in a second upload phase
+	ArchiveUnmatchedOnly bool
+
+	// Source archive format, zip or tar.gz, and compression level; nil uses the format default
+	ArchiveFormat        string
+	CompressArchiveLevel *int
+
+	// File names collected as project licenses; NOTICE files are recorded separately
+	LicenseFilenames []string
+
+	// Paths excluded from fingerprinting, relative to the scan directory
+	ExcludePaths []string
+	ExcludeTests bool // Also exclude test and generated source directories such as testdata/
+
+	// Experimental scanners
+	ExperimentalCScan bool
 
 	// Notification
 	NotificationEmail string
 
+	// Build tool selection; empty OnlyTools allows every detected tool
+	OnlyTools []string
+	SkipTools []string
+
+	// Time an external build tool command may run before it is killed
+	BuildToolTimeout time.Duration
+
 	// Build tool paths
 	MavenPath           string
 	MavenBuildCommand   string
+	MavenSettings       string // settings.xml passed to mvn as -s; defaults to ~/.m2/settings.xml
 	PipPath             string
 	PipRequirementsPath string
+	PipConstraintsPath  string
+	VenvPath            string // Virtualenv read when pip cannot be invoked; defaults to VIRTUAL_ENV
+	GoProxy             string // GOPROXY for go list; empty inherits the environment
+	DotnetPath          string // dotnet executable; when set, dotnet list package resolves transitive packages
+	GoFlags             string // GOFLAGS for go list; empty inherits the environment
+
+	// Authoritative source when several files describe the same packages, e.g. requirements.txt
+	// and Pipfile, or several npm lockfiles; with neither set all are used and a warning is logged
+	PreferLockfile  bool
+	PreferManifest  bool
+	ExternalScanner string // Executable printing dependency roots as JSON, merged with the built-in scanners
+
+	// CycloneDX or SPDX JSON file read instead of running the build tool scanners
+	SBOMInput string
+
+	// Patterns marking first-party dependencies as internal; "re:" prefixes a regular expression
+	InternalPatterns []string
+
+	// Dependency output
+	ExcludeScopes       []string
+	DependencyDepth     *int // Transitive levels kept below direct dependencies; nil keeps the full tree
+	NormalizeVersions   bool // Strip range operators and "v" prefixes from single versions
+	NormalizeTypes      bool // Map dependency types to package URL ecosystems such as maven or pypi
+	ResolveVersions     bool // Resolve npm and Python constraints to the newest matching registry version
+	ReportUnmatchedOnly bool // Keep only dependencies with an empty or unknown version
+	FlattenDeps         bool // Replace dependency trees with flat deduplicated lists carrying each depth
+	Format              string
+	OutputPath          string
+	HTMLReport          string // Local HTML summary; when set the scan runs offline
+	ProjectsOnly        bool   // Write the detected projects to OutputPath without resolving dependencies; runs offline
+
+	// Conditions that fail the scan
+	FailOn []string
+	Strict bool // Any scan warning or failed scanner fails the run
 
 	// Default parameters
 	DefaultParam *DefaultParamInfo
@@ -56,6 +275,7 @@ type AuthType int
 const (
 	AuthTypeCookie AuthType = iota
 	AuthTypeToken
+	AuthTypeBasic
 )
 
 // NewScanConfig creates a new scan configuration with default values
@@ -66,6 +286,9 @@ func NewScanConfig() *ScanConfig {
 		BuildDepend: true,
 		ThreadNum:   "30",
 		LogLevel:    "info",
+		Redact:      true,
+		RetryCount:  DefaultRetryCount,
+		Format:      FormatJSON,
 		DefaultParam: &DefaultParamInfo{
 			ScanWay:             1, // Full scan
 			IsSaveSourceFile:    0,
@@ -90,16 +313,487 @@ func (c *ScanConfig) SetToPath(scanPath string) {
 	}
 }
 
+// GetThreadNum returns the configured thread number, falling back to the default when invalid
+func (c *ScanConfig) GetThreadNum() int {
+	threadNum, err := strconv.Atoi(strings.TrimSpace(c.ThreadNum))
+	if err != nil || threadNum < 1 || threadNum > 60 {
+		return DefaultThreadNum
+	}
+	return threadNum
+}
+
+// GetDetectMaxDepth returns the directory depth limit for recursive build file detection
+func (c *ScanConfig) GetDetectMaxDepth() int {
+	if c.MaxDepth > 0 {
+		return c.MaxDepth
+	}
+	return DefaultDetectMaxDepth
+}
+
+// GetRetryWait returns the base retry wait, falling back to the default when unset
+func (c *ScanConfig) GetRetryWait() time.Duration {
+	if c.RetryWait > 0 {
+		return c.RetryWait
+	}
+	return DefaultRetryWait
+}
+
+// GetMaxIdleConns returns the number of idle server connections kept, falling back to the default when unset
+func (c *ScanConfig) GetMaxIdleConns() int {
+	if c.MaxIdleConns > 0 {
+		return c.MaxIdleConns
+	}
+	return DefaultMaxIdleConns
+}
+
+// GetKeepAlive returns the TCP keep-alive interval, falling back to the default when unset
+func (c *ScanConfig) GetKeepAlive() time.Duration {
+	if c.KeepAlive > 0 {
+		return c.KeepAlive
+	}
+	return DefaultKeepAlive
+}
+
+// GetBuildToolTimeout returns the build tool command timeout, falling back to the default when unset
+func (c *ScanConfig) GetBuildToolTimeout() time.Duration {
+	if c.BuildToolTimeout > 0 {
+		return c.BuildToolTimeout
+	}
+	return DefaultBuildToolTimeout
+}
+
+// GetRetryMaxWait returns the maximum retry wait, never below the base retry wait
+func (c *ScanConfig) GetRetryMaxWait() time.Duration {
+	maxWait := c.RetryMaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+	if wait := c.GetRetryWait(); maxWait < wait {
+		return wait
+	}
+	return maxWait
+}
+
+// GetUploadRate returns the upload bandwidth limit in bytes per second, 0 when unlimited
+func (c *ScanConfig) GetUploadRate() int64 {
+	if c.UploadRate == "" {
+		return 0
+	}
+	rate, err := utils.ParseByteSize(c.UploadRate)
+	if err != nil {
+		return 0
+	}
+	return rate
+}
+
+// GetWfpPath returns the path the fingerprint file is written to
+func (c *ScanConfig) GetWfpPath() string {
+	if c.WfpName == "" {
+		return filepath.Join(c.ToPath, DefaultWfpName)
+	}
+	return filepath.Join(c.ToPath, utils.SanitizeFileName(c.WfpName))
+}
+
+// GetDepsPath returns the path the dependency file is written to
+func (c *ScanConfig) GetDepsPath() string {
+	if c.DepsName == "" {
+		return filepath.Join(c.ToPath, DefaultDepsName)
+	}
+	return filepath.Join(c.ToPath, utils.SanitizeFileName(c.DepsName))
+}
+
+// GetToolDepsPath returns the path the dependencies of one build tool are written to, the
+// dependency file name with the tool inserted before its extension
+func (c *ScanConfig) GetToolDepsPath(buildTool string) string {
+	path := c.GetDepsPath()
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + utils.SanitizeFileName(buildTool) + ext
+}
+
+// GetMinFileSize returns the size in bytes below which files are not fingerprinted
+func (c *ScanConfig) GetMinFileSize() int64 {
+	if c.MinFileSize > 0 {
+		return c.MinFileSize
+	}
+	return DefaultMinFileSize
+}
+
+// GetSince returns the time files must be modified after to be fingerprinted, resolving a
+// duration against now; it is zero when every file is fingerprinted
+func (c *ScanConfig) GetSince(now time.Time) time.Time {
+	if c.Since == "" {
+		return time.Time{}
+	}
+	since, err := utils.ParseSince(c.Since, now)
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
+
+// GetWfpChunkSize returns the largest WFP upload in bytes, 0 when the file is never split
+func (c *ScanConfig) GetWfpChunkSize() int64 {
+	if c.WfpChunkSize == "" {
+		return 0
+	}
+	size, err := utils.ParseByteSize(c.WfpChunkSize)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// GetReadBufferSize returns the size in bytes of the buffer files are read with while fingerprinting
+func (c *ScanConfig) GetReadBufferSize() int {
+	if c.ReadBuffer == "" {
+		return DefaultReadBufferSize
+	}
+	size, err := utils.ParseByteSize(c.ReadBuffer)
+	if err != nil || size < MinReadBufferSize || size > MaxFingerprintFileSize {
+		return DefaultReadBufferSize
+	}
+	return int(size)
+}
+
+// GetWfpCachePath returns the fingerprint cache path used by incremental fingerprinting
+func (c *ScanConfig) GetWfpCachePath() string {
+	if c.WfpCache != "" {
+		return c.WfpCache
+	}
+	return filepath.Join(c.ToPath, DefaultWfpCacheName)
+}
+
+// GetWfpHashPath returns the file holding the hash and upload target of the last uploaded WFP file, stored
+// next to the fingerprint cache
+func (c *ScanConfig) GetWfpHashPath() string {
+	return c.GetWfpCachePath() + ".sha256"
+}
+
+// GetSourceExts returns the --source-ext extensions lowercased with a leading dot
+func (c *ScanConfig) GetSourceExts() []string {
+	return normalizeExtensions(c.SourceExts)
+}
+
+// GetBinaryExts returns the --binary-ext extensions lowercased with a leading dot
+func (c *ScanConfig) GetBinaryExts() []string {
+	return normalizeExtensions(c.BinaryExts)
+}
+
+// normalizeExtensions lowercases file extensions and gives them a leading dot, so "MyExt" and
+// ".myext" compare equal to filepath.Ext output
+func normalizeExtensions(exts []string) []string {
+	var normalized []string
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext != "" && !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		normalized = append(normalized, ext)
+	}
+	return normalized
+}
+
+// GetLicenseFilenames returns the file names collected as licenses, falling back to the defaults
+func (c *ScanConfig) GetLicenseFilenames() []string {
+	if len(c.LicenseFilenames) > 0 {
+		return c.LicenseFilenames
+	}
+	return DefaultLicenseFilenames
+}
+
+// ToolEnabled reports whether scanners of the given build tool may run
+func (c *ScanConfig) ToolEnabled(tool string) bool {
+	if len(c.OnlyTools) > 0 && !slices.Contains(c.OnlyTools, tool) {
+		return false
+	}
+	return !slices.Contains(c.SkipTools, tool)
+}
+
+// GetDependencyDepth returns the transitive levels kept below direct dependencies, -1 for unlimited
+func (c *ScanConfig) GetDependencyDepth() int {
+	if c.DependencyDepth == nil || *c.DependencyDepth < 0 {
+		return -1
+	}
+	return *c.DependencyDepth
+}
+
+// GetArchiveFormat returns the source archive format, zip unless tar.gz is selected
+func (c *ScanConfig) GetArchiveFormat() string {
+	if c.ArchiveFormat == "" {
+		return utils.ArchiveFormatZip
+	}
+	return c.ArchiveFormat
+}
+
+// GetCompressArchiveLevel returns the compression level of the source archive, from 0 (stored)
+// to 9 (smallest), or utils.DefaultCompressionLevel (-1) when none is set
+func (c *ScanConfig) GetCompressArchiveLevel() int {
+	if c.CompressArchiveLevel == nil {
+		return utils.DefaultCompressionLevel
+	}
+	return *c.CompressArchiveLevel
+}
+
+// Offline reports whether the scan only produces local output without contacting the server
+func (c *ScanConfig) Offline() bool {
+	return c.HTMLReport != "" || c.ProjectsOnly
+}
+
+// CombineDir is a directory whose fingerprints are combined into the task's WFP file
+type CombineDir struct {
+	Prefix string // Path prefix of its entries, without leading or trailing slashes
+	Dir    string
+}
+
+// GetCombineDirs returns the --combine-dir pairs in order, skipping malformed entries rejected
+// by Validate
+func (c *ScanConfig) GetCombineDirs() []CombineDir {
+	var dirs []CombineDir
+	for _, pair := range c.CombineDirs {
+		prefix, dir, ok := strings.Cut(pair, "=")
+		prefix = strings.Trim(filepath.ToSlash(strings.TrimSpace(prefix)), "/")
+		if ok && prefix != "" && dir != "" {
+			dirs = append(dirs, CombineDir{Prefix: prefix, Dir: dir})
+		}
+	}
+	return dirs
+}
+
+// UserMeta returns the --meta pairs as a map, skipping malformed entries rejected by Validate.
+// A repeated key keeps its last value.
+func (c *ScanConfig) UserMeta() map[string]string {
+	meta := make(map[string]string)
+	for _, pair := range c.Meta {
+		if key, value, ok := strings.Cut(pair, "="); ok && metaKeyPattern.MatchString(key) {
+			meta[key] = value
+		}
+	}
+	return meta
+}
+
+// LoadSecretFiles reads the password and token from PasswordFile and TokenFile when given,
+// overriding the inline values. A trailing newline is trimmed.
+func (c *ScanConfig) LoadSecretFiles() error {
+	for _, secret := range []struct {
+		name  string
+		path  string
+		value *string
+	}{
+		{"password", c.PasswordFile, &c.Password},
+		{"token", c.TokenFile, &c.Token},
+	} {
+		if secret.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(secret.path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s file: %w", secret.name, err)
+		}
+		value := strings.TrimRight(string(data), "\r\n")
+		if value == "" {
+			return fmt.Errorf("%s file %s is empty", secret.name, secret.path)
+		}
+		*secret.value = value
+	}
+	return nil
+}
+
+// CompileInternalPattern compiles an --internal-pattern into an anchored regular expression.
+// Patterns prefixed with "re:" are regular expressions; others are globs where * matches any
+// run of characters, including separators, and ? a single character.
+func CompileInternalPattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		return regexp.Compile("^(?:" + expr + ")$")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// redactedSecret replaces secrets in displayed configuration
+const redactedSecret = "***"
+
+// Redacted returns a copy of the configuration with the password, token and any credentials
+// in the server URL masked, for display
+func (c *ScanConfig) Redacted() *ScanConfig {
+	redacted := *c
+	for _, secret := range []*string{&redacted.Password, &redacted.Token} {
+		if *secret != "" {
+			*secret = redactedSecret
+		}
+	}
+	if u, err := url.Parse(c.ServerURL); err == nil && u.User != nil {
+		u.User = url.User(redactedSecret)
+		redacted.ServerURL = u.String()
+	}
+	return &redacted
+}
+
+// FailsOn reports whether the given --fail-on condition is enabled
+func (c *ScanConfig) FailsOn(condition string) bool {
+	return slices.Contains(c.FailOn, condition)
+}
+
 // Validate validates the configuration
 func (c *ScanConfig) Validate() error {
 	if c.TaskDir == "" {
 		return ErrMissingTaskDir
 	}
+	if c.Offline() {
+		// Nothing is sent to a server, so neither a URL nor credentials are needed
+		return c.validateOptions()
+	}
 	if c.ServerURL == "" {
 		return ErrMissingServerURL
 	}
+	if strings.HasPrefix(strings.ToLower(c.ServerURL), "http://") && !c.AllowInsecureHTTP {
+		return ErrInsecureServerURL
+	}
 	if c.Username == "" && c.Token == "" {
 		return ErrMissingAuth
 	}
+	switch c.AuthMode {
+	case "":
+	case AuthModeToken:
+		if c.Token == "" {
+			return ErrMissingAuth
+		}
+	case AuthModeCookie, AuthModeBasic:
+		if c.Username == "" {
+			return ErrMissingAuth
+		}
+	default:
+		return ErrInvalidAuthMode
+	}
+	return c.validateOptions()
+}
+
+// validateOptions validates the settings that do not concern the server
+func (c *ScanConfig) validateOptions() error {
+	if c.Format != "" && !slices.Contains(OutputFormats, c.Format) {
+		return ErrInvalidFormat
+	}
+
+	if c.Color != "" && !slices.Contains(ColorModes, c.Color) {
+		return ErrInvalidColor
+	}
+
+	if c.ArchiveFormat != "" && !slices.Contains(ArchiveFormats, c.ArchiveFormat) {
+		return ErrInvalidArchiveFormat
+	}
+	if level := c.CompressArchiveLevel; level != nil && *level != utils.DefaultCompressionLevel && (*level < 0 || *level > 9) {
+		return ErrInvalidCompressLevel
+	}
+
+	if c.UploadRate != "" {
+		if rate, err := utils.ParseByteSize(c.UploadRate); err != nil || rate <= 0 {
+			return ErrInvalidUploadRate
+		}
+	}
+	if c.GetWfpPath() == c.GetDepsPath() {
+		return ErrOutputNameCollision
+	}
+	if c.PreferLockfile && c.PreferManifest {
+		return ErrConflictingPreference
+	}
+
+	if c.Since != "" {
+		if _, err := utils.ParseSince(c.Since, time.Now()); err != nil {
+			return ErrInvalidSince
+		}
+	}
+
+	if c.FilesFrom != "" {
+		if info, err := os.Stat(c.FilesFrom); err != nil || info.IsDir() {
+			return ErrFilesFromNotFound
+		}
+	}
+
+	if c.MinFileSize < 0 || c.MinFileSize > MaxFingerprintFileSize {
+		return ErrInvalidMinFileSize
+	}
+
+	sourceExts := c.GetSourceExts()
+	for _, ext := range append(sourceExts, c.GetBinaryExts()...) {
+		if len(ext) < 2 || strings.ContainsAny(ext[1:], `./\`) {
+			return ErrInvalidExtension
+		}
+	}
+	for _, ext := range c.GetBinaryExts() {
+		if slices.Contains(sourceExts, ext) {
+			return ErrInvalidExtension
+		}
+	}
+
+	if c.ReadBuffer != "" {
+		if size, err := utils.ParseByteSize(c.ReadBuffer); err != nil || size < MinReadBufferSize || size > MaxFingerprintFileSize {
+			return ErrInvalidReadBuffer
+		}
+	}
+
+	if c.WfpChunkSize != "" {
+		if size, err := utils.ParseByteSize(c.WfpChunkSize); err != nil || size <= 0 {
+			return ErrInvalidWfpChunkSize
+		}
+	}
+	if c.SBOMInput != "" {
+		if info, err := os.Stat(c.SBOMInput); err != nil || info.IsDir() {
+			return ErrSBOMInputNotFound
+		}
+	}
+	if c.ProjectsOnly && c.OutputPath == "" {
+		return ErrProjectsOnlyOutput
+	}
+	if c.SplitOnly && !c.DependencyOutputPerTool {
+		return ErrSplitOnlyPerTool
+	}
+	for _, tool := range append(slices.Clone(c.OnlyTools), c.SkipTools...) {
+		if !slices.Contains(BuildTools, tool) {
+			return ErrInvalidBuildTool
+		}
+	}
+
+	for _, pair := range c.Meta {
+		if key, _, ok := strings.Cut(pair, "="); !ok || !metaKeyPattern.MatchString(key) {
+			return ErrInvalidMeta
+		}
+	}
+
+	combineDirs := c.GetCombineDirs()
+	if len(combineDirs) != len(c.CombineDirs) {
+		return ErrInvalidCombineDir
+	}
+	prefixes := make(map[string]bool)
+	for _, dir := range combineDirs {
+		info, err := os.Stat(dir.Dir)
+		if err != nil || !info.IsDir() || prefixes[dir.Prefix] || slices.Contains(strings.Split(dir.Prefix, "/"), "..") {
+			return ErrInvalidCombineDir
+		}
+		prefixes[dir.Prefix] = true
+	}
+
+	for _, pattern := range c.InternalPatterns {
+		if _, err := CompileInternalPattern(pattern); err != nil || strings.TrimSpace(pattern) == "" {
+			return ErrInvalidInternalPattern
+		}
+	}
+
+	for _, condition := range c.FailOn {
+		if !slices.Contains(FailOnConditions, condition) {
+			return ErrInvalidFailOn
+		}
+	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewScanConfig(t *testing.T) {
@@ -154,6 +155,371 @@ func TestScanConfig_Validate(t *testing.T) {
 			},
 			wantErr: ErrMissingAuth,
 		},
+		{
+			name: "Plaintext http server URL",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "HTTP://example.com"
+				cfg.Token = "test-token"
+				return cfg
+			},
+			wantErr: ErrInsecureServerURL,
+		},
+		{
+			name: "Plaintext http server URL explicitly allowed",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "http://localhost:8080"
+				cfg.Token = "test-token"
+				cfg.AllowInsecureHTTP = true
+				return cfg
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Invalid format",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.Format = "xml"
+				return cfg
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "Valid configuration with basic auth",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Username = "testuser"
+				cfg.Password = "testpass"
+				cfg.AuthMode = AuthModeBasic
+				return cfg
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Basic auth without username",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.AuthMode = AuthModeBasic
+				return cfg
+			},
+			wantErr: ErrMissingAuth,
+		},
+		{
+			name: "Invalid auth mode",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.AuthMode = "digest"
+				return cfg
+			},
+			wantErr: ErrInvalidAuthMode,
+		},
+		{
+			name: "Invalid fail-on condition",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.FailOn = []string{"stale-lockfile", "vulnerabilities"}
+				return cfg
+			},
+			wantErr: ErrInvalidFailOn,
+		},
+		{
+			name: "Invalid metadata key",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.Meta = []string{"branch=main", "build number=42"}
+				return cfg
+			},
+			wantErr: ErrInvalidMeta,
+		},
+		{
+			name: "Metadata without value separator",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.Meta = []string{"branch"}
+				return cfg
+			},
+			wantErr: ErrInvalidMeta,
+		},
+		{
+			name: "Invalid internal pattern",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.InternalPatterns = []string{"com.mycorp.*", "re:(unclosed"}
+				return cfg
+			},
+			wantErr: ErrInvalidInternalPattern,
+		},
+		{
+			name: "Invalid upload rate",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.UploadRate = "5 parsecs"
+				return cfg
+			},
+			wantErr: ErrInvalidUploadRate,
+		},
+		{
+			name: "Colliding output names",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.WfpName = "scan output"
+				cfg.DepsName = "scan_output"
+				return cfg
+			},
+			wantErr: ErrOutputNameCollision,
+		},
+		{
+			name: "Invalid color mode",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.Color = "rainbow"
+				return cfg
+			},
+			wantErr: ErrInvalidColor,
+		},
+		{
+			name: "Conflicting source preferences",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.PreferLockfile = true
+				cfg.PreferManifest = true
+				return cfg
+			},
+			wantErr: ErrConflictingPreference,
+		},
+		{
+			name: "Combine directory without a prefix",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.CombineDirs = []string{os.TempDir()}
+				return cfg
+			},
+			wantErr: ErrInvalidCombineDir,
+		},
+		{
+			name: "Combine directory with a duplicate prefix",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.CombineDirs = []string{"lib=" + os.TempDir(), "/lib/=" + os.TempDir()}
+				return cfg
+			},
+			wantErr: ErrInvalidCombineDir,
+		},
+		{
+			name: "Extension listed as both source and binary",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.SourceExts = []string{".myext"}
+				cfg.BinaryExts = []string{"MYEXT"}
+				return cfg
+			},
+			wantErr: ErrInvalidExtension,
+		},
+		{
+			name: "Missing files-from list",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.FilesFrom = filepath.Join(os.TempDir(), "does-not-exist.txt")
+				return cfg
+			},
+			wantErr: ErrFilesFromNotFound,
+		},
+		{
+			name: "Projects only without output",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ProjectsOnly = true
+				return cfg
+			},
+			wantErr: ErrProjectsOnlyOutput,
+		},
+		{
+			name: "Split only without per-tool output",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.SplitOnly = true
+				return cfg
+			},
+			wantErr: ErrSplitOnlyPerTool,
+		},
+		{
+			name: "Invalid archive format",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.ArchiveFormat = "rar"
+				return cfg
+			},
+			wantErr: ErrInvalidArchiveFormat,
+		},
+		{
+			name: "Invalid archive compression level",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				level := 10
+				cfg.CompressArchiveLevel = &level
+				return cfg
+			},
+			wantErr: ErrInvalidCompressLevel,
+		},
+		{
+			name: "Default archive compression level",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				level := -1
+				cfg.CompressArchiveLevel = &level
+				return cfg
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Archive compression level below the default",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				level := -2
+				cfg.CompressArchiveLevel = &level
+				return cfg
+			},
+			wantErr: ErrInvalidCompressLevel,
+		},
+		{
+			name: "Read buffer below the minimum",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.ReadBuffer = "64B"
+				return cfg
+			},
+			wantErr: ErrInvalidReadBuffer,
+		},
+		{
+			name: "Invalid since",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.Since = "last week"
+				return cfg
+			},
+			wantErr: ErrInvalidSince,
+		},
+		{
+			name: "Minimum file size above the fingerprint limit",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.MinFileSize = MaxFingerprintFileSize + 1
+				return cfg
+			},
+			wantErr: ErrInvalidMinFileSize,
+		},
+		{
+			name: "Invalid WFP chunk size",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.WfpChunkSize = "0"
+				return cfg
+			},
+			wantErr: ErrInvalidWfpChunkSize,
+		},
+		{
+			name: "Missing SBOM input",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.SBOMInput = "/nonexistent/bom.json"
+				return cfg
+			},
+			wantErr: ErrSBOMInputNotFound,
+		},
+		{
+			name: "Invalid build tool",
+			setupFunc: func() *ScanConfig {
+				cfg := NewScanConfig()
+				cfg.TaskDir = "/tmp/test"
+				cfg.ServerURL = "https://example.com"
+				cfg.Token = "test-token"
+				cfg.OnlyTools = []string{"go"}
+				cfg.SkipTools = []string{"bazel"}
+				return cfg
+			},
+			wantErr: ErrInvalidBuildTool,
+		},
 	}
 
 	for _, tt := range tests {
@@ -176,6 +542,68 @@ func TestScanConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestScanConfig_GetThreadNum(t *testing.T) {
+	tests := []struct {
+		threadNum string
+		expected  int
+	}{
+		{"16", 16},
+		{" 4 ", 4},
+		{"1", 1},
+		{"60", 60},
+		{"", DefaultThreadNum},
+		{"0", DefaultThreadNum},
+		{"61", DefaultThreadNum},
+		{"abc", DefaultThreadNum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.threadNum, func(t *testing.T) {
+			cfg := &ScanConfig{ThreadNum: tt.threadNum}
+			if got := cfg.GetThreadNum(); got != tt.expected {
+				t.Errorf("GetThreadNum() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScanConfig_GetDetectMaxDepth(t *testing.T) {
+	cfg := &ScanConfig{}
+	if got := cfg.GetDetectMaxDepth(); got != DefaultDetectMaxDepth {
+		t.Errorf("Expected default detection depth %d, got %d", DefaultDetectMaxDepth, got)
+	}
+
+	cfg.MaxDepth = 2
+	if got := cfg.GetDetectMaxDepth(); got != 2 {
+		t.Errorf("Expected configured detection depth 2, got %d", got)
+	}
+}
+
+func TestScanConfig_GetDependencyDepth(t *testing.T) {
+	cfg := &ScanConfig{}
+	if got := cfg.GetDependencyDepth(); got != -1 {
+		t.Errorf("Expected unlimited depth when unset, got %d", got)
+	}
+
+	depth := 0
+	cfg.DependencyDepth = &depth
+	if got := cfg.GetDependencyDepth(); got != 0 {
+		t.Errorf("Expected direct-only depth 0, got %d", got)
+	}
+}
+
+func TestScanConfig_GetBuildToolTimeout(t *testing.T) {
+	cfg := &ScanConfig{}
+	if got := cfg.GetBuildToolTimeout(); got != DefaultBuildToolTimeout {
+		t.Errorf("Expected default build tool timeout %s, got %s", DefaultBuildToolTimeout, got)
+	}
+
+	cfg.BuildToolTimeout = 30 * time.Second
+	if got := cfg.GetBuildToolTimeout(); got != 30*time.Second {
+		t.Errorf("Expected configured build tool timeout 30s, got %s", got)
+	}
+}
+
 func TestAuthType(t *testing.T) {
 	if AuthTypeCookie != 0 {
 		t.Errorf("Expected AuthTypeCookie to be 0, got %d", AuthTypeCookie)
@@ -183,6 +611,9 @@ func TestAuthType(t *testing.T) {
 	if AuthTypeToken != 1 {
 		t.Errorf("Expected AuthTypeToken to be 1, got %d", AuthTypeToken)
 	}
+	if AuthTypeBasic != 2 {
+		t.Errorf("Expected AuthTypeBasic to be 2, got %d", AuthTypeBasic)
+	}
 }
 
 // Helper function to create a temporary directory for testing
@@ -214,3 +645,122 @@ func TestScanConfig_SetToPath_Integration(t *testing.T) {
 		t.Errorf("Expected ToPath to be %s, got %s", expectedParent, cfg.ToPath)
 	}
 }
+
+func TestScanConfig_GetRetryWait(t *testing.T) {
+	tests := []struct {
+		name            string
+		retryWait       time.Duration
+		retryMaxWait    time.Duration
+		expectedWait    time.Duration
+		expectedMaxWait time.Duration
+	}{
+		{"defaults", 0, 0, DefaultRetryWait, DefaultRetryMaxWait},
+		{"configured", 2 * time.Second, time.Minute, 2 * time.Second, time.Minute},
+		{"max below base", 10 * time.Second, time.Second, 10 * time.Second, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ScanConfig{RetryWait: tt.retryWait, RetryMaxWait: tt.retryMaxWait}
+			if got := cfg.GetRetryWait(); got != tt.expectedWait {
+				t.Errorf("GetRetryWait() = %v, want %v", got, tt.expectedWait)
+			}
+			if got := cfg.GetRetryMaxWait(); got != tt.expectedMaxWait {
+				t.Errorf("GetRetryMaxWait() = %v, want %v", got, tt.expectedMaxWait)
+			}
+		})
+	}
+}
+
+func TestScanConfig_ApplyProjectConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `exclude:
+  - docs/**
+  - "*.min.js"
+exclude-scope: [test]
+custom-project: from-project-file
+pip-path: /opt/python/bin/pip
+`
+	if err := os.WriteFile(filepath.Join(tempDir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create project config: %v", err)
+	}
+
+	projectConfig, err := LoadProjectConfig(tempDir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig failed: %v", err)
+	}
+
+	t.Run("project file applies when flags are unset", func(t *testing.T) {
+		cfg := NewScanConfig()
+		cfg.ApplyProjectConfig(projectConfig, func(string) bool { return false })
+
+		if len(cfg.ExcludePaths) != 2 || cfg.ExcludePaths[0] != "docs/**" {
+			t.Errorf("Expected excludes from project file, got %v", cfg.ExcludePaths)
+		}
+		if len(cfg.ExcludeScopes) != 1 || cfg.ExcludeScopes[0] != "test" {
+			t.Errorf("Expected exclude scopes from project file, got %v", cfg.ExcludeScopes)
+		}
+		if cfg.CustomProject != "from-project-file" || cfg.PipPath != "/opt/python/bin/pip" {
+			t.Errorf("Expected project name and pip path from project file, got %s %s", cfg.CustomProject, cfg.PipPath)
+		}
+	})
+
+	t.Run("flags override project file", func(t *testing.T) {
+		cfg := NewScanConfig()
+		cfg.ExcludePaths = []string{"vendor/"}
+		cfg.CustomProject = "from-flag"
+		isSet := func(name string) bool { return name == "exclude" || name == "custom-project" }
+
+		cfg.ApplyProjectConfig(projectConfig, isSet)
+
+		if len(cfg.ExcludePaths) != 1 || cfg.ExcludePaths[0] != "vendor/" {
+			t.Errorf("Expected --exclude to override project file, got %v", cfg.ExcludePaths)
+		}
+		if cfg.CustomProject != "from-flag" {
+			t.Errorf("Expected --custom-project to override project file, got %s", cfg.CustomProject)
+		}
+		if len(cfg.ExcludeScopes) != 1 {
+			t.Errorf("Expected unset exclude scopes to come from project file, got %v", cfg.ExcludeScopes)
+		}
+	})
+}
+
+func TestLoadProjectConfig_Missing(t *testing.T) {
+	projectConfig, err := LoadProjectConfig(t.TempDir())
+	if err != nil || projectConfig != nil {
+		t.Errorf("Expected no project config and no error, got %v, %v", projectConfig, err)
+	}
+}
+
+func TestScanConfig_LoadSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("s3cret\r\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+
+	cfg := &ScanConfig{Token: "inline-token", TokenFile: tokenFile, PasswordFile: passwordFile}
+	if err := cfg.LoadSecretFiles(); err != nil {
+		t.Fatalf("LoadSecretFiles failed: %v", err)
+	}
+	if cfg.Token != "file-token" {
+		t.Errorf("Expected token from file overriding the inline value, got %q", cfg.Token)
+	}
+	if cfg.Password != "s3cret" {
+		t.Errorf("Expected password without trailing newline, got %q", cfg.Password)
+	}
+
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatalf("Failed to write empty file: %v", err)
+	}
+	for _, path := range []string{emptyFile, filepath.Join(dir, "missing")} {
+		if err := (&ScanConfig{TokenFile: path}).LoadSecretFiles(); err == nil {
+			t.Errorf("Expected an error for token file %s", path)
+		}
+	}
+}
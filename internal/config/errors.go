@@ -4,9 +4,33 @@ import "errors"
 
 // Configuration validation errors
 var (
-	ErrMissingTaskDir   = errors.New("task directory is required")
-	ErrMissingServerURL = errors.New("server URL is required")
-	ErrMissingAuth      = errors.New("username/password or token is required for authentication")
-	ErrInvalidScanType  = errors.New("invalid scan type, must be one of: source, docker, binary")
-	ErrInvalidThreadNum = errors.New("thread number must be between 1 and 60")
+	ErrMissingTaskDir         = errors.New("task directory is required")
+	ErrTaskDirNotDirectory    = errors.New("task directory is not a directory")
+	ErrMissingServerURL       = errors.New("server URL is required")
+	ErrMissingAuth            = errors.New("username/password or token is required for authentication")
+	ErrInsecureServerURL      = errors.New("server URL uses plaintext http, which would send credentials unencrypted; use https or pass --allow-insecure-http")
+	ErrInvalidAuthMode        = errors.New("invalid auth mode, must be one of: cookie, token, basic")
+	ErrInvalidScanType        = errors.New("invalid scan type, must be one of: source, docker, binary")
+	ErrInvalidThreadNum       = errors.New("thread number must be between 1 and 60")
+	ErrInvalidFormat          = errors.New("invalid format, must be one of: json, cyclonedx, spdx, csv, dot, jsonl")
+	ErrInvalidColor           = errors.New("invalid color mode, must be one of: auto, always, never")
+	ErrInvalidArchiveFormat   = errors.New("invalid archive format, must be one of: zip, tar.gz")
+	ErrInvalidCompressLevel   = errors.New("invalid archive compression level, must be between 0 (stored) and 9 (smallest), or -1 for the format default")
+	ErrInvalidBuildTool       = errors.New("invalid build tool, must be one of: maven, gradle, pip, pipenv, npm, go, cargo, composer, dotnet, cmake, c-heuristic")
+	ErrInvalidMeta            = errors.New("invalid metadata, must be key=value with a key of letters, digits, '_', '.' or '-' starting with a letter")
+	ErrInvalidInternalPattern = errors.New("invalid internal pattern, must be a glob or a regular expression prefixed with re:")
+	ErrInvalidUploadRate      = errors.New("invalid upload rate, must be a positive size per second such as 512KB or 5MB")
+	ErrInvalidWfpChunkSize    = errors.New("invalid WFP chunk size, must be a positive size such as 10MB")
+	ErrInvalidReadBuffer      = errors.New("invalid read buffer, must be a size from 512B up to the 1MB fingerprint size limit")
+	ErrInvalidCombineDir      = errors.New("invalid combine directory, must be prefix=dir with a unique prefix and an existing directory")
+	ErrInvalidSince           = errors.New("invalid since, must be a positive duration such as 24h or a timestamp such as 2024-05-01")
+	ErrInvalidMinFileSize     = errors.New("invalid minimum file size, must be between 1 and the 1MB fingerprint size limit")
+	ErrInvalidExtension       = errors.New("invalid file extension, must be a single extension such as .myext listed as either source or binary")
+	ErrOutputNameCollision    = errors.New("fingerprint and dependency file names must differ")
+	ErrConflictingPreference  = errors.New("--prefer-lockfile and --prefer-manifest cannot be combined")
+	ErrFilesFromNotFound      = errors.New("files-from list does not exist or is not a regular file")
+	ErrProjectsOnlyOutput     = errors.New("--projects-only needs --output for the project list")
+	ErrSplitOnlyPerTool       = errors.New("--split-only needs --dependency-output-per-tool")
+	ErrSBOMInputNotFound      = errors.New("SBOM input file does not exist or is not a regular file")
+	ErrInvalidFailOn          = errors.New("invalid fail-on condition, must be one of: stale-lockfile")
 )
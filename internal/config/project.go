@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the project-local configuration discovered at the scan root
+const ProjectConfigFile = ".cleansource.yml"
+
+// ProjectConfig is the scan policy a repository can carry in .cleansource.yml.
+// Keys use the names of the corresponding command line flags.
+type ProjectConfig struct {
+	Exclude             []string `yaml:"exclude"`
+	ExcludeScope        []string `yaml:"exclude-scope"`
+	CustomProject       string   `yaml:"custom-project"`
+	CustomProduct       string   `yaml:"custom-product"`
+	CustomVersion       string   `yaml:"custom-version"`
+	MavenPath           string   `yaml:"maven-path"`
+	MavenBuildCommand   string   `yaml:"maven-build-command"`
+	MavenSettings       string   `yaml:"maven-settings"`
+	PipPath             string   `yaml:"pip-path"`
+	PipRequirementsPath string   `yaml:"pip-requirements-path"`
+	PipConstraintsPath  string   `yaml:"pip-constraints"`
+}
+
+// LoadProjectConfig reads the project-local config in dir, returning nil when there is none
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	path := filepath.Join(dir, ProjectConfigFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var projectConfig ProjectConfig
+	if err := yaml.Unmarshal(data, &projectConfig); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ProjectConfigFile, err)
+	}
+	return &projectConfig, nil
+}
+
+// ApplyProjectConfig applies project-local settings with the lowest precedence:
+// a setting is only used when its flag was not set explicitly (isSet reports that by flag name)
+func (c *ScanConfig) ApplyProjectConfig(pc *ProjectConfig, isSet func(name string) bool) {
+	if pc == nil {
+		return
+	}
+
+	setSlice := func(name string, target *[]string, value []string) {
+		if len(value) > 0 && !isSet(name) {
+			*target = value
+		}
+	}
+	setString := func(name string, target *string, value string) {
+		if value != "" && !isSet(name) {
+			*target = value
+		}
+	}
+
+	setSlice("exclude", &c.ExcludePaths, pc.Exclude)
+	setSlice("exclude-scope", &c.ExcludeScopes, pc.ExcludeScope)
+	setString("custom-project", &c.CustomProject, pc.CustomProject)
+	setString("custom-product", &c.CustomProduct, pc.CustomProduct)
+	setString("custom-version", &c.CustomVersion, pc.CustomVersion)
+	setString("maven-path", &c.MavenPath, pc.MavenPath)
+	setString("maven-build-command", &c.MavenBuildCommand, pc.MavenBuildCommand)
+	setString("maven-settings", &c.MavenSettings, pc.MavenSettings)
+	setString("pip-path", &c.PipPath, pc.PipPath)
+	setString("pip-requirements-path", &c.PipRequirementsPath, pc.PipRequirementsPath)
+	setString("pip-constraints", &c.PipConstraintsPath, pc.PipConstraintsPath)
+}
@@ -8,6 +8,13 @@ import (
 
 var log *logrus.Logger
 
+// Context fields identifying what a log entry is about
+const (
+	FieldScanner = "scanner" // Build tool scanner that logged the entry
+	FieldDir     = "dir"     // Directory being scanned
+	FieldFile    = "file"    // File being read
+)
+
 // InitLogger initializes the global logger with the specified level
 func InitLogger(level string) {
 	log = logrus.New()
@@ -16,6 +23,7 @@ func InitLogger(level string) {
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
+	log.AddHook(redactHook{})
 
 	// Set log level
 	switch level {
@@ -32,6 +40,23 @@ func InitLogger(level string) {
 	}
 }
 
+// SetColorMode sets whether the global logger colors its output: "always" even when output is
+// redirected, "never" not at all, and any other mode only on a terminal
+func SetColorMode(mode string) {
+	formatter, ok := GetLogger().Formatter.(*logrus.TextFormatter)
+	if !ok {
+		return
+	}
+	formatter.ForceColors = mode == "always"
+	formatter.DisableColors = mode == "never"
+}
+
+// WithScanner returns an entry of the global logger carrying the scanner name and directory as
+// context fields. Entries share the logger, which is safe for concurrent use.
+func WithScanner(name, dir string) *logrus.Entry {
+	return GetLogger().WithFields(logrus.Fields{FieldScanner: name, FieldDir: dir})
+}
+
 // GetLogger returns the global logger instance
 func GetLogger() *logrus.Logger {
 	if log == nil {
@@ -91,6 +91,36 @@ func TestLoggerOutput(t *testing.T) {
 	}
 }
 
+func TestSetColorMode(t *testing.T) {
+	tests := []struct {
+		mode      string
+		wantColor bool
+	}{
+		{"always", true},
+		{"never", false},
+		{"auto", false}, // A buffer is not a terminal
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// Reset global logger
+			log = nil
+
+			var buf bytes.Buffer
+			InitLogger("info")
+			SetColorMode(tt.mode)
+			logger := GetLogger()
+			logger.SetOutput(&buf)
+
+			logger.Warn("colored message")
+
+			if hasColor := strings.Contains(buf.String(), "\x1b["); hasColor != tt.wantColor {
+				t.Errorf("Expected ANSI escape codes %v, got: %q", tt.wantColor, buf.String())
+			}
+		})
+	}
+}
+
 func TestLoggerLevels(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -155,6 +185,55 @@ func TestLoggerLevels(t *testing.T) {
 	}
 }
 
+func TestRedact(t *testing.T) {
+	defer func() {
+		redaction.enabled = true
+		redaction.secrets = nil
+	}()
+	SetRedaction(true, "s3cr3t-pass", "", "tok-123")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"login failed: invalid password s3cr3t-pass", "login failed: invalid password ***"},
+		{"Bearer tok-123", "Bearer ***"},
+		{"Server URL: https://admin:pw@scan.example.com/api", "Server URL: https://***@scan.example.com/api"},
+		{"GET http://host/api/auth/verify?token=abc&page=2", "GET http://host/api/auth/verify?token=***&page=2"},
+		{"Server URL: https://scan.example.com/api", "Server URL: https://scan.example.com/api"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.input); got != tt.expected {
+			t.Errorf("Redact(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	SetRedaction(false)
+	if got := Redact("Bearer tok-123"); got != "Bearer tok-123" {
+		t.Errorf("Expected redaction to be disabled, got %q", got)
+	}
+}
+
+func TestLoggerOutput_Redacted(t *testing.T) {
+	defer func() {
+		redaction.enabled = true
+		redaction.secrets = nil
+	}()
+	log = nil
+	SetRedaction(true, "s3cr3t-pass")
+
+	var buf bytes.Buffer
+	InitLogger("info")
+	logger := GetLogger()
+	logger.SetOutput(&buf)
+	logger.WithField("url", "https://admin:pw@scan.example.com").Errorf("login failed: %s", "s3cr3t-pass")
+
+	output := buf.String()
+	if strings.Contains(output, "s3cr3t-pass") || strings.Contains(output, "admin:pw") {
+		t.Errorf("Expected secrets to be redacted, got: %s", output)
+	}
+}
+
 // Benchmark tests
 func BenchmarkGetLogger(b *testing.B) {
 	log = nil
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// redactedValue replaces secrets in log output
+const redactedValue = "***"
+
+var (
+	// urlCredentialsPattern matches the user info of a URL, e.g. "https://user:pass@"
+	urlCredentialsPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#@\s]+@`)
+	// secretParamPattern matches credentials passed as query parameters
+	secretParamPattern = regexp.MustCompile(`(?i)([?&](?:token|access_token|password|passwd|secret|api_key|apikey)=)[^&#\s]+`)
+)
+
+// redaction holds the secrets scrubbed from every log entry
+var redaction = struct {
+	sync.RWMutex
+	enabled bool
+	secrets []string
+}{enabled: true}
+
+// SetRedaction enables or disables log redaction and registers secret values, such as
+// passwords and tokens, that must never appear in log output. Empty values are ignored.
+func SetRedaction(enabled bool, secrets ...string) {
+	redaction.Lock()
+	defer redaction.Unlock()
+	redaction.enabled = enabled
+	for _, secret := range secrets {
+		if secret != "" {
+			redaction.secrets = append(redaction.secrets, secret)
+		}
+	}
+}
+
+// Redact masks registered secrets and URL credentials in s. It returns s unchanged when
+// redaction is disabled.
+func Redact(s string) string {
+	redaction.RLock()
+	defer redaction.RUnlock()
+	if !redaction.enabled {
+		return s
+	}
+
+	for _, secret := range redaction.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	s = urlCredentialsPattern.ReplaceAllString(s, "${1}"+redactedValue+"@")
+	return secretParamPattern.ReplaceAllString(s, "${1}"+redactedValue)
+}
+
+// redactHook scrubs secrets from log messages and string fields before they are written
+type redactHook struct{}
+
+// Levels returns the levels the hook applies to
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the entry in place
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = Redact(v)
+		case error:
+			entry.Data[key] = Redact(v.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package model
+
+// FlattenRoots replaces the dependency tree of every root with a flat list holding each
+// dependency once, children removed. Depth records the shallowest level it was found at,
+// 1 for direct dependencies; the list keeps the order dependencies are first seen in.
+func FlattenRoots(roots []DependencyRoot) []DependencyRoot {
+	for i := range roots {
+		roots[i].Dependencies = flattenDependencies(roots[i].Dependencies)
+	}
+	return roots
+}
+
+// dependencyKey identifies a dependency by type, group, name and version
+type dependencyKey struct {
+	typ, group, name, version string
+}
+
+// flattenDependencies walks a dependency tree depth first, collecting unique dependencies
+func flattenDependencies(dependencies []Dependency) []Dependency {
+	var flat []Dependency
+	index := make(map[dependencyKey]int)
+
+	var walk func(dependencies []Dependency, depth int)
+	walk = func(dependencies []Dependency, depth int) {
+		for _, dep := range dependencies {
+			key := dependencyKey{dep.Type, dep.group(), dep.Name, dep.Version}
+			if i, seen := index[key]; seen {
+				flat[i].Depth = min(flat[i].Depth, depth)
+			} else {
+				index[key] = len(flat)
+				flattened := dep
+				flattened.Children = nil
+				flattened.Depth = depth
+				flat = append(flat, flattened)
+			}
+			walk(dep.Children, depth+1)
+		}
+	}
+	walk(dependencies, 1)
+	return flat
+}
@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+func TestFlattenRoots(t *testing.T) {
+	roots := []DependencyRoot{{
+		BuildTool: "npm",
+		Dependencies: []Dependency{
+			{Name: "express", Version: "4.18.2", Type: "npm", Children: []Dependency{
+				{Name: "body-parser", Version: "1.20.1", Type: "npm", Children: []Dependency{
+					{Name: "qs", Version: "6.11.0", Type: "npm"},
+				}},
+				{Name: "qs", Version: "6.11.0", Type: "npm"},
+			}},
+			{Name: "body-parser", Version: "1.20.1", Type: "npm"},
+			{Name: "qs", Version: "6.9.0", Type: "npm"},
+		},
+	}}
+
+	flat := FlattenRoots(roots)[0].Dependencies
+
+	expected := []struct {
+		name, version string
+		depth         int
+	}{
+		{"express", "4.18.2", 1},
+		{"body-parser", "1.20.1", 1}, // Seen below express first, but also declared directly
+		{"qs", "6.11.0", 2},          // Seen at depth 3 first, then at depth 2
+		{"qs", "6.9.0", 1},           // Another version is another dependency
+	}
+	if len(flat) != len(expected) {
+		t.Fatalf("Expected %d unique dependencies, got %d: %+v", len(expected), len(flat), flat)
+	}
+	for i, want := range expected {
+		dep := flat[i]
+		if dep.Name != want.name || dep.Version != want.version || dep.Depth != want.depth {
+			t.Errorf("Expected %s@%s at depth %d, got %s@%s at depth %d", want.name, want.version, want.depth, dep.Name, dep.Version, dep.Depth)
+		}
+		if dep.Children != nil {
+			t.Errorf("Expected %s to have no children once flattened", dep.Name)
+		}
+	}
+
+	// Dependencies found below another still count as transitive
+	summary := NewRiskSummary([]DependencyRoot{{BuildTool: "npm", Dependencies: flat}})
+	if summary.Direct != 3 || summary.Transitive != 1 {
+		t.Errorf("Expected 3 direct and 1 transitive dependencies, got %+v", summary)
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DependencySchema is the JSON schema of the dependency output, an array of dependency roots
+//
+//go:embed dependencies.schema.json
+var DependencySchema []byte
+
+// ValidateDependencyOutput checks dependency output against DependencySchema. Only the keywords
+// the schema uses are understood: type, properties, required, additionalProperties, items,
+// minimum and local $ref.
+func ValidateDependencyOutput(data []byte) error {
+	var schema map[string]any
+	if err := json.Unmarshal(DependencySchema, &schema); err != nil {
+		return fmt.Errorf("invalid dependency schema: %w", err)
+	}
+
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("invalid dependency output: %w", err)
+	}
+	return schemaValidator{root: schema}.validate(schema, document, "$")
+}
+
+// schemaValidator validates JSON values against the subschemas of a root schema
+type schemaValidator struct {
+	root map[string]any
+}
+
+// validate checks value against schema, naming the offending value by its path on failure
+func (v schemaValidator) validate(schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			return err
+		}
+		return v.validate(resolved, value, path)
+	}
+
+	if want, ok := schema["type"]; ok && !matchesSchemaType(want, value) {
+		return fmt.Errorf("%s: expected %v, got %s", path, want, jsonType(value))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		return v.validateObject(schema, value, path)
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				if err := v.validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case json.Number:
+		if minimum, ok := schema["minimum"].(float64); ok {
+			if number, err := value.Float64(); err == nil && number < minimum {
+				return fmt.Errorf("%s: %s is below the minimum of %v", path, value, minimum)
+			}
+		}
+	}
+	return nil
+}
+
+// validateObject checks the required, declared and additional properties of an object
+func (v schemaValidator) validateObject(schema, object map[string]any, path string) error {
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		if _, ok := object[name.(string)]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, name)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		propertyPath := path + "." + key
+		if property, ok := properties[key].(map[string]any); ok {
+			if err := v.validate(property, object[key], propertyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property", propertyPath)
+			}
+		case map[string]any:
+			if err := v.validate(additional, object[key], propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve looks up a reference to a definition of the root schema, such as #/$defs/root
+func (v schemaValidator) resolve(ref string) (map[string]any, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported schema reference %s", ref)
+	}
+	defs, _ := v.root["$defs"].(map[string]any)
+	schema, ok := defs[name].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unknown schema reference %s", ref)
+	}
+	return schema, nil
+}
+
+// matchesSchemaType reports whether value has the type, or one of the types, a schema allows
+func matchesSchemaType(want any, value any) bool {
+	var types []string
+	switch want := want.(type) {
+	case string:
+		types = []string{want}
+	case []any:
+		for _, typ := range want {
+			if typ, ok := typ.(string); ok {
+				types = append(types, typ)
+			}
+		}
+	}
+
+	actual := jsonType(value)
+	return slices.Contains(types, actual) || (actual == "integer" && slices.Contains(types, "number"))
+}
+
+// jsonType names the JSON schema type of a decoded value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateDependencyOutput(t *testing.T) {
+	// Every field is set so that the schema has to describe all of them
+	roots := []DependencyRoot{{
+		ProjectName:    "web-app",
+		ProjectVersion: "1.0.0",
+		BuildTool:      "npm",
+		Warnings:       []string{"Failed to parse package-lock.json, using declared versions"},
+		IndexURLs:      []string{"https://registry.npmjs.org/"},
+		Engines:        map[string]string{"node": ">=18"},
+		PackageManager: "pnpm@8.6.0",
+		Dependencies: []Dependency{{
+			ID:         &DependencyID{Group: "org.example", Name: "core", Version: "1.2.0", Type: "jar"},
+			Name:       "core",
+			GroupID:    "org.example",
+			Version:    "1.2.0",
+			RawVersion: "v1.2.0",
+			Type:       "maven",
+			RawType:    "jar",
+			Classifier: "jdk8",
+			Scope:      "runtime",
+			RawScope:   "compile",
+			License:    "Apache-2.0",
+			Internal:   true,
+			Depth:      1,
+			Children:   []Dependency{{Name: "util", Version: "2.0.0", Type: "jar"}},
+		}},
+	}, {
+		ProjectName:    "empty",
+		ProjectVersion: "unknown",
+		BuildTool:      "pip",
+	}}
+
+	data, err := json.MarshalIndent(roots, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal dependency roots: %v", err)
+	}
+	if err := ValidateDependencyOutput(data); err != nil {
+		t.Errorf("Expected the dependency output to match the schema, got %v", err)
+	}
+}
+
+func TestValidateDependencyOutput_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		errMsg string
+	}{
+		{
+			name:   "Not an array",
+			output: `{"projectName": "demo"}`,
+			errMsg: "$: expected array",
+		},
+		{
+			name:   "Missing required property",
+			output: `[{"projectName": "demo", "projectVersion": "1.0.0", "dependencies": []}]`,
+			errMsg: `missing required property "buildTool"`,
+		},
+		{
+			name:   "Unknown property",
+			output: `[{"projectName": "demo", "projectVersion": "1.0.0", "buildTool": "npm", "dependencies": null, "license": "MIT"}]`,
+			errMsg: "$[0].license: unexpected property",
+		},
+		{
+			name:   "Wrong nested type",
+			output: `[{"projectName": "demo", "projectVersion": "1.0.0", "buildTool": "npm", "dependencies": [{"id": null, "name": "lodash", "version": 4, "type": "npm"}]}]`,
+			errMsg: "$[0].dependencies[0].version: expected string",
+		},
+		{
+			name:   "Depth below one",
+			output: `[{"projectName": "demo", "projectVersion": "1.0.0", "buildTool": "npm", "dependencies": [{"id": null, "name": "lodash", "version": "4.17.21", "type": "npm", "depth": 0}]}]`,
+			errMsg: "below the minimum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDependencyOutput([]byte(tt.output))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
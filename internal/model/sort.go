@@ -0,0 +1,40 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortDependencies orders the dependencies of every root, and recursively their children, by
+// type, group, name and version so serialized output is stable across runs
+func SortDependencies(roots []DependencyRoot) {
+	for i := range roots {
+		sortDependencyTree(roots[i].Dependencies)
+	}
+}
+
+// sortDependencyTree sorts dependencies and their children in place
+func sortDependencyTree(dependencies []Dependency) {
+	slices.SortStableFunc(dependencies, compareDependencies)
+	for i := range dependencies {
+		sortDependencyTree(dependencies[i].Children)
+	}
+}
+
+// compareDependencies orders two dependencies by type, group, name and version
+func compareDependencies(a, b Dependency) int {
+	return cmp.Or(
+		cmp.Compare(a.Type, b.Type),
+		cmp.Compare(a.group(), b.group()),
+		cmp.Compare(a.Name, b.Name),
+		cmp.Compare(a.Version, b.Version),
+	)
+}
+
+// group returns the dependency group, preferring the one in its ID
+func (d Dependency) group() string {
+	if d.ID != nil && d.ID.Group != "" {
+		return d.ID.Group
+	}
+	return d.GroupID
+}
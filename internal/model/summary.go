@@ -0,0 +1,47 @@
+package model
+
+import "strings"
+
+// RiskSummary counts dependencies by how they are reached and whether their version is known,
+// a quick measure of scan completeness that needs no vulnerability data
+type RiskSummary struct {
+	Total       int            `json:"total"`
+	Direct      int            `json:"direct"`
+	Transitive  int            `json:"transitive"`
+	Unknown     int            `json:"unknown"`     // Dependencies with an empty or "unknown" version
+	ByEcosystem map[string]int `json:"byEcosystem"` // Totals keyed by the build tool of their root
+}
+
+// NewRiskSummary computes the risk summary of the given dependency roots
+func NewRiskSummary(roots []DependencyRoot) *RiskSummary {
+	summary := &RiskSummary{ByEcosystem: make(map[string]int)}
+
+	var count func(buildTool string, dependencies []Dependency, direct bool)
+	count = func(buildTool string, dependencies []Dependency, direct bool) {
+		for _, dep := range dependencies {
+			summary.Total++
+			summary.ByEcosystem[buildTool]++
+			// Flattened dependencies keep the level they were found at in Depth
+			if direct && dep.Depth <= 1 {
+				summary.Direct++
+			} else {
+				summary.Transitive++
+			}
+			if IsUnknownVersion(dep.Version) {
+				summary.Unknown++
+			}
+			count(buildTool, dep.Children, false)
+		}
+	}
+
+	for _, root := range roots {
+		count(root.BuildTool, root.Dependencies, true)
+	}
+	return summary
+}
+
+// IsUnknownVersion reports whether a version is empty or "unknown"
+func IsUnknownVersion(version string) bool {
+	version = strings.TrimSpace(version)
+	return version == "" || strings.EqualFold(version, "unknown")
+}
@@ -0,0 +1,34 @@
+package model
+
+import "testing"
+
+func TestNewRiskSummary(t *testing.T) {
+	roots := []DependencyRoot{
+		{
+			BuildTool: "npm",
+			Dependencies: []Dependency{
+				{Name: "express", Version: "4.18.2", Children: []Dependency{
+					{Name: "body-parser", Version: "unknown"},
+					{Name: "qs", Version: "6.11.0", Children: []Dependency{{Name: "side-channel", Version: ""}}},
+				}},
+				{Name: "lodash", Version: "4.17.21"},
+			},
+		},
+		{
+			BuildTool:    "maven",
+			Dependencies: []Dependency{{Name: "guava", Version: "Unknown"}},
+		},
+	}
+
+	summary := NewRiskSummary(roots)
+
+	if summary.Total != 6 || summary.Direct != 3 || summary.Transitive != 3 {
+		t.Errorf("Expected 6 dependencies, 3 direct and 3 transitive, got %+v", summary)
+	}
+	if summary.Unknown != 3 {
+		t.Errorf("Expected 3 dependencies with an unknown version, got %d", summary.Unknown)
+	}
+	if summary.ByEcosystem["npm"] != 5 || summary.ByEcosystem["maven"] != 1 || len(summary.ByEcosystem) != 2 {
+		t.Errorf("Expected 5 npm and 1 maven dependencies, got %v", summary.ByEcosystem)
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/craftslab/cleansource-sca-cli/internal/config"
+import (
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
 
 // UploadData represents data to be uploaded to the server
 type UploadData struct {
@@ -9,17 +13,50 @@ type UploadData struct {
 	ArchiveFile string             `json:"archiveFile"`
 	Config      *config.ScanConfig `json:"config"`
 	DirSize     int64              `json:"dirSize"`
+
+	// Heuristic project description, set when no build manifest was found
+	DetectedProject string   `json:"detectedProject,omitempty"`
+	Languages       []string `json:"languages,omitempty"` // Dominant languages, most common first
+
+	// License and NOTICE files found in the scan directory
+	LicenseFiles *FilePathCollect `json:"licenseFiles,omitempty"`
+
+	// The fingerprints match the last upload, so WfpFile and ArchiveFile are left empty
+	WfpUnchanged bool `json:"wfpUnchanged,omitempty"`
+
+	// Only the files of --files-from were fingerprinted, making the WFP file partial
+	WfpPartial bool `json:"wfpPartial,omitempty"`
+
+	// Modification time --since limited fingerprinting to, making the WFP file partial; nil when complete
+	WfpSince *time.Time `json:"wfpSince,omitempty"`
+
+	// Number of WFP chunks uploaded after the scan in place of WfpFile, 0 when not split
+	WfpChunks int `json:"wfpChunks,omitempty"`
+
+	// How the scan was run (full, incremental, deps-only, binary or docker), for the server to merge results
+	ScanMode string `json:"scanMode,omitempty"`
+
+	// Git commit of the scan directory and whether it had uncommitted changes, empty outside a repository
+	Commit string `json:"commit,omitempty"`
+	Dirty  bool   `json:"dirty,omitempty"`
 }
 
 // Dependency represents a single dependency
 type Dependency struct {
-	ID       *DependencyID `json:"id"`
-	Name     string        `json:"name"`
-	GroupID  string        `json:"groupId,omitempty"` // Add GroupID for compatibility
-	Version  string        `json:"version"`
-	Type     string        `json:"type"`
-	Scope    string        `json:"scope,omitempty"`
-	Children []Dependency  `json:"children,omitempty"`
+	ID         *DependencyID `json:"id"`
+	Name       string        `json:"name"`
+	GroupID    string        `json:"groupId,omitempty"` // Add GroupID for compatibility
+	Version    string        `json:"version"`
+	RawVersion string        `json:"rawVersion,omitempty"` // Version as declared, before --normalize-versions
+	Type       string        `json:"type"`
+	RawType    string        `json:"rawType,omitempty"` // Type as reported by the scanner, before --normalize-types
+	Classifier string        `json:"classifier,omitempty"` // Maven artifact classifier, e.g. sources or jdk8
+	Scope      string        `json:"scope,omitempty"`
+	RawScope   string        `json:"rawScope,omitempty"` // Scope as reported by the build tool
+	License    string        `json:"license,omitempty"`  // Declared license or SPDX expression, from lockfiles that record one
+	Internal   bool          `json:"internal,omitempty"` // First-party package matched by --internal-pattern
+	Depth      int           `json:"depth,omitempty"`    // Shallowest tree level, 1 for direct; only set by --flatten-deps
+	Children   []Dependency  `json:"children,omitempty"`
 }
 
 // DependencyID represents a unique identifier for a dependency
@@ -36,6 +73,12 @@ type DependencyRoot struct {
 	ProjectVersion string       `json:"projectVersion"`
 	BuildTool      string       `json:"buildTool"`
 	Dependencies   []Dependency `json:"dependencies"`
+	Warnings       []string     `json:"warnings,omitempty"`  // Non-fatal problems found while scanning
+	IndexURLs      []string     `json:"indexUrls,omitempty"` // Package indexes the dependencies resolve from, credentials redacted
+
+	// Runtime the project is meant for, such as package.json engines and packageManager
+	Engines        map[string]string `json:"engines,omitempty"`        // Version constraints keyed by runtime, e.g. node: >=18
+	PackageManager string            `json:"packageManager,omitempty"` // Pinned package manager, e.g. pnpm@8.6.0
 }
 
 // ScanType represents different types of scans
@@ -57,6 +100,7 @@ const (
 // FilePathCollect represents collected file paths during scanning
 type FilePathCollect struct {
 	ProjectLicenseFiles []string `json:"projectLicenseFiles"`
+	NoticeFiles         []string `json:"noticeFiles"` // Attribution files, kept apart from licenses
 	SourceFiles         []string `json:"sourceFiles"`
 	BinaryFiles         []string `json:"binaryFiles"`
 }
@@ -0,0 +1,132 @@
+// Package report renders local scan results for human review without a server.
+package report
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// LanguageFiles is the number of source files written in one language
+type LanguageFiles struct {
+	Language string
+	Files    int
+}
+
+// Data is the content of a local scan report
+type Data struct {
+	ProjectName  string
+	TaskDir      string
+	GeneratedAt  time.Time
+	Commit       string   // Git commit scanned, empty outside a repository
+	Dirty        bool     // The scanned directory had uncommitted changes
+	Tools        []string // Detected build tools
+	Files        int      // Files that would be fingerprinted
+	SourceFiles  int      // Files with a recognized source extension
+	Languages    []LanguageFiles
+	Dependencies []model.DependencyRoot
+}
+
+// Warnings returns the warnings of every dependency root, prefixed with the project they belong to
+func (d *Data) Warnings() []string {
+	var warnings []string
+	for _, root := range d.Dependencies {
+		for _, warning := range root.Warnings {
+			warnings = append(warnings, root.ProjectName+": "+warning)
+		}
+	}
+	return warnings
+}
+
+// DependencyCount returns the number of dependencies in all roots, including transitive ones
+func (d *Data) DependencyCount() int {
+	return d.RiskSummary().Total
+}
+
+// RiskSummary returns the dependency counts of all roots
+func (d *Data) RiskSummary() *model.RiskSummary {
+	return model.NewRiskSummary(d.Dependencies)
+}
+
+// htmlTemplate renders a self-contained page; html/template escapes every value it inserts
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Scan report: {{.ProjectName}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+th { background: #f3f3f3; }
+ul.tree { list-style: none; padding-left: 1.2em; }
+.scope { color: #666; font-size: 0.9em; }
+.warning { color: #a35200; }
+</style>
+</head>
+<body>
+<h1>Scan report: {{.ProjectName}}</h1>
+<table>
+<tr><th>Directory</th><td>{{.TaskDir}}</td></tr>
+{{if .Commit}}<tr><th>Git commit</th><td>{{.Commit}}{{if .Dirty}} <span class="warning">(uncommitted changes)</span>{{end}}</td></tr>
+{{end}}<tr><th>Generated</th><td>{{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><th>Build tools</th><td>{{range $i, $tool := .Tools}}{{if $i}}, {{end}}{{$tool}}{{else}}none detected{{end}}</td></tr>
+<tr><th>Files</th><td>{{.Files}} ({{.SourceFiles}} source files)</td></tr>
+<tr><th>Dependencies</th><td>{{.DependencyCount}}</td></tr>
+{{with .RiskSummary}}{{if .Total}}<tr><th>Direct / transitive</th><td>{{.Direct}} / {{.Transitive}}</td></tr>
+<tr><th>Unknown versions</th><td>{{.Unknown}}</td></tr>
+<tr><th>By ecosystem</th><td>{{range $ecosystem, $count := .ByEcosystem}}{{$ecosystem}}: {{$count}} {{end}}</td></tr>
+{{end}}{{end}}
+</table>
+{{with .Languages}}
+<h2>Languages</h2>
+<table>
+<tr><th>Language</th><th>Files</th></tr>
+{{range .}}<tr><td>{{.Language}}</td><td>{{.Files}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Warnings}}
+<h2>Warnings</h2>
+<ul>
+{{range .}}<li class="warning">{{.}}</li>
+{{end}}</ul>
+{{end}}
+<h2>Dependencies</h2>
+{{range .Dependencies}}
+<h3>{{.ProjectName}} {{.ProjectVersion}} <span class="scope">({{.BuildTool}})</span></h3>
+{{template "tree" .Dependencies}}
+{{else}}
+<p>No dependencies found.</p>
+{{end}}
+</body>
+</html>
+{{define "tree"}}{{if .}}<ul class="tree">
+{{range .}}<li>{{.Name}} {{.Version}}{{with .Scope}} <span class="scope">{{.}}</span>{{end}}{{template "tree" .Children}}</li>
+{{end}}</ul>{{end}}{{end}}
+`))
+
+// WriteHTML renders data as an HTML page to w
+func WriteHTML(w io.Writer, data *Data) error {
+	return htmlTemplate.Execute(w, data)
+}
+
+// WriteHTMLFile renders data as an HTML page to the file at path
+func WriteHTMLFile(path string, data *Data) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	return WriteHTML(file, data)
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestWriteHTML(t *testing.T) {
+	data := &Data{
+		ProjectName: "demo",
+		TaskDir:     "/src/demo",
+		GeneratedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Commit:      "3f9c2a1d8e7b6c5a4f3e2d1c0b9a8f7e6d5c4b3a",
+		Dirty:       true,
+		Tools:       []string{"npm"},
+		Files:       12,
+		SourceFiles: 9,
+		Languages:   []LanguageFiles{{Language: "javascript", Files: 9}},
+		Dependencies: []model.DependencyRoot{{
+			ProjectName:    "demo",
+			ProjectVersion: "1.0.0",
+			BuildTool:      "npm",
+			Dependencies: []model.Dependency{{
+				Name:     "express",
+				Version:  "4.18.2",
+				Scope:    "runtime",
+				Children: []model.Dependency{{Name: "body-parser", Version: "1.20.1"}},
+			}, {
+				Name:    "<script>alert(1)</script>",
+				Version: "0.0.1",
+			}},
+			Warnings: []string{"stale lockfile: package-lock.json is missing left-pad"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, data); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	output := buf.String()
+
+	if _, err := html.Parse(strings.NewReader(output)); err != nil {
+		t.Fatalf("Report is not valid HTML: %v", err)
+	}
+	for _, expected := range []string{"express", "4.18.2", "body-parser", "javascript", "left-pad", "<td>3</td>",
+		"3f9c2a1d8e7b6c5a4f3e2d1c0b9a8f7e6d5c4b3a", "uncommitted changes"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected report to contain %q", expected)
+		}
+	}
+	if strings.Contains(output, "<script>alert(1)</script>") {
+		t.Error("Dependency names must be escaped")
+	}
+}
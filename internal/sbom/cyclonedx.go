@@ -0,0 +1,197 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+const (
+	// toolName identifies this scanner in generated documents
+	toolName = "cleansource-sca-cli"
+	// CycloneDXSpecVersion is the CycloneDX specification version produced
+	CycloneDXSpecVersion = "1.5"
+)
+
+// CycloneDXBOM is a CycloneDX JSON document
+type CycloneDXBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	Version      int                   `json:"version"`
+	Metadata     CycloneDXMetadata     `json:"metadata"`
+	Components   []CycloneDXComponent  `json:"components"`
+	Dependencies []CycloneDXDependency `json:"dependencies"`
+}
+
+// CycloneDXMetadata describes how the BOM was produced
+type CycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp"`
+	Tools     CycloneDXTools      `json:"tools"`
+	Component *CycloneDXComponent `json:"component,omitempty"` // Subject of the BOM, set by other producers
+}
+
+// CycloneDXTool names a tool that produced the BOM
+type CycloneDXTool struct {
+	Name string `json:"name"`
+}
+
+// CycloneDXTools lists the tools that produced the BOM. It is written as the legacy array;
+// reading also accepts the object CycloneDX 1.5 introduced, whose tools are split into
+// components and services.
+type CycloneDXTools []CycloneDXTool
+
+// UnmarshalJSON reads tools given as an array or as an object of components and services
+func (tools *CycloneDXTools) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return json.Unmarshal(data, (*[]CycloneDXTool)(tools))
+	}
+
+	var object struct {
+		Components []CycloneDXTool `json:"components"`
+		Services   []CycloneDXTool `json:"services"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*tools = append(object.Components, object.Services...)
+	return nil
+}
+
+// CycloneDXComponent is a project or library in the BOM
+type CycloneDXComponent struct {
+	Type     string                   `json:"type"`
+	BOMRef   string                   `json:"bom-ref"`
+	Group    string                   `json:"group,omitempty"`
+	Name     string                   `json:"name"`
+	Version  string                   `json:"version,omitempty"`
+	Scope    string                   `json:"scope,omitempty"`
+	Licenses []CycloneDXLicenseChoice `json:"licenses,omitempty"`
+	PURL     string                   `json:"purl,omitempty"`
+}
+
+// CycloneDXLicenseChoice is a license of a component, given either as a license or as an SPDX
+// expression. Licenses are written as expressions; both forms are read.
+type CycloneDXLicenseChoice struct {
+	License    *CycloneDXLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+// CycloneDXLicense is a license named by SPDX identifier or by free-form name
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// CycloneDXDependency lists the direct dependencies of a component
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cycloneDXScopes maps canonical dependency scopes to CycloneDX component scopes
+var cycloneDXScopes = map[string]string{
+	"runtime":     "required",
+	"provided":    "required",
+	"optional":    "optional",
+	"development": "excluded",
+	"test":        "excluded",
+}
+
+// ToCycloneDX converts dependency roots into a CycloneDX BOM.
+// Each root becomes an application component depending on its dependency tree.
+func ToCycloneDX(roots []model.DependencyRoot) *CycloneDXBOM {
+	bom := &CycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: CycloneDXSpecVersion,
+		Version:     1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     CycloneDXTools{{Name: toolName}},
+		},
+		Components:   []CycloneDXComponent{},
+		Dependencies: []CycloneDXDependency{},
+	}
+
+	seen := make(map[string]bool)
+	graph := make(map[string][]string)
+	var order []string
+
+	addEdge := func(from, to string) {
+		if _, ok := graph[from]; !ok {
+			order = append(order, from)
+		}
+		graph[from] = append(graph[from], to)
+	}
+
+	var walk func(buildTool, parent string, dependencies []model.Dependency)
+	walk = func(buildTool, parent string, dependencies []model.Dependency) {
+		for _, dep := range dependencies {
+			ref := componentRef(buildTool, dep)
+			addEdge(parent, ref)
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			group, name := dependencyCoordinates(dep)
+			bom.Components = append(bom.Components, CycloneDXComponent{
+				Type:     "library",
+				BOMRef:   ref,
+				Group:    group,
+				Name:     name,
+				Version:  dep.Version,
+				Scope:    cycloneDXScopes[dep.Scope],
+				Licenses: cycloneDXLicenses(dep.License),
+				PURL:     PackageURL(buildTool, dep),
+			})
+			walk(buildTool, ref, dep.Children)
+		}
+	}
+
+	for _, root := range roots {
+		ref := rootRef(root)
+		if !seen[ref] {
+			seen[ref] = true
+			bom.Components = append(bom.Components, CycloneDXComponent{
+				Type:    "application",
+				BOMRef:  ref,
+				Name:    root.ProjectName,
+				Version: root.ProjectVersion,
+			})
+		}
+		if _, ok := graph[ref]; !ok {
+			order = append(order, ref)
+			graph[ref] = []string{}
+		}
+		walk(root.BuildTool, ref, root.Dependencies)
+	}
+
+	for _, ref := range order {
+		bom.Dependencies = append(bom.Dependencies, CycloneDXDependency{Ref: ref, DependsOn: uniqueRefs(graph[ref])})
+	}
+
+	return bom
+}
+
+// cycloneDXLicenses returns the license choices of a declared license, nil when none is known
+func cycloneDXLicenses(license string) []CycloneDXLicenseChoice {
+	if license == "" {
+		return nil
+	}
+	return []CycloneDXLicenseChoice{{Expression: license}}
+}
+
+// uniqueRefs removes duplicate references while keeping their order
+func uniqueRefs(refs []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
@@ -0,0 +1,310 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// ErrUnknownFormat is returned for documents that are neither CycloneDX nor SPDX JSON
+var ErrUnknownFormat = errors.New("unrecognized SBOM format, expected CycloneDX or SPDX JSON")
+
+// purlBuildTools maps package URL types back to the build tool recorded on imported roots
+var purlBuildTools = map[string]string{
+	"maven":    "maven",
+	"npm":      "npm",
+	"golang":   "go",
+	"pypi":     "pip",
+	"cargo":    "cargo",
+	"composer": "composer",
+	"nuget":    "dotnet",
+}
+
+// canonicalScopes maps CycloneDX component scopes back to canonical dependency scopes
+var canonicalScopes = map[string]string{
+	"required": "runtime",
+	"optional": "optional",
+	"excluded": "test",
+}
+
+// ReadFile reads a CycloneDX or SPDX JSON document and converts it into dependency roots
+func ReadFile(path string) ([]model.DependencyRoot, error) {
+	data, err := utils.ReadTextFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse converts a CycloneDX or SPDX JSON document into dependency roots, detecting the
+// format from its header fields
+func Parse(data []byte) ([]model.DependencyRoot, error) {
+	var header struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+
+	switch {
+	case header.BOMFormat == "CycloneDX":
+		var bom CycloneDXBOM
+		if err := json.Unmarshal(data, &bom); err != nil {
+			return nil, fmt.Errorf("failed to parse CycloneDX SBOM: %w", err)
+		}
+		return FromCycloneDX(&bom), nil
+	case strings.HasPrefix(header.SPDXVersion, "SPDX-"):
+		var doc SPDXDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse SPDX SBOM: %w", err)
+		}
+		return FromSPDX(&doc), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// FromCycloneDX converts a CycloneDX BOM into dependency roots. Application components that
+// no other component depends on become roots; without any, the BOM's libraries hang off a
+// single root named after the metadata component.
+func FromCycloneDX(bom *CycloneDXBOM) []model.DependencyRoot {
+	components := make(map[string]CycloneDXComponent)
+	for _, component := range bom.Components {
+		components[component.BOMRef] = component
+	}
+
+	graph := make(map[string][]string)
+	dependedOn := make(map[string]bool)
+	for _, dependency := range bom.Dependencies {
+		graph[dependency.Ref] = dependency.DependsOn
+		for _, ref := range dependency.DependsOn {
+			dependedOn[ref] = true
+		}
+	}
+
+	componentPURL := func(ref string) string { return components[ref].PURL }
+	convert := func(component CycloneDXComponent) model.Dependency {
+		dep := importedDependency(component.Group, component.Name, component.Version, component.PURL)
+		dep.Scope = canonicalScopes[component.Scope]
+		dep.License = componentLicense(component.Licenses)
+		return dep
+	}
+
+	var roots []model.DependencyRoot
+	for _, component := range bom.Components {
+		if component.Type != "application" || dependedOn[component.BOMRef] {
+			continue
+		}
+		root := model.DependencyRoot{
+			ProjectName:    component.Name,
+			ProjectVersion: component.Version,
+			BuildTool:      rootBuildTool(component.BOMRef),
+		}
+		root.Dependencies = importTree(component.BOMRef, graph, components, convert)
+		if root.BuildTool == "" {
+			root.BuildTool = refsBuildTool(graph[component.BOMRef], componentPURL)
+		}
+		roots = append(roots, root)
+	}
+
+	if len(roots) == 0 {
+		root := model.DependencyRoot{}
+		if meta := bom.Metadata.Component; meta != nil {
+			root.ProjectName, root.ProjectVersion = meta.Name, meta.Version
+		}
+		var refs []string
+		for _, component := range bom.Components {
+			if component.Type != "application" {
+				root.Dependencies = append(root.Dependencies, convert(component))
+				refs = append(refs, component.BOMRef)
+			}
+		}
+		root.BuildTool = refsBuildTool(refs, componentPURL)
+		roots = append(roots, root)
+	}
+
+	return roots
+}
+
+// FromSPDX converts an SPDX document into dependency roots. Packages the document describes
+// become roots, with DEPENDS_ON and DEPENDENCY_OF relationships forming the trees below them.
+func FromSPDX(doc *SPDXDocument) []model.DependencyRoot {
+	packages := make(map[string]SPDXPackage)
+	for _, pkg := range doc.Packages {
+		packages[pkg.SPDXID] = pkg
+	}
+
+	var described []string
+	graph := make(map[string][]string)
+	for _, relationship := range doc.Relationships {
+		from, to := relationship.SPDXElementID, relationship.RelatedSPDXElement
+		switch relationship.RelationshipType {
+		case "DESCRIBES":
+			if from == doc.SPDXID {
+				described = append(described, to)
+			}
+		case "DEPENDS_ON":
+			graph[from] = append(graph[from], to)
+		case "DEPENDENCY_OF":
+			graph[to] = append(graph[to], from)
+		}
+	}
+
+	convert := func(pkg SPDXPackage) model.Dependency {
+		// SPDX has no group field, so Maven coordinates come from the purl
+		purl := packagePURL(pkg)
+		var dep model.Dependency
+		if purlType, namespace, name, ok := parsePURL(purl); ok && purlType == "maven" {
+			dep = importedDependency(namespace, name, pkg.VersionInfo, purl)
+		} else {
+			dep = importedDependency("", pkg.Name, pkg.VersionInfo, purl)
+		}
+		// NOASSERTION and NONE state that no license is known
+		if pkg.LicenseDeclared != "NOASSERTION" && pkg.LicenseDeclared != "NONE" {
+			dep.License = pkg.LicenseDeclared
+		}
+		return dep
+	}
+
+	var roots []model.DependencyRoot
+	for _, id := range described {
+		pkg, ok := packages[id]
+		if !ok {
+			continue
+		}
+		root := model.DependencyRoot{ProjectName: pkg.Name, ProjectVersion: pkg.VersionInfo}
+		root.Dependencies = importTree(id, graph, packages, convert)
+		root.BuildTool = refsBuildTool(graph[id], func(ref string) string { return packagePURL(packages[ref]) })
+		roots = append(roots, root)
+	}
+
+	return roots
+}
+
+// importTree builds the dependency tree below ref, cutting cycles at the first repeat on a path
+func importTree[T any](ref string, graph map[string][]string, elements map[string]T, convert func(T) model.Dependency) []model.Dependency {
+	var walk func(ref string, path map[string]bool) []model.Dependency
+	walk = func(ref string, path map[string]bool) []model.Dependency {
+		var dependencies []model.Dependency
+		for _, childRef := range graph[ref] {
+			element, ok := elements[childRef]
+			if !ok || path[childRef] {
+				continue
+			}
+			path[childRef] = true
+			dep := convert(element)
+			dep.Children = walk(childRef, path)
+			delete(path, childRef)
+			dependencies = append(dependencies, dep)
+		}
+		return dependencies
+	}
+	return walk(ref, map[string]bool{ref: true})
+}
+
+// importedDependency builds a dependency from SBOM fields, taking missing coordinates from its purl
+func importedDependency(group, name, version, purl string) model.Dependency {
+	if purlType, namespace, purlName, ok := parsePURL(purl); ok && name == "" {
+		if purlType == "maven" {
+			group, name = namespace, purlName
+		} else {
+			name = strings.TrimPrefix(namespace+"/"+purlName, "/")
+		}
+	}
+
+	dep := model.Dependency{Name: name, Version: version}
+	if group != "" {
+		dep.GroupID = group
+		dep.ID = &model.DependencyID{Group: group, Name: name, Version: version}
+	}
+	return dep
+}
+
+// componentLicense joins the licenses of a CycloneDX component into one SPDX expression, using
+// license names where no identifier is given
+func componentLicense(choices []CycloneDXLicenseChoice) string {
+	var licenses []string
+	for _, choice := range choices {
+		switch {
+		case choice.Expression != "":
+			licenses = append(licenses, choice.Expression)
+		case choice.License != nil && choice.License.ID != "":
+			licenses = append(licenses, choice.License.ID)
+		case choice.License != nil && choice.License.Name != "":
+			licenses = append(licenses, choice.License.Name)
+		}
+	}
+	return strings.Join(licenses, " AND ")
+}
+
+// packagePURL returns the purl external reference of an SPDX package
+func packagePURL(pkg SPDXPackage) string {
+	for _, ref := range pkg.ExternalRefs {
+		if ref.ReferenceType == "purl" {
+			return ref.ReferenceLocator
+		}
+	}
+	return ""
+}
+
+// parsePURL splits a package URL into its type, namespace and name, ignoring the version,
+// qualifiers and subpath
+func parsePURL(purl string) (string, string, string, bool) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return "", "", "", false
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		rest = rest[:at]
+	}
+
+	purlType, path, ok := strings.Cut(rest, "/")
+	if !ok {
+		return "", "", "", false
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	purlType = strings.ToLower(purlType)
+	if purlType == "golang" {
+		namespace, name := goModuleCoordinates(strings.Join(segments, "/"))
+		return purlType, namespace, name, name != ""
+	}
+
+	name := segments[len(segments)-1]
+	namespace := strings.Join(segments[:len(segments)-1], "/")
+	return purlType, namespace, name, name != ""
+}
+
+// rootBuildTool recovers the build tool from a project reference written by ToCycloneDX
+func rootBuildTool(ref string) string {
+	rest, ok := strings.CutPrefix(ref, "project:")
+	if !ok {
+		return ""
+	}
+	buildTool, _, _ := strings.Cut(rest, ":")
+	return buildTool
+}
+
+// refsBuildTool returns the build tool of the first referenced element with a known purl type
+func refsBuildTool(refs []string, purlOf func(string) string) string {
+	for _, ref := range refs {
+		purlType, _, _, _ := parsePURL(purlOf(ref))
+		if buildTool, ok := purlBuildTools[purlType]; ok {
+			return buildTool
+		}
+	}
+	return ""
+}
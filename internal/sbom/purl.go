@@ -0,0 +1,135 @@
+package sbom
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// purlTypes maps build tools to package URL types
+var purlTypes = map[string]string{
+	"maven":    "maven",
+	"gradle":   "maven",
+	"npm":      "npm",
+	"go":       "golang",
+	"pip":      "pypi",
+	"pipenv":   "pypi",
+	"cargo":    "cargo",
+	"composer": "composer",
+	"dotnet":   "nuget",
+}
+
+// goMajorVersionPattern matches the major version suffix of a Go module path at v2 or later,
+// e.g. the "v2" of github.com/foo/bar/v2
+var goMajorVersionPattern = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+
+// goModuleCoordinates splits a Go module path into the purl namespace and name. A major version
+// suffix is part of the module name, not a subpackage, so github.com/foo/bar/v2 is named "bar/v2"
+// in the github.com/foo namespace.
+func goModuleCoordinates(modulePath string) (string, string) {
+	elements := strings.Split(modulePath, "/")
+	last := len(elements) - 1
+	if last >= 2 && goMajorVersionPattern.MatchString(elements[last]) {
+		last--
+	}
+	return strings.Join(elements[:last], "/"), strings.Join(elements[last:], "/")
+}
+
+// goModuleVersion returns a Go module version with the "v" prefix that --normalize-versions strips
+func goModuleVersion(version string) string {
+	if version != "" && version[0] >= '0' && version[0] <= '9' {
+		return "v" + version
+	}
+	return version
+}
+
+// PackageURL returns the package URL (purl) of a dependency found by the given
+// build tool, or an empty string when the ecosystem has no purl type
+func PackageURL(buildTool string, dep model.Dependency) string {
+	purlType, ok := purlTypes[buildTool]
+	if !ok || dep.Name == "" {
+		return ""
+	}
+
+	group, name := dependencyCoordinates(dep)
+	if purlType == "maven" && group == "" {
+		if parts := strings.SplitN(name, ":", 2); len(parts) == 2 {
+			group, name = parts[0], parts[1]
+		}
+	}
+	if purlType == "pypi" {
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+
+	version := dep.Version
+	if purlType == "golang" {
+		group, name = goModuleCoordinates(name)
+		version = goModuleVersion(version)
+	}
+
+	path := name
+	if group != "" {
+		path = group + "/" + name
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		segments = append(segments, strings.ReplaceAll(url.PathEscape(segment), "@", "%40"))
+	}
+
+	purl := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version != "" && version != "unknown" {
+		purl += "@" + url.PathEscape(version)
+	}
+	if purlType == "maven" {
+		purl += mavenQualifiers(buildTool, dep)
+	}
+	return purl
+}
+
+// mavenQualifiers returns the purl qualifiers that tell apart artifacts sharing Maven
+// coordinates, sorted by key as the purl spec requires. The default jar type is omitted, and
+// the packaging is read from RawType once --normalize-types replaced it with the ecosystem.
+func mavenQualifiers(buildTool string, dep model.Dependency) string {
+	var qualifiers []string
+	if dep.Classifier != "" {
+		qualifiers = append(qualifiers, "classifier="+url.QueryEscape(dep.Classifier))
+	}
+	packaging := dep.Type
+	if dep.RawType != "" {
+		packaging = dep.RawType
+	}
+	if buildTool == "maven" && packaging != "" && packaging != "jar" {
+		qualifiers = append(qualifiers, "type="+url.QueryEscape(packaging))
+	}
+	if len(qualifiers) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(qualifiers, "&")
+}
+
+// dependencyCoordinates returns the group and name of a dependency
+func dependencyCoordinates(dep model.Dependency) (string, string) {
+	group := dep.GroupID
+	if dep.ID != nil && dep.ID.Group != "" {
+		group = dep.ID.Group
+	}
+	return group, dep.Name
+}
+
+// componentRef returns a stable reference for a dependency, preferring its purl
+func componentRef(buildTool string, dep model.Dependency) string {
+	if purl := PackageURL(buildTool, dep); purl != "" {
+		return purl
+	}
+	group, name := dependencyCoordinates(dep)
+	return fmt.Sprintf("%s:%s:%s@%s", buildTool, group, name, dep.Version)
+}
+
+// rootRef returns a stable reference for a scanned project
+func rootRef(root model.DependencyRoot) string {
+	return fmt.Sprintf("project:%s:%s@%s", root.BuildTool, root.ProjectName, root.ProjectVersion)
+}
@@ -0,0 +1,251 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		buildTool string
+		dep       model.Dependency
+		expected  string
+	}{
+		{"maven", model.Dependency{ID: &model.DependencyID{Group: "org.apache.commons"}, Name: "commons-lang3", Version: "3.12.0"}, "pkg:maven/org.apache.commons/commons-lang3@3.12.0"},
+		{"maven", model.Dependency{ID: &model.DependencyID{Group: "org.example"}, Name: "lib", Version: "1.0", Type: "jar", Classifier: "jdk8"}, "pkg:maven/org.example/lib@1.0?classifier=jdk8"},
+		{"maven", model.Dependency{ID: &model.DependencyID{Group: "org.example"}, Name: "app", Version: "1.0", Type: "war", Classifier: "sources"}, "pkg:maven/org.example/app@1.0?classifier=sources&type=war"},
+		{"maven", model.Dependency{ID: &model.DependencyID{Group: "org.example"}, Name: "app", Version: "1.0", Type: "maven", RawType: "war"}, "pkg:maven/org.example/app@1.0?type=war"},
+		{"maven", model.Dependency{ID: &model.DependencyID{Group: "org.example"}, Name: "lib", Version: "1.0", Type: "maven", RawType: "jar"}, "pkg:maven/org.example/lib@1.0"},
+		{"gradle", model.Dependency{Name: "org.springframework:spring-core", Version: "5.3.21"}, "pkg:maven/org.springframework/spring-core@5.3.21"},
+		{"npm", model.Dependency{Name: "express", Version: "4.18.2"}, "pkg:npm/express@4.18.2"},
+		{"npm", model.Dependency{Name: "@types/node", Version: "^20.1.0"}, "pkg:npm/%40types/node@%5E20.1.0"},
+		{"go", model.Dependency{Name: "github.com/gin-gonic/gin", Version: "v1.9.1"}, "pkg:golang/github.com/gin-gonic/gin@v1.9.1"},
+		{"go", model.Dependency{Name: "github.com/foo/bar/v2", Version: "v2.1.0"}, "pkg:golang/github.com/foo/bar/v2@v2.1.0"},
+		{"go", model.Dependency{Name: "github.com/foo/bar/v2", Version: "2.1.0"}, "pkg:golang/github.com/foo/bar/v2@v2.1.0"},
+		{"pip", model.Dependency{Name: "Typing_Extensions", Version: "4.8.0"}, "pkg:pypi/typing-extensions@4.8.0"},
+		{"cargo", model.Dependency{Name: "serde", Version: "unknown"}, "pkg:cargo/serde"},
+		{"composer", model.Dependency{Name: "monolog/monolog", Version: "3.0.0"}, "pkg:composer/monolog/monolog@3.0.0"},
+		{"unknown", model.Dependency{Name: "thing", Version: "1.0"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := PackageURL(tt.buildTool, tt.dep); got != tt.expected {
+			t.Errorf("PackageURL(%s, %s) = %s, want %s", tt.buildTool, tt.dep.Name, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePURL_GoMajorVersion(t *testing.T) {
+	tests := []struct {
+		purl      string
+		namespace string
+		name      string
+	}{
+		{"pkg:golang/github.com/foo/bar/v2@v2.1.0", "github.com/foo", "bar/v2"},
+		{"pkg:golang/github.com/foo/bar@v1.0.0", "github.com/foo", "bar"},
+		{"pkg:golang/gopkg.in/yaml.v3@v3.0.1", "gopkg.in", "yaml.v3"},
+	}
+
+	for _, tt := range tests {
+		purlType, namespace, name, ok := parsePURL(tt.purl)
+		if !ok || purlType != "golang" || namespace != tt.namespace || name != tt.name {
+			t.Errorf("parsePURL(%s) = %s, %s, %s, want golang, %s, %s", tt.purl, purlType, namespace, name, tt.namespace, tt.name)
+		}
+	}
+
+	// Imported modules keep their full path, major version suffix included
+	if dep := importedDependency("", "", "v2.1.0", "pkg:golang/github.com/foo/bar/v2@v2.1.0"); dep.Name != "github.com/foo/bar/v2" {
+		t.Errorf("Expected imported module github.com/foo/bar/v2, got %s", dep.Name)
+	}
+}
+
+func TestToCycloneDX(t *testing.T) {
+	shared := model.Dependency{Name: "ms", Version: "2.1.3", Scope: "runtime"}
+	roots := []model.DependencyRoot{
+		{
+			ProjectName:    "app",
+			ProjectVersion: "1.0.0",
+			BuildTool:      "npm",
+			Dependencies: []model.Dependency{
+				{Name: "debug", Version: "4.3.4", Scope: "runtime", License: "MIT", Children: []model.Dependency{shared}},
+				{Name: "jest", Version: "29.0.0", Scope: "development", Children: []model.Dependency{shared}},
+			},
+		},
+	}
+
+	bom := ToCycloneDX(roots)
+
+	if len(bom.Components) != 4 {
+		t.Fatalf("Expected 4 unique components, got %d", len(bom.Components))
+	}
+	if bom.Components[0].Type != "application" || bom.Components[0].Name != "app" {
+		t.Errorf("Expected the project as first component, got %+v", bom.Components[0])
+	}
+	for _, component := range bom.Components {
+		if component.Name == "jest" && component.Scope != "excluded" {
+			t.Errorf("Expected development dependency to be excluded, got %s", component.Scope)
+		}
+		if component.Name == "debug" && (len(component.Licenses) != 1 || component.Licenses[0].Expression != "MIT") {
+			t.Errorf("Expected the declared license of debug as expression, got %+v", component.Licenses)
+		}
+		if component.Name == "jest" && component.Licenses != nil {
+			t.Errorf("Expected no license for jest, got %+v", component.Licenses)
+		}
+	}
+
+	dependsOn := make(map[string][]string)
+	for _, dependency := range bom.Dependencies {
+		dependsOn[dependency.Ref] = dependency.DependsOn
+	}
+	if refs := dependsOn["project:npm:app@1.0.0"]; len(refs) != 2 {
+		t.Errorf("Expected project to depend on 2 components, got %v", refs)
+	}
+	if refs := dependsOn["pkg:npm/jest@29.0.0"]; len(refs) != 1 || refs[0] != "pkg:npm/ms@2.1.3" {
+		t.Errorf("Expected jest to depend on ms, got %v", refs)
+	}
+}
+
+func TestToSPDX(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{
+			ProjectName:    "service",
+			ProjectVersion: "2.0.0",
+			BuildTool:      "go",
+			Dependencies: []model.Dependency{
+				{Name: "github.com/gin-gonic/gin", Version: "v1.9.1", Scope: "runtime", License: "MIT"},
+			},
+		},
+	}
+
+	doc := ToSPDX(roots)
+
+	if doc.Name != "service" || doc.DataLicense != "CC0-1.0" {
+		t.Errorf("Unexpected document header: %s %s", doc.Name, doc.DataLicense)
+	}
+	if len(doc.Packages) != 2 {
+		t.Fatalf("Expected 2 packages, got %d", len(doc.Packages))
+	}
+	if refs := doc.Packages[1].ExternalRefs; len(refs) != 1 || refs[0].ReferenceLocator != "pkg:golang/github.com/gin-gonic/gin@v1.9.1" {
+		t.Errorf("Expected purl external reference, got %v", refs)
+	}
+	if license := doc.Packages[1].LicenseDeclared; license != "MIT" {
+		t.Errorf("Expected the declared license MIT, got %q", license)
+	}
+	if len(doc.Relationships) != 2 || doc.Relationships[0].RelationshipType != "DESCRIBES" || doc.Relationships[1].RelationshipType != "DEPENDS_ON" {
+		t.Errorf("Unexpected relationships: %v", doc.Relationships)
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{
+			ProjectName:    "web",
+			ProjectVersion: "1.0.0",
+			BuildTool:      "npm",
+			Dependencies: []model.Dependency{
+				{Name: "express", Version: "4.18.2", Scope: "runtime", License: "MIT", Children: []model.Dependency{
+					{Name: "debug", Version: "2.6.9", Scope: "runtime"},
+				}},
+				{Name: "@types/node", Version: "20.1.0", Scope: "optional"},
+			},
+		},
+		{
+			ProjectName:    "api",
+			ProjectVersion: "2.0.0",
+			BuildTool:      "maven",
+			Dependencies: []model.Dependency{
+				{ID: &model.DependencyID{Group: "org.apache.commons"}, GroupID: "org.apache.commons", Name: "commons-lang3", Version: "3.12.0", Scope: "runtime"},
+			},
+		},
+	}
+
+	exports := map[string]any{"cyclonedx": ToCycloneDX(roots), "spdx": ToSPDX(roots)}
+	for format, document := range exports {
+		t.Run(format, func(t *testing.T) {
+			data, err := json.Marshal(document)
+			if err != nil {
+				t.Fatalf("Failed to marshal %s document: %v", format, err)
+			}
+
+			imported, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if got, want := flattenRoots(imported, format == "cyclonedx"), flattenRoots(roots, format == "cyclonedx"); !slices.Equal(got, want) {
+				t.Errorf("Imported dependencies differ\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParse_CycloneDXToolsObject(t *testing.T) {
+	// CycloneDX 1.5 and later list tools as an object of components and services
+	data := []byte(`{
+	"bomFormat": "CycloneDX",
+	"specVersion": "1.6",
+	"version": 1,
+	"metadata": {
+		"tools": {
+			"components": [{"type": "application", "name": "syft", "version": "1.4.1"}],
+			"services": [{"name": "sbom-service"}]
+		},
+		"component": {"type": "application", "bom-ref": "app", "name": "app", "version": "1.0.0"}
+	},
+	"components": [
+		{"type": "library", "bom-ref": "pkg:npm/lodash@4.17.21", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"}
+	]
+}`)
+
+	roots, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(roots) != 1 || len(roots[0].Dependencies) != 1 || roots[0].Dependencies[0].Name != "lodash" {
+		t.Errorf("Expected one root depending on lodash, got %+v", roots)
+	}
+
+	var metadata CycloneDXMetadata
+	if err := json.Unmarshal([]byte(`{"tools": {"components": [{"name": "syft"}], "services": [{"name": "sbom-service"}]}}`), &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if !slices.Equal(metadata.Tools, CycloneDXTools{{Name: "syft"}, {Name: "sbom-service"}}) {
+		t.Errorf("Expected the tool components and services, got %v", metadata.Tools)
+	}
+}
+
+func TestParse_UnknownFormat(t *testing.T) {
+	if _, err := Parse([]byte(`{"name": "not an sbom"}`)); err != ErrUnknownFormat {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+// flattenRoots lists every dependency with its root and parent path, including scopes when the
+// format records them
+func flattenRoots(roots []model.DependencyRoot, withScope bool) []string {
+	var lines []string
+	var walk func(prefix string, dependencies []model.Dependency)
+	walk = func(prefix string, dependencies []model.Dependency) {
+		for _, dep := range dependencies {
+			group, name := dependencyCoordinates(dep)
+			line := fmt.Sprintf("%s > %s:%s@%s", prefix, group, name, dep.Version)
+			if dep.License != "" {
+				line += " [" + dep.License + "]"
+			}
+			if withScope {
+				line += " (" + dep.Scope + ")"
+			}
+			lines = append(lines, line)
+			walk(line, dep.Children)
+		}
+	}
+	for _, root := range roots {
+		walk(fmt.Sprintf("%s %s@%s", root.BuildTool, root.ProjectName, root.ProjectVersion), root.Dependencies)
+	}
+	slices.Sort(lines)
+	return lines
+}
@@ -0,0 +1,143 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// SPDXVersion is the SPDX specification version produced
+const SPDXVersion = "SPDX-2.3"
+
+// SPDXDocument is an SPDX JSON document
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo describes when and by whom the document was created
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage is a project or dependency in the document
+type SPDXPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+// SPDXExternalRef references a package in an external system, such as its purl
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship relates two SPDX elements
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// ToSPDX converts dependency roots into an SPDX document.
+// The document describes each root, which depends on its dependency tree.
+func ToSPDX(roots []model.DependencyRoot) *SPDXDocument {
+	name := "cleansource-scan"
+	if len(roots) == 1 && roots[0].ProjectName != "" {
+		name = roots[0].ProjectName
+	}
+
+	doc := &SPDXDocument{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://cleansource.sca/spdx/%s-%s", name, newUUID()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + toolName},
+		},
+		Packages:      []SPDXPackage{},
+		Relationships: []SPDXRelationship{},
+	}
+
+	ids := make(map[string]string)
+	related := make(map[string]bool)
+
+	addPackage := func(ref string, pkg SPDXPackage) (string, bool) {
+		if id, ok := ids[ref]; ok {
+			return id, false
+		}
+		pkg.SPDXID = fmt.Sprintf("SPDXRef-Package-%d", len(ids)+1)
+		pkg.DownloadLocation = "NOASSERTION"
+		ids[ref] = pkg.SPDXID
+		doc.Packages = append(doc.Packages, pkg)
+		return pkg.SPDXID, true
+	}
+	addRelationship := func(from, relationship, to string) {
+		key := from + " " + relationship + " " + to
+		if !related[key] {
+			related[key] = true
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{
+				SPDXElementID:      from,
+				RelationshipType:   relationship,
+				RelatedSPDXElement: to,
+			})
+		}
+	}
+
+	var walk func(buildTool, parentID string, dependencies []model.Dependency)
+	walk = func(buildTool, parentID string, dependencies []model.Dependency) {
+		for _, dep := range dependencies {
+			pkg := SPDXPackage{Name: dep.Name, VersionInfo: dep.Version, LicenseDeclared: dep.License}
+			if group, name := dependencyCoordinates(dep); group != "" {
+				pkg.Name = group + ":" + name
+			}
+			if purl := PackageURL(buildTool, dep); purl != "" {
+				pkg.ExternalRefs = []SPDXExternalRef{{
+					ReferenceCategory: "PACKAGE-MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  purl,
+				}}
+			}
+
+			id, added := addPackage(componentRef(buildTool, dep), pkg)
+			addRelationship(parentID, "DEPENDS_ON", id)
+			if added {
+				walk(buildTool, id, dep.Children)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		id, _ := addPackage(rootRef(root), SPDXPackage{Name: root.ProjectName, VersionInfo: root.ProjectVersion})
+		addRelationship(doc.SPDXID, "DESCRIBES", id)
+		walk(root.BuildTool, id, root.Dependencies)
+	}
+
+	return doc
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// fingerprintCacheVersion is bumped whenever the cache layout or fingerprint format changes
+const fingerprintCacheVersion = 3 // 3: decoded text sizes are recorded apart from the file size
+
+// fingerprintCache records file fingerprints with the modification time and size they were
+// computed at, so unchanged files can be carried forward without rehashing
+type fingerprintCache struct {
+	Version int                          `json:"version"`
+	Entries map[string]fingerprintRecord `json:"entries"` // Keyed by path relative to the task directory
+}
+
+// fingerprintRecord is a cached fingerprint of a single file
+type fingerprintRecord struct {
+	ModTime  int64  `json:"modTime"`  // Modification time in Unix nanoseconds
+	Size     int64  `json:"size"`     // Size of the file on disk
+	TextSize int64  `json:"textSize"` // Size of the hashed content, the decoded text for text files
+	Hash     string `json:"hash"`
+}
+
+// newFingerprintCache creates an empty fingerprint cache
+func newFingerprintCache() *fingerprintCache {
+	return &fingerprintCache{Version: fingerprintCacheVersion, Entries: make(map[string]fingerprintRecord)}
+}
+
+// loadFingerprintCache reads a fingerprint cache, returning an empty cache when it is missing,
+// unreadable or was written by an incompatible version
+func loadFingerprintCache(path string) *fingerprintCache {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newFingerprintCache()
+	}
+
+	cache := newFingerprintCache()
+	if err := json.Unmarshal(data, cache); err != nil || cache.Version != fingerprintCacheVersion || cache.Entries == nil {
+		return newFingerprintCache()
+	}
+	return cache
+}
+
+// lookup returns the cached fingerprint of a file when its modification time and size are unchanged
+func (c *fingerprintCache) lookup(relPath string, info os.FileInfo) (*fileFingerprint, bool) {
+	record, ok := c.Entries[relPath]
+	if !ok || record.ModTime != info.ModTime().UnixNano() || record.Size != info.Size() {
+		return nil, false
+	}
+	return &fileFingerprint{Path: relPath, Hash: record.Hash, Size: record.TextSize}, true
+}
+
+// save writes the cache to the given path
+func (c *fingerprintCache) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
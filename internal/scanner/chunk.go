@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// wfpEntryPrefixes start a new entry in a WFP file; any other line continues the current entry
+var wfpEntryPrefixes = []string{"file=", "hash="}
+
+// SplitWfpFile splits a WFP file into chunk files of at most maxSize bytes, written next to it
+// as <wfpFile>.001, <wfpFile>.002 and so on. Chunks only break between entries, so an entry
+// larger than maxSize is written to a chunk of its own. It returns the chunk paths in order.
+func SplitWfpFile(wfpFile string, maxSize int64) ([]string, error) {
+	file, err := os.Open(wfpFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	var chunks []string
+	var chunk, entry bytes.Buffer
+	flushChunk := func() error {
+		if chunk.Len() == 0 {
+			return nil
+		}
+		name := fmt.Sprintf("%s.%03d", wfpFile, len(chunks)+1)
+		if err := os.WriteFile(name, chunk.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write wfp chunk: %w", err)
+		}
+		chunks = append(chunks, name)
+		chunk.Reset()
+		return nil
+	}
+	flushEntry := func() error {
+		if chunk.Len() > 0 && int64(chunk.Len()+entry.Len()) > maxSize {
+			if err := flushChunk(); err != nil {
+				return err
+			}
+		}
+		chunk.Write(entry.Bytes())
+		entry.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if isWfpEntryStart(line) && entry.Len() > 0 {
+			if err := flushEntry(); err != nil {
+				return nil, err
+			}
+		}
+		entry.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wfp file: %w", err)
+	}
+
+	if err := flushEntry(); err != nil {
+		return nil, err
+	}
+	if err := flushChunk(); err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// isWfpEntryStart reports whether a WFP line begins a new file entry
+func isWfpEntryStart(line string) bool {
+	for _, prefix := range wfpEntryPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitWfpFile(t *testing.T) {
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("file=src/file%02d.go,hash=%032d,size=%d", i, i, i*100))
+	}
+	wfpFile := filepath.Join(t.TempDir(), "fingerprints.wfp")
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(wfpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write wfp file: %v", err)
+	}
+
+	// Room for three entries per chunk, but not four
+	entrySize := int64(len(lines[0]) + 1)
+	maxSize := 3*entrySize + entrySize/2
+
+	chunks, err := SplitWfpFile(wfpFile, maxSize)
+	if err != nil {
+		t.Fatalf("SplitWfpFile failed: %v", err)
+	}
+	if len(chunks) != 7 {
+		t.Fatalf("Expected 7 chunks, got %d", len(chunks))
+	}
+
+	var joined strings.Builder
+	for i, chunk := range chunks {
+		if want := fmt.Sprintf("%s.%03d", wfpFile, i+1); chunk != want {
+			t.Errorf("Expected chunk %d at %s, got %s", i+1, want, chunk)
+		}
+		data, err := os.ReadFile(chunk)
+		if err != nil {
+			t.Fatalf("Failed to read chunk %s: %v", chunk, err)
+		}
+		if int64(len(data)) > maxSize {
+			t.Errorf("Chunk %d is %d bytes, over the %d byte limit", i+1, len(data), maxSize)
+		}
+		if !strings.HasPrefix(string(data), "file=") || !strings.HasSuffix(string(data), "\n") {
+			t.Errorf("Chunk %d does not start and end on an entry boundary: %q", i+1, data)
+		}
+		joined.Write(data)
+	}
+	if joined.String() != content {
+		t.Error("Expected the chunks to reassemble into the original WFP file")
+	}
+}
+
+func TestSplitWfpFile_OversizedEntry(t *testing.T) {
+	wfpFile := filepath.Join(t.TempDir(), "fingerprints.wfp")
+	content := "file=a.go,hash=1,size=1\nfile=" + strings.Repeat("x", 200) + ",hash=2,size=2\nfile=b.go,hash=3,size=3\n"
+	if err := os.WriteFile(wfpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write wfp file: %v", err)
+	}
+
+	chunks, err := SplitWfpFile(wfpFile, 64)
+	if err != nil {
+		t.Fatalf("SplitWfpFile failed: %v", err)
+	}
+	// The long entry is never cut, so it gets a chunk of its own
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	data, err := os.ReadFile(chunks[1])
+	if err != nil {
+		t.Fatalf("Failed to read chunk: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "file=xxx") || strings.Count(string(data), "file=") != 1 {
+		t.Errorf("Expected the oversized entry alone in the second chunk, got %q", data)
+	}
+}
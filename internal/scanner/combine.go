@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WfpPart is a WFP file combined with others into a single WFP file
+type WfpPart struct {
+	File   string
+	Prefix string // Directory its paths are placed below; empty keeps them unchanged
+}
+
+// CombineWfpFiles concatenates the parts into output, which may itself be one of the parts,
+// placing the paths of each part below its prefix. Both per-file and deduplicated entries are
+// rewritten. It fails when two entries end up with the same path, since the server could not
+// tell their files apart.
+func CombineWfpFiles(output string, parts []WfpPart) error {
+	tempFile := output + ".tmp"
+	file, err := os.Create(tempFile)
+	if err != nil {
+		return fmt.Errorf("failed to create combined wfp file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(tempFile)
+	}()
+
+	writer := bufio.NewWriter(file)
+	seen := make(map[string]string) // Path to the part file it came from
+	for _, part := range parts {
+		if err := appendWfpPart(writer, part, seen); err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing combined wfp file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing combined wfp file: %w", err)
+	}
+	return os.Rename(tempFile, output)
+}
+
+// appendWfpPart writes the entries of a part with prefixed paths, recording each path in seen
+func appendWfpPart(writer *bufio.Writer, part WfpPart, seen map[string]string) error {
+	file, err := os.Open(part.File)
+	if err != nil {
+		return fmt.Errorf("failed to read wfp file: %w", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line, paths := prefixWfpLine(scanner.Text(), part.Prefix)
+		for _, path := range paths {
+			if other, exists := seen[path]; exists {
+				return fmt.Errorf("path %s is fingerprinted by both %s and %s", path, other, part.File)
+			}
+			seen[path] = part.File
+		}
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("error writing combined wfp file: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read wfp file: %w", err)
+	}
+	return nil
+}
+
+// prefixWfpLine places the paths of a WFP line below prefix, returning the rewritten line and
+// its paths. Lines that name no file are returned unchanged.
+func prefixWfpLine(line, prefix string) (string, []string) {
+	prefixPath := func(path string) string {
+		if prefix == "" {
+			return path
+		}
+		return prefix + "/" + path
+	}
+
+	// file=<path>,hash=<md5>,size=<n>
+	if rest, ok := strings.CutPrefix(line, "file="); ok {
+		path, fields, found := strings.Cut(rest, ",hash=")
+		path = prefixPath(path)
+		if !found {
+			return "file=" + path, []string{path}
+		}
+		return "file=" + path + ",hash=" + fields, []string{path}
+	}
+
+	// hash=<md5>,size=<n>,files=<path>|<path>
+	if head, files, ok := strings.Cut(line, ",files="); ok && strings.HasPrefix(line, "hash=") {
+		paths := splitDedupPaths(files)
+		for i, path := range paths {
+			paths[i] = prefixPath(path)
+		}
+		return head + ",files=" + joinDedupPaths(paths), paths
+	}
+	return line, nil
+}
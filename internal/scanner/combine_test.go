@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestCombineWfpFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Both directories hold a src/main.go, which must not collide once prefixed
+	var parts []WfpPart
+	for i, prefix := range []string{"frontend", "backend"} {
+		scanDir := filepath.Join(tempDir, prefix)
+		if err := os.MkdirAll(filepath.Join(scanDir, "src"), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := "package main\n\n// " + prefix + "\n"
+		if err := os.WriteFile(filepath.Join(scanDir, "src", "main.go"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		cfg := &config.ScanConfig{ToPath: tempDir, WfpName: prefix + ".wfp", DedupWfp: i == 1}
+		wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+		if err != nil {
+			t.Fatalf("GenerateWfpFile failed for %s: %v", prefix, err)
+		}
+		parts = append(parts, WfpPart{File: wfpFile, Prefix: prefix})
+	}
+
+	output := filepath.Join(tempDir, "combined.wfp")
+	if err := CombineWfpFiles(output, parts); err != nil {
+		t.Fatalf("CombineWfpFiles failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read combined WFP file: %v", err)
+	}
+
+	lines := ExpandWfpLines(strings.Split(strings.TrimSpace(string(data)), "\n"))
+	var paths []string
+	for _, line := range lines {
+		path, _, _ := strings.Cut(strings.TrimPrefix(line, "file="), ",hash=")
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if want := []string{"backend/src/main.go", "frontend/src/main.go"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+
+	if _, err := os.Stat(output + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected the temporary combined file to be removed")
+	}
+}
+
+func TestCombineWfpFiles_Collision(t *testing.T) {
+	tempDir := t.TempDir()
+	first := filepath.Join(tempDir, "first.wfp")
+	second := filepath.Join(tempDir, "second.wfp")
+	if err := os.WriteFile(first, []byte("file=lib/util.go,hash=1,size=1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wfp file: %v", err)
+	}
+	if err := os.WriteFile(second, []byte("hash=2,size=2,files=util.go|other.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wfp file: %v", err)
+	}
+
+	err := CombineWfpFiles(first, []WfpPart{{File: first}, {File: second, Prefix: "lib"}})
+	if err == nil || !strings.Contains(err.Error(), "lib/util.go") {
+		t.Fatalf("Expected a collision on lib/util.go, got %v", err)
+	}
+
+	// The task's own WFP file is left untouched on failure
+	data, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatalf("Failed to read wfp file: %v", err)
+	}
+	if string(data) != "file=lib/util.go,hash=1,size=1\n" {
+		t.Errorf("Expected the original WFP file to be unchanged, got %q", data)
+	}
+}
@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// dockerignoreFile is the file listing paths left out of a Docker build context
+const dockerignoreFile = ".dockerignore"
+
+// dockerignore holds the patterns of a .dockerignore file in their declared order
+type dockerignore struct {
+	patterns []dockerignorePattern
+}
+
+// dockerignorePattern is one .dockerignore line; negated patterns re-include matching paths
+type dockerignorePattern struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// loadDockerignore reads the .dockerignore file at the root of a build context, returning nil
+// when there is none
+func loadDockerignore(contextDir string) *dockerignore {
+	data, err := utils.ReadTextFile(filepath.Join(contextDir, dockerignoreFile))
+	if err != nil {
+		return nil
+	}
+	return parseDockerignore(data)
+}
+
+// parseDockerignore parses .dockerignore content. Patterns are relative to the context root,
+// support *, ? and ** wildcards, and a leading ! re-includes what earlier patterns excluded.
+func parseDockerignore(data []byte) *dockerignore {
+	ignore := &dockerignore{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "!"))
+		line = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(line)), "/")
+		if line == "" {
+			continue
+		}
+
+		if re, err := regexp.Compile(dockerignoreRegexp(line)); err == nil {
+			ignore.patterns = append(ignore.patterns, dockerignorePattern{re: re, negate: negate})
+		}
+	}
+	return ignore
+}
+
+// dockerignoreRegexp translates a cleaned .dockerignore pattern into an anchored regexp
+func dockerignoreRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**/") {
+				sb.WriteString("(.*/)?")
+				i += 2
+			} else if strings.HasPrefix(pattern[i:], "**") {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// excluded reports whether a path relative to the context root is left out of the context.
+// A pattern matching a directory covers everything below it, and the last matching pattern wins.
+func (d *dockerignore) excluded(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	excluded := false
+	for _, pattern := range d.patterns {
+		if pattern.matches(relPath) {
+			excluded = !pattern.negate
+		}
+	}
+	return excluded
+}
+
+// hasNegations reports whether any pattern re-includes paths, in which case excluded
+// directories must still be walked
+func (d *dockerignore) hasNegations() bool {
+	for _, pattern := range d.patterns {
+		if pattern.negate {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the pattern matches the path or one of its parent directories
+func (p dockerignorePattern) matches(relPath string) bool {
+	for dir := relPath; dir != "." && dir != ""; dir = path.Dir(dir) {
+		if p.re.MatchString(dir) {
+			return true
+		}
+	}
+	return false
+}
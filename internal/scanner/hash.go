@@ -0,0 +1,158 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/md5"
+	"io"
+	"sync"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// utf16BOMs are the byte order marks of UTF-16 text, which has to be decoded whole before hashing
+var utf16BOMs = [][]byte{{0xFF, 0xFE}, {0xFE, 0xFF}}
+
+// bufferPool hands out read buffers of one size, so hashing many small files does not allocate
+// a buffer per file
+type bufferPool struct {
+	pool sync.Pool
+}
+
+// newBufferPool creates a pool of buffers of size bytes
+func newBufferPool(size int) *bufferPool {
+	return &bufferPool{pool: sync.Pool{New: func() any {
+		buf := make([]byte, size)
+		return &buf
+	}}}
+}
+
+func (bp *bufferPool) get() *[]byte  { return bp.pool.Get().(*[]byte) }
+func (bp *bufferPool) put(b *[]byte) { bp.pool.Put(b) }
+
+// readHead fills buf from r, returning the bytes read and whether r is exhausted
+func readHead(r io.Reader, buf []byte) ([]byte, bool, error) {
+	n, err := io.ReadFull(r, buf)
+	switch err {
+	case nil:
+		return buf[:n], false, nil
+	case io.EOF, io.ErrUnexpectedEOF:
+		return buf[:n], true, nil
+	}
+	return nil, false, err
+}
+
+// readRest returns head followed by what is left to read from r, for content that has to be
+// decoded whole
+func readRest(r io.Reader, head []byte, done bool) ([]byte, error) {
+	content := bytes.Clone(head)
+	if done {
+		return content, nil
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return append(content, rest...), nil
+}
+
+// isUTF16 reports whether head starts with a UTF-16 byte order mark
+func isUTF16(head []byte) bool {
+	for _, bom := range utf16BOMs {
+		if bytes.HasPrefix(head, bom) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashText streams r through MD5 using buf. For text it hashes the UTF-8 text like
+// utils.DecodeText, so a copy saved with a byte order mark or as UTF-16 matches; other content
+// is hashed as is. It returns the hash and the number of bytes hashed. Only UTF-16 content is
+// read whole, as it has to be decoded first.
+func hashText(r io.Reader, buf []byte, text bool) ([md5.Size]byte, int64, error) {
+	head, done, err := readHead(r, buf)
+	if err != nil {
+		return [md5.Size]byte{}, 0, err
+	}
+
+	if text && isUTF16(head) {
+		content, err := readRest(r, head, done)
+		if err != nil {
+			return [md5.Size]byte{}, 0, err
+		}
+		// Binary content whose first bytes happen to match a byte order mark decodes to NULs
+		if decoded := utils.DecodeText(content); bytes.IndexByte(decoded, 0) < 0 {
+			content = decoded
+		}
+		return md5.Sum(content), int64(len(content)), nil
+	}
+
+	if text {
+		head = utils.DecodeText(head)
+	}
+	hash := md5.New()
+	hash.Write(head)
+	size := int64(len(head))
+	if !done {
+		// Hide any WriterTo of r, which would bypass buf
+		n, err := io.CopyBuffer(hash, struct{ io.Reader }{r}, buf)
+		if err != nil {
+			return [md5.Size]byte{}, 0, err
+		}
+		size += n
+	}
+
+	var sum [md5.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	return sum, size, nil
+}
+
+// countTextLines counts the lines of the UTF-8 text read from r using buf. A trailing newline
+// does not start another line, unless the text also holds an empty line.
+func countTextLines(r io.Reader, buf []byte) (int, error) {
+	head, done, err := readHead(r, buf)
+	if err != nil {
+		return 0, err
+	}
+	if len(head) == 0 {
+		return 0, nil
+	}
+	if isUTF16(head) {
+		content, err := readRest(r, head, done)
+		if err != nil {
+			return 0, err
+		}
+		head, done = utils.DecodeText(content), true
+	} else {
+		head = utils.DecodeText(head)
+	}
+
+	var newlines int
+	var emptyLine bool
+	var last byte
+	count := func(chunk []byte) {
+		for _, b := range chunk {
+			if b == '\n' {
+				newlines++
+				emptyLine = emptyLine || last == '\n'
+			}
+			last = b
+		}
+	}
+	count(head)
+	for !done {
+		n, err := r.Read(buf)
+		count(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if newlines > 0 && last == '\n' && !emptyLine {
+		return newlines, nil
+	}
+	return newlines + 1, nil
+}
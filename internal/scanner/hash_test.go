@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// readBufferSizes are the buffer sizes results must not depend on, from below the length of a
+// byte order mark to larger than any test content
+var readBufferSizes = []int{3, 7, config.MinReadBufferSize, 4096, config.DefaultReadBufferSize, config.MaxFingerprintFileSize}
+
+// benchmarkText returns n bytes of source-like text
+func benchmarkText(n int) []byte {
+	line := "func main() { fmt.Println(\"hello, world\") } // benchmark line\n"
+	return []byte(strings.Repeat(line, n/len(line)+1)[:n])
+}
+
+// utf16LE encodes text as UTF-16LE with a byte order mark
+func utf16LE(text string) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, r := range text {
+		data = append(data, byte(r), byte(r>>8))
+	}
+	return data
+}
+
+func TestHashText_BufferSizes(t *testing.T) {
+	contents := map[string][]byte{
+		"empty":    {},
+		"short":    []byte("x"),
+		"text":     []byte("package main\n\nfunc main() {}\n"),
+		"utf8 bom": append([]byte{0xEF, 0xBB, 0xBF}, "print('hi')\n"...),
+		"utf16":    utf16LE("print('héllo')\n" + strings.Repeat("# comment\n", 100)),
+		"large":    benchmarkText(300 * 1024),
+	}
+
+	for name, content := range contents {
+		expected := md5.Sum(utils.DecodeText(content))
+		for _, size := range readBufferSizes {
+			t.Run(fmt.Sprintf("%s/%d", name, size), func(t *testing.T) {
+				hash, n, err := hashText(bytes.NewReader(content), make([]byte, size), true)
+				if err != nil {
+					t.Fatalf("hashText failed: %v", err)
+				}
+				if hash != expected {
+					t.Errorf("Expected hash %x, got %x", expected, hash)
+				}
+				if decoded := utils.DecodeText(content); n != int64(len(decoded)) {
+					t.Errorf("Expected the decoded size %d, got %d", len(decoded), n)
+				}
+				if hash, n, err := hashText(bytes.NewReader(content), make([]byte, size), false); err != nil || hash != md5.Sum(content) || n != int64(len(content)) {
+					t.Errorf("Expected binary content to hash as is, got %x with size %d (%v)", hash, n, err)
+				}
+			})
+		}
+	}
+}
+
+func TestCountTextLines_BufferSizes(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"", 0},
+		{"line", 1},
+		{"\n", 1},
+		{"line 1\nline 2\n", 2},
+		{"line 1\nline 2", 2},
+		{"line 1\n\nline 3\n", 4},
+		{"line 1\n\n", 3},
+		{"\ufeffline 1\nline 2\n", 2},
+		{strings.Repeat("line\n", 5000), 5000},
+	}
+
+	for _, tt := range tests {
+		for _, size := range readBufferSizes {
+			count, err := countTextLines(strings.NewReader(tt.content), make([]byte, size))
+			if err != nil {
+				t.Fatalf("countTextLines failed: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("countTextLines(%.20q) with a %d byte buffer = %d, want %d", tt.content, size, count, tt.expected)
+			}
+		}
+	}
+
+	count, err := countTextLines(bytes.NewReader(utf16LE("a\nb\n")), make([]byte, 3))
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 lines of UTF-16 text, got %d (%v)", count, err)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_ReadBuffer(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	files := map[string][]byte{
+		"main.go":        []byte("package main\n"),
+		"src/large.js":   benchmarkText(200 * 1024),
+		"src/utf16.py":   utf16LE("print('hi')\n"),
+		"docs/readme.md": append([]byte{0xEF, 0xBB, 0xBF}, "# Readme\n"...),
+	}
+	for name, content := range files {
+		path := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	var expected string
+	for _, readBuffer := range []string{"", "512B", "4KB", "1MB"} {
+		cfg := &config.ScanConfig{TaskDir: scanDir, ToPath: tempDir, ThreadNum: "4", ReadBuffer: readBuffer}
+		wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+		if err != nil {
+			t.Fatalf("GenerateWfpFile failed with read buffer %q: %v", readBuffer, err)
+		}
+		data, err := os.ReadFile(wfpFile)
+		if err != nil {
+			t.Fatalf("Failed to read WFP file: %v", err)
+		}
+		if readBuffer == "" {
+			expected = string(data)
+		} else if string(data) != expected {
+			t.Errorf("Expected read buffer %s to produce the same WFP file:\n%s\ngot:\n%s", readBuffer, expected, data)
+		}
+	}
+}
+
+// BenchmarkWfpScanner_generateFileFingerprint_ReadBuffer compares read buffer sizes on a tree of
+// many small files and on one of few files near the fingerprint size limit
+func BenchmarkWfpScanner_generateFileFingerprint_ReadBuffer(b *testing.B) {
+	trees := []struct {
+		name     string
+		files    int
+		fileSize int
+	}{
+		{"small-files", 2000, 2 * 1024},
+		{"large-files", 20, 900 * 1024},
+	}
+
+	for _, tree := range trees {
+		dir := b.TempDir()
+		var paths []string
+		content := benchmarkText(tree.fileSize)
+		for i := 0; i < tree.files; i++ {
+			path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				b.Fatalf("Failed to create test file: %v", err)
+			}
+			paths = append(paths, path)
+		}
+
+		for _, readBuffer := range []string{"4KB", "32KB", "256KB", "1MB"} {
+			b.Run(tree.name+"/"+readBuffer, func(b *testing.B) {
+				scanner := NewWfpScanner(&config.ScanConfig{TaskDir: dir, ReadBuffer: readBuffer})
+				b.SetBytes(int64(tree.files * tree.fileSize))
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for _, path := range paths {
+						if _, err := scanner.generateFileFingerprint(path); err != nil {
+							b.Fatalf("generateFileFingerprint failed: %v", err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
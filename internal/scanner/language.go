@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// dominantLanguageShare is the share of source files above which a language counts as dominant
+const dominantLanguageShare = 0.25
+
+// languageExtensions maps source file extensions to language names
+var languageExtensions = map[string]string{
+	".go":     "go",
+	".java":   "java",
+	".kt":     "kotlin",
+	".kts":    "kotlin",
+	".scala":  "scala",
+	".groovy": "groovy",
+	".py":     "python",
+	".js":     "javascript",
+	".jsx":    "javascript",
+	".mjs":    "javascript",
+	".cjs":    "javascript",
+	".ts":     "typescript",
+	".tsx":    "typescript",
+	".c":      "c",
+	".h":      "c",
+	".cc":     "c++",
+	".cpp":    "c++",
+	".cxx":    "c++",
+	".hpp":    "c++",
+	".cs":     "c#",
+	".rs":     "rust",
+	".rb":     "ruby",
+	".php":    "php",
+	".swift":  "swift",
+	".m":      "objective-c",
+	".dart":   "dart",
+	".lua":    "lua",
+	".pl":     "perl",
+	".r":      "r",
+	".sh":     "shell",
+}
+
+// LanguageCount is the number of source files written in one language
+type LanguageCount struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+}
+
+// LanguageSummary is a heuristic description of a directory without a build manifest
+type LanguageSummary struct {
+	ProjectName string          // Name of the scan directory
+	Languages   []LanguageCount // Most common first
+	Files       int             // Files that would be fingerprinted
+	SourceFiles int             // Files with a recognized source extension
+}
+
+// DetectLanguages tallies the source file extensions under scanDir, honoring the same skip
+// rules and excludes as fingerprinting
+func (w *WfpScanner) DetectLanguages(scanDir string) (*LanguageSummary, error) {
+	files, err := w.collectFiles(scanDir)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	summary := &LanguageSummary{ProjectName: utils.ProjectNameFromDir(scanDir), Files: len(files)}
+	sourceExts := w.config.GetSourceExts()
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file))
+		if language, ok := languageExtensions[ext]; ok {
+			counts[language]++
+			summary.SourceFiles++
+		} else if slices.Contains(sourceExts, ext) {
+			// Extensions added by --source-ext are named after themselves
+			counts[strings.TrimPrefix(ext, ".")]++
+			summary.SourceFiles++
+		}
+	}
+
+	for language, files := range counts {
+		summary.Languages = append(summary.Languages, LanguageCount{Language: language, Files: files})
+	}
+	slices.SortFunc(summary.Languages, func(a, b LanguageCount) int {
+		if a.Files != b.Files {
+			return b.Files - a.Files
+		}
+		return strings.Compare(a.Language, b.Language)
+	})
+
+	return summary, nil
+}
+
+// Dominant returns the languages making up a significant share of the source files, always
+// including the most common one
+func (s *LanguageSummary) Dominant() []string {
+	var dominant []string
+	for i, count := range s.Languages {
+		if i == 0 || float64(count.Files) >= dominantLanguageShare*float64(s.SourceFiles) {
+			dominant = append(dominant, count.Language)
+		}
+	}
+	return dominant
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestWfpScanner_DetectLanguages(t *testing.T) {
+	scanDir := filepath.Join(t.TempDir(), "inventory-service")
+
+	files := []string{
+		"main.go", "server.go", "store/store.go", "store/cache.go", "api/handler.go",
+		"scripts/release.py", "README.md", "node_modules/lib/index.js",
+	}
+	for _, name := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	summary, err := NewWfpScanner(&config.ScanConfig{}).DetectLanguages(scanDir)
+	if err != nil {
+		t.Fatalf("DetectLanguages failed: %v", err)
+	}
+
+	if summary.ProjectName != "inventory-service" {
+		t.Errorf("Expected project name from the directory, got %q", summary.ProjectName)
+	}
+	if summary.SourceFiles != 6 {
+		t.Errorf("Expected 6 source files outside node_modules, got %d", summary.SourceFiles)
+	}
+	if dominant := summary.Dominant(); !slices.Equal(dominant, []string{"go"}) {
+		t.Errorf("Expected go to be the only dominant language, got %v", dominant)
+	}
+	if len(summary.Languages) != 2 || summary.Languages[1] != (LanguageCount{Language: "python", Files: 1}) {
+		t.Errorf("Expected python to be tallied second, got %+v", summary.Languages)
+	}
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// noticeFileName is the attribution file collected apart from license files
+const noticeFileName = "NOTICE"
+
+// CollectLicenseFiles returns the license and NOTICE files under scanDir as paths relative to
+// it, matching the configured file names case-insensitively with or without an extension
+func (w *WfpScanner) CollectLicenseFiles(scanDir string) (*model.FilePathCollect, error) {
+	files, err := w.collectFiles(scanDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := w.config.GetLicenseFilenames()
+	collect := &model.FilePathCollect{}
+	for _, file := range files {
+		name, ok := matchLicenseFilename(filepath.Base(file), names)
+		if !ok {
+			continue
+		}
+
+		relPath, err := filepath.Rel(scanDir, file)
+		if err != nil {
+			relPath = file
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// NOTICE files carry attributions rather than license terms
+		if strings.EqualFold(name, noticeFileName) {
+			collect.NoticeFiles = append(collect.NoticeFiles, relPath)
+		} else {
+			collect.ProjectLicenseFiles = append(collect.ProjectLicenseFiles, relPath)
+		}
+	}
+
+	return collect, nil
+}
+
+// matchLicenseFilename returns the configured name matching a file base name, such as
+// LICENSE for "license.md"
+func matchLicenseFilename(base string, names []string) (string, bool) {
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	for _, name := range names {
+		if strings.EqualFold(base, name) || strings.EqualFold(stem, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
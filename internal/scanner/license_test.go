@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestWfpScanner_CollectLicenseFiles(t *testing.T) {
+	scanDir := t.TempDir()
+
+	files := []string{
+		"LICENSE", "NOTICE.txt", "LEGAL.md", "third_party/lib/COPYING", "third_party/lib/notice",
+		"main.go", "README.md",
+	}
+	for _, name := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		collect, err := NewWfpScanner(&config.ScanConfig{}).CollectLicenseFiles(scanDir)
+		if err != nil {
+			t.Fatalf("CollectLicenseFiles failed: %v", err)
+		}
+
+		if want := []string{"LICENSE", "third_party/lib/COPYING"}; !slices.Equal(collect.ProjectLicenseFiles, want) {
+			t.Errorf("Expected license files %v, got %v", want, collect.ProjectLicenseFiles)
+		}
+		if want := []string{"NOTICE.txt", "third_party/lib/notice"}; !slices.Equal(collect.NoticeFiles, want) {
+			t.Errorf("Expected NOTICE files %v, got %v", want, collect.NoticeFiles)
+		}
+	})
+
+	t.Run("custom filenames", func(t *testing.T) {
+		cfg := &config.ScanConfig{LicenseFilenames: []string{"LEGAL", "NOTICE"}}
+		collect, err := NewWfpScanner(cfg).CollectLicenseFiles(scanDir)
+		if err != nil {
+			t.Fatalf("CollectLicenseFiles failed: %v", err)
+		}
+
+		if want := []string{"LEGAL.md"}; !slices.Equal(collect.ProjectLicenseFiles, want) {
+			t.Errorf("Expected only the configured license file %v, got %v", want, collect.ProjectLicenseFiles)
+		}
+		if want := []string{"NOTICE.txt", "third_party/lib/notice"}; !slices.Equal(collect.NoticeFiles, want) {
+			t.Errorf("Expected NOTICE files %v, got %v", want, collect.NoticeFiles)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// manifestEntry is a scanned file listed in the file manifest
+type manifestEntry struct {
+	Path string `json:"path"` // Relative to the task directory
+	Size int64  `json:"size"`
+	Hash string `json:"hash"` // MD5, as in the WFP file
+}
+
+// writeFileManifest writes every fingerprinted file to path, as CSV when the path ends in
+// .csv and as a JSON array otherwise. It returns the number of files listed.
+func writeFileManifest(path string, fingerprints []*fileFingerprint) (int, error) {
+	entries := make([]manifestEntry, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		if fingerprint != nil {
+			entries = append(entries, manifestEntry{Path: fingerprint.Path, Size: fingerprint.Size, Hash: fingerprint.Hash})
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return len(entries), encoder.Encode(entries)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"path", "size", "hash"}); err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if err := writer.Write([]string{entry.Path, strconv.FormatInt(entry.Size, 10), entry.Hash}); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return len(entries), writer.Error()
+}
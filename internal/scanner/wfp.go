@@ -1,24 +1,112 @@
 package scanner
 
 import (
+	"bufio"
 	"crypto/md5"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
+// Paths sharing one content hash in a deduplicated WFP line are separated by dedupPathSeparator.
+// Both it and dedupPathEscape are legal in file names, so paths escape them with dedupPathEscape.
+const (
+	dedupPathSeparator = '|'
+	dedupPathEscape    = '\\'
+)
+
+// dedupPathEscaper escapes the separator and the escape character in a path
+var dedupPathEscaper = strings.NewReplacer(string(dedupPathEscape), string(dedupPathEscape)+string(dedupPathEscape),
+	string(dedupPathSeparator), string(dedupPathEscape)+string(dedupPathSeparator))
+
+// joinDedupPaths joins paths into the files= value of a deduplicated WFP line
+func joinDedupPaths(paths []string) string {
+	escaped := make([]string, len(paths))
+	for i, path := range paths {
+		escaped[i] = dedupPathEscaper.Replace(path)
+	}
+	return strings.Join(escaped, string(dedupPathSeparator))
+}
+
+// splitDedupPaths splits the files= value of a deduplicated WFP line into unescaped paths
+func splitDedupPaths(files string) []string {
+	var paths []string
+	var path strings.Builder
+	escaped := false
+	for _, r := range files {
+		switch {
+		case escaped:
+			path.WriteRune(r)
+			escaped = false
+		case r == dedupPathEscape:
+			escaped = true
+		case r == dedupPathSeparator:
+			paths = append(paths, path.String())
+			path.Reset()
+		default:
+			path.WriteRune(r)
+		}
+	}
+	return append(paths, path.String())
+}
+
+// binaryExtensions are the file extensions skipped as binary unless listed by --source-ext
+var binaryExtensions = []string{
+	".exe", ".dll", ".so", ".dylib", ".jar", ".war", ".ear",
+	".zip", ".tar", ".gz", ".bz2", ".7z", ".rar",
+	".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico",
+	".mp3", ".mp4", ".avi", ".mov", ".wav",
+	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+	".bin", ".class", ".o", ".a", ".lib",
+}
+
+// testDirNames are the test and generated source directories --exclude-tests leaves out
+var testDirNames = []string{"test", "tests", "__tests__", "testdata", "generated", "gen"}
+
+// inTestDir reports whether a file, given by its path relative to the scan directory, lies
+// below a test or generated source directory
+func inTestDir(relPath string) bool {
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/")
+	return slices.ContainsFunc(dirs, func(dir string) bool { return slices.Contains(testDirNames, dir) })
+}
+
+// fileFingerprint holds the fingerprint of a single file
+type fileFingerprint struct {
+	Path string
+	Hash string
+	Size int64
+}
+
+// String formats the fingerprint as a WFP line: file=path,hash=md5hash,size=filesize
+func (f *fileFingerprint) String() string {
+	return fmt.Sprintf("file=%s,hash=%s,size=%d", f.Path, f.Hash, f.Size)
+}
+
 // WfpScanner handles fingerprint generation for source files
 type WfpScanner struct {
-	config *config.ScanConfig
-	log    *logrus.Logger
+	config  *config.ScanConfig
+	log     *logrus.Logger
+	hashed  int64     // Files read and hashed by the last generation
+	since   time.Time // Modification time the last generation was limited to, zero for all files
+	tooLong []string  // Paths the last walk left out because they exceed the path length limit
+
+	fingerprinted map[string]bool // Slash-separated relative paths the last generation wrote to the WFP file
+
+	buffers     *bufferPool // Read buffers sized by --read-buffer, created on first use
+	buffersOnce sync.Once
 }
 
 // NewWfpScanner creates a new WFP scanner
@@ -43,7 +131,65 @@ func (w *WfpScanner) GenerateWfpFile(scanDir string) (string, error) {
 		w.config.TaskDir = scanDir
 	}
 
-	wfpFile := filepath.Join(w.config.ToPath, "fingerprints.wfp")
+	wfpFile := w.config.GetWfpPath()
+	cacheFile := w.config.GetWfpCachePath()
+
+	// Collect candidate files up front so every fingerprint has a stable position
+	outputFiles := []string{wfpFile, cacheFile}
+	if manifestFile := w.config.FileManifest; manifestFile != "" {
+		outputFiles = append(outputFiles, manifestFile)
+		if absPath, err := filepath.Abs(manifestFile); err == nil {
+			outputFiles = append(outputFiles, absPath)
+		}
+	}
+	w.since = w.config.GetSince(time.Now())
+	if !w.since.IsZero() {
+		w.log.Infof("Fingerprinting only files modified since %s, the WFP file is partial", w.since.Format(time.RFC3339))
+	}
+	var files []string
+	var err error
+	if w.config.FilesFrom != "" {
+		files, err = w.listedFiles(scanDir)
+		if err != nil {
+			return "", fmt.Errorf("invalid file list %s: %w", w.config.FilesFrom, err)
+		}
+		w.log.Infof("Fingerprinting %d files listed in %s, the WFP file is partial", len(files), w.config.FilesFrom)
+	} else if files, err = w.collectFiles(scanDir, outputFiles...); err != nil {
+		return "", fmt.Errorf("error walking directory: %w", err)
+	}
+
+	// Incremental mode carries forward fingerprints of files unchanged since the cached run
+	var previous *fingerprintCache
+	if w.config.Incremental {
+		previous = loadFingerprintCache(cacheFile)
+	}
+
+	atomic.StoreInt64(&w.hashed, 0)
+	fingerprints, current := w.generateFingerprints(files, previous)
+	w.fingerprinted = make(map[string]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		if fingerprint != nil {
+			w.fingerprinted[fingerprint.Path] = true
+		}
+	}
+
+	// A partial run leaves out unchanged files, which must stay in the cache
+	if current != nil && w.since.IsZero() && w.config.FilesFrom == "" {
+		w.log.Infof("Incremental fingerprinting rehashed %d of %d files", atomic.LoadInt64(&w.hashed), len(files))
+		if err := current.save(cacheFile); err != nil {
+			w.log.Warnf("Failed to save fingerprint cache: %v", err)
+		}
+	}
+
+	// The manifest lists the same files as the WFP file, without reading them again
+	if w.config.FileManifest != "" {
+		count, err := writeFileManifest(w.config.FileManifest, fingerprints)
+		if err != nil {
+			return "", fmt.Errorf("failed to write file manifest: %w", err)
+		}
+		w.log.Infof("File manifest with %d files written to: %s", count, w.config.FileManifest)
+	}
+
 	file, err := os.Create(wfpFile)
 	if err != nil {
 		return "", fmt.Errorf("failed to create wfp file: %w", err)
@@ -52,76 +198,318 @@ func (w *WfpScanner) GenerateWfpFile(scanDir string) (string, error) {
 		_ = file.Close()
 	}(file)
 
-	var wg sync.WaitGroup
-	fingerprintChan := make(chan string, 100)
-	errorChan := make(chan error, 10)
-	var writerWG sync.WaitGroup
-
-	// Start writer goroutine and ensure it completes before returning
-	writerWG.Add(1)
-	go func() {
-		defer writerWG.Done()
-		for fingerprint := range fingerprintChan {
-			if _, err := file.WriteString(fingerprint + "\n"); err != nil {
-				errorChan <- err
-				return
-			}
+	lines := w.formatFingerprints(fingerprints)
+
+	writer := bufio.NewWriter(file)
+	for _, line := range lines {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return "", fmt.Errorf("error writing fingerprints: %w", err)
 		}
-	}()
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("error writing fingerprints: %w", err)
+	}
+
+	w.log.Infof("Fingerprint file generated: %s", wfpFile)
+	return wfpFile, nil
+}
+
+// Since returns the modification time the last generation was limited to by --since, zero when
+// every file was fingerprinted
+func (w *WfpScanner) Since() time.Time {
+	return w.since
+}
+
+// TooLongPaths returns the paths the last generation left out, with everything below them,
+// because they exceed the path length limit of the platform
+func (w *WfpScanner) TooLongPaths() []string {
+	return w.tooLong
+}
 
-	// Walk through all files and generate fingerprints
-	err = filepath.Walk(scanDir, func(path string, info os.FileInfo, err error) error {
+// Fingerprinted filters paths relative to the task directory, such as those the server asks to
+// be archived, to the files the last generation wrote to the WFP file. Paths the excludes or
+// --exclude-tests leave out are dropped as well.
+func (w *WfpScanner) Fingerprinted(relPaths []string) []string {
+	var paths []string
+	for _, relPath := range relPaths {
+		switch {
+		case !w.fingerprinted[relPath]:
+		case len(w.config.ExcludePaths) > 0 && utils.MatchesPathPattern(relPath, w.config.ExcludePaths):
+		case w.config.ExcludeTests && inTestDir(relPath):
+		default:
+			paths = append(paths, relPath)
+		}
+	}
+	return paths
+}
+
+// collectFiles walks the scan directory and returns the files to fingerprint in lexical order,
+// skipping the given output files
+func (w *WfpScanner) collectFiles(scanDir string, outputFiles ...string) ([]string, error) {
+	var files []string
+	w.tooLong = nil
+
+	// A docker scan fingerprints a build context, which leaves out what .dockerignore excludes
+	var ignore *dockerignore
+	if w.config.ScanType == string(model.ScanTypeDocker) {
+		ignore = loadDockerignore(scanDir)
+	}
+
+	err := filepath.Walk(scanDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
+			// Unreadable entries are skipped, but a subtree lost to the path length limit is worth a warning
+			if utils.IsPathTooLong(err) {
+				w.tooLong = append(w.tooLong, path)
+				w.log.Warnf("Skipping %s, its path is too long to be read", path)
+			}
 			return nil // Continue walking
 		}
 
-		// Skip the output file itself to avoid self-fingerprinting / races
-		if path == wfpFile {
+		// Skip the output files themselves to avoid self-fingerprinting
+		if slices.Contains(outputFiles, path) {
 			return nil
 		}
 
-		if info.IsDir() || w.shouldSkipFile(path, info) {
+		// Honor the optional depth limit; fingerprinting is unlimited by default
+		if info.IsDir() && w.config.MaxDepth > 0 && utils.PathDepth(scanDir, path) > w.config.MaxDepth {
+			return filepath.SkipDir
+		}
+
+		// Honor configured path excludes
+		if relPath, err := filepath.Rel(scanDir, path); err == nil && relPath != "." &&
+			len(w.config.ExcludePaths) > 0 && utils.MatchesPathPattern(relPath, w.config.ExcludePaths) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		wg.Add(1)
-		go func(filePath string) {
-			defer wg.Done()
+		// --exclude-tests leaves out test and generated sources by convention
+		if w.config.ExcludeTests && info.IsDir() && path != scanDir && slices.Contains(testDirNames, info.Name()) {
+			return filepath.SkipDir
+		}
 
-			fingerprint, err := w.generateFileFingerprint(filePath)
-			if err != nil {
-				w.log.Debugf("Failed to generate fingerprint for %s: %v", filePath, err)
-				return
+		if relPath, err := filepath.Rel(scanDir, path); err == nil && relPath != "." && ignore != nil && ignore.excluded(relPath) {
+			if info.IsDir() && !ignore.hasNegations() {
+				return filepath.SkipDir
 			}
-
-			if fingerprint != "" {
-				fingerprintChan <- fingerprint
+			if !info.IsDir() {
+				return nil
 			}
-		}(path)
+		}
+
+		if info.IsDir() || w.shouldSkipFile(path, info) {
+			return nil
+		}
+
+		// --since leaves out files not modified recently
+		if !w.since.IsZero() && !info.ModTime().After(w.since) {
+			return nil
+		}
 
+		files = append(files, path)
 		return nil
 	})
 
-	// Wait for all goroutines to complete
+	return files, err
+}
+
+// listedFiles returns the files named by the --files-from list in lexical order, in place of
+// walking the scan directory. Every path must name an existing file below the scan directory,
+// also once symlinks are resolved; excludes, --since and the usual skip rules still apply to them.
+func (w *WfpScanner) listedFiles(scanDir string) ([]string, error) {
+	data, err := utils.ReadTextFile(w.config.FilesFrom)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(scanDir)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.EvalSymlinks(absDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	listed := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		relPath := filepath.Clean(filepath.FromSlash(line))
+		if filepath.IsAbs(relPath) {
+			if relPath, err = filepath.Rel(absDir, relPath); err != nil {
+				return nil, fmt.Errorf("%s is outside the task directory", line)
+			}
+		}
+		if relPath == ".." || strings.HasPrefix(relPath, ".."+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("%s is outside the task directory", line)
+		}
+
+		path := filepath.Join(scanDir, relPath)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s does not exist", line)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is not a file", line)
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err != nil {
+			return nil, fmt.Errorf("%s does not exist", line)
+		} else if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("%s is outside the task directory", line)
+		}
+
+		switch {
+		case listed[path]:
+		case len(w.config.ExcludePaths) > 0 && utils.MatchesPathPattern(relPath, w.config.ExcludePaths):
+		case w.config.ExcludeTests && inTestDir(relPath):
+		case w.shouldSkipFile(path, info):
+		case !w.since.IsZero() && !info.ModTime().After(w.since):
+		default:
+			listed[path] = true
+			files = append(files, path)
+			continue
+		}
+		w.log.Debugf("Not fingerprinting listed file %s", line)
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+// generateFingerprints fingerprints files with a bounded worker pool, keeping the input order.
+// Given a previous cache, files with an unchanged modification time and size reuse their cached
+// fingerprint, and the returned cache describes the current files; otherwise it is nil.
+func (w *WfpScanner) generateFingerprints(files []string, previous *fingerprintCache) ([]*fileFingerprint, *fingerprintCache) {
+	fingerprints := make([]*fileFingerprint, len(files))
+	infos := make([]os.FileInfo, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.config.GetThreadNum(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				fingerprint, info, err := w.fingerprintFile(files[index], previous)
+				if err != nil {
+					w.log.Debugf("Failed to generate fingerprint for %s: %v", files[index], err)
+					continue
+				}
+				fingerprints[index] = fingerprint
+				infos[index] = info
+			}
+		}()
+	}
+
+	for index := range files {
+		jobs <- index
+	}
+	close(jobs)
 	wg.Wait()
-	close(fingerprintChan)
 
-	// Wait for writer to finish to ensure all data flushed
-	writerWG.Wait()
+	if previous == nil {
+		return fingerprints, nil
+	}
 
-	// Check for errors (non-blocking)
-	select {
-	case err := <-errorChan:
-		return "", fmt.Errorf("error writing fingerprints: %w", err)
-	default:
+	// Deleted files are not collected, so they drop out of the new cache
+	current := newFingerprintCache()
+	for index, fingerprint := range fingerprints {
+		if fingerprint != nil {
+			current.Entries[fingerprint.Path] = fingerprintRecord{
+				ModTime:  infos[index].ModTime().UnixNano(),
+				Size:     infos[index].Size(),
+				TextSize: fingerprint.Size,
+				Hash:     fingerprint.Hash,
+			}
+		}
+	}
+	return fingerprints, current
+}
+
+// fingerprintFile returns the fingerprint of a file, reusing the cached one when the file is unchanged,
+// along with the file information the fingerprint describes when a cache is used
+func (w *WfpScanner) fingerprintFile(filePath string, previous *fingerprintCache) (*fileFingerprint, os.FileInfo, error) {
+	if previous == nil {
+		atomic.AddInt64(&w.hashed, 1)
+		fingerprint, err := w.generateFileFingerprint(filePath)
+		return fingerprint, nil, err
 	}
 
+	// Stat before hashing so a concurrent modification is picked up by the next run
+	info, err := os.Stat(filePath)
 	if err != nil {
-		return "", fmt.Errorf("error walking directory: %w", err)
+		return nil, nil, err
+	}
+	if fingerprint, ok := previous.lookup(w.relativePath(filePath), info); ok {
+		return fingerprint, info, nil
 	}
 
-	w.log.Infof("Fingerprint file generated: %s", wfpFile)
-	return wfpFile, nil
+	atomic.AddInt64(&w.hashed, 1)
+	fingerprint, err := w.generateFileFingerprint(filePath)
+	return fingerprint, info, err
+}
+
+// formatFingerprints renders fingerprints as WFP lines, grouping identical content when deduplication is enabled
+func (w *WfpScanner) formatFingerprints(fingerprints []*fileFingerprint) []string {
+	var lines []string
+
+	if !w.config.DedupWfp {
+		for _, fingerprint := range fingerprints {
+			if fingerprint != nil {
+				lines = append(lines, fingerprint.String())
+			}
+		}
+		return lines
+	}
+
+	// Group paths by content hash, keeping groups in order of first appearance
+	groups := make(map[string][]string)
+	var order []*fileFingerprint
+	for _, fingerprint := range fingerprints {
+		if fingerprint == nil {
+			continue
+		}
+		if _, exists := groups[fingerprint.Hash]; !exists {
+			order = append(order, fingerprint)
+		}
+		groups[fingerprint.Hash] = append(groups[fingerprint.Hash], fingerprint.Path)
+	}
+
+	for _, fingerprint := range order {
+		lines = append(lines, fmt.Sprintf("hash=%s,size=%d,files=%s",
+			fingerprint.Hash, fingerprint.Size, joinDedupPaths(groups[fingerprint.Hash])))
+	}
+	return lines
+}
+
+// ExpandWfpLines converts deduplicated WFP lines back to one file= entry per path
+func ExpandWfpLines(lines []string) []string {
+	var expanded []string
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "hash=") {
+			expanded = append(expanded, line)
+			continue
+		}
+
+		parts := strings.SplitN(line, ",files=", 2)
+		if len(parts) != 2 {
+			expanded = append(expanded, line)
+			continue
+		}
+
+		// parts[0] is "hash=<md5>,size=<n>"
+		for _, path := range splitDedupPaths(parts[1]) {
+			expanded = append(expanded, fmt.Sprintf("file=%s,%s", path, parts[0]))
+		}
+	}
+
+	return expanded
 }
 
 // shouldSkipFile determines if a file should be skipped during fingerprinting
@@ -145,66 +533,83 @@ func (w *WfpScanner) shouldSkipFile(path string, info os.FileInfo) bool {
 	}
 
 	// Skip binary files based on extension
-	ext := strings.ToLower(filepath.Ext(path))
-	binaryExts := []string{
-		".exe", ".dll", ".so", ".dylib", ".jar", ".war", ".ear",
-		".zip", ".tar", ".gz", ".bz2", ".7z", ".rar",
-		".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico",
-		".mp3", ".mp4", ".avi", ".mov", ".wav",
-		".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
-		".bin", ".class", ".o", ".a", ".lib",
+	if w.isBinaryFile(path) {
+		return true
 	}
 
-	for _, binaryExt := range binaryExts {
-		if ext == binaryExt {
-			return true
-		}
+	// Skip files outside the fingerprinted size range, keeping empty files when asked to
+	size := info.Size()
+	if size == 0 && w.config.IncludeEmpty {
+		return false
 	}
+	return size < w.config.GetMinFileSize() || size > config.MaxFingerprintFileSize
+}
 
-	// Skip files larger than 1MB
-	if info.Size() > 1024*1024 {
-		return true
+// isBinaryFile reports whether a file is skipped as binary by its extension. --source-ext
+// overrides the built-in list and --binary-ext extends it.
+func (w *WfpScanner) isBinaryFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if w.config != nil {
+		if slices.Contains(w.config.GetSourceExts(), ext) {
+			return false
+		}
+		if slices.Contains(w.config.GetBinaryExts(), ext) {
+			return true
+		}
 	}
-
-	return false
+	return slices.Contains(binaryExtensions, ext)
 }
 
-// generateFileFingerprint generates a fingerprint for a single file
-func (w *WfpScanner) generateFileFingerprint(filePath string) (string, error) {
+// generateFileFingerprint generates a fingerprint for a single file, returning nil for empty files
+// unless --include-empty records them as zero-size entries
+func (w *WfpScanner) generateFileFingerprint(filePath string) (*fileFingerprint, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer func(file *os.File) {
 		_ = file.Close()
 	}(file)
 
-	// Read file content
-	content, err := io.ReadAll(file)
+	// Hash the UTF-8 text so a copy saved with a byte order mark or as UTF-16 matches
+	buf := w.readBuffers().get()
+	defer w.readBuffers().put(buf)
+	hash, size, err := hashText(file, *buf, !w.isBinaryFile(filePath))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Skip empty files
-	if len(content) == 0 {
-		return "", nil
+	if size == 0 && !w.config.IncludeEmpty {
+		return nil, nil
 	}
 
-	// Generate MD5 hash
-	hash := md5.Sum(content)
-	hashStr := fmt.Sprintf("%x", hash)
+	return &fileFingerprint{
+		Path: w.relativePath(filePath),
+		Hash: fmt.Sprintf("%x", hash),
+		Size: size,
+	}, nil
+}
+
+// readBuffers returns the pool of buffers files are read with, sized by --read-buffer
+func (w *WfpScanner) readBuffers() *bufferPool {
+	w.buffersOnce.Do(func() {
+		size := config.DefaultReadBufferSize
+		if w.config != nil {
+			size = w.config.GetReadBufferSize()
+		}
+		w.buffers = newBufferPool(size)
+	})
+	return w.buffers
+}
 
-	// Get relative path
+// relativePath returns the slash-separated path of a file relative to the task directory
+func (w *WfpScanner) relativePath(filePath string) string {
 	relPath, err := filepath.Rel(w.config.TaskDir, filePath)
 	if err != nil {
 		relPath = filePath
 	}
-
-	// Format: file=path,hash=md5hash,size=filesize
-	fingerprint := fmt.Sprintf("file=%s,hash=%s,size=%d",
-		strings.ReplaceAll(relPath, "\\", "/"), hashStr, len(content))
-
-	return fingerprint, nil
+	return strings.ReplaceAll(relPath, "\\", "/")
 }
 
 // shouldIncludeFile checks if a file should be included in scanning
@@ -244,20 +649,8 @@ func (w *WfpScanner) shouldIncludeFile(path string, info ...os.FileInfo) bool {
 		}
 
 		// Skip binary files based on extension
-		ext := strings.ToLower(filepath.Ext(path))
-		binaryExts := []string{
-			".exe", ".dll", ".so", ".dylib", ".jar", ".war", ".ear",
-			".zip", ".tar", ".gz", ".bz2", ".7z", ".rar",
-			".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico",
-			".mp3", ".mp4", ".avi", ".mov", ".wav",
-			".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
-			".bin", ".class", ".o", ".a", ".lib",
-		}
-
-		for _, binaryExt := range binaryExts {
-			if ext == binaryExt {
-				return false
-			}
+		if w.isBinaryFile(path) {
+			return false
 		}
 
 		return true
@@ -277,8 +670,10 @@ func (w *WfpScanner) calculateFileHash(filePath string) (string, error) {
 		_ = file.Close()
 	}(file)
 
+	buf := w.readBuffers().get()
+	defer w.readBuffers().put(buf)
 	hash := md5.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	if _, err := io.CopyBuffer(hash, struct{ io.Reader }{file}, *buf); err != nil {
 		return "", err
 	}
 
@@ -295,38 +690,7 @@ func (w *WfpScanner) countLines(filePath string) (int, error) {
 		_ = file.Close()
 	}(file)
 
-	content, err := io.ReadAll(file)
-	if err != nil {
-		return 0, err
-	}
-
-	if len(content) == 0 {
-		return 0, nil
-	}
-
-	contentStr := string(content)
-
-	// Split by newlines to get all line segments
-	lines := strings.Split(contentStr, "\n")
-
-	// Remove the last empty element only if the string ends with newline
-	// and there are no empty lines in the middle
-	if len(lines) > 1 && lines[len(lines)-1] == "" && strings.HasSuffix(contentStr, "\n") {
-		// Check if there are empty lines in the middle
-		hasEmptyLinesInMiddle := false
-		for i := 1; i < len(lines)-1; i++ {
-			if lines[i] == "" {
-				hasEmptyLinesInMiddle = true
-				break
-			}
-		}
-
-		// If there are empty lines in the middle, keep all lines including the trailing empty one
-		// If no empty lines in middle, remove the trailing empty line
-		if !hasEmptyLinesInMiddle {
-			return len(lines) - 1, nil
-		}
-	}
-
-	return len(lines), nil
+	buf := w.readBuffers().get()
+	defer w.readBuffers().put(buf)
+	return countTextLines(file, *buf)
 }
@@ -1,10 +1,18 @@
 package scanner
 
 import (
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 )
@@ -96,6 +104,729 @@ func TestWfpScanner_GenerateWfpFile(t *testing.T) {
 	_ = os.Remove(wfpFile)
 }
 
+func TestWfpScanner_GenerateWfpFile_Deterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	outDir := filepath.Join(tempDir, "out")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	// Create enough files across directories for workers to finish out of order
+	for i := 0; i < 50; i++ {
+		fullPath := filepath.Join(scanDir, fmt.Sprintf("pkg%d", i%5), fmt.Sprintf("file%d.go", i))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := strings.Repeat(fmt.Sprintf("// line %d\n", i), i+1)
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	cfg := &config.ScanConfig{
+		ThreadNum: "8",
+		ToPath:    outDir,
+	}
+
+	scanner := NewWfpScanner(cfg)
+	wfpFile, err := scanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("First GenerateWfpFile failed: %v", err)
+	}
+	first, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	wfpFile, err = scanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("Second GenerateWfpFile failed: %v", err)
+	}
+	second, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Error("Expected byte-identical WFP output across runs")
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(first)), "\n")
+	if len(lines) != 50 {
+		t.Fatalf("Expected 50 fingerprints, got %d", len(lines))
+	}
+	if !sort.StringsAreSorted(lines) {
+		t.Error("Expected fingerprints to be ordered by file path")
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_MaxDepth(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	for _, name := range []string{"main.go", "a/shallow.go", "a/b/c/deep.go"} {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("package "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.ScanConfig{
+		ToPath:   tempDir,
+		MaxDepth: 1,
+	}
+
+	wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "file=a/shallow.go,") {
+		t.Error("Expected file within max depth to be fingerprinted")
+	}
+	if strings.Contains(string(content), "deep.go") {
+		t.Error("Expected file beyond max depth to be skipped")
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_DeepTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Go prefixes long paths on Windows, the PATH_MAX limit is Unix only")
+	}
+
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(scanDir, 0755); err != nil {
+		t.Fatalf("Failed to create scan directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(scanDir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	// Nest directories one relative step at a time until the full path exceeds PATH_MAX (4096)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+	if err := os.Chdir(scanDir); err != nil {
+		t.Fatalf("Failed to enter scan directory: %v", err)
+	}
+	name := strings.Repeat("d", 200)
+	for i := 0; i < 22; i++ {
+		if err := os.Mkdir(name, 0755); err != nil {
+			t.Fatalf("Failed to create level %d: %v", i, err)
+		}
+		if err := os.Chdir(name); err != nil {
+			t.Fatalf("Failed to enter level %d: %v", i, err)
+		}
+	}
+	if err := os.WriteFile("deep.go", []byte("package deep"), 0644); err != nil {
+		t.Fatalf("Failed to create deep.go: %v", err)
+	}
+	if err := os.Chdir(wd); err != nil {
+		t.Fatalf("Failed to restore working directory: %v", err)
+	}
+
+	scanner := NewWfpScanner(&config.ScanConfig{ToPath: tempDir})
+	wfpFile, err := scanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "file=main.go,") {
+		t.Error("Expected the shallow file to be fingerprinted despite the deep subtree")
+	}
+	tooLong := scanner.TooLongPaths()
+	if len(tooLong) == 0 {
+		t.Fatal("Expected the subtree beyond PATH_MAX to be recorded as too long")
+	}
+	for _, path := range tooLong {
+		if !strings.HasPrefix(path, scanDir) || len(path) <= 4096-len(name) {
+			t.Errorf("Expected a path near or beyond PATH_MAX below the scan directory, got one of %d bytes", len(path))
+		}
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_ExcludePaths(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	for _, name := range []string{"main.go", "docs/guide.md", "web/app.min.js", "web/app.js"} {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.ScanConfig{
+		ToPath:       tempDir,
+		ExcludePaths: []string{"docs/**", "*.min.js"},
+	}
+
+	wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	if strings.Contains(string(content), "guide.md") || strings.Contains(string(content), "app.min.js") {
+		t.Errorf("Expected excluded paths to be skipped, got:\n%s", content)
+	}
+	if !strings.Contains(string(content), "file=web/app.js,") {
+		t.Error("Expected non-excluded file to be fingerprinted")
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_ExcludeTests(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	files := []string{"main.go", "test_util.go", "contest/entry.go", "pkg/parser/testdata/input.go",
+		"src/__tests__/app.test.js", "gen/api.go"}
+	for _, name := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+	filesFrom := filepath.Join(tempDir, "changed.txt")
+	if err := os.WriteFile(filesFrom, []byte("main.go\npkg/parser/testdata/input.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file list: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		excludeTests bool
+		filesFrom    string
+		included     []string
+		excluded     []string
+	}{
+		{"off", false, "", files, nil},
+		{"on", true, "", []string{"main.go", "test_util.go", "contest/entry.go"},
+			[]string{"pkg/parser/testdata/input.go", "src/__tests__/app.test.js", "gen/api.go"}},
+		{"on with files-from", true, filesFrom, []string{"main.go"}, []string{"pkg/parser/testdata/input.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.ScanConfig{ToPath: t.TempDir(), ExcludeTests: tt.excludeTests, FilesFrom: tt.filesFrom}
+			wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+			if err != nil {
+				t.Fatalf("GenerateWfpFile failed: %v", err)
+			}
+			content, err := os.ReadFile(wfpFile)
+			if err != nil {
+				t.Fatalf("Failed to read WFP file: %v", err)
+			}
+
+			for _, name := range tt.included {
+				if !strings.Contains(string(content), "file="+name+",") {
+					t.Errorf("Expected %s to be fingerprinted, got:\n%s", name, content)
+				}
+			}
+			for _, name := range tt.excluded {
+				if strings.Contains(string(content), "file="+name+",") {
+					t.Errorf("Expected %s to be skipped, got:\n%s", name, content)
+				}
+			}
+		})
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_EmptyFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.ScanConfig
+		wantEmpty bool
+		wantSmall bool
+	}{
+		{"default skips empty files", config.ScanConfig{}, false, true},
+		{"include empty", config.ScanConfig{IncludeEmpty: true}, true, true},
+		{"include empty above min size", config.ScanConfig{IncludeEmpty: true, MinFileSize: 10}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			scanDir := filepath.Join(tempDir, "project")
+			if err := os.MkdirAll(scanDir, 0755); err != nil {
+				t.Fatalf("Failed to create scan directory: %v", err)
+			}
+			files := map[string]string{"empty.txt": "", "small.txt": "tiny", "main.go": "package main\n\nfunc main() {}\n"}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(scanDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to create test file %s: %v", name, err)
+				}
+			}
+
+			cfg := tt.cfg
+			cfg.ToPath = tempDir
+			wfpFile, err := NewWfpScanner(&cfg).GenerateWfpFile(scanDir)
+			if err != nil {
+				t.Fatalf("GenerateWfpFile failed: %v", err)
+			}
+			content, err := os.ReadFile(wfpFile)
+			if err != nil {
+				t.Fatalf("Failed to read WFP file: %v", err)
+			}
+
+			emptyEntry := "file=empty.txt,hash=d41d8cd98f00b204e9800998ecf8427e,size=0"
+			if got := strings.Contains(string(content), emptyEntry); got != tt.wantEmpty {
+				t.Errorf("Expected empty file entry %v, got:\n%s", tt.wantEmpty, content)
+			}
+			if got := strings.Contains(string(content), "file=small.txt,"); got != tt.wantSmall {
+				t.Errorf("Expected small file entry %v, got:\n%s", tt.wantSmall, content)
+			}
+			if !strings.Contains(string(content), "file=main.go,") {
+				t.Error("Expected main.go to be fingerprinted")
+			}
+		})
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_CustomExtensions(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(scanDir, 0755); err != nil {
+		t.Fatalf("Failed to create scan directory: %v", err)
+	}
+	files := map[string]string{
+		"module.myext": "define module\n",
+		"firmware.bin": "source kept in a .bin file\n",
+		"table.dat":    "generated table\n",
+		"main.go":      "package main\n",
+		"archive.zip":  "not really a zip\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(scanDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.ScanConfig{ToPath: tempDir, SourceExts: []string{".myext", "BIN"}, BinaryExts: []string{"dat"}}
+	wfpScanner := NewWfpScanner(cfg)
+	wfpFile, err := wfpScanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	for name, want := range map[string]bool{"module.myext": true, "firmware.bin": true, "main.go": true, "table.dat": false, "archive.zip": false} {
+		if got := strings.Contains(string(content), "file="+name+","); got != want {
+			t.Errorf("Expected %s fingerprinted %v, got:\n%s", name, want, content)
+		}
+	}
+
+	// Custom source extensions count as source files of their own language
+	summary, err := wfpScanner.DetectLanguages(scanDir)
+	if err != nil {
+		t.Fatalf("DetectLanguages failed: %v", err)
+	}
+	if summary.SourceFiles != 3 {
+		t.Errorf("Expected 3 source files, got %d: %+v", summary.SourceFiles, summary.Languages)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_Dockerignore(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "context")
+
+	files := map[string]string{
+		".dockerignore":        "# Not part of the image\ntestdata\n**/*.log\n!keep.log\n",
+		"Dockerfile":           "FROM scratch\n",
+		"main.go":              "package main\n",
+		"testdata/fixture.go":  "package testdata\n",
+		"testdata/deep/big.go": "package deep\n",
+		"logs/debug.log":       "debug\n",
+		"keep.log":             "kept\n",
+		"pkg/testdata/util.go": "package testdata\n",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	generate := func(scanType string) string {
+		cfg := &config.ScanConfig{ToPath: tempDir, ScanType: scanType}
+		wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+		if err != nil {
+			t.Fatalf("GenerateWfpFile failed: %v", err)
+		}
+		content, err := os.ReadFile(wfpFile)
+		if err != nil {
+			t.Fatalf("Failed to read WFP file: %v", err)
+		}
+		return string(content)
+	}
+
+	content := generate("docker")
+	for _, excluded := range []string{"testdata/fixture.go", "testdata/deep/big.go", "logs/debug.log"} {
+		if strings.Contains(content, "file="+excluded+",") {
+			t.Errorf("Expected %s to be left out of the build context, got:\n%s", excluded, content)
+		}
+	}
+	// Patterns are anchored at the context root, and negations re-include files
+	for _, included := range []string{"main.go", "Dockerfile", "keep.log", "pkg/testdata/util.go"} {
+		if !strings.Contains(content, "file="+included+",") {
+			t.Errorf("Expected %s to be fingerprinted, got:\n%s", included, content)
+		}
+	}
+
+	// Source scans fingerprint the whole directory
+	if content := generate("source"); !strings.Contains(content, "file=testdata/fixture.go,") {
+		t.Errorf("Expected source scans to ignore .dockerignore, got:\n%s", content)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_Dedup(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	files := map[string]string{
+		"src/util.js":         "module.exports = {}\n",
+		"vendor_copy/util.js": "module.exports = {}\n",
+		"src/main.js":         "require('./util')\n",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	plainFile, err := NewWfpScanner(&config.ScanConfig{ToPath: tempDir}).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	plain, err := os.ReadFile(plainFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	dedupFile, err := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, DedupWfp: true}).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile with dedup failed: %v", err)
+	}
+	dedup, err := os.ReadFile(dedupFile)
+	if err != nil {
+		t.Fatalf("Failed to read deduplicated WFP file: %v", err)
+	}
+
+	dedupLines := strings.Split(strings.TrimSpace(string(dedup)), "\n")
+	if len(dedupLines) != 2 {
+		t.Fatalf("Expected 2 unique hash entries, got %d: %v", len(dedupLines), dedupLines)
+	}
+
+	found := false
+	for _, line := range dedupLines {
+		if strings.HasSuffix(line, ",files=src/util.js|vendor_copy/util.js") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a single hash entry listing both identical files, got %v", dedupLines)
+	}
+
+	// Expanding the deduplicated form restores the per-path entries
+	expanded := ExpandWfpLines(dedupLines)
+	plainLines := strings.Split(strings.TrimSpace(string(plain)), "\n")
+	sort.Strings(expanded)
+	sort.Strings(plainLines)
+	if strings.Join(expanded, "\n") != strings.Join(plainLines, "\n") {
+		t.Errorf("Expanded WFP does not match per-path output:\n%v\n%v", expanded, plainLines)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_DedupSeparatorInName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("| is not allowed in Windows file names")
+	}
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	for _, name := range []string{"a|b.txt", "e.txt"} {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("same content\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	dedupFile, err := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, DedupWfp: true}).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile with dedup failed: %v", err)
+	}
+	dedup, err := os.ReadFile(dedupFile)
+	if err != nil {
+		t.Fatalf("Failed to read deduplicated WFP file: %v", err)
+	}
+	dedupLines := strings.Split(strings.TrimSpace(string(dedup)), "\n")
+	if len(dedupLines) != 1 || !strings.HasSuffix(dedupLines[0], `,files=a\|b.txt|e.txt`) {
+		t.Fatalf("Expected one entry with escaped paths, got %v", dedupLines)
+	}
+
+	var paths []string
+	for _, line := range ExpandWfpLines(dedupLines) {
+		path, _, _ := strings.Cut(strings.TrimPrefix(line, "file="), ",hash=")
+		paths = append(paths, path)
+	}
+	if expected := []string{"a|b.txt", "e.txt"}; !slices.Equal(paths, expected) {
+		t.Errorf("Expected expanded paths %q, got %q", expected, paths)
+	}
+
+	line, prefixed := prefixWfpLine(dedupLines[0], "lib")
+	if expected := []string{"lib/a|b.txt", "lib/e.txt"}; !slices.Equal(prefixed, expected) || !strings.HasSuffix(line, `,files=lib/a\|b.txt|lib/e.txt`) {
+		t.Errorf("Expected prefixed paths %q, got %q in %s", expected, prefixed, line)
+	}
+
+	// The escape character is escaped as well, so WFP files from elsewhere round-trip
+	if paths := splitDedupPaths(joinDedupPaths([]string{`x\|y`, "z"})); !slices.Equal(paths, []string{`x\|y`, "z"}) {
+		t.Errorf("Expected escaped paths to round-trip, got %q", paths)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_FilesFrom(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	for _, name := range []string{"main.go", "src/changed.js", "src/unchanged.js", "docs/guide.md"} {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	listFile := filepath.Join(tempDir, "changed.txt")
+	if err := os.WriteFile(listFile, []byte("src/changed.js\r\n\nmain.go\n./main.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file list: %v", err)
+	}
+
+	wfpFile, err := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, FilesFrom: listFile}).GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "file=main.go,") || !strings.HasPrefix(lines[1], "file=src/changed.js,") {
+		t.Errorf("Expected only the listed files to be fingerprinted, got:\n%s", content)
+	}
+
+	// Listed paths must exist below the task directory, also through symlinks
+	lists := []string{"main.go\nsrc/deleted.js\n", "../changed.txt\n", "src\n"}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink(listFile, filepath.Join(scanDir, "outside.txt")); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+		lists = append(lists, "outside.txt\n")
+	}
+	for _, list := range lists {
+		if err := os.WriteFile(listFile, []byte(list), 0644); err != nil {
+			t.Fatalf("Failed to write file list: %v", err)
+		}
+		if _, err := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, FilesFrom: listFile}).GenerateWfpFile(scanDir); err == nil {
+			t.Errorf("Expected an error for the file list %q", list)
+		}
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_Since(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	now := time.Now()
+	modTimes := map[string]time.Time{
+		"recent.go":     now.Add(-time.Hour),
+		"src/recent.js": now.Add(-2 * time.Hour),
+		"old.go":        now.Add(-48 * time.Hour),
+		"src/old.js":    now.Add(-72 * time.Hour),
+	}
+	for name, modTime := range modTimes {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+		if err := os.Chtimes(fullPath, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set modification time of %s: %v", name, err)
+		}
+	}
+
+	scanner := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, Since: "24h"})
+	wfpFile, err := scanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+
+	for _, name := range []string{"recent.go", "src/recent.js"} {
+		if !strings.Contains(string(content), "file="+name+",") {
+			t.Errorf("Expected recently modified %s to be fingerprinted, got:\n%s", name, content)
+		}
+	}
+	for _, name := range []string{"old.go", "src/old.js"} {
+		if strings.Contains(string(content), "file="+name+",") {
+			t.Errorf("Expected %s, modified before --since, to be skipped", name)
+		}
+	}
+	if since := scanner.Since(); since.IsZero() || since.Before(now.Add(-25*time.Hour)) || since.After(now.Add(-23*time.Hour)) {
+		t.Errorf("Expected the partial generation to be limited to about 24h ago, got %v", since)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_Incremental(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	for _, name := range []string{"modified.go", "deleted.go", "unchanged.go"} {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	generate := func() (*WfpScanner, string) {
+		scanner := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, Incremental: true})
+		wfpFile, err := scanner.GenerateWfpFile(scanDir)
+		if err != nil {
+			t.Fatalf("GenerateWfpFile failed: %v", err)
+		}
+		content, err := os.ReadFile(wfpFile)
+		if err != nil {
+			t.Fatalf("Failed to read WFP file: %v", err)
+		}
+		return scanner, string(content)
+	}
+
+	// The first run seeds the cache
+	scanner, before := generate()
+	if scanner.hashed != 3 {
+		t.Errorf("Expected all 3 files to be hashed on the first run, got %d", scanner.hashed)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, config.DefaultWfpCacheName)); err != nil {
+		t.Fatalf("Expected fingerprint cache to be written: %v", err)
+	}
+
+	modified := filepath.Join(scanDir, "modified.go")
+	if err := os.WriteFile(modified, []byte("new content of modified.go"), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(modified, later, later); err != nil {
+		t.Fatalf("Failed to update modification time: %v", err)
+	}
+	if err := os.Remove(filepath.Join(scanDir, "deleted.go")); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+
+	scanner, after := generate()
+	if scanner.hashed != 1 {
+		t.Errorf("Expected only the modified file to be rehashed, got %d", scanner.hashed)
+	}
+
+	expectedModified := fmt.Sprintf("file=modified.go,hash=%x,size=26", md5.Sum([]byte("new content of modified.go")))
+	if !strings.Contains(after, expectedModified) {
+		t.Errorf("Expected updated fingerprint %q, got:\n%s", expectedModified, after)
+	}
+	if strings.Contains(after, "deleted.go") {
+		t.Errorf("Expected deleted file to be dropped, got:\n%s", after)
+	}
+	unchangedLine := ""
+	for _, line := range strings.Split(before, "\n") {
+		if strings.HasPrefix(line, "file=unchanged.go,") {
+			unchangedLine = line
+		}
+	}
+	if unchangedLine == "" || !strings.Contains(after, unchangedLine) {
+		t.Errorf("Expected unchanged entry %q to be carried forward, got:\n%s", unchangedLine, after)
+	}
+}
+
+func TestWfpScanner_GenerateWfpFile_OutdatedCache(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(scanDir, 0755); err != nil {
+		t.Fatalf("Failed to create scan directory: %v", err)
+	}
+	file := filepath.Join(scanDir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("Failed to stat test file: %v", err)
+	}
+
+	// An entry from an older fingerprint format matches the file but must not be reused
+	stale := fmt.Sprintf(`{"version": %d, "entries": {"main.go": {"modTime": %d, "size": %d, "hash": "stale"}}}`,
+		fingerprintCacheVersion-1, info.ModTime().UnixNano(), info.Size())
+	if err := os.WriteFile(filepath.Join(tempDir, config.DefaultWfpCacheName), []byte(stale), 0644); err != nil {
+		t.Fatalf("Failed to write fingerprint cache: %v", err)
+	}
+
+	scanner := NewWfpScanner(&config.ScanConfig{ToPath: tempDir, Incremental: true})
+	wfpFile, err := scanner.GenerateWfpFile(scanDir)
+	if err != nil {
+		t.Fatalf("GenerateWfpFile failed: %v", err)
+	}
+	content, err := os.ReadFile(wfpFile)
+	if err != nil {
+		t.Fatalf("Failed to read WFP file: %v", err)
+	}
+	if scanner.hashed != 1 || strings.Contains(string(content), "stale") {
+		t.Errorf("Expected the outdated cache to be ignored, hashed %d files:\n%s", scanner.hashed, content)
+	}
+}
+
 func TestWfpScanner_GenerateWfpFile_EmptyDirectory(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -314,3 +1045,118 @@ func BenchmarkWfpScanner_shouldIncludeFile(b *testing.B) {
 		}
 	}
 }
+
+func TestWfpScanner_GenerateWfpFile_FileManifest(t *testing.T) {
+	tempDir := t.TempDir()
+	scanDir := filepath.Join(tempDir, "project")
+
+	files := map[string]string{
+		"src/main.go":         "package main\n",
+		"src/util.go":         "package main\n\nfunc util() {}\n",
+		"generated/schema.go": "package generated\n",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(scanDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	for _, manifestName := range []string{"manifest.json", "manifest.csv"} {
+		t.Run(manifestName, func(t *testing.T) {
+			manifestFile := filepath.Join(tempDir, manifestName)
+			cfg := &config.ScanConfig{ToPath: tempDir, ExcludePaths: []string{"generated/**"}, FileManifest: manifestFile}
+
+			wfpFile, err := NewWfpScanner(cfg).GenerateWfpFile(scanDir)
+			if err != nil {
+				t.Fatalf("GenerateWfpFile failed: %v", err)
+			}
+			wfp, err := os.ReadFile(wfpFile)
+			if err != nil {
+				t.Fatalf("Failed to read WFP file: %v", err)
+			}
+			wfpLines := strings.Split(strings.TrimSpace(string(wfp)), "\n")
+
+			data, err := os.ReadFile(manifestFile)
+			if err != nil {
+				t.Fatalf("Failed to read file manifest: %v", err)
+			}
+			var paths []string
+			if strings.HasSuffix(manifestName, ".csv") {
+				records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+				if err != nil {
+					t.Fatalf("Failed to parse CSV manifest: %v", err)
+				}
+				for _, record := range records[1:] {
+					paths = append(paths, record[0])
+				}
+			} else {
+				var entries []manifestEntry
+				if err := json.Unmarshal(data, &entries); err != nil {
+					t.Fatalf("Failed to parse JSON manifest: %v", err)
+				}
+				for _, entry := range entries {
+					if entry.Hash == "" || entry.Size == 0 {
+						t.Errorf("Expected hash and size for %s, got %+v", entry.Path, entry)
+					}
+					paths = append(paths, entry.Path)
+				}
+			}
+
+			if len(paths) != len(wfpLines) {
+				t.Errorf("Expected %d manifest rows, one per fingerprinted file, got %d: %v", len(wfpLines), len(paths), paths)
+			}
+			for _, path := range paths {
+				if strings.HasPrefix(path, "generated/") {
+					t.Errorf("Excluded file %s should not be listed in the manifest", path)
+				}
+			}
+		})
+	}
+}
+
+func TestWfpScanner_generateFileFingerprint_UTF16(t *testing.T) {
+	tempDir := t.TempDir()
+	text := "print('héllo')\n"
+
+	// UTF-16LE with a byte order mark
+	utf16File := filepath.Join(tempDir, "hello_utf16.py")
+	data := []byte{0xFF, 0xFE}
+	for _, r := range text {
+		data = append(data, byte(r), byte(r>>8))
+	}
+	if err := os.WriteFile(utf16File, data, 0644); err != nil {
+		t.Fatalf("Failed to create UTF-16 file: %v", err)
+	}
+
+	fingerprint, err := NewWfpScanner(&config.ScanConfig{TaskDir: tempDir}).generateFileFingerprint(utf16File)
+	if err != nil {
+		t.Fatalf("generateFileFingerprint failed: %v", err)
+	}
+	if expected := fmt.Sprintf("%x", md5.Sum([]byte(text))); fingerprint.Hash != expected {
+		t.Errorf("Expected the UTF-16 file to hash like its UTF-8 text %s, got %s", expected, fingerprint.Hash)
+	}
+	if fingerprint.Size != int64(len(text)) {
+		t.Errorf("Expected the size of the decoded text %d, got %d", len(text), fingerprint.Size)
+	}
+
+	// Binaries are hashed as is, whether classified by extension or holding NULs once decoded
+	binary := []byte{0xFF, 0xFE, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}
+	for _, name := range []string{"icon.ico", "blob"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, binary, 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		fingerprint, err := NewWfpScanner(&config.ScanConfig{TaskDir: tempDir}).generateFileFingerprint(path)
+		if err != nil {
+			t.Fatalf("generateFileFingerprint failed for %s: %v", name, err)
+		}
+		if expected := fmt.Sprintf("%x", md5.Sum(binary)); fingerprint.Hash != expected || fingerprint.Size != int64(len(binary)) {
+			t.Errorf("Expected %s to hash as is to %s with size %d, got %s with size %d",
+				name, expected, len(binary), fingerprint.Hash, fingerprint.Size)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"unicode/utf16"
+)
+
+// Byte order marks recognized at the start of text files
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodeText normalizes text file content to UTF-8 without a byte order mark. UTF-16 content
+// is only recognized by its byte order mark; anything else is returned unchanged.
+func DecodeText(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return data[len(bomUTF8):]
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+	default:
+		return data
+	}
+}
+
+// decodeUTF16 converts UTF-16 code units to UTF-8, dropping a trailing odd byte
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// ReadTextFile reads a text file as UTF-8, stripping a byte order mark and converting UTF-16
+func ReadTextFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeText(data), nil
+}
+
+// OpenTextFile opens a text file for reading as UTF-8 like ReadTextFile. Manifests are small,
+// so the file is read whole.
+func OpenTextFile(path string) (io.ReadCloser, error) {
+	data, err := ReadTextFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
@@ -1,14 +1,34 @@
 package utils
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"syscall"
 )
 
+// errFilenameExcedRange is the Windows ERROR_FILENAME_EXCED_RANGE error
+const errFilenameExcedRange = syscall.Errno(206)
+
+// IsPathTooLong reports whether err was caused by a path exceeding the length limit of the
+// platform, such as PATH_MAX on Unix or MAX_PATH on Windows
+func IsPathTooLong(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	return errno == syscall.ENAMETOOLONG || (runtime.GOOS == "windows" && errno == errFilenameExcedRange)
+}
+
 // IsDirEmpty checks if a directory is empty
 func IsDirEmpty(dirname string) bool {
 	entries, err := os.ReadDir(dirname)
@@ -18,29 +38,104 @@ func IsDirEmpty(dirname string) bool {
 	return len(entries) == 0
 }
 
+// ProjectNameFromDir returns the base name of a project directory, resolving relative paths
+// such as ".". It names projects whose build files do not.
+func ProjectNameFromDir(dir string) string {
+	if absDir, err := filepath.Abs(dir); err == nil {
+		dir = absDir
+	}
+	if name := filepath.Base(dir); name != string(os.PathSeparator) && name != "." {
+		return name
+	}
+	return ""
+}
+
+// Archive formats of the source archive
+const (
+	ArchiveFormatZip   = "zip"
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+// DefaultCompressionLevel selects the default compression level of the archive format
+const DefaultCompressionLevel = flate.DefaultCompression
+
+// CreateArchive creates an archive of the specified directory in the given format, compressed
+// at level 0 (stored) to 9 (smallest) or DefaultCompressionLevel
+func CreateArchive(sourceDir, outputDir, format string, level int) (string, error) {
+	if format == ArchiveFormatTarGz {
+		return CreateTarGzArchive(sourceDir, outputDir, level)
+	}
+	return CreateZipArchive(sourceDir, outputDir, level)
+}
+
 // CreateZipArchive creates a ZIP archive of the specified directory
-func CreateZipArchive(sourceDir, outputDir string) (string, error) {
-	// Create output file path
-	baseName := filepath.Base(sourceDir)
-	zipPath := filepath.Join(outputDir, baseName+".zip")
+func CreateZipArchive(sourceDir, outputDir string, level int) (string, error) {
+	zipPath := filepath.Join(outputDir, filepath.Base(sourceDir)+"."+ArchiveFormatZip)
+	return writeArchive(zipPath, ArchiveFormatZip, level, func(archive archiveWriter) error {
+		return addArchiveDir(archive, sourceDir)
+	})
+}
+
+// CreateTarGzArchive creates a gzip compressed tarball of the specified directory
+func CreateTarGzArchive(sourceDir, outputDir string, level int) (string, error) {
+	tarPath := filepath.Join(outputDir, filepath.Base(sourceDir)+"."+ArchiveFormatTarGz)
+	return writeArchive(tarPath, ArchiveFormatTarGz, level, func(archive archiveWriter) error {
+		return addArchiveDir(archive, sourceDir)
+	})
+}
 
-	// Create ZIP file
-	zipFile, err := os.Create(zipPath)
+// CreateArchiveFiles creates an archive of the given files in the given format, with paths
+// relative to sourceDir. Paths that are missing, not regular files, hidden, skipped by
+// CreateArchive or outside sourceDir, also through a symlinked directory, are skipped.
+func CreateArchiveFiles(sourceDir, outputDir, format string, level int, relPaths []string) (string, error) {
+	root, err := filepath.EvalSymlinks(sourceDir)
 	if err != nil {
-		return "", fmt.Errorf("failed to create zip file: %w", err)
+		return "", fmt.Errorf("failed to resolve %s: %w", sourceDir, err)
 	}
-	defer func(zipFile *os.File) {
-		_ = zipFile.Close()
-	}(zipFile)
 
-	// Create ZIP writer
-	zipWriter := zip.NewWriter(zipFile)
-	defer func(zipWriter *zip.Writer) {
-		_ = zipWriter.Close()
-	}(zipWriter)
+	archivePath := filepath.Join(outputDir, filepath.Base(sourceDir)+"-unmatched."+format)
+	return writeArchive(archivePath, format, level, func(archive archiveWriter) error {
+		for _, relPath := range relPaths {
+			localPath := filepath.FromSlash(relPath)
+			if !filepath.IsLocal(localPath) || hasHiddenComponent(relPath) || shouldSkipForArchive(localPath) {
+				continue
+			}
+
+			path := filepath.Join(sourceDir, localPath)
+			info, err := os.Lstat(path)
+			if err != nil || !info.Mode().IsRegular() || !withinDir(root, path) {
+				continue
+			}
+
+			if err := archive.add(path, relPath, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// hasHiddenComponent reports whether a slash-separated path names a hidden file or lies below
+// a hidden directory
+func hasHiddenComponent(relPath string) bool {
+	return slices.ContainsFunc(strings.Split(filepath.ToSlash(relPath), "/"), func(name string) bool {
+		return strings.HasPrefix(name, ".")
+	})
+}
+
+// withinDir reports whether path, with every symlink resolved, lies below the resolved directory root
+func withinDir(root, path string) bool {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && filepath.IsLocal(rel)
+}
 
-	// Walk through source directory
-	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+// addArchiveDir adds the files of a directory to the archive, skipping build output and hidden files
+func addArchiveDir(archive archiveWriter, sourceDir string) error {
+	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -56,42 +151,137 @@ func CreateZipArchive(sourceDir, outputDir string) (string, error) {
 			return err
 		}
 
-		// Normalize path separators for ZIP
-		relPath = strings.ReplaceAll(relPath, "\\", "/")
+		return archive.add(path, relPath, info)
+	})
+}
 
-		// Create file header
-		header, err := zip.FileInfoHeader(info)
-		if err != nil {
-			return err
-		}
-		header.Name = relPath
-		header.Method = zip.Deflate
+// writeArchive creates the archive file at archivePath and fills it, removing it again on error
+func writeArchive(archivePath, format string, level int, fill func(archiveWriter) error) (string, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s file: %w", format, err)
+	}
+
+	archive := newArchiveWriter(file, format, level)
+	err = fill(archive)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(archivePath) // Clean up on error
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	return archivePath, nil
+}
 
-		// Create writer for this file
-		writer, err := zipWriter.CreateHeader(header)
+// archiveWriter writes files into a ZIP or tar.gz archive
+type archiveWriter interface {
+	// add copies a file into the archive under the given relative path
+	add(path, relPath string, info os.FileInfo) error
+	Close() error
+}
+
+// newArchiveWriter returns a writer of the given format compressing at level
+func newArchiveWriter(w io.Writer, format string, level int) archiveWriter {
+	if format == ArchiveFormatTarGz {
+		// The level is validated by the configuration, fall back to the default if it is not
+		gz, err := gzip.NewWriterLevel(w, level)
 		if err != nil {
-			return err
+			gz = gzip.NewWriter(w)
 		}
+		return &tarGzWriter{gz: gz, tar: tar.NewWriter(gz)}
+	}
+
+	zw := &zipWriter{zip: zip.NewWriter(w), method: zip.Deflate}
+	switch {
+	case level == flate.NoCompression:
+		zw.method = zip.Store
+	case level != flate.DefaultCompression:
+		zw.zip.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	return zw
+}
+
+// zipWriter writes a ZIP archive
+type zipWriter struct {
+	zip    *zip.Writer
+	method uint16
+}
+
+func (zw *zipWriter) add(path, relPath string, info os.FileInfo) error {
+	// Create file header, normalizing path separators for ZIP
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = strings.ReplaceAll(relPath, "\\", "/")
+	header.Method = zw.method
+
+	// Create writer for this file
+	writer, err := zw.zip.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	return copyFile(writer, path)
+}
+
+func (zw *zipWriter) Close() error {
+	return zw.zip.Close()
+}
+
+// tarGzWriter writes a gzip compressed tarball
+type tarGzWriter struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+}
 
-		// Copy file content
-		file, err := os.Open(path)
+func (tw *tarGzWriter) add(path, relPath string, info os.FileInfo) error {
+	// Symbolic links are archived with the content they point to, as in ZIP archives
+	if !info.Mode().IsRegular() {
+		target, err := os.Stat(path)
 		if err != nil {
 			return err
 		}
-		defer func(file *os.File) {
-			_ = file.Close()
-		}(file)
+		info = target
+	}
 
-		_, err = io.Copy(writer, file)
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
 		return err
-	})
+	}
+	header.Name = strings.ReplaceAll(relPath, "\\", "/")
 
+	if err := tw.tar.WriteHeader(header); err != nil {
+		return err
+	}
+	return copyFile(tw.tar, path)
+}
+
+func (tw *tarGzWriter) Close() error {
+	if err := tw.tar.Close(); err != nil {
+		_ = tw.gz.Close()
+		return err
+	}
+	return tw.gz.Close()
+}
+
+// copyFile copies the content of the file at path to w
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
 	if err != nil {
-		_ = os.Remove(zipPath) // Clean up on error
-		return "", fmt.Errorf("failed to create archive: %w", err)
+		return err
 	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
 
-	return zipPath, nil
+	_, err = io.Copy(w, file)
+	return err
 }
 
 // shouldSkipForArchive determines if a file should be skipped when creating archives
@@ -151,6 +341,50 @@ func IsTextFile(filePath string) bool {
 	return false
 }
 
+// PathDepth returns the number of directory levels between root and path (0 for root itself)
+func PathDepth(root, path string) int {
+	relPath, err := filepath.Rel(root, path)
+	if err != nil || relPath == "." {
+		return 0
+	}
+	return strings.Count(filepath.ToSlash(relPath), "/") + 1
+}
+
+// MatchesPathPattern reports whether a slash-separated path relative to the scan root
+// matches any pattern. Patterns ending in "/" or "/**" match a directory and everything
+// below it, patterns containing "/" match the whole relative path, and other patterns
+// match any single path element (e.g. "*.min.js" or "testdata").
+func MatchesPathPattern(relPath string, patterns []string) bool {
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(pattern)), "./")
+		if pattern == "" {
+			continue
+		}
+
+		if dir := strings.TrimSuffix(strings.TrimSuffix(pattern, "**"), "/"); dir != pattern {
+			if relPath == dir || strings.HasPrefix(relPath, dir+"/") {
+				return true
+			}
+			continue
+		}
+
+		if strings.Contains(pattern, "/") {
+			if matched, _ := filepath.Match(pattern, relPath); matched {
+				return true
+			}
+			continue
+		}
+
+		for _, element := range strings.Split(relPath, "/") {
+			if matched, _ := filepath.Match(pattern, element); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // NormalizePath normalizes a file path for cross-platform compatibility
 func NormalizePath(path string) string {
 	return filepath.ToSlash(path)
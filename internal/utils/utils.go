@@ -6,7 +6,9 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // SanitizeFileName removes or replaces invalid characters from a filename
@@ -59,3 +61,59 @@ func GetFileSize(filePath string) (int64, error) {
 	}
 	return info.Size(), nil
 }
+
+// byteSizeUnits maps size suffixes to their multiplier; K, M and G are binary multiples
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+}
+
+// ParseByteSize parses a size such as "512KB", "5MB" or "1048576" into bytes
+func ParseByteSize(size string) (int64, error) {
+	size = strings.TrimSpace(size)
+	i := strings.IndexFunc(size, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i == -1 {
+		i = len(size)
+	}
+
+	multiplier, ok := byteSizeUnits[strings.ToUpper(strings.TrimSpace(size[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit in %q", size)
+	}
+	value, err := strconv.ParseFloat(size[:i], 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// sinceLayouts are the timestamp layouts accepted by ParseSince, most specific first
+var sinceLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// ParseSince parses a point in time given as a duration before now, such as "24h", or as an
+// RFC 3339 timestamp or date, such as "2024-05-01T08:00:00Z" or "2024-05-01", in local time
+// when no zone is given
+func ParseSince(since string, now time.Time) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if duration, err := time.ParseDuration(since); err == nil {
+		if duration <= 0 {
+			return time.Time{}, fmt.Errorf("duration %q must be positive", since)
+		}
+		return now.Add(-duration), nil
+	}
+	for _, layout := range sinceLayouts {
+		if t, err := time.ParseInLocation(layout, since, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, must be a duration such as 24h or a timestamp such as 2024-05-01", since)
+}
@@ -1,9 +1,16 @@
 package utils
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFileExists(t *testing.T) {
@@ -59,6 +66,24 @@ func TestIsDirEmpty(t *testing.T) {
 	}
 }
 
+func TestProjectNameFromDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+
+	tests := map[string]string{
+		filepath.Join("projects", "demo"): "demo",
+		".":                               filepath.Base(wd),
+		string(os.PathSeparator):          "",
+	}
+	for dir, expected := range tests {
+		if name := ProjectNameFromDir(dir); name != expected {
+			t.Errorf("ProjectNameFromDir(%q) = %q, expected %q", dir, name, expected)
+		}
+	}
+}
+
 func TestCreateZipArchive(t *testing.T) {
 	// Create a temporary directory structure
 	tempDir := t.TempDir()
@@ -91,7 +116,7 @@ func TestCreateZipArchive(t *testing.T) {
 	}
 
 	// Create zip archive
-	zipFile, err := CreateZipArchive(sourceDir, outputDir)
+	zipFile, err := CreateZipArchive(sourceDir, outputDir, DefaultCompressionLevel)
 	if err != nil {
 		t.Fatalf("CreateZipArchive failed: %v", err)
 	}
@@ -111,6 +136,142 @@ func TestCreateZipArchive(t *testing.T) {
 	}
 }
 
+func TestCreateArchiveFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	sourceDir := filepath.Join(tempDir, "source")
+	for _, file := range []string{"matched.go", "src/unmatched.go", "src/other.go", ".env", ".git/config", "build/out.go"} {
+		fullPath := filepath.Join(sourceDir, file)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", file, err)
+		}
+		if err := os.WriteFile(fullPath, []byte("content of "+file), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", file, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "secret.txt"), []byte("outside"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	requested := []string{"src/unmatched.go", "missing.go", "../secret.txt", "src", ".env", ".git/config", "build/out.go"}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink(tempDir, filepath.Join(sourceDir, "linkdir")); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+		requested = append(requested, "linkdir/secret.txt")
+	}
+
+	zipFile, err := CreateArchiveFiles(sourceDir, tempDir, ArchiveFormatZip, DefaultCompressionLevel, requested)
+	if err != nil {
+		t.Fatalf("CreateArchiveFiles failed: %v", err)
+	}
+
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("Failed to open zip file: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if len(reader.File) != 1 || reader.File[0].Name != "src/unmatched.go" {
+		var names []string
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+		}
+		t.Errorf("Expected only src/unmatched.go in the archive, got %v", names)
+	}
+}
+
+func TestCreateArchive_FormatAndLevel(t *testing.T) {
+	sourceDir := filepath.Join(t.TempDir(), "source")
+	if err := os.MkdirAll(filepath.Join(sourceDir, "src"), 0755); err != nil {
+		t.Fatalf("Failed to create source directory: %v", err)
+	}
+	// Repetitive but irregular text, which higher levels compress noticeably better
+	words := []string{"func", "return", "err", "nil", "if", "package", "import", "string", "int", "for"}
+	var content strings.Builder
+	seed := uint32(1)
+	for i := 0; i < 40000; i++ {
+		seed = seed*1664525 + 1013904223
+		content.WriteString(words[seed>>28%uint32(len(words))] + " ")
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "src", "main.go"), []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	for _, format := range []string{ArchiveFormatZip, ArchiveFormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			sizes := make(map[int]int64)
+			for _, level := range []int{0, 1, 9} {
+				outputDir := t.TempDir()
+				archive, err := CreateArchive(sourceDir, outputDir, format, level)
+				if err != nil {
+					t.Fatalf("CreateArchive failed at level %d: %v", level, err)
+				}
+				if want := filepath.Join(outputDir, "source."+format); archive != want {
+					t.Errorf("Expected archive %s, got %s", want, archive)
+				}
+				if got := readArchiveFile(t, archive, format, "src/main.go"); got != content.String() {
+					t.Errorf("Expected src/main.go to round-trip at level %d, got %d bytes", level, len(got))
+				}
+				info, err := os.Stat(archive)
+				if err != nil {
+					t.Fatalf("Failed to stat archive: %v", err)
+				}
+				sizes[level] = info.Size()
+			}
+
+			if sizes[9] >= sizes[1] || sizes[1] >= sizes[0] {
+				t.Errorf("Expected archives to shrink with the level, got sizes %v", sizes)
+			}
+		})
+	}
+}
+
+// readArchiveFile returns the content of the named file in a ZIP or tar.gz archive
+func readArchiveFile(t *testing.T, archive, format, name string) string {
+	t.Helper()
+	if format == ArchiveFormatZip {
+		reader, err := zip.OpenReader(archive)
+		if err != nil {
+			t.Fatalf("Failed to open zip file: %v", err)
+		}
+		defer func() { _ = reader.Close() }()
+		file, err := reader.Open(name)
+		if err != nil {
+			t.Fatalf("Failed to open %s in the archive: %v", name, err)
+		}
+		defer func() { _ = file.Close() }()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s from the archive: %v", name, err)
+		}
+		return string(data)
+	}
+
+	file, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("Failed to open tarball: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to open gzip stream: %v", err)
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Failed to find %s in the tarball: %v", name, err)
+		}
+		if header.Name == name {
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("Failed to read %s from the tarball: %v", name, err)
+			}
+			return string(data)
+		}
+	}
+}
+
 func TestSanitizeFileName(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -140,6 +301,54 @@ func TestSanitizeFileName(t *testing.T) {
 	}
 }
 
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{"1048576", 1048576, false},
+		{"512KB", 512 << 10, false},
+		{"5MB", 5 << 20, false},
+		{"5 mb", 5 << 20, false},
+		{"1.5MiB", 3 << 19, false},
+		{"2G", 2 << 30, false},
+		{"10TB", 0, true},
+		{"fast", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseByteSize(tt.input)
+		if (err != nil) != tt.wantErr || result != tt.expected {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d, error %t", tt.input, result, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"24h", now.Add(-24 * time.Hour), false},
+		{"90m", now.Add(-90 * time.Minute), false},
+		{"2024-05-01T08:00:00Z", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), false},
+		{"-1h", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseSince(tt.input, now)
+		if (err != nil) != tt.wantErr || !result.Equal(tt.expected) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v, error %t", tt.input, result, err, tt.expected, tt.wantErr)
+		}
+	}
+}
+
 func TestEnsureDir(t *testing.T) {
 	tempDir := t.TempDir()
 	testDir := filepath.Join(tempDir, "test", "nested", "directory")
@@ -222,6 +431,45 @@ func TestCalculateFileHash(t *testing.T) {
 	}
 }
 
+func TestPathDepth(t *testing.T) {
+	root := filepath.Join("home", "project")
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{root, 0},
+		{filepath.Join(root, "src"), 1},
+		{filepath.Join(root, "src", "main", "java"), 3},
+	}
+
+	for _, tt := range tests {
+		if got := PathDepth(root, tt.path); got != tt.expected {
+			t.Errorf("PathDepth(%s, %s) = %d, want %d", root, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestMatchesPathPattern(t *testing.T) {
+	patterns := []string{"docs/**", "third_party/", "*.min.js", "src/gen/*.go"}
+
+	tests := map[string]bool{
+		"docs":                 true,
+		"docs/guide/index.md":  true,
+		"third_party/lib/a.c":  true,
+		"web/app.min.js":       true,
+		"src/gen/types.go":     true,
+		"src/gen/sub/types.go": false,
+		"src/main.go":          false,
+		"documentation/a.md":   false,
+	}
+	for path, expected := range tests {
+		if got := MatchesPathPattern(path, patterns); got != expected {
+			t.Errorf("MatchesPathPattern(%s) = %t, want %t", path, got, expected)
+		}
+	}
+}
+
 func TestGetFileSize(t *testing.T) {
 	tempDir := t.TempDir()
 	testFile := filepath.Join(tempDir, "test.txt")
@@ -277,3 +525,35 @@ func BenchmarkCalculateFileHash(b *testing.B) {
 		_, _ = CalculateFileHash(testFile)
 	}
 }
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"plain", []byte("module a\n"), "module a\n"},
+		{"utf-8 bom", []byte("\xef\xbb\xbfmodule a\n"), "module a\n"},
+		{"utf-16le", []byte{0xFF, 0xFE, 'g', 0, 'o', 0, 0xE9, 0}, "goé"},
+		{"utf-16be", []byte{0xFE, 0xFF, 0, 'g', 0, 'o', 0, 0xE9}, "goé"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(DecodeText(tt.data)); got != tt.expected {
+				t.Errorf("DecodeText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsPathTooLong(t *testing.T) {
+	_, err := os.Stat(filepath.Join(t.TempDir(), strings.Repeat("x", 300)))
+	if runtime.GOOS != "windows" && !IsPathTooLong(err) {
+		t.Errorf("Expected a file name beyond NAME_MAX to be too long, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(t.TempDir(), "missing")); IsPathTooLong(err) {
+		t.Errorf("Expected a missing file not to be too long, got %v", err)
+	}
+}
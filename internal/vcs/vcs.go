@@ -0,0 +1,66 @@
+package vcs
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Info describes the Git repository a scan directory belongs to
+type Info struct {
+	Project string // Repository name from the origin remote, or the work tree directory name
+	Version string // Nearest tag from git describe, or the short commit SHA
+	Commit  string // Full SHA of the checked out commit, empty before the first commit
+	Dirty   bool   // The scan directory holds uncommitted changes or untracked files
+}
+
+// Detect reads repository information for dir. It returns nil when dir is not inside a Git
+// work tree or git is not installed.
+func Detect(dir string) *Info {
+	topLevel, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil || topLevel == "" {
+		return nil
+	}
+
+	info := &Info{Project: filepath.Base(topLevel)}
+	if remote, err := git(dir, "config", "--get", "remote.origin.url"); err == nil {
+		if name := repositoryName(remote); name != "" {
+			info.Project = name
+		}
+	}
+
+	// Fails without commits, leaving the version and commit empty
+	if version, err := git(dir, "describe", "--tags", "--always"); err == nil {
+		info.Version = version
+	}
+	if commit, err := git(dir, "rev-parse", "--verify", "-q", "HEAD"); err == nil {
+		info.Commit = commit
+	}
+
+	// Only changes below dir affect what is scanned; ignored files are left out as git does
+	if status, err := git(dir, "status", "--porcelain", "--", "."); err == nil {
+		info.Dirty = status != ""
+	}
+
+	return info
+}
+
+// repositoryName returns the last path element of a remote URL without the .git suffix,
+// e.g. "git@github.com:org/repo.git" gives "repo"
+func repositoryName(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimRight(strings.TrimSpace(remote), "/"), ".git")
+	if idx := strings.LastIndexAny(remote, "/:"); idx != -1 {
+		remote = remote[idx+1:]
+	}
+	return remote
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,151 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepository creates a Git repository in dir with a single commit
+func initRepository(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "add", "main.go")
+	runGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial")
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestDetect_Tag(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create repository directory: %v", err)
+	}
+	initRepository(t, dir)
+	runGit(t, dir, "tag", "v1.2.3")
+	runGit(t, dir, "remote", "add", "origin", "git@github.com:example/demo-service.git")
+
+	info := Detect(dir)
+	if info == nil {
+		t.Fatal("Expected repository information")
+	}
+	if info.Version != "v1.2.3" {
+		t.Errorf("Expected version v1.2.3, got %q", info.Version)
+	}
+	if info.Project != "demo-service" {
+		t.Errorf("Expected project demo-service from the remote, got %q", info.Project)
+	}
+}
+
+func TestDetect_UntaggedWithoutRemote(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create repository directory: %v", err)
+	}
+	initRepository(t, dir)
+
+	info := Detect(dir)
+	if info == nil {
+		t.Fatal("Expected repository information")
+	}
+	if info.Project != "checkout" {
+		t.Errorf("Expected project from the directory name, got %q", info.Project)
+	}
+	if len(info.Version) < 7 {
+		t.Errorf("Expected the short commit SHA as version, got %q", info.Version)
+	}
+}
+
+func TestDetect_NotRepository(t *testing.T) {
+	if info := Detect(t.TempDir()); info != nil {
+		t.Errorf("Expected nil outside a repository, got %+v", info)
+	}
+}
+
+func TestRepositoryName(t *testing.T) {
+	tests := []struct {
+		remote   string
+		expected string
+	}{
+		{"https://github.com/craftslab/cleansource-cli.git", "cleansource-cli"},
+		{"git@github.com:craftslab/cleansource-cli.git", "cleansource-cli"},
+		{"https://gitlab.example.com/group/project/", "project"},
+		{"/srv/git/local.git", "local"},
+	}
+	for _, tt := range tests {
+		if got := repositoryName(tt.remote); got != tt.expected {
+			t.Errorf("repositoryName(%q) = %q, want %q", tt.remote, got, tt.expected)
+		}
+	}
+}
+
+func TestDetect_CommitAndDirty(t *testing.T) {
+	dir := t.TempDir()
+	initRepository(t, dir)
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatalf("Failed to create docs directory: %v", err)
+	}
+
+	head := exec.Command("git", "-C", dir, "rev-parse", "HEAD")
+	output, err := head.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	commit := strings.TrimSpace(string(output))
+
+	info := Detect(dir)
+	if info == nil || info.Commit != commit || info.Dirty {
+		t.Fatalf("Expected clean commit %s, got %+v", commit, info)
+	}
+
+	// Untracked files outside the scan directory leave it clean
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("draft\n"), 0644); err != nil {
+		t.Fatalf("Failed to create notes.txt: %v", err)
+	}
+	if info := Detect(filepath.Join(dir, "docs")); info == nil || info.Commit != commit || info.Dirty {
+		t.Errorf("Expected the docs directory to be clean, got %+v", info)
+	}
+	if info := Detect(dir); info == nil || !info.Dirty {
+		t.Errorf("Expected an untracked file to make the work tree dirty, got %+v", info)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Fatalf("Failed to remove notes.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify main.go: %v", err)
+	}
+	if info := Detect(dir); info == nil || info.Commit != commit || !info.Dirty {
+		t.Errorf("Expected a modified file to make the work tree dirty, got %+v", info)
+	}
+}
+
+func TestDetect_NoCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+
+	info := Detect(dir)
+	if info == nil {
+		t.Fatal("Expected repository information")
+	}
+	if info.Commit != "" || info.Version != "" {
+		t.Errorf("Expected no commit and version without commits, got %+v", info)
+	}
+}
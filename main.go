@@ -5,11 +5,13 @@ import (
 	"os"
 
 	"github.com/craftslab/cleansource-sca-cli/cmd"
+	"github.com/craftslab/cleansource-sca-cli/internal/app"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		// Command line parsing errors are usage errors
 		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(app.ExitConfig)
 	}
 }
@@ -0,0 +1,150 @@
+package buildtools
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// IsApplicable checks that pom.xml is a Maven project descriptor
+func (ms *MavenScanner) IsApplicable() bool {
+	if _, err := ms.parsePOM(filepath.Join(ms.environment.GetDirectory(), "pom.xml")); err != nil {
+		ms.log.Infof("Ignoring pom.xml in %s: not a Maven project descriptor: %v", ms.environment.GetDirectory(), err)
+		return false
+	}
+	return true
+}
+
+// IsApplicable reports true, any Gradle build file describes a Gradle project
+func (gs *GradleScanner) IsApplicable() bool { return true }
+
+// IsApplicable checks that the Python files describe a project; a pyproject.toml alone may only
+// configure tools such as black or ruff
+func (ps *PipScanner) IsApplicable() bool {
+	projectDir := ps.environment.GetDirectory()
+	for _, name := range []string{"requirements.txt", "setup.py", "uv.lock"} {
+		if _, err := os.Stat(filepath.Join(projectDir, name)); err == nil {
+			return true
+		}
+	}
+	if ps.config.PipRequirementsPath != "" {
+		return true
+	}
+
+	tables, err := parseTomlFile(filepath.Join(projectDir, "pyproject.toml"))
+	if err == nil {
+		for _, table := range tables {
+			if table.Name == "project" || table.Name == "build-system" || strings.HasPrefix(table.Name, "tool.poetry") {
+				return true
+			}
+		}
+	}
+	ps.log.Infof("Ignoring pyproject.toml in %s: no [project], [build-system] or [tool.poetry] table", projectDir)
+	return false
+}
+
+// IsApplicable reports true, a Pipfile always describes a Pipenv project
+func (ps *PipenvScanner) IsApplicable() bool { return true }
+
+// IsApplicable checks that package.json is a package manifest rather than a tool config file,
+// i.e. valid JSON naming the package or declaring dependencies
+func (ns *NpmScanner) IsApplicable() bool {
+	var manifest struct {
+		Name                 string          `json:"name"`
+		Dependencies         json.RawMessage `json:"dependencies"`
+		DevDependencies      json.RawMessage `json:"devDependencies"`
+		PeerDependencies     json.RawMessage `json:"peerDependencies"`
+		OptionalDependencies json.RawMessage `json:"optionalDependencies"`
+		Workspaces           json.RawMessage `json:"workspaces"`
+	}
+	if !readJSONManifest(filepath.Join(ns.environment.GetDirectory(), "package.json"), &manifest) {
+		ns.log.Infof("Ignoring package.json in %s: not valid JSON", ns.environment.GetDirectory())
+		return false
+	}
+	if manifest.Name == "" && manifest.Dependencies == nil && manifest.DevDependencies == nil &&
+		manifest.PeerDependencies == nil && manifest.OptionalDependencies == nil && manifest.Workspaces == nil {
+		ns.log.Infof("Ignoring package.json in %s: no name, dependencies or workspaces", ns.environment.GetDirectory())
+		return false
+	}
+	return true
+}
+
+// IsApplicable checks that go.mod declares a module or go.work uses one
+func (gs *GoScanner) IsApplicable() bool {
+	if dirs, err := gs.workspaceModules(); err == nil && len(dirs) > 0 {
+		return true
+	}
+
+	file, err := utils.OpenTextFile(filepath.Join(gs.environment.GetDirectory(), "go.mod"))
+	if err == nil {
+		defer func() { _ = file.Close() }()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && fields[0] == "module" {
+				return true
+			}
+		}
+	}
+	gs.log.Infof("Ignoring go.mod in %s: no module directive", gs.environment.GetDirectory())
+	return false
+}
+
+// IsApplicable checks that Cargo.toml declares a package or a workspace
+func (cs *CargoScanner) IsApplicable() bool {
+	tables, err := parseTomlFile(filepath.Join(cs.environment.GetDirectory(), "Cargo.toml"))
+	if err == nil {
+		for _, table := range tables {
+			if table.Name == "package" || table.Name == "workspace" {
+				return true
+			}
+		}
+	}
+	cs.log.Infof("Ignoring Cargo.toml in %s: no [package] or [workspace] table", cs.environment.GetDirectory())
+	return false
+}
+
+// IsApplicable checks that composer.json is valid JSON naming the package or requiring packages
+func (cs *ComposerScanner) IsApplicable() bool {
+	var manifest struct {
+		Name       string            `json:"name"`
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if !readJSONManifest(filepath.Join(cs.environment.GetDirectory(), "composer.json"), &manifest) {
+		cs.log.Infof("Ignoring composer.json in %s: not valid JSON", cs.environment.GetDirectory())
+		return false
+	}
+	if manifest.Name == "" && len(manifest.Require) == 0 && len(manifest.RequireDev) == 0 {
+		cs.log.Infof("Ignoring composer.json in %s: no name and empty require tables", cs.environment.GetDirectory())
+		return false
+	}
+	return true
+}
+
+// IsApplicable checks that a project file is found directly or through a solution file
+func (ds *DotnetScanner) IsApplicable() bool {
+	if err := ds.FileFind(); err != nil {
+		ds.log.Infof("Ignoring solution in %s: it lists no project file found on disk", ds.environment.GetDirectory())
+		return false
+	}
+	return true
+}
+
+// IsApplicable reports true, CMakeLists.txt and vcpkg.json always describe a C/C++ project
+func (cs *CMakeScanner) IsApplicable() bool { return true }
+
+// IsApplicable reports true, the build files were already found by FileFind
+func (cs *CSystemScanner) IsApplicable() bool { return true }
+
+// readJSONManifest decodes a JSON manifest into v, reporting whether it is a valid JSON object
+func readJSONManifest(path string, v any) bool {
+	data, err := utils.ReadTextFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -1,11 +1,18 @@
 package buildtools
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
 )
 
@@ -278,6 +285,130 @@ func TestBuildScanner_ScanDependencies(t *testing.T) {
 	}
 }
 
+func TestBuildScanner_ScanDependencies_OverlappingPythonSources(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"requirements.txt": "requests==2.31.0\n",
+		"Pipfile":          "[packages]\nrequests = \"*\"\n",
+		"Pipfile.lock":     "{}\n",
+	})
+	env := NewScannableEnvironment(tempDir, "")
+
+	tests := []struct {
+		name        string
+		cfg         *config.ScanConfig
+		wantPip     bool
+		wantPipenv  bool
+		wantWarning bool
+	}{
+		{"no preference", &config.ScanConfig{}, true, true, true},
+		{"prefer lockfile", &config.ScanConfig{PreferLockfile: true}, false, true, false},
+		{"prefer manifest", &config.ScanConfig{PreferManifest: true}, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewBuildScanner(env, tt.cfg)
+
+			var pip, pipenv bool
+			for _, s := range scanner.scanners {
+				switch s.(type) {
+				case *PipScanner:
+					pip = true
+				case *PipenvScanner:
+					pipenv = true
+				}
+			}
+			if pip != tt.wantPip || pipenv != tt.wantPipenv {
+				t.Errorf("Expected pip=%v pipenv=%v, got pip=%v pipenv=%v", tt.wantPip, tt.wantPipenv, pip, pipenv)
+			}
+
+			if !tt.wantPip {
+				return
+			}
+
+			// The pip scan reads requirements.txt without a pip executable and comes first
+			roots, err := scanner.ScanDependencies()
+			if err != nil {
+				t.Fatalf("ScanDependencies failed: %v", err)
+			}
+			if len(roots) == 0 || roots[0].BuildTool != "pip" {
+				t.Fatalf("Expected the pip root first, got %+v", roots)
+			}
+			warned := slices.ContainsFunc(roots[0].Warnings, func(w string) bool {
+				return strings.Contains(w, "Both pip and Pipenv files found")
+			})
+			if warned != tt.wantWarning {
+				t.Errorf("Expected overlap warning %v, got %v", tt.wantWarning, roots[0].Warnings)
+			}
+		})
+	}
+}
+
+// writeMavenModules writes a parent pom.xml and count module directories below modules/, each
+// with a pom.xml declaring one dependency
+func writeMavenModules(tb testing.TB, dir string, count int) {
+	tb.Helper()
+	files := map[string]string{"pom.xml": `<project><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.0.0</version></project>`}
+	for i := 0; i < count; i++ {
+		files[fmt.Sprintf("modules/module-%02d/pom.xml", i)] = fmt.Sprintf(`<project>
+  <groupId>com.example</groupId><artifactId>module-%02d</artifactId><version>1.0.0</version>
+  <dependencies>
+    <dependency><groupId>org.example</groupId><artifactId>lib-%02d</artifactId><version>2.%d.0</version></dependency>
+  </dependencies>
+</project>`, i, i, i)
+	}
+	writeTestFiles(tb, dir, files)
+}
+
+func TestBuildScanner_ScanDependencies_RecursiveModules(t *testing.T) {
+	tempDir := t.TempDir()
+	writeMavenModules(t, tempDir, 50)
+
+	scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{Recursive: true, ThreadNum: "8"})
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 51 {
+		t.Fatalf("Expected the parent and 50 module roots, got %d", len(roots))
+	}
+
+	// Roots keep the detection order however the scanners are scheduled
+	if roots[0].ProjectName != "parent" {
+		t.Errorf("Expected the parent project first, got %s", roots[0].ProjectName)
+	}
+	for i, root := range roots[1:] {
+		module := fmt.Sprintf("module-%02d", i)
+		if root.ProjectName != module {
+			t.Errorf("Root %d: expected %s, got %s", i+1, module, root.ProjectName)
+		}
+		if len(root.Dependencies) != 1 || root.Dependencies[0].Version != fmt.Sprintf("2.%d.0", i) {
+			t.Errorf("Root %d: expected lib-%02d 2.%d.0, got %+v", i+1, i, i, root.Dependencies)
+		}
+	}
+}
+
+func BenchmarkBuildScanner_ScanDependencies_RecursiveModules(b *testing.B) {
+	tempDir := b.TempDir()
+	writeMavenModules(b, tempDir, 50)
+	log := logger.GetLogger()
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(logrus.ErrorLevel)
+
+	for _, threads := range []string{"1", "8"} {
+		b.Run("threads-"+threads, func(b *testing.B) {
+			scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{Recursive: true, ThreadNum: threads})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := scanner.ScanDependencies(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func TestBuildScanner_ScanDependencies_EmptyProject(t *testing.T) {
 	tempDir := t.TempDir()
 	env := NewScannableEnvironment(tempDir, "")
@@ -294,6 +425,42 @@ func TestBuildScanner_ScanDependencies_EmptyProject(t *testing.T) {
 	}
 }
 
+func TestBuildScanner_ScanDependencies_ScannerProblemsWarn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake external scanner is a shell script")
+	}
+
+	// Without any root, a skipped scanner is still reported
+	scanner := NewBuildScanner(NewScannableEnvironment(t.TempDir(), ""), &config.ScanConfig{ExternalScanner: "no-such-scanner"})
+	roots, err := scanner.ScanDependencies()
+	if err != nil || len(roots) != 0 {
+		t.Fatalf("Expected no roots and no error, got %+v, %v", roots, err)
+	}
+	if warnings := scanner.Warnings(); len(warnings) != 1 || !strings.HasPrefix(warnings[0], "Scanner skipped: ") {
+		t.Errorf("Expected a skipped scanner warning, got %v", warnings)
+	}
+
+	// A failing scanner is recorded on the first root
+	binDir := t.TempDir()
+	writeTestFiles(t, binDir, map[string]string{"scan-broken": "#!/bin/sh\nexit 3\n"})
+	script := filepath.Join(binDir, "scan-broken")
+	if err := os.Chmod(script, 0755); err != nil {
+		t.Fatalf("Failed to make fake scanner executable: %v", err)
+	}
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{"requirements.txt": "requests==2.31.0\n"})
+
+	scanner = NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{ExternalScanner: script})
+	roots, err = scanner.ScanDependencies()
+	if err != nil || len(roots) != 1 {
+		t.Fatalf("Expected the pip root and no error, got %+v, %v", roots, err)
+	}
+	failed := func(w string) bool { return strings.HasPrefix(w, "Scan execution failed: ") }
+	if !slices.ContainsFunc(roots[0].Warnings, failed) || !slices.ContainsFunc(scanner.Warnings(), failed) {
+		t.Errorf("Expected a failed scanner warning, got %v and %v", roots[0].Warnings, scanner.Warnings())
+	}
+}
+
 func TestBuildScanner_ScanDependencies_GoProject(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -515,6 +682,141 @@ pytest = "*"`
 	}
 }
 
+func TestBuildScanner_RecursiveDetection(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":                              "module root\n\ngo 1.21\n",
+		"services/api/go.mod":                 "module api\n\ngo 1.21\n",
+		"node_modules/lib/package.json":       `{"name": "lib"}`,
+		"a/b/c/d/e/f/deep/package.json":       `{"name": "deep"}`,
+		"services/api/.hidden/package.json":   `{"name": "hidden"}`,
+		"services/web/src/components/App.jsx": "export default {}",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	env := NewScannableEnvironment(tempDir, "")
+
+	// Without recursion only the root project is registered
+	scanner := NewBuildScanner(env, &config.ScanConfig{})
+	if len(scanner.scanners) != 1 {
+		t.Errorf("Expected 1 scanner without recursion, got %d", len(scanner.scanners))
+	}
+
+	// Default detection depth finds the nested module but not the deep manifest
+	scanner = NewBuildScanner(env, &config.ScanConfig{Recursive: true})
+	if len(scanner.scanners) != 2 {
+		t.Errorf("Expected 2 scanners with default depth, got %d", len(scanner.scanners))
+	}
+
+	// Deep manifest beyond the limit is not detected
+	scanner = NewBuildScanner(env, &config.ScanConfig{Recursive: true, MaxDepth: 1})
+	if len(scanner.scanners) != 1 {
+		t.Errorf("Expected only the root scanner with max depth 1, got %d", len(scanner.scanners))
+	}
+
+	// Raising the limit picks up the deep manifest
+	scanner = NewBuildScanner(env, &config.ScanConfig{Recursive: true, MaxDepth: 7})
+	if len(scanner.scanners) != 3 {
+		t.Errorf("Expected 3 scanners with max depth 7, got %d", len(scanner.scanners))
+	}
+}
+
+func TestBuildScanner_ToolSelection(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"go.mod":       "module polyglot\n\ngo 1.21\n",
+		"package.json": `{"name": "polyglot-web", "version": "1.0.0"}`,
+	})
+	env := NewScannableEnvironment(tempDir, "")
+
+	dependencies, err := NewBuildScanner(env, &config.ScanConfig{OnlyTools: []string{"go"}}).ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(dependencies) != 1 || dependencies[0].BuildTool != "go" {
+		t.Errorf("Expected only the Go root with --only-tool go, got %+v", dependencies)
+	}
+
+	scanner := NewBuildScanner(env, &config.ScanConfig{SkipTools: []string{"go"}})
+	if len(scanner.scanners) != 1 {
+		t.Fatalf("Expected 1 scanner with --skip-tool go, got %d", len(scanner.scanners))
+	}
+	if _, ok := scanner.scanners[0].(*NpmScanner); !ok {
+		t.Errorf("Expected the npm scanner to remain, got %T", scanner.scanners[0])
+	}
+}
+
+func TestBuildScanner_SkipsInapplicableManifests(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		// Tool configuration only, not a Node.js or Python project
+		"package.json":   `{"eslintConfig": {"extends": "standard"}, "prettier": {"semi": false}}`,
+		"pyproject.toml": "[tool.black]\nline-length = 100\n",
+		"go.mod":         "module decoy\n\ngo 1.21\n",
+	})
+
+	scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	if len(scanner.scanners) != 1 {
+		t.Fatalf("Expected only the Go scanner, got %d scanners", len(scanner.scanners))
+	}
+	if _, ok := scanner.scanners[0].(*GoScanner); !ok {
+		t.Errorf("Expected the Go scanner, got %T", scanner.scanners[0])
+	}
+
+	// A package.json declaring dependencies is a Node.js project even without a name
+	writeTestFiles(t, tempDir, map[string]string{"package.json": `{"private": true, "dependencies": {"express": "^4.18.2"}}`})
+	if !NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).IsApplicable() {
+		t.Error("Expected a package.json with dependencies to be applicable")
+	}
+
+	writeTestFiles(t, tempDir, map[string]string{"package.json": `not json`})
+	if NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).IsApplicable() {
+		t.Error("Expected an invalid package.json not to be applicable")
+	}
+}
+
+func TestBuildScanner_DetectProjects(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId><artifactId>platform</artifactId><version>3.0.0</version>
+  <description>Platform parent</description>
+  <licenses><license><name>Apache-2.0</name></license></licenses>
+</project>`,
+		"native/Cargo.toml":    "[package]\nname = \"engine\"\nversion = \"0.4.1\"\nlicense = \"MIT\"\n",
+		"service/go.mod":       "module example.com/service\n\ngo 1.22\n",
+		"tools/pyproject.toml": "[project]\nname = \"tools\"\nversion = \"1.2.0\"\n",
+		"web/package.json": `{"name": "web", "version": "2.0.0", "description": "Storefront",
+			"license": {"type": "ISC"}, "dependencies": {"react": "^18.2.0"}}`,
+	})
+
+	scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{Recursive: true})
+	projects, err := scanner.DetectProjects()
+	if err != nil {
+		t.Fatalf("DetectProjects failed: %v", err)
+	}
+
+	expected := []model.ProjectInfo{
+		{Name: "platform", Version: "3.0.0", Description: "Platform parent", License: "Apache-2.0", BuildTool: "maven"},
+		{Name: "engine", Version: "0.4.1", License: "MIT", BuildTool: "cargo"},
+		{Name: "example.com/service", Version: "1.22", BuildTool: "go"},
+		{Name: "tools", Version: "1.2.0", BuildTool: "pip"},
+		{Name: "web", Version: "2.0.0", Description: "Storefront", License: "ISC", BuildTool: "npm"},
+	}
+	if !slices.Equal(projects, expected) {
+		t.Errorf("Expected one project per detected project:\n%+v\ngot:\n%+v", expected, projects)
+	}
+}
+
 func TestDetectBuildToolFromFile(t *testing.T) {
 	tests := []struct {
 		fileName     string
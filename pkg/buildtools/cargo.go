@@ -0,0 +1,159 @@
+package buildtools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// CargoScanner handles Rust Cargo project scanning
+type CargoScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	log         *logrus.Entry
+}
+
+// cargoDependencyTables maps Cargo.toml dependency tables to dependency scopes
+var cargoDependencyTables = map[string]string{
+	"dependencies":       "runtime",
+	"dev-dependencies":   "development",
+	"build-dependencies": "build",
+}
+
+// NewCargoScanner creates a new Cargo scanner
+func NewCargoScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *CargoScanner {
+	return &CargoScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger("cargo", env),
+	}
+}
+
+// ExeFind finds the Cargo executable
+func (cs *CargoScanner) ExeFind() error { return nil } // Cargo.toml is parsed statically
+
+// FileFind checks if required Cargo files exist
+func (cs *CargoScanner) FileFind() error {
+	cargoToml := filepath.Join(cs.environment.GetDirectory(), "Cargo.toml")
+	if _, err := os.Stat(cargoToml); os.IsNotExist(err) {
+		return fmt.Errorf("cargo manifest not found: %s", cargoToml)
+	}
+	return nil
+}
+
+// ScanExecute executes the Cargo dependency scan
+func (cs *CargoScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	cs.log.Info("Scanning Cargo dependencies (direct only)...")
+
+	projectName, projectVersion, dependencies, err := cs.parseCargoToml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Cargo.toml: %w", err)
+	}
+
+	root := model.DependencyRoot{
+		ProjectName:    projectName,
+		ProjectVersion: projectVersion,
+		BuildTool:      "cargo",
+		Dependencies:   dependencies,
+	}
+
+	return []model.DependencyRoot{root}, nil
+}
+
+// parseCargoToml parses Cargo.toml to extract project info and direct dependencies
+func (cs *CargoScanner) parseCargoToml() (string, string, []model.Dependency, error) {
+	tables, err := parseTomlFile(filepath.Join(cs.environment.GetDirectory(), "Cargo.toml"))
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	projectName := "unknown"
+	projectVersion := "unknown"
+	var dependencies []model.Dependency
+
+	for _, table := range tables {
+		if table.Name == "package" {
+			if name := tomlString(table.Values["name"]); name != "" {
+				projectName = name
+			}
+			if version := tomlString(table.Values["version"]); version != "" && !strings.HasPrefix(version, "{") {
+				projectVersion = version
+			}
+			continue
+		}
+
+		// [dependencies], [dev-dependencies] and [target.'cfg(..)'.dependencies]
+		if scope, ok := cs.dependencyTableScope(table.Name); ok {
+			for name, raw := range table.Values {
+				// Dotted keys such as serde.workspace = true carry no version
+				if dot := strings.Index(name, "."); dot != -1 {
+					name, raw = name[:dot], ""
+				}
+				dependencies = append(dependencies, cs.newDependency(name, cs.dependencyVersion(raw), scope))
+			}
+			continue
+		}
+
+		// [dependencies.serde] style tables
+		if dot := strings.LastIndex(table.Name, "."); dot != -1 {
+			if scope, ok := cs.dependencyTableScope(table.Name[:dot]); ok {
+				name := tomlString(table.Name[dot+1:])
+				version := tomlString(table.Values["version"])
+				if version == "" {
+					version = "unknown"
+				}
+				dependencies = append(dependencies, cs.newDependency(name, version, scope))
+			}
+		}
+	}
+
+	return projectName, projectVersion, dependencies, nil
+}
+
+// dependencyTableScope returns the scope for a dependency table name
+func (cs *CargoScanner) dependencyTableScope(tableName string) (string, bool) {
+	if scope, ok := cargoDependencyTables[tableName]; ok {
+		return scope, true
+	}
+	if strings.HasPrefix(tableName, "target.") {
+		for suffix, scope := range cargoDependencyTables {
+			if strings.HasSuffix(tableName, "."+suffix) {
+				return scope, true
+			}
+		}
+	}
+	return "", false
+}
+
+// dependencyVersion extracts the version from a plain string or inline table requirement
+func (cs *CargoScanner) dependencyVersion(raw string) string {
+	if inline := tomlInlineTable(raw); inline != nil {
+		raw = inline["version"]
+	}
+	if version := tomlString(raw); version != "" {
+		return version
+	}
+	return "unknown"
+}
+
+// newDependency creates a Cargo dependency
+func (cs *CargoScanner) newDependency(name, version, scope string) model.Dependency {
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Group:   "",
+			Name:    name,
+			Version: version,
+			Type:    "cargo",
+		},
+		Name:    name,
+		Version: version,
+		Type:    "cargo",
+		Scope:   scope,
+	}
+}
@@ -0,0 +1,235 @@
+package buildtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// CMakeScanner handles CMake project scanning, including vcpkg manifests
+type CMakeScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	log         *logrus.Entry
+}
+
+var (
+	cmakeCommentRegex = regexp.MustCompile(`(?m)#.*$`)
+	cmakeCommandRegex = regexp.MustCompile(`(?is)\b(project|find_package|FetchContent_Declare|ExternalProject_Add)\s*\(([^)]*)\)`)
+	cmakeVersionRegex = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+	urlVersionRegex   = regexp.MustCompile(`v?(\d+(?:\.\d+)+)`)
+)
+
+// vcpkgManifest represents the parts of vcpkg.json used for dependency scanning
+type vcpkgManifest struct {
+	Name          string            `json:"name"`
+	Version       string            `json:"version"`
+	VersionString string            `json:"version-string"`
+	VersionSemver string            `json:"version-semver"`
+	VersionDate   string            `json:"version-date"`
+	Dependencies  []json.RawMessage `json:"dependencies"`
+	Overrides     []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"overrides"`
+}
+
+// NewCMakeScanner creates a new CMake scanner
+func NewCMakeScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *CMakeScanner {
+	return &CMakeScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger("cmake", env),
+	}
+}
+
+// ExeFind finds the CMake executable
+func (cs *CMakeScanner) ExeFind() error { return nil } // CMakeLists.txt and vcpkg.json are parsed statically
+
+// FileFind checks if required CMake files exist
+func (cs *CMakeScanner) FileFind() error {
+	dir := cs.environment.GetDirectory()
+	for _, name := range []string{"CMakeLists.txt", "vcpkg.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("CMakeLists.txt or vcpkg.json not found")
+}
+
+// ScanExecute executes the CMake dependency scan
+func (cs *CMakeScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	cs.log.Info("Scanning CMake dependencies (direct only)...")
+
+	dir := cs.environment.GetDirectory()
+	projectName := "unknown"
+	projectVersion := "unknown"
+	var dependencies []model.Dependency
+
+	// vcpkg.json is preferred as it carries precise versions
+	vcpkgNames := make(map[string]bool)
+	if content, err := utils.ReadTextFile(filepath.Join(dir, "vcpkg.json")); err == nil {
+		name, version, vcpkgDeps, err := parseVcpkgManifest(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vcpkg.json: %w", err)
+		}
+		projectName, projectVersion = name, version
+		for _, dep := range vcpkgDeps {
+			vcpkgNames[strings.ToLower(dep.Name)] = true
+		}
+		dependencies = append(dependencies, vcpkgDeps...)
+	}
+
+	if content, err := utils.ReadTextFile(filepath.Join(dir, "CMakeLists.txt")); err == nil {
+		name, version, cmakeDeps := parseCMakeLists(string(content))
+		if name != "unknown" {
+			projectName = name
+		}
+		if version != "unknown" {
+			projectVersion = version
+		}
+		for _, dep := range cmakeDeps {
+			if !vcpkgNames[strings.ToLower(dep.Name)] {
+				dependencies = append(dependencies, dep)
+			}
+		}
+	}
+
+	root := model.DependencyRoot{
+		ProjectName:    projectName,
+		ProjectVersion: projectVersion,
+		BuildTool:      "cmake",
+		Dependencies:   dependencies,
+	}
+
+	return []model.DependencyRoot{root}, nil
+}
+
+// parseCMakeLists extracts the project and find_package/FetchContent/ExternalProject dependencies
+func parseCMakeLists(content string) (string, string, []model.Dependency) {
+	content = cmakeCommentRegex.ReplaceAllString(content, "")
+
+	projectName := "unknown"
+	projectVersion := "unknown"
+	var dependencies []model.Dependency
+
+	for _, match := range cmakeCommandRegex.FindAllStringSubmatch(content, -1) {
+		args := strings.Fields(strings.ReplaceAll(match[2], `"`, ""))
+		if len(args) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(match[1]) {
+		case "project":
+			projectName = args[0]
+			if version := cmakeKeywordValue(args, "VERSION"); version != "" {
+				projectVersion = version
+			}
+		case "find_package":
+			version := "unknown"
+			if len(args) > 1 && cmakeVersionRegex.MatchString(args[1]) {
+				version = args[1]
+			}
+			dependencies = append(dependencies, newCMakeDependency(args[0], version, "cmake"))
+		default: // FetchContent_Declare and ExternalProject_Add
+			version := cmakeKeywordValue(args, "GIT_TAG")
+			if version == "" {
+				if match := urlVersionRegex.FindStringSubmatch(cmakeKeywordValue(args, "URL")); match != nil {
+					version = match[1]
+				}
+			}
+			if version == "" {
+				version = "unknown"
+			}
+			dependencies = append(dependencies, newCMakeDependency(args[0], version, "cmake"))
+		}
+	}
+
+	return projectName, projectVersion, dependencies
+}
+
+// cmakeKeywordValue returns the argument following a keyword, or an empty string
+func cmakeKeywordValue(args []string, keyword string) string {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == keyword {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// parseVcpkgManifest extracts the project and dependencies of a vcpkg.json manifest.
+// Versions come from overrides when present, otherwise from "version>=" minimums.
+func parseVcpkgManifest(content []byte) (string, string, []model.Dependency, error) {
+	var manifest vcpkgManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return "", "", nil, err
+	}
+
+	projectName := manifest.Name
+	if projectName == "" {
+		projectName = "unknown"
+	}
+	projectVersion := "unknown"
+	for _, version := range []string{manifest.Version, manifest.VersionSemver, manifest.VersionDate, manifest.VersionString} {
+		if version != "" {
+			projectVersion = version
+			break
+		}
+	}
+
+	overrides := make(map[string]string)
+	for _, override := range manifest.Overrides {
+		overrides[override.Name] = override.Version
+	}
+
+	var dependencies []model.Dependency
+	for _, raw := range manifest.Dependencies {
+		// Dependencies are either a plain name or an object
+		var name, version string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			var entry struct {
+				Name       string `json:"name"`
+				MinVersion string `json:"version>="`
+			}
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return "", "", nil, err
+			}
+			name, version = entry.Name, entry.MinVersion
+		}
+		if override, ok := overrides[name]; ok {
+			version = override
+		}
+		if version == "" {
+			version = "unknown"
+		}
+		dependencies = append(dependencies, newCMakeDependency(name, version, "vcpkg"))
+	}
+
+	return projectName, projectVersion, dependencies, nil
+}
+
+// newCMakeDependency creates a CMake or vcpkg dependency
+func newCMakeDependency(name, version, depType string) model.Dependency {
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Group:   "",
+			Name:    name,
+			Version: version,
+			Type:    depType,
+		},
+		Name:    name,
+		Version: version,
+		Type:    depType,
+		Scope:   "runtime",
+	}
+}
@@ -0,0 +1,75 @@
+package buildtools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+// buildToolWaitDelay bounds the wait for a killed build tool to release its output
+const buildToolWaitDelay = 2 * time.Second
+
+// buildToolCommand creates a command for an external build tool running in dir, killed when
+// ctx is done
+func buildToolCommand(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.WaitDelay = buildToolWaitDelay
+	return cmd
+}
+
+// runBuildTool runs an external build tool in dir and hands its stdout to parse while it is
+// produced. The tool is killed once the configured build tool timeout elapses.
+func runBuildTool(cfg *config.ScanConfig, dir string, parse func(io.Reader) error, name string, args ...string) error {
+	return runBuildToolCommand(cfg, func(ctx context.Context) *exec.Cmd {
+		return buildToolCommand(ctx, dir, name, args...)
+	}, parse)
+}
+
+// runBuildToolCommand runs the command created by newCmd, which must be bound to ctx, and hands
+// its stdout to parse like runBuildTool
+func runBuildToolCommand(cfg *config.ScanConfig, newCmd func(ctx context.Context) *exec.Cmd, parse func(io.Reader) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetBuildToolTimeout())
+	defer cancel()
+
+	cmd := newCmd(ctx)
+	err := streamOutput(cmd, parse)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s", cmd.Args[0], cfg.GetBuildToolTimeout())
+	}
+	return err
+}
+
+// streamOutput runs cmd and hands its stdout to parse while it is produced, so large outputs
+// are never buffered whole. Output left unread when parse returns early is drained so the
+// command can exit.
+func streamOutput(cmd *exec.Cmd, parse func(io.Reader) error) error {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	// A killed command may leave children holding the pipe open; closing it unblocks parse
+	if cancel := cmd.Cancel; cancel != nil {
+		cmd.Cancel = func() error {
+			_ = stdout.Close()
+			return cancel()
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	parseErr := parse(stdout)
+	_, _ = io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return err
+	}
+	return parseErr
+}
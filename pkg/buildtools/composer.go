@@ -0,0 +1,175 @@
+package buildtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// ComposerScanner handles PHP Composer project scanning
+type ComposerScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	log         *logrus.Entry
+}
+
+// NewComposerScanner creates a new Composer scanner
+func NewComposerScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *ComposerScanner {
+	return &ComposerScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger("composer", env),
+	}
+}
+
+// ExeFind finds the Composer executable
+func (cs *ComposerScanner) ExeFind() error { return nil } // composer.json is parsed statically
+
+// FileFind checks if required Composer files exist
+func (cs *ComposerScanner) FileFind() error {
+	composerJson := filepath.Join(cs.environment.GetDirectory(), "composer.json")
+	if _, err := os.Stat(composerJson); os.IsNotExist(err) {
+		return fmt.Errorf("composer.json not found")
+	}
+	return nil
+}
+
+// ScanExecute executes the Composer dependency scan
+func (cs *ComposerScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	cs.log.Info("Scanning Composer dependencies (direct only)...")
+
+	projectName, projectVersion, dependencies, err := cs.parseComposerJson()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse composer.json: %w", err)
+	}
+	cs.applyLockLicenses(dependencies)
+
+	root := model.DependencyRoot{
+		ProjectName:    projectName,
+		ProjectVersion: projectVersion,
+		BuildTool:      "composer",
+		Dependencies:   dependencies,
+	}
+
+	return []model.DependencyRoot{root}, nil
+}
+
+// parseComposerJson parses composer.json to extract project info and direct dependencies
+func (cs *ComposerScanner) parseComposerJson() (string, string, []model.Dependency, error) {
+	composerJsonPath := filepath.Join(cs.environment.GetDirectory(), "composer.json")
+	file, err := utils.OpenTextFile(composerJsonPath)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var composerInfo struct {
+		Name       string            `json:"name"`
+		Version    string            `json:"version"`
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+
+	if err := json.NewDecoder(file).Decode(&composerInfo); err != nil {
+		return "", "", nil, err
+	}
+
+	projectName := composerInfo.Name
+	if projectName == "" {
+		projectName = "unknown"
+	}
+
+	projectVersion := composerInfo.Version
+	if projectVersion == "" {
+		projectVersion = "unknown"
+	}
+
+	var dependencies []model.Dependency
+	for name, version := range composerInfo.Require {
+		if cs.isPlatformPackage(name) {
+			continue
+		}
+		dependencies = append(dependencies, cs.newDependency(name, version, "runtime"))
+	}
+	for name, version := range composerInfo.RequireDev {
+		if cs.isPlatformPackage(name) {
+			continue
+		}
+		dependencies = append(dependencies, cs.newDependency(name, version, "development"))
+	}
+
+	return projectName, projectVersion, dependencies, nil
+}
+
+// applyLockLicenses records the licenses composer.lock declares for the dependencies. A missing
+// or unreadable lockfile leaves them without license.
+func (cs *ComposerScanner) applyLockLicenses(dependencies []model.Dependency) {
+	data, err := utils.ReadTextFile(filepath.Join(cs.environment.GetDirectory(), "composer.lock"))
+	if err != nil {
+		return
+	}
+
+	type lockPackage struct {
+		Name    string          `json:"name"`
+		License json.RawMessage `json:"license"`
+	}
+	var lock struct {
+		Packages    []lockPackage `json:"packages"`
+		PackagesDev []lockPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		cs.log.Warnf("Failed to parse composer.lock, dependency licenses are not known: %v", err)
+		return
+	}
+
+	licenses := make(map[string]string)
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		if license := composerLicense(pkg.License); license != "" {
+			licenses[pkg.Name] = license
+		}
+	}
+	for i := range dependencies {
+		dependencies[i].License = licenses[dependencies[i].Name]
+	}
+}
+
+// composerLicense decodes a Composer license, a single identifier or a list of alternatives
+func composerLicense(raw json.RawMessage) string {
+	var license string
+	if json.Unmarshal(raw, &license) == nil {
+		return license
+	}
+	var licenses []string
+	_ = json.Unmarshal(raw, &licenses)
+	return strings.Join(licenses, " OR ")
+}
+
+// isPlatformPackage reports whether a requirement targets the PHP platform rather than a package
+func (cs *ComposerScanner) isPlatformPackage(name string) bool {
+	return name == "php" || strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-") ||
+		name == "composer-plugin-api" || name == "composer-runtime-api"
+}
+
+// newDependency creates a Composer dependency
+func (cs *ComposerScanner) newDependency(name, version, scope string) model.Dependency {
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Group:   "",
+			Name:    name,
+			Version: version,
+			Type:    "composer",
+		},
+		Name:    name,
+		Version: version,
+		Type:    "composer",
+		Scope:   scope,
+	}
+}
@@ -0,0 +1,228 @@
+package buildtools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// CSystemScanner heuristically scans Make/CMake driven C/C++ projects for
+// system library references. It is experimental and only enabled with --experimental-c-scan.
+type CSystemScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	log         *logrus.Entry
+}
+
+// cSystemBuildTool marks dependency roots produced by the heuristic C scanner
+const cSystemBuildTool = "c-heuristic"
+
+// cMakefiles lists the Makefile names understood by the heuristic C scanner
+var cMakefiles = []string{"GNUmakefile", "Makefile", "makefile"}
+
+// cToolchainLibs lists libraries provided by the C toolchain itself, not recorded as dependencies
+var cToolchainLibs = map[string]bool{
+	"c": true, "m": true, "dl": true, "rt": true, "pthread": true,
+	"stdc++": true, "gcc": true, "gcc_s": true, "util": true,
+}
+
+// cmakeKeywords lists pkg_check_modules arguments that are not module names
+var cmakeKeywords = map[string]bool{
+	"REQUIRED": true, "QUIET": true, "IMPORTED_TARGET": true, "GLOBAL": true,
+	"NO_CMAKE_PATH": true, "NO_CMAKE_ENVIRONMENT_PATH": true,
+}
+
+var (
+	linkFlagRegex      = regexp.MustCompile(`(?:^|\s)-l([A-Za-z0-9_+.-]+)`)
+	pkgConfigRegex     = regexp.MustCompile("pkg-config\\s+([^)`\\n]+)")
+	pkgCheckRegex      = regexp.MustCompile(`(?is)pkg_(?:check|search)_modules?\s*\(([^)]*)\)`)
+	pkgConfigNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.+-]+`)
+)
+
+// NewCSystemScanner creates a new heuristic C scanner
+func NewCSystemScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *CSystemScanner {
+	return &CSystemScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger(cSystemBuildTool, env),
+	}
+}
+
+// ExeFind finds the build executable
+func (cs *CSystemScanner) ExeFind() error { return nil } // Build files are read statically
+
+// FileFind checks if a Makefile, CMakeLists.txt or conanfile.txt exists
+func (cs *CSystemScanner) FileFind() error {
+	if len(cs.buildFiles()) == 0 {
+		return fmt.Errorf("no Makefile, CMakeLists.txt or conanfile.txt found")
+	}
+	return nil
+}
+
+// ScanExecute executes the heuristic C dependency scan
+func (cs *CSystemScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	cs.log.Warn("Scanning C/C++ system library references (experimental, heuristic results)...")
+
+	seen := make(map[string]bool)
+	var dependencies []model.Dependency
+	add := func(dep model.Dependency) {
+		key := dep.Type + ":" + dep.Name
+		if !seen[key] {
+			seen[key] = true
+			dependencies = append(dependencies, dep)
+		}
+	}
+
+	for _, buildFile := range cs.buildFiles() {
+		content, err := utils.ReadTextFile(buildFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(buildFile), err)
+		}
+
+		switch filepath.Base(buildFile) {
+		case "CMakeLists.txt":
+			for _, name := range parseCMakeReferences(string(content)) {
+				add(newSystemDependency(name))
+			}
+		case "conanfile.txt":
+			for _, dep := range parseConanfile(string(content)) {
+				add(dep)
+			}
+		default:
+			for _, name := range parseMakefileReferences(string(content)) {
+				add(newSystemDependency(name))
+			}
+		}
+	}
+
+	root := model.DependencyRoot{
+		ProjectName:    utils.ProjectNameFromDir(cs.environment.GetDirectory()),
+		ProjectVersion: "unknown",
+		BuildTool:      cSystemBuildTool,
+		Dependencies:   dependencies,
+	}
+
+	return []model.DependencyRoot{root}, nil
+}
+
+// buildFiles returns the existing build files understood by the scanner
+func (cs *CSystemScanner) buildFiles() []string {
+	var files []string
+	for _, name := range append(append([]string{}, cMakefiles...), "CMakeLists.txt", "conanfile.txt") {
+		path := filepath.Join(cs.environment.GetDirectory(), name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// parseMakefileReferences extracts pkg-config modules and -l<lib> link flags from a Makefile
+func parseMakefileReferences(content string) []string {
+	var names []string
+
+	for _, match := range pkgConfigRegex.FindAllStringSubmatch(content, -1) {
+		for _, field := range strings.Fields(match[1]) {
+			if strings.HasPrefix(field, "-") || strings.HasPrefix(field, "$") {
+				continue
+			}
+			if name := pkgConfigNameRegex.FindString(field); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, match := range linkFlagRegex.FindAllStringSubmatch(content, -1) {
+		if !cToolchainLibs[match[1]] {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// parseCMakeReferences extracts pkg_check_modules modules from CMakeLists.txt.
+// find_package calls are reported by the CMake scanner.
+func parseCMakeReferences(content string) []string {
+	var names []string
+
+	for _, match := range pkgCheckRegex.FindAllStringSubmatch(content, -1) {
+		fields := strings.Fields(match[1])
+		// The first argument is the variable prefix
+		for _, field := range fields[min(1, len(fields)):] {
+			if cmakeKeywords[field] {
+				continue
+			}
+			if name := pkgConfigNameRegex.FindString(field); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// parseConanfile extracts the [requires] references of a conanfile.txt
+func parseConanfile(content string) []model.Dependency {
+	var dependencies []model.Dependency
+	inRequires := false
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inRequires = line == "[requires]"
+			continue
+		}
+		if !inRequires {
+			continue
+		}
+
+		// References look like name/version[@user/channel]
+		reference := strings.SplitN(line, "@", 2)[0]
+		name, version, found := strings.Cut(reference, "/")
+		if !found || version == "" {
+			version = "unknown"
+		}
+		dependencies = append(dependencies, model.Dependency{
+			ID: &model.DependencyID{
+				Group:   "",
+				Name:    name,
+				Version: version,
+				Type:    "conan",
+			},
+			Name:    name,
+			Version: version,
+			Type:    "conan",
+			Scope:   "runtime",
+		})
+	}
+
+	return dependencies
+}
+
+// newSystemDependency creates a heuristic system library dependency with an unknown version
+func newSystemDependency(name string) model.Dependency {
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Group:   "",
+			Name:    name,
+			Version: "unknown",
+			Type:    "system",
+		},
+		Name:    name,
+		Version: "unknown",
+		Type:    "system",
+		Scope:   "runtime",
+	}
+}
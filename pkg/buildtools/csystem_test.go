@@ -0,0 +1,88 @@
+package buildtools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestParseMakefileReferences(t *testing.T) {
+	makefile := `CC = gcc
+CFLAGS += $(shell pkg-config --cflags gtk+-3.0 libcurl)
+LDLIBS = ` + "`pkg-config --libs openssl>=1.1`" + ` -lz -lm -lpthread
+
+app: main.o
+	$(CC) -o $@ $^ $(LDLIBS) -lsqlite3
+`
+	expected := []string{"gtk+-3.0", "libcurl", "openssl", "z", "sqlite3"}
+	if got := parseMakefileReferences(makefile); !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseMakefileReferences() = %v, want %v", got, expected)
+	}
+}
+
+func TestParseCMakeReferences(t *testing.T) {
+	cmake := `cmake_minimum_required(VERSION 3.16)
+project(demo C)
+find_package(PkgConfig REQUIRED)
+pkg_check_modules(GLIB REQUIRED IMPORTED_TARGET
+    glib-2.0>=2.56
+    gio-2.0)
+find_package(ZLIB 1.2 REQUIRED)
+`
+	expected := []string{"glib-2.0", "gio-2.0"}
+	if got := parseCMakeReferences(cmake); !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseCMakeReferences() = %v, want %v", got, expected)
+	}
+}
+
+func TestParseConanfile(t *testing.T) {
+	conanfile := `[requires]
+zlib/1.2.13
+openssl/3.1.0@conan/stable
+
+[generators]
+CMakeDeps
+`
+	dependencies := parseConanfile(conanfile)
+	if len(dependencies) != 2 {
+		t.Fatalf("Expected 2 conan requirements, got %d", len(dependencies))
+	}
+	if dependencies[0].Name != "zlib" || dependencies[0].Version != "1.2.13" || dependencies[0].Type != "conan" {
+		t.Errorf("Unexpected first requirement: %+v", dependencies[0])
+	}
+	if dependencies[1].Name != "openssl" || dependencies[1].Version != "3.1.0" {
+		t.Errorf("Unexpected second requirement: %+v", dependencies[1])
+	}
+}
+
+func TestBuildScanner_ExperimentalCScan(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"Makefile": "LDLIBS = -lssl -lcrypto -lm\n",
+	})
+
+	disabled := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	if len(disabled.scanners) != 0 {
+		t.Errorf("Expected no scanners without --experimental-c-scan, got %d", len(disabled.scanners))
+	}
+
+	scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{ExperimentalCScan: true})
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 1 || roots[0].BuildTool != cSystemBuildTool {
+		t.Fatalf("Expected 1 heuristic C root, got %v", roots)
+	}
+
+	deps := roots[0].Dependencies
+	if len(deps) != 2 {
+		t.Fatalf("Expected ssl and crypto dependencies, got %v", deps)
+	}
+	for _, dep := range deps {
+		if dep.Type != "system" || dep.Version != "unknown" {
+			t.Errorf("Expected system dependency with unknown version, got %s %s", dep.Type, dep.Version)
+		}
+	}
+}
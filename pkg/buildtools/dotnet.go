@@ -0,0 +1,278 @@
+package buildtools
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// dotnetBuildTool is the build tool name of .NET projects
+const dotnetBuildTool = "dotnet"
+
+// dotnetProjectPatterns match MSBuild project files declaring NuGet package references
+var dotnetProjectPatterns = []string{"*.csproj", "*.fsproj", "*.vbproj"}
+
+var (
+	// slnProjectPattern matches a project entry of a solution file, capturing its relative path
+	slnProjectPattern = regexp.MustCompile(`^Project\("[^"]*"\)\s*=\s*"[^"]*",\s*"([^"]+\.(?:cs|fs|vb)proj)"`)
+	// dotnetListProjectPattern matches the line heading each project in dotnet list package output
+	dotnetListProjectPattern = regexp.MustCompile(`^Project '(.+)' has the following package references`)
+)
+
+// DotnetScanner handles .NET project scanning
+type DotnetScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	log         *logrus.Entry
+}
+
+// dotnetProject is the part of an MSBuild project file describing the project and its packages
+type dotnetProject struct {
+	PropertyGroups []struct {
+		AssemblyName string `xml:"AssemblyName"`
+		Version      string `xml:"Version"`
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences []dotnetPackageReference `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+// dotnetPackageReference is a NuGet package reference; the version may be an attribute or element
+type dotnetPackageReference struct {
+	Include        string `xml:"Include,attr"`
+	Version        string `xml:"Version,attr"`
+	VersionElement string `xml:"Version"`
+	PrivateAssets  string `xml:"PrivateAssets,attr"`
+}
+
+// NewDotnetScanner creates a new .NET scanner
+func NewDotnetScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *DotnetScanner {
+	return &DotnetScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger(dotnetBuildTool, env),
+	}
+}
+
+// ExeFind finds the dotnet executable
+func (ds *DotnetScanner) ExeFind() error { return nil } // Project files are parsed statically without --dotnet-path
+
+// FileFind checks if .NET project files exist
+func (ds *DotnetScanner) FileFind() error {
+	if len(ds.projectFiles()) == 0 {
+		return fmt.Errorf("no .NET project or solution file found in %s", ds.environment.GetDirectory())
+	}
+	return nil
+}
+
+// ScanExecute executes the .NET dependency scan, one root per project file
+func (ds *DotnetScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	projectFiles := ds.projectFiles()
+
+	// Listing restored packages needs a restore, so it only runs with an explicit --dotnet-path
+	var warnings scanWarnings
+	if ds.config.DotnetPath != "" {
+		ds.log.Info("Scanning .NET dependencies with dotnet list package...")
+		roots, err := ds.listPackages(projectFiles)
+		if err == nil && len(roots) > 0 {
+			return roots, nil
+		}
+		warnings.add(ds.log, "dotnet list package failed, falling back to project files: %v", err)
+	}
+
+	ds.log.Info("Scanning .NET dependencies (direct only)...")
+	var roots []model.DependencyRoot
+	for _, projectFile := range projectFiles {
+		root, err := parseDotnetProject(projectFile)
+		if err != nil {
+			warnings.add(ds.log.WithField(logger.FieldFile, projectFile), "Failed to parse %s: %v", filepath.Base(projectFile), err)
+			continue
+		}
+		roots = append(roots, *root)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no .NET project file could be parsed")
+	}
+	roots[0].Warnings = append(roots[0].Warnings, warnings...)
+	return roots, nil
+}
+
+// projectFiles returns the project files in the scan directory or, without any, those listed by
+// its solution files. Recursive detection registers nested projects itself, so solutions are
+// only followed when it is off.
+func (ds *DotnetScanner) projectFiles() []string {
+	dir := ds.environment.GetDirectory()
+	files := dotnetProjectFiles(dir)
+	if len(files) > 0 || ds.config.Recursive {
+		return files
+	}
+
+	solutions, _ := filepath.Glob(filepath.Join(dir, "*.sln"))
+	for _, solution := range solutions {
+		for _, projectFile := range parseSolutionProjects(solution) {
+			if _, err := os.Stat(projectFile); err == nil && !slices.Contains(files, projectFile) {
+				files = append(files, projectFile)
+			}
+		}
+	}
+	return files
+}
+
+// listPackages runs dotnet list package for every project file and parses the restored graph
+func (ds *DotnetScanner) listPackages(projectFiles []string) ([]model.DependencyRoot, error) {
+	var roots []model.DependencyRoot
+	for _, projectFile := range projectFiles {
+		err := runBuildTool(ds.config, ds.environment.GetDirectory(), func(r io.Reader) error {
+			projectRoots, err := parseDotnetPackageList(r)
+			roots = append(roots, projectRoots...)
+			return err
+		}, ds.config.DotnetPath, "list", projectFile, "package", "--include-transitive")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return roots, nil
+}
+
+// dotnetProjectFiles returns the MSBuild project files directly in dir, in lexical order
+func dotnetProjectFiles(dir string) []string {
+	var files []string
+	for _, pattern := range dotnetProjectPatterns {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		files = append(files, matches...)
+	}
+	slices.Sort(files)
+	return files
+}
+
+// parseSolutionProjects returns the paths of the projects a solution file lists
+func parseSolutionProjects(solution string) []string {
+	data, err := utils.ReadTextFile(solution)
+	if err != nil {
+		return nil
+	}
+
+	var projects []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if matches := slnProjectPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text())); matches != nil {
+			// Solutions are written on Windows with backslash separators
+			relPath := strings.ReplaceAll(matches[1], `\`, "/")
+			projects = append(projects, filepath.Join(filepath.Dir(solution), filepath.FromSlash(relPath)))
+		}
+	}
+	return projects
+}
+
+// parseDotnetProject reads the direct package references of an MSBuild project file
+func parseDotnetProject(projectFile string) (*model.DependencyRoot, error) {
+	data, err := utils.ReadTextFile(projectFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var project dotnetProject
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, err
+	}
+
+	root := &model.DependencyRoot{
+		ProjectName:    strings.TrimSuffix(filepath.Base(projectFile), filepath.Ext(projectFile)),
+		ProjectVersion: "unknown",
+		BuildTool:      dotnetBuildTool,
+	}
+	for _, group := range project.PropertyGroups {
+		if group.AssemblyName != "" {
+			root.ProjectName = group.AssemblyName
+		}
+		if group.Version != "" {
+			root.ProjectVersion = group.Version
+		}
+	}
+
+	for _, group := range project.ItemGroups {
+		for _, reference := range group.PackageReferences {
+			if reference.Include == "" {
+				continue
+			}
+			version := reference.Version
+			if version == "" {
+				version = strings.TrimSpace(reference.VersionElement)
+			}
+			// Assets private to the build, such as analyzers, do not flow to consumers
+			scope := "runtime"
+			if strings.EqualFold(reference.PrivateAssets, "all") {
+				scope = "development"
+			}
+			root.Dependencies = append(root.Dependencies, newDotnetDependency(reference.Include, version, scope))
+		}
+	}
+	return root, nil
+}
+
+// parseDotnetPackageList parses dotnet list package --include-transitive output into one root
+// per project. The output does not say which package pulls in a transitive one, so transitive
+// packages are listed next to the top-level ones with the "transitive" scope. Packages listed
+// for several target frameworks are reported once.
+func parseDotnetPackageList(r io.Reader) ([]model.DependencyRoot, error) {
+	var roots []model.DependencyRoot
+	var scope string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case dotnetListProjectPattern.MatchString(line):
+			name := dotnetListProjectPattern.FindStringSubmatch(line)[1]
+			roots = append(roots, model.DependencyRoot{ProjectName: name, ProjectVersion: "unknown", BuildTool: dotnetBuildTool})
+			scope = ""
+			seen = make(map[string]bool)
+		case strings.HasPrefix(line, "Top-level Package"):
+			scope = "runtime"
+		case strings.HasPrefix(line, "Transitive Package"):
+			scope = "transitive"
+		case strings.HasPrefix(line, "> ") && scope != "" && len(roots) > 0:
+			// "> Name [(A)] [Requested] Resolved"; the resolved version is always last
+			fields := strings.Fields(strings.TrimPrefix(line, "> "))
+			if len(fields) < 2 {
+				continue
+			}
+			name, version := fields[0], fields[len(fields)-1]
+			if key := name + "@" + version; !seen[key] {
+				seen[key] = true
+				root := &roots[len(roots)-1]
+				root.Dependencies = append(root.Dependencies, newDotnetDependency(name, version, scope))
+			}
+		}
+	}
+	return roots, scanner.Err()
+}
+
+// newDotnetDependency creates a NuGet dependency
+func newDotnetDependency(name, version, scope string) model.Dependency {
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Name:    name,
+			Version: version,
+			Type:    "nuget",
+		},
+		Name:    name,
+		Version: version,
+		Type:    "nuget",
+		Scope:   scope,
+	}
+}
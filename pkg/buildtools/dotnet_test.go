@@ -0,0 +1,169 @@
+package buildtools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+)
+
+func TestParseDotnetPackageList(t *testing.T) {
+	output := `Project 'WebApi' has the following package references
+   [net6.0]:
+   Top-level Package                      Requested   Resolved
+   > Microsoft.NETCore.App          (A)   [6.0.0, )   6.0.0
+   > Newtonsoft.Json                      13.0.1      13.0.1
+   > Serilog                              3.*         3.1.1
+
+   Transitive Package                                 Resolved
+   > Microsoft.CSharp                                 4.7.0
+   > System.Runtime                                   4.3.0
+
+   [net8.0]:
+   Top-level Package                      Requested   Resolved
+   > Newtonsoft.Json                      13.0.1      13.0.1
+
+   Transitive Package                                 Resolved
+   > System.Runtime                                   4.3.1
+
+Project 'WebApi.Tests' has the following package references
+   [net8.0]:
+   Top-level Package      Requested   Resolved
+   > xunit                2.6.1       2.6.1
+
+   Transitive Package                 Resolved
+   > xunit.core                       2.6.1
+`
+
+	roots, err := parseDotnetPackageList(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("parseDotnetPackageList failed: %v", err)
+	}
+	if len(roots) != 2 || roots[0].ProjectName != "WebApi" || roots[1].ProjectName != "WebApi.Tests" {
+		t.Fatalf("Expected WebApi and WebApi.Tests roots, got %+v", roots)
+	}
+
+	type entry struct{ version, scope string }
+	got := make(map[string]entry)
+	for _, dep := range roots[0].Dependencies {
+		got[dep.Name+"@"+dep.Version] = entry{dep.Version, dep.Scope}
+		if dep.Type != "nuget" || dep.ID == nil || dep.ID.Version != dep.Version {
+			t.Errorf("Expected a nuget dependency with a matching ID, got %+v", dep)
+		}
+	}
+	expected := map[string]string{
+		"Microsoft.NETCore.App@6.0.0": "runtime",
+		"Newtonsoft.Json@13.0.1":      "runtime",
+		"Serilog@3.1.1":               "runtime", // Resolved, not requested, version
+		"Microsoft.CSharp@4.7.0":      "transitive",
+		"System.Runtime@4.3.0":        "transitive",
+		"System.Runtime@4.3.1":        "transitive", // Differs per framework, so both are kept
+	}
+	if len(got) != len(expected) || len(roots[0].Dependencies) != len(expected) {
+		t.Errorf("Expected %d distinct packages for WebApi, got %v", len(expected), roots[0].Dependencies)
+	}
+	for key, scope := range expected {
+		if got[key].scope != scope {
+			t.Errorf("Expected %s with scope %s, got %+v", key, scope, got[key])
+		}
+	}
+	if deps := roots[1].Dependencies; len(deps) != 2 || deps[1].Name != "xunit.core" || deps[1].Scope != "transitive" {
+		t.Errorf("Expected xunit and transitive xunit.core for the test project, got %+v", deps)
+	}
+}
+
+func TestDotnetScanner_ScanExecute_Solution(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"App.sln": `Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "WebApi", "src\WebApi\WebApi.csproj", "{11111111-1111-1111-1111-111111111111}"
+EndProject
+`,
+		filepath.Join("src", "WebApi", "WebApi.csproj"): `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+    <Version>2.3.0</Version>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageReference Include="Serilog">
+      <Version>3.1.1</Version>
+    </PackageReference>
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`,
+	})
+
+	scanner := NewDotnetScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	if !scanner.IsApplicable() {
+		t.Fatal("Expected the solution to make the scanner applicable")
+	}
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	if len(roots) != 1 || roots[0].ProjectName != "WebApi" || roots[0].ProjectVersion != "2.3.0" || roots[0].BuildTool != "dotnet" {
+		t.Fatalf("Expected a single WebApi 2.3.0 dotnet root, got %+v", roots)
+	}
+	deps := roots[0].Dependencies
+	if len(deps) != 3 {
+		t.Fatalf("Expected 3 package references, got %+v", deps)
+	}
+	if deps[1].Name != "Serilog" || deps[1].Version != "3.1.1" {
+		t.Errorf("Expected the Serilog version from its element, got %+v", deps[1])
+	}
+	if deps[2].Name != "StyleCop.Analyzers" || deps[2].Scope != "development" {
+		t.Errorf("Expected the private analyzer as a development dependency, got %+v", deps[2])
+	}
+}
+
+func TestDotnetScanner_ScanExecute_WarningFields(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"App.csproj":    `<Project><ItemGroup><PackageReference Include="Serilog" Version="3.1.1" /></ItemGroup></Project>`,
+		"Broken.csproj": `<Project><ItemGroup>`,
+	})
+
+	// Record the entries of the global logger, restoring its hooks afterwards
+	log := logger.GetLogger()
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range log.Hooks {
+		hooks[level] = append(hooks[level], levelHooks...)
+	}
+	defer log.ReplaceHooks(hooks)
+	recorder := test.NewLocal(log)
+
+	roots, err := NewDotnetScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 1 || len(roots[0].Warnings) != 1 {
+		t.Fatalf("Expected one root with a warning for the broken project, got %+v", roots)
+	}
+
+	var warning *logrus.Entry
+	for _, entry := range recorder.AllEntries() {
+		if entry.Level == logrus.WarnLevel && entry.Message == roots[0].Warnings[0] {
+			warning = entry
+		}
+	}
+	if warning == nil {
+		t.Fatalf("Expected the warning %q to be logged", roots[0].Warnings[0])
+	}
+	expected := logrus.Fields{
+		logger.FieldScanner: dotnetBuildTool,
+		logger.FieldDir:     tempDir,
+		logger.FieldFile:    filepath.Join(tempDir, "Broken.csproj"),
+	}
+	for key, value := range expected {
+		if warning.Data[key] != value {
+			t.Errorf("Expected warning field %s=%v, got %v", key, value, warning.Data[key])
+		}
+	}
+}
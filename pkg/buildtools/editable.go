@@ -0,0 +1,152 @@
+package buildtools
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+var (
+	// setupInstallRequiresPattern captures the list passed to setup(install_requires=[...])
+	setupInstallRequiresPattern = regexp.MustCompile(`(?s)install_requires\s*=\s*\[(.*?)\]`)
+	// quotedStringPattern matches single- or double-quoted string literals
+	quotedStringPattern = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// editableTarget returns the local path of an "-e <path>" or "--editable <path>" requirement
+// line. Editable VCS URLs are not local and are not returned.
+func editableTarget(line string) (string, bool) {
+	var target string
+	switch {
+	case strings.HasPrefix(line, "--editable"):
+		target = strings.TrimPrefix(line, "--editable")
+	case strings.HasPrefix(line, "-e"):
+		target = strings.TrimPrefix(line, "-e")
+	default:
+		return "", false
+	}
+	if target != "" && target[0] != '=' && target[0] != ' ' && target[0] != '\t' {
+		return "", false
+	}
+
+	target = strings.TrimSpace(strings.TrimPrefix(target, "="))
+	// Drop extras such as ".[dev]"; they select optional dependencies of the same package
+	if idx := strings.Index(target, "["); idx != -1 {
+		target = target[:idx]
+	}
+	if target == "" || strings.Contains(target, "://") || strings.HasPrefix(target, "git+") {
+		return "", false
+	}
+	return target, true
+}
+
+// editableDependencies resolves an editable install of the local package in dir. The
+// dependencies of the scanned project itself ("-e .") are merged as direct dependencies;
+// any other local package becomes a direct dependency with the "local" scope whose children
+// are its own requirements.
+func (ps *PipScanner) editableDependencies(dir string) []model.Dependency {
+	name, version, requires := ps.localPackage(dir)
+	if name == "" && len(requires) == 0 {
+		ps.log.Warnf("Editable install %s has no setup.py or pyproject.toml to read", dir)
+		return nil
+	}
+
+	var dependencies []model.Dependency
+	for _, requirement := range requires {
+		// Drop environment markers such as "; python_version < '3.11'"
+		requirement, _, _ = strings.Cut(requirement, ";")
+		if requirement = strings.TrimSpace(requirement); requirement == "" {
+			continue
+		}
+		if dep, err := ps.parseRequirementLine(requirement); err == nil {
+			dependencies = append(dependencies, dep)
+		}
+	}
+
+	if sameDirectory(dir, ps.environment.GetDirectory()) {
+		return dependencies
+	}
+
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	if version == "" {
+		version = "unknown"
+	}
+	return []model.Dependency{{
+		ID: &model.DependencyID{
+			Name:    name,
+			Version: version,
+			Type:    "pip",
+		},
+		Name:     name,
+		Version:  version,
+		Type:     "pip",
+		Scope:    "local",
+		Children: dependencies,
+	}}
+}
+
+// localPackage reads the name, version and runtime requirements of a local Python package
+// from its pyproject.toml [project] table, falling back to setup.py
+func (ps *PipScanner) localPackage(dir string) (string, string, []string) {
+	var name, version string
+	var requires []string
+
+	setupPath := filepath.Join(dir, "setup.py")
+	if _, err := os.Stat(setupPath); err == nil {
+		name, version = ps.parseSetupPy(setupPath)
+		requires = parseSetupInstallRequires(setupPath)
+	}
+
+	if tables, err := parseTomlFile(filepath.Join(dir, "pyproject.toml")); err == nil {
+		for _, table := range tables {
+			if table.Name != "project" {
+				continue
+			}
+			if value := tomlString(table.Values["name"]); value != "" {
+				name = value
+			}
+			if value := tomlString(table.Values["version"]); value != "" {
+				version = value
+			}
+			if values := tomlArray(table.Values["dependencies"]); len(values) > 0 {
+				requires = nil
+				for _, raw := range values {
+					requires = append(requires, tomlString(raw))
+				}
+			}
+		}
+	}
+
+	return name, version, requires
+}
+
+// parseSetupInstallRequires returns the literal install_requires entries of a setup.py file
+func parseSetupInstallRequires(setupPath string) []string {
+	data, err := utils.ReadTextFile(setupPath)
+	if err != nil {
+		return nil
+	}
+	match := setupInstallRequiresPattern.FindSubmatch(data)
+	if match == nil {
+		return nil
+	}
+
+	var requires []string
+	for _, quoted := range quotedStringPattern.FindAllSubmatch(match[1], -1) {
+		requires = append(requires, string(quoted[1])+string(quoted[2]))
+	}
+	return requires
+}
+
+// sameDirectory reports whether two paths refer to the same directory
+func sameDirectory(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,120 @@
+package buildtools
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+// versionProbeTimeout bounds asking an executable for its version, which for Gradle may start a daemon
+const versionProbeTimeout = 30 * time.Second
+
+// Names the build tool executables are looked up under on PATH, in order of preference
+var (
+	gradleExecutables = []string{"gradle", "gradle.bat"}
+	pythonExecutables = []string{"python3", "python", "py"}
+	pipExecutables    = []string{"pip3", "pip"}
+	pipenvExecutables = []string{"pipenv", "pipenv.exe"}
+	npmExecutables    = []string{"npm", "npm.cmd"}
+	goExecutables     = []string{"go"}
+)
+
+// toolExecutable is an executable a scanner may run
+type toolExecutable struct {
+	tool        string
+	candidates  []string
+	versionArgs []string
+	configured  func(cfg *config.ScanConfig) string   // Path given by a flag, which replaces the PATH lookup
+	globalArgs  func(cfg *config.ScanConfig) []string // Arguments given to every invocation, such as Maven's -s
+}
+
+// toolExecutables are the executables of the build tool scanners, in scanner order
+var toolExecutables = []toolExecutable{
+	{tool: "maven", candidates: []string{"mvn", "mvn.cmd"}, versionArgs: []string{"--version"},
+		configured: func(cfg *config.ScanConfig) string { return cfg.MavenPath }, globalArgs: mavenSettingsArgs},
+	{tool: "gradle", candidates: gradleExecutables, versionArgs: []string{"--version"}},
+	{tool: "pip", candidates: pythonExecutables, versionArgs: []string{"--version"}},
+	{tool: "pip", candidates: pipExecutables, versionArgs: []string{"--version"},
+		configured: func(cfg *config.ScanConfig) string { return cfg.PipPath }},
+	{tool: "pipenv", candidates: pipenvExecutables, versionArgs: []string{"--version"}},
+	{tool: "npm", candidates: npmExecutables, versionArgs: []string{"--version"}},
+	{tool: "go", candidates: goExecutables, versionArgs: []string{"version"}},
+	{tool: dotnetBuildTool, candidates: []string{"dotnet"}, versionArgs: []string{"--version"},
+		configured: func(cfg *config.ScanConfig) string { return cfg.DotnetPath }},
+}
+
+// findExecutable returns the path of the first candidate found on PATH
+func findExecutable(candidates []string) (string, bool) {
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// ExecutableStatus is the outcome of looking up one build tool executable
+type ExecutableStatus struct {
+	Tool    string // Build tool the executable serves
+	Name    string // Executable name, or the configured path
+	Path    string // Resolved path, empty when the executable was not found
+	Version string // First line the executable printed for its version
+	Err     error  // Why the version could not be read
+}
+
+// ProbeExecutables looks up the executable of every build tool scanner on PATH, or at the path
+// given by its flag, and asks those found for their version
+func ProbeExecutables(cfg *config.ScanConfig) []ExecutableStatus {
+	statuses := make([]ExecutableStatus, len(toolExecutables))
+	var wg sync.WaitGroup
+	for i, executable := range toolExecutables {
+		status := ExecutableStatus{Tool: executable.tool, Name: executable.candidates[0]}
+		candidates := executable.candidates
+		if executable.configured != nil && executable.configured(cfg) != "" {
+			status.Name = executable.configured(cfg)
+			candidates = []string{status.Name}
+		}
+
+		var found bool
+		if status.Path, found = findExecutable(candidates); !found {
+			statuses[i] = status
+			continue
+		}
+
+		args := executable.versionArgs
+		if executable.globalArgs != nil {
+			args = append(executable.globalArgs(cfg), args...)
+		}
+
+		wg.Add(1)
+		go func(i int, status ExecutableStatus, args []string) {
+			defer wg.Done()
+			status.Version, status.Err = executableVersion(status.Path, args)
+			statuses[i] = status
+		}(i, status, args)
+	}
+	wg.Wait()
+	return statuses
+}
+
+// executableVersion runs an executable with its version arguments and returns the first line
+// it printed, skipping the rulers Gradle frames its version with
+func executableVersion(path string, args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+
+	output, err := buildToolCommand(ctx, "", path, args...).CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); strings.Trim(line, "-") != "" {
+			return line, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,114 @@
+package buildtools
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestProbeExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake build tools are shell scripts")
+	}
+
+	// Only the fake npm and gradle are on PATH; maven is given by its flag
+	binDir := t.TempDir()
+	toolDir := t.TempDir()
+	writeTestFiles(t, binDir, map[string]string{
+		"npm":    "#!/bin/sh\necho 10.2.4\n",
+		"gradle": "#!/bin/sh\necho\necho ------------\necho Gradle 8.5\necho ------------\n",
+	})
+	writeTestFiles(t, toolDir, map[string]string{"mvn-custom": "#!/bin/sh\necho 'Apache Maven 3.9.6'\nexit 1\n"})
+	for _, path := range []string{filepath.Join(binDir, "npm"), filepath.Join(binDir, "gradle"), filepath.Join(toolDir, "mvn-custom")} {
+		if err := os.Chmod(path, 0755); err != nil {
+			t.Fatalf("Failed to make %s executable: %v", path, err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+
+	mavenPath := filepath.Join(toolDir, "mvn-custom")
+	statuses := ProbeExecutables(&config.ScanConfig{MavenPath: mavenPath})
+	if len(statuses) != len(toolExecutables) {
+		t.Fatalf("Expected a status per executable, got %d", len(statuses))
+	}
+
+	find := func(name string) ExecutableStatus {
+		index := slices.IndexFunc(statuses, func(s ExecutableStatus) bool { return s.Name == name })
+		if index < 0 {
+			t.Fatalf("Expected a status for %s, got %+v", name, statuses)
+		}
+		return statuses[index]
+	}
+	if npm := find("npm"); npm.Path != filepath.Join(binDir, "npm") || npm.Version != "10.2.4" || npm.Err != nil {
+		t.Errorf("Expected npm 10.2.4 to be found, got %+v", npm)
+	}
+	if gradle := find("gradle"); gradle.Version != "Gradle 8.5" {
+		t.Errorf("Expected the ruler around the Gradle version to be skipped, got %q", gradle.Version)
+	}
+	if maven := find(mavenPath); maven.Tool != "maven" || maven.Path != mavenPath || maven.Err == nil {
+		t.Errorf("Expected the configured Maven path to be probed and its failure reported, got %+v", maven)
+	}
+	if goStatus := find("go"); goStatus.Path != "" {
+		t.Errorf("Expected go not to be found, got %+v", goStatus)
+	}
+}
+
+func TestProbeExecutables_MavenSettings(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake build tools are shell scripts")
+	}
+
+	// The fake mvn prints its arguments as its version
+	home := t.TempDir()
+	toolDir := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", toolDir)
+	mavenPath := filepath.Join(toolDir, "mvn")
+	writeTestFiles(t, toolDir, map[string]string{"mvn": "#!/bin/sh\necho \"$@\"\n"})
+	if err := os.Chmod(mavenPath, 0755); err != nil {
+		t.Fatalf("Failed to make mvn executable: %v", err)
+	}
+
+	mavenVersion := func(cfg *config.ScanConfig) string {
+		cfg.MavenPath = mavenPath
+		for _, status := range ProbeExecutables(cfg) {
+			if status.Tool == "maven" {
+				return status.Version
+			}
+		}
+		return ""
+	}
+
+	if version := mavenVersion(&config.ScanConfig{MavenSettings: "/etc/maven/corp-settings.xml"}); version != "-s /etc/maven/corp-settings.xml --version" {
+		t.Errorf("Expected -s with the configured settings, got %q", version)
+	}
+	if version := mavenVersion(&config.ScanConfig{}); version != "--version" {
+		t.Errorf("Expected no -s without a settings file, got %q", version)
+	}
+
+	// The user's settings.xml is picked up by default
+	writeTestFiles(t, home, map[string]string{".m2/settings.xml": "<settings/>"})
+	if version := mavenVersion(&config.ScanConfig{}); version != "-s "+filepath.Join(home, ".m2", "settings.xml")+" --version" {
+		t.Errorf("Expected -s with ~/.m2/settings.xml, got %q", version)
+	}
+}
+
+func TestBuildScanner_DetectBuildFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"requirements.txt": "requests==2.31.0\n",
+		"package.json":     `{"name": "app"}`,
+		"App.csproj":       "<Project />",
+		"README.md":        "# App\n",
+	})
+
+	scanner := NewBuildScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	expected := []BuildFile{{"App.csproj", dotnetBuildTool}, {"package.json", "npm"}, {"requirements.txt", "pip"}}
+	if buildFiles := scanner.DetectBuildFiles(); !slices.Equal(buildFiles, expected) {
+		t.Errorf("Expected build files %v, got %v", expected, buildFiles)
+	}
+}
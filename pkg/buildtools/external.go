@@ -0,0 +1,146 @@
+package buildtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// Environment variables passed to an external scanner
+const (
+	externalScanDirEnv   = "CLEANSOURCE_SCAN_DIR"  // Absolute path of the directory to scan
+	externalRecursiveEnv = "CLEANSOURCE_RECURSIVE" // "true" when nested projects should be scanned too
+)
+
+// ExternalScanner runs a user-provided executable that reports dependencies of formats the
+// built-in scanners do not understand. The executable is run in the scan directory, which is
+// also its only argument, and must print a JSON array of dependency roots on stdout.
+type ExternalScanner struct {
+	environment *ScannableEnvironment
+	config      *config.ScanConfig
+	executable  string // Absolute path of the external scanner, empty when it was not found
+	lookupErr   error
+	log         *logrus.Entry
+}
+
+// NewExternalScanner creates a new external scanner. The executable is resolved to an absolute
+// path right away, relative to the working directory, as it runs in the scan directory where
+// a relative path could name a file of the scanned repository instead.
+func NewExternalScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *ExternalScanner {
+	es := &ExternalScanner{
+		environment: env,
+		config:      cfg,
+		log:         scannerLogger("external", env),
+	}
+	es.executable, es.lookupErr = resolveExecutable(cfg.ExternalScanner)
+	return es
+}
+
+// resolveExecutable returns the absolute path of an executable given by name or path
+func resolveExecutable(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(path)
+}
+
+// ExeFind checks that the external scanner can be executed
+func (es *ExternalScanner) ExeFind() error {
+	if es.lookupErr != nil {
+		return fmt.Errorf("external scanner %s not found: %w", es.config.ExternalScanner, es.lookupErr)
+	}
+	return nil
+}
+
+// FileFind has nothing to check; the external scanner finds its own files
+func (es *ExternalScanner) FileFind() error { return nil }
+
+// IsApplicable always accepts the scan directory, since the user asked for the external scanner
+func (es *ExternalScanner) IsApplicable() bool { return true }
+
+// ScanExecute runs the external scanner and validates the dependency roots it reports
+func (es *ExternalScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	es.log.Infof("Running external scanner %s...", es.executable)
+
+	dir, err := filepath.Abs(es.environment.GetDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []model.DependencyRoot
+	err = runBuildToolCommand(es.config, func(ctx context.Context) *exec.Cmd {
+		cmd := buildToolCommand(ctx, dir, es.executable, dir)
+		cmd.Env = append(os.Environ(),
+			externalScanDirEnv+"="+dir,
+			fmt.Sprintf("%s=%t", externalRecursiveEnv, es.config.Recursive))
+		return cmd
+	}, func(r io.Reader) error {
+		var parseErr error
+		roots, parseErr = parseExternalOutput(r)
+		return parseErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("external scanner failed: %w", err)
+	}
+	return roots, nil
+}
+
+// parseExternalOutput decodes and validates the dependency roots printed by an external scanner.
+// Every root needs a build tool and every dependency a name; dependency IDs left out are filled
+// in from the dependency itself.
+func parseExternalOutput(r io.Reader) ([]model.DependencyRoot, error) {
+	var roots []model.DependencyRoot
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&roots); err != nil {
+		return nil, fmt.Errorf("invalid external scanner output: %w", err)
+	}
+
+	for i := range roots {
+		root := &roots[i]
+		if root.BuildTool == "" {
+			return nil, fmt.Errorf("invalid external scanner output: root %d has no buildTool", i)
+		}
+		if root.ProjectName == "" {
+			root.ProjectName = "unknown"
+		}
+		if root.ProjectVersion == "" {
+			root.ProjectVersion = "unknown"
+		}
+		if err := validateExternalDependencies(root.Dependencies); err != nil {
+			return nil, fmt.Errorf("invalid external scanner output: root %d: %w", i, err)
+		}
+	}
+	return roots, nil
+}
+
+// validateExternalDependencies checks that every dependency of a tree is named and gives those
+// without an ID one made of their name, version and type
+func validateExternalDependencies(dependencies []model.Dependency) error {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if dep.Name == "" {
+			return fmt.Errorf("dependency %d has no name", i)
+		}
+		if dep.Version == "" {
+			dep.Version = "unknown"
+		}
+		if dep.ID == nil {
+			dep.ID = &model.DependencyID{Name: dep.Name, Version: dep.Version, Type: dep.Type}
+		}
+		if err := validateExternalDependencies(dep.Children); err != nil {
+			return fmt.Errorf("%s: %w", dep.Name, err)
+		}
+	}
+	return nil
+}
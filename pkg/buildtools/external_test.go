@@ -0,0 +1,133 @@
+package buildtools
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+func TestBuildScanner_ScanDependencies_ExternalScanner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake external scanner is a shell script")
+	}
+
+	// The scanner echoes its environment back so the contract can be checked
+	binDir := t.TempDir()
+	script := filepath.Join(binDir, "scan-custom")
+	writeTestFiles(t, binDir, map[string]string{"scan-custom": `#!/bin/sh
+cat <<JSON
+[{"projectName": "custom", "buildTool": "custom", "dependencies": [
+  {"name": "libfoo", "version": "1.2.3", "type": "custom", "scope": "$CLEANSOURCE_RECURSIVE"},
+  {"name": "$(basename "$1")", "type": "custom"}
+]}]
+JSON
+`})
+	if err := os.Chmod(script, 0755); err != nil {
+		t.Fatalf("Failed to make fake scanner executable: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{"requirements.txt": "requests==2.31.0\n"})
+
+	env := NewScannableEnvironment(tempDir, "")
+	scanner := NewBuildScanner(env, &config.ScanConfig{ExternalScanner: script})
+
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 2 || roots[0].BuildTool != "pip" || roots[1].BuildTool != "custom" {
+		t.Fatalf("Expected the pip root followed by the external root, got %+v", roots)
+	}
+
+	custom := roots[1]
+	if custom.ProjectVersion != "unknown" {
+		t.Errorf("Expected missing project version to default to unknown, got %q", custom.ProjectVersion)
+	}
+	if len(custom.Dependencies) != 2 {
+		t.Fatalf("Expected 2 external dependencies, got %d", len(custom.Dependencies))
+	}
+	libfoo := custom.Dependencies[0]
+	if libfoo.ID == nil || libfoo.ID.Name != "libfoo" || libfoo.ID.Version != "1.2.3" {
+		t.Errorf("Expected an ID derived from libfoo 1.2.3, got %+v", libfoo.ID)
+	}
+	if libfoo.Scope != "false" {
+		t.Errorf("Expected CLEANSOURCE_RECURSIVE=false, got %q", libfoo.Scope)
+	}
+	if dirDep := custom.Dependencies[1]; dirDep.Name != filepath.Base(tempDir) || dirDep.Version != "unknown" {
+		t.Errorf("Expected the scan directory as argument and an unknown version, got %s@%s", dirDep.Name, dirDep.Version)
+	}
+}
+
+func TestExternalScanner_RelativePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake external scanner is a shell script")
+	}
+
+	// The same relative path exists in the working directory and in the scanned repository
+	script := func(project string) string {
+		return "#!/bin/sh\necho '[{\"projectName\": \"" + project + "\", \"buildTool\": \"custom\"}]'\n"
+	}
+	workDir := t.TempDir()
+	scanDir := t.TempDir()
+	writeTestFiles(t, workDir, map[string]string{"tools/scan.sh": script("trusted")})
+	writeTestFiles(t, scanDir, map[string]string{"tools/scan.sh": script("from-repository")})
+	for _, dir := range []string{workDir, scanDir} {
+		if err := os.Chmod(filepath.Join(dir, "tools", "scan.sh"), 0755); err != nil {
+			t.Fatalf("Failed to make fake scanner executable: %v", err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+	if err := os.Chdir(workDir); err != nil {
+		t.Fatalf("Failed to enter working directory: %v", err)
+	}
+
+	scanner := NewExternalScanner(NewScannableEnvironment(scanDir, ""), &config.ScanConfig{ExternalScanner: "./tools/scan.sh"})
+	if err := scanner.ExeFind(); err != nil {
+		t.Fatalf("ExeFind failed: %v", err)
+	}
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 1 || roots[0].ProjectName != "trusted" {
+		t.Errorf("Expected the scanner of the working directory to run, got %+v", roots)
+	}
+
+	missing := NewExternalScanner(NewScannableEnvironment(workDir, ""), &config.ScanConfig{ExternalScanner: "./tools/missing.sh"})
+	if err := missing.ExeFind(); err == nil {
+		t.Error("Expected a missing external scanner to be reported")
+	}
+}
+
+func TestParseExternalOutput_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		errMsg string
+	}{
+		{"not json", "scanning...", "invalid external scanner output"},
+		{"unknown field", `[{"buildTool": "custom", "deps": []}]`, "unknown field"},
+		{"missing build tool", `[{"projectName": "app"}]`, "has no buildTool"},
+		{"unnamed child", `[{"buildTool": "custom", "dependencies": [{"name": "a", "children": [{"version": "1"}]}]}]`, "a: dependency 0 has no name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseExternalOutput(strings.NewReader(tt.output))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package buildtools
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// parseGoWork returns the module directories listed by the use directives of a go.work file,
+// in order and relative to the directory of the file
+func parseGoWork(goWorkPath string) ([]string, error) {
+	file, err := utils.OpenTextFile(goWorkPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var dirs []string
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "use (" || line == "use(":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use"))
+		case !inBlock:
+			continue
+		}
+
+		// Paths containing spaces are quoted
+		if unquoted, err := strconv.Unquote(line); err == nil {
+			line = unquoted
+		}
+		if line != "" {
+			dirs = append(dirs, filepath.FromSlash(line))
+		}
+	}
+	return dirs, scanner.Err()
+}
+
+// goWorkPath returns the path of the workspace file in the scan directory
+func (gs *GoScanner) goWorkPath() string {
+	return filepath.Join(gs.environment.GetDirectory(), "go.work")
+}
+
+// workspaceModules returns the directories of the modules used by go.work that have a go.mod,
+// or nil without a workspace. Recursive detection registers nested modules itself, so the
+// workspace is only followed when it is off.
+func (gs *GoScanner) workspaceModules() ([]string, error) {
+	if gs.config.Recursive {
+		return nil, nil
+	}
+	if _, err := os.Stat(gs.goWorkPath()); err != nil {
+		return nil, nil
+	}
+
+	uses, err := parseGoWork(gs.goWorkPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, use := range uses {
+		dir := use
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(gs.environment.GetDirectory(), dir)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+			gs.log.Debugf("Skipping Go workspace module %s: no go.mod", use)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
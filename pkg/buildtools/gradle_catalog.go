@@ -0,0 +1,216 @@
+package buildtools
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// Scopes of Gradle dependencies that only take part in the build
+const (
+	gradlePluginScope    = "plugin"              // Build plugins and the buildscript classpath
+	gradleProcessorScope = "annotationProcessor" // Annotation processors run by kapt, ksp or javac
+)
+
+// gradleCatalogFile is the default version catalog of a Gradle build, relative to its root
+var gradleCatalogFile = filepath.Join("gradle", "libs.versions.toml")
+
+var (
+	// gradleConfigurationPattern matches the configuration a dependency line starts with
+	gradleConfigurationPattern = regexp.MustCompile(`^([A-Za-z]+)\s*[\s(]`)
+	// gradlePluginsBlockPattern matches the opening of a plugins block
+	gradlePluginsBlockPattern = regexp.MustCompile(`^plugins\s*\{`)
+	// gradleCatalogAliasPattern matches a catalog accessor such as libs.androidx.core.ktx,
+	// capturing the alias after libs.
+	gradleCatalogAliasPattern = regexp.MustCompile(`\blibs\.([A-Za-z0-9_.]+)`)
+	// gradlePluginPattern matches a plugin declared in a plugins block by id or, with the
+	// Kotlin DSL shorthand, by kotlin("android"), followed by an optional version
+	gradlePluginPattern = regexp.MustCompile(`^(id|kotlin)\s*\(?\s*["']([^"']+)["']\s*\)?(?:\s+version\s*\(?\s*["']([^"']+)["']\s*\)?)?`)
+)
+
+// gradleVersionCatalog holds the libraries, bundles and plugins of a version catalog, keyed by
+// the accessor alias with "-" and "_" separators replaced by "."
+type gradleVersionCatalog struct {
+	Libraries map[string]gradleCatalogLibrary
+	Bundles   map[string][]string // Bundle alias to library aliases
+	Plugins   map[string]gradleCatalogLibrary
+}
+
+// gradleCatalogLibrary is a library or plugin declared in a version catalog. Plugins only set
+// Name, to their plugin id.
+type gradleCatalogLibrary struct {
+	Group   string
+	Name    string
+	Version string // Empty when the version comes from a platform or is not declared
+}
+
+// parseGradleVersionCatalog reads gradle/libs.versions.toml below dir, returning nil when the
+// build has no version catalog
+func parseGradleVersionCatalog(dir string) (*gradleVersionCatalog, error) {
+	path := filepath.Join(dir, gradleCatalogFile)
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	tables, err := parseTomlFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string)
+	for _, table := range tables {
+		if table.Name == "versions" {
+			for key, value := range table.Values {
+				versions[key] = catalogVersion(value, nil)
+			}
+		}
+	}
+
+	catalog := &gradleVersionCatalog{
+		Libraries: make(map[string]gradleCatalogLibrary),
+		Bundles:   make(map[string][]string),
+		Plugins:   make(map[string]gradleCatalogLibrary),
+	}
+	for _, table := range tables {
+		for key, value := range table.Values {
+			alias := normalizeCatalogAlias(key)
+			switch table.Name {
+			case "libraries":
+				if library, ok := parseCatalogLibrary(value, versions); ok {
+					catalog.Libraries[alias] = library
+				}
+			case "bundles":
+				for _, element := range tomlArray(value) {
+					catalog.Bundles[alias] = append(catalog.Bundles[alias], normalizeCatalogAlias(tomlString(element)))
+				}
+			case "plugins":
+				if plugin, ok := parseCatalogPlugin(value, versions); ok {
+					catalog.Plugins[alias] = plugin
+				}
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// parseCatalogLibrary decodes a library given as "group:name:version" or as an inline table
+// with module or group and name, and version or version.ref
+func parseCatalogLibrary(raw string, versions map[string]string) (gradleCatalogLibrary, bool) {
+	table := tomlInlineTable(raw)
+	if table == nil {
+		parts := strings.Split(tomlString(raw), ":")
+		if len(parts) < 2 {
+			return gradleCatalogLibrary{}, false
+		}
+		library := gradleCatalogLibrary{Group: parts[0], Name: parts[1]}
+		if len(parts) > 2 {
+			library.Version = parts[2]
+		}
+		return library, true
+	}
+
+	library := gradleCatalogLibrary{Group: tomlString(table["group"]), Name: tomlString(table["name"])}
+	if module := tomlString(table["module"]); module != "" {
+		library.Group, library.Name, _ = strings.Cut(module, ":")
+	}
+	library.Version = catalogTableVersion(table, versions)
+	return library, library.Group != "" && library.Name != ""
+}
+
+// parseCatalogPlugin decodes a plugin given as "id:version" or as an inline table with id,
+// and version or version.ref
+func parseCatalogPlugin(raw string, versions map[string]string) (gradleCatalogLibrary, bool) {
+	table := tomlInlineTable(raw)
+	if table == nil {
+		id, version, _ := strings.Cut(tomlString(raw), ":")
+		return gradleCatalogLibrary{Name: id, Version: version}, id != ""
+	}
+	plugin := gradleCatalogLibrary{Name: tomlString(table["id"]), Version: catalogTableVersion(table, versions)}
+	return plugin, plugin.Name != ""
+}
+
+// catalogTableVersion returns the version of a library or plugin table, following version.ref
+func catalogTableVersion(table map[string]string, versions map[string]string) string {
+	if ref := tomlString(table["version.ref"]); ref != "" {
+		return versions[ref]
+	}
+	return catalogVersion(table["version"], versions)
+}
+
+// catalogVersion decodes a version given as a string or as a rich version table, preferring
+// its strictly, require and prefer constraints in that order
+func catalogVersion(raw string, versions map[string]string) string {
+	table := tomlInlineTable(raw)
+	if table == nil {
+		return tomlString(raw)
+	}
+	if ref := tomlString(table["ref"]); ref != "" {
+		return versions[ref]
+	}
+	for _, key := range []string{"strictly", "require", "prefer"} {
+		if version := tomlString(table[key]); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// normalizeCatalogAlias turns a catalog key into the alias its accessor uses, e.g.
+// androidx-core_ktx into androidx.core.ktx
+func normalizeCatalogAlias(key string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(key)
+}
+
+// catalogDependencies resolves the libs accessor of a dependency declaration, such as
+// implementation(libs.androidx.core) or implementation(libs.bundles.compose), into the
+// dependencies it stands for. Unknown aliases resolve to nothing.
+func (c *gradleVersionCatalog) catalogDependencies(line, scope string) []model.Dependency {
+	matches := gradleCatalogAliasPattern.FindStringSubmatch(line)
+	if c == nil || matches == nil {
+		return nil
+	}
+
+	aliases := []string{matches[1]}
+	if bundle, ok := strings.CutPrefix(matches[1], "bundles."); ok {
+		aliases = c.Bundles[bundle]
+	}
+
+	var dependencies []model.Dependency
+	for _, alias := range aliases {
+		if library, ok := c.Libraries[alias]; ok {
+			dependencies = append(dependencies, newGradleDependency(library.Group, library.Name, library.Version, scope))
+		}
+	}
+	return dependencies
+}
+
+// pluginDependency resolves a line of a plugins block into the plugin it applies, reported as
+// its plugin marker artifact. Plugins declared without a version are provided by Gradle or
+// resolved elsewhere, so they are skipped.
+func (c *gradleVersionCatalog) pluginDependency(line string) *model.Dependency {
+	var id, version string
+	if alias, ok := strings.CutPrefix(line, "alias"); ok {
+		matches := gradleCatalogAliasPattern.FindStringSubmatch(alias)
+		if c == nil || matches == nil {
+			return nil
+		}
+		plugin, found := c.Plugins[strings.TrimPrefix(matches[1], "plugins.")]
+		if !found {
+			return nil
+		}
+		id, version = plugin.Name, plugin.Version
+	} else if matches := gradlePluginPattern.FindStringSubmatch(line); matches != nil {
+		id, version = matches[2], matches[3]
+		if matches[1] == "kotlin" {
+			id = "org.jetbrains.kotlin." + id
+		}
+	}
+	if id == "" || version == "" {
+		return nil
+	}
+
+	dep := newGradleDependency(id, id+".gradle.plugin", version, gradlePluginScope)
+	return &dep
+}
@@ -0,0 +1,219 @@
+package buildtools
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+var (
+	// gradleIncludePattern matches include statements such as include 'app', ':lib' or include(":app")
+	gradleIncludePattern = regexp.MustCompile(`^include\b\s*\(?(.*?)\)?\s*$`)
+	// gradleProjectDirPattern matches remappings such as project(':lib').projectDir = file('libs/lib')
+	gradleProjectDirPattern = regexp.MustCompile(`^project\s*\(\s*["']([^"']+)["']\s*\)\s*\.projectDir\s*=\s*(.+)$`)
+	// gradleRootNamePattern matches rootProject.name = 'platform'
+	gradleRootNamePattern = regexp.MustCompile(`^rootProject\.name\s*=\s*["']([^"']+)["']`)
+	// gradleQuotedPattern matches a quoted string literal
+	gradleQuotedPattern = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// gradleSettings is what settings.gradle declares about a multi-project build
+type gradleSettings struct {
+	RootName    string // rootProject.name, empty when not set
+	Subprojects []gradleSubproject
+}
+
+// gradleSubproject is a subproject declared in settings.gradle
+type gradleSubproject struct {
+	Path string // Gradle project path, e.g. ":lib:core"
+	Dir  string // Directory relative to the root project
+}
+
+// parseGradleSettings reads the root project name and the subprojects included by
+// settings.gradle or settings.gradle.kts in dir, applying projectDir remappings. It returns
+// empty settings when there is no settings file.
+func parseGradleSettings(dir string) (*gradleSettings, error) {
+	var file io.ReadCloser
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		f, err := utils.OpenTextFile(filepath.Join(dir, name))
+		if err == nil {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return &gradleSettings{}, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	settings := &gradleSettings{}
+	remapped := make(map[string]string)
+	var include string // Include statement continued on the following lines
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if include != "" {
+			include += " " + stripGradleComment(line)
+			if !gradleStatementContinues(include) {
+				settings.include(include)
+				include = ""
+			}
+			continue
+		}
+
+		if matches := gradleRootNamePattern.FindStringSubmatch(line); matches != nil {
+			settings.RootName = matches[1]
+			continue
+		}
+
+		if matches := gradleProjectDirPattern.FindStringSubmatch(line); matches != nil {
+			// new File(settingsDir, 'libs/lib') names the directory in its last argument
+			if paths := gradleQuotedPattern.FindAllStringSubmatch(matches[2], -1); len(paths) > 0 {
+				remapped[normalizeGradleProjectPath(matches[1])] = paths[len(paths)-1][1]
+			}
+			continue
+		}
+
+		if gradleIncludePattern.MatchString(line) {
+			if line = stripGradleComment(line); gradleStatementContinues(line) {
+				include = line
+				continue
+			}
+			settings.include(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if include != "" {
+		settings.include(include)
+	}
+
+	for i, subproject := range settings.Subprojects {
+		if dir, ok := remapped[subproject.Path]; ok {
+			settings.Subprojects[i].Dir = filepath.FromSlash(dir)
+		}
+	}
+	return settings, nil
+}
+
+// include adds the subprojects named by an include statement
+func (settings *gradleSettings) include(statement string) {
+	matches := gradleIncludePattern.FindStringSubmatch(statement)
+	if matches == nil {
+		return
+	}
+	for _, quoted := range gradleQuotedPattern.FindAllStringSubmatch(matches[1], -1) {
+		path := normalizeGradleProjectPath(quoted[1])
+		// By default ":lib:core" lives in lib/core
+		settings.Subprojects = append(settings.Subprojects, gradleSubproject{
+			Path: path,
+			Dir:  filepath.FromSlash(strings.ReplaceAll(strings.TrimPrefix(path, ":"), ":", "/")),
+		})
+	}
+}
+
+// gradleStatementContinues reports whether a statement goes on past the end of its line: a
+// parenthesis is still open, or an argument list ends with a comma
+func gradleStatementContinues(statement string) bool {
+	return strings.Count(statement, "(") > strings.Count(statement, ")") || strings.HasSuffix(statement, ",")
+}
+
+// stripGradleComment removes a trailing // comment from a line of an include statement, whose
+// project paths never contain one
+func stripGradleComment(line string) string {
+	if before, _, found := strings.Cut(line, "//"); found {
+		return strings.TrimSpace(before)
+	}
+	return line
+}
+
+// normalizeGradleProjectPath prefixes a project path with ":" so "lib" and ":lib" compare equal
+func normalizeGradleProjectPath(path string) string {
+	if strings.HasPrefix(path, ":") {
+		return path
+	}
+	return ":" + path
+}
+
+// gradleProjectName returns the name declared in a build file. Without one it composes
+// "group:name" from the group and the given fallback name, which is more useful than unknown.
+func gradleProjectName(build *gradleBuild, fallback string) string {
+	switch {
+	case build.Name != "unknown":
+		return build.Name
+	case fallback == "":
+		return "unknown"
+	case build.Group != "":
+		return build.Group + ":" + fallback
+	default:
+		return fallback
+	}
+}
+
+// rootProjectName names the root project from its build file, then rootProject.name in
+// settings.gradle, then the group and the project directory
+func (gs *GradleScanner) rootProjectName(build *gradleBuild) string {
+	if build.Name == "unknown" {
+		settings, err := parseGradleSettings(gs.environment.GetDirectory())
+		if err == nil && settings.RootName != "" {
+			return settings.RootName
+		}
+	}
+	return gradleProjectName(build, utils.ProjectNameFromDir(gs.environment.GetDirectory()))
+}
+
+// scanSubprojects parses the build file of every subproject declared in settings.gradle,
+// returning one root per subproject that has a build file. Build files are independent, so
+// they are parsed concurrently.
+func (gs *GradleScanner) scanSubprojects(rootVersion string) []model.DependencyRoot {
+	rootDir := gs.environment.GetDirectory()
+	settings, err := parseGradleSettings(rootDir)
+	if err != nil {
+		gs.log.Warnf("Failed to parse Gradle settings: %v", err)
+		return nil
+	}
+
+	builds := parseConcurrently(settings.Subprojects, parseWorkers(gs.config), func(subproject gradleSubproject) *gradleBuild {
+		dir := subproject.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(rootDir, dir)
+		}
+		build, err := gs.parseBuildGradleIn(dir)
+		if err != nil {
+			gs.log.Debugf("Skipping Gradle subproject %s: %v", subproject.Path, err)
+			return nil
+		}
+		return build
+	})
+
+	var roots []model.DependencyRoot
+	for i, subproject := range settings.Subprojects {
+		build := builds[i]
+		if build == nil {
+			continue
+		}
+
+		// Subprojects usually inherit their version from the root project
+		version := build.Version
+		if version == "unknown" {
+			version = rootVersion
+		}
+		roots = append(roots, model.DependencyRoot{
+			ProjectName:    gradleProjectName(build, subproject.Path[strings.LastIndex(subproject.Path, ":")+1:]),
+			ProjectVersion: version,
+			BuildTool:      "gradle",
+			Dependencies:   build.Dependencies,
+		})
+	}
+	return roots
+}
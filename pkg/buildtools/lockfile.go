@@ -0,0 +1,317 @@
+package buildtools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StaleLockfileWarning prefixes scan warnings reporting a lockfile out of sync with its manifest
+const StaleLockfileWarning = "stale lockfile"
+
+// IsStaleLockfileWarning reports whether a scan warning reports a stale lockfile
+func IsStaleLockfileWarning(warning string) bool {
+	return strings.HasPrefix(warning, StaleLockfileWarning+": ")
+}
+
+// staleLockfileWarning describes the drift between a manifest and its lockfile, or returns an
+// empty string when they agree. missing lists declared dependencies absent from the lockfile,
+// undeclared lists direct dependencies recorded by the lockfile but no longer declared.
+func staleLockfileWarning(lockfile, manifest string, missing, undeclared []string) string {
+	if len(missing) == 0 && len(undeclared) == 0 {
+		return ""
+	}
+
+	var details []string
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		details = append(details, fmt.Sprintf("is missing %s declared in %s", strings.Join(missing, ", "), manifest))
+	}
+	if len(undeclared) > 0 {
+		slices.Sort(undeclared)
+		details = append(details, fmt.Sprintf("lists %s not declared in %s", strings.Join(undeclared, ", "), manifest))
+	}
+	return fmt.Sprintf("%s: %s %s", StaleLockfileWarning, lockfile, strings.Join(details, "; "))
+}
+
+// lockfileResult holds the versions resolved by a JavaScript lockfile
+type lockfileResult struct {
+	File     string            // Lockfile name, e.g. "package-lock.json"
+	Versions map[string]string // Resolved versions keyed by package name or "name@range"
+	Skipped  int               // Entries that could not be parsed and were skipped
+	Unparsed map[string]bool   // Names of skipped entries, which are present even though unresolved
+	Direct   map[string]bool   // Dependencies the lockfile records as declared by the project, nil when unknown
+	Ranged   bool              // Entries are keyed by the declared range, so range changes make the lockfile stale
+	Licenses map[string]string // Declared licenses keyed by package name, for lockfiles that record them
+}
+
+// newLockfileResult creates an empty lockfile result
+func newLockfileResult(file string) *lockfileResult {
+	return &lockfileResult{File: file, Versions: make(map[string]string), Unparsed: make(map[string]bool), Licenses: make(map[string]string)}
+}
+
+// skip counts an entry that could not be parsed
+func (lr *lockfileResult) skip(name string) {
+	lr.Skipped++
+	lr.Unparsed[name] = true
+}
+
+// resolve returns the locked version for a dependency declared with the given range
+func (lr *lockfileResult) resolve(name, versionRange string) (string, bool) {
+	if version, ok := lr.Versions[name+"@"+versionRange]; ok {
+		return version, true
+	}
+	version, ok := lr.Versions[name]
+	return version, ok
+}
+
+// locks reports whether the lockfile has an entry for a dependency declared with the given range
+func (lr *lockfileResult) locks(name, versionRange string) bool {
+	key := name
+	if lr.Ranged {
+		key = name + "@" + versionRange
+	}
+	_, ok := lr.Versions[key]
+	return ok || lr.Unparsed[name] || lr.Direct[name]
+}
+
+// warning describes the skipped entries, or returns an empty string when none were skipped
+func (lr *lockfileResult) warning() string {
+	if lr.Skipped == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s: skipped %d unparseable entries", lr.File, lr.Skipped)
+}
+
+// parsePackageLock parses package-lock.json (lockfileVersion 1-3). Entries that
+// cannot be decoded are skipped and counted instead of failing the whole parse.
+func parsePackageLock(data []byte) (*lockfileResult, error) {
+	var lock struct {
+		Packages     map[string]json.RawMessage `json:"packages"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	result := newLockfileResult("package-lock.json")
+	var entry struct {
+		Version string          `json:"version"`
+		License json.RawMessage `json:"license"`
+	}
+
+	// lockfileVersion 2 and 3 list installed packages by path
+	for path, raw := range lock.Packages {
+		if path == "" {
+			result.Direct = packageLockRootDependencies(raw)
+			continue
+		}
+		name, found := strings.CutPrefix(path, "node_modules/")
+		if !found || strings.Contains(name, "/node_modules/") {
+			continue // Root project or nested install
+		}
+		entry.Version, entry.License = "", nil
+		if err := json.Unmarshal(raw, &entry); err != nil || entry.Version == "" {
+			result.skip(name)
+			continue
+		}
+		result.Versions[name] = entry.Version
+		if license := npmLicense(entry.License); license != "" {
+			result.Licenses[name] = license
+		}
+	}
+
+	// lockfileVersion 1 lists top-level packages by name
+	if len(lock.Packages) == 0 {
+		for name, raw := range lock.Dependencies {
+			entry.Version = ""
+			if err := json.Unmarshal(raw, &entry); err != nil || entry.Version == "" {
+				result.skip(name)
+				continue
+			}
+			result.Versions[name] = entry.Version
+		}
+	}
+
+	return result, nil
+}
+
+// npmLicense decodes the license field of a package, an SPDX expression or, in older packages,
+// an object with a type
+func npmLicense(raw json.RawMessage) string {
+	var license string
+	if json.Unmarshal(raw, &license) == nil {
+		return license
+	}
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(raw, &licenseObject)
+	return licenseObject.Type
+}
+
+// packageLockRootDependencies returns the dependencies declared by the root project entry of a
+// package-lock.json, or nil when the entry cannot be decoded
+func packageLockRootDependencies(raw json.RawMessage) map[string]bool {
+	var root struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil
+	}
+
+	direct := make(map[string]bool)
+	for _, section := range []map[string]string{root.Dependencies, root.DevDependencies, root.PeerDependencies, root.OptionalDependencies} {
+		for name := range section {
+			direct[name] = true
+		}
+	}
+	return direct
+}
+
+// parseYarnLock parses yarn.lock in both the classic (v1) and berry formats.
+// Entries without a readable header or version are skipped and counted.
+func parseYarnLock(data []byte) (*lockfileResult, error) {
+	result := newLockfileResult("yarn.lock")
+	result.Ranged = true
+
+	var specs []string
+	version := ""
+	inEntry := false
+
+	flush := func() {
+		if inEntry && (len(specs) == 0 || version == "") {
+			result.Skipped++
+		} else if inEntry {
+			for _, spec := range specs {
+				name, versionRange := splitYarnSpec(spec)
+				result.Versions[name+"@"+versionRange] = version
+				if _, ok := result.Versions[name]; !ok {
+					result.Versions[name] = version
+				}
+			}
+		}
+		specs, version, inEntry = nil, "", false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Unindented lines start a new entry: "pkg@^1.0.0", pkg@^1.1.0:
+		if !strings.HasPrefix(line, " ") {
+			flush()
+			if trimmed == "__metadata:" {
+				continue // Berry metadata block, not a package entry
+			}
+			inEntry = true
+			if !strings.HasSuffix(trimmed, ":") {
+				continue // Corrupt header, counted as skipped
+			}
+			for _, spec := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				if spec = strings.Trim(strings.TrimSpace(spec), `"`); strings.Contains(spec, "@") {
+					specs = append(specs, spec)
+				}
+			}
+			continue
+		}
+
+		// Only the first level of indentation holds the entry's own fields
+		if strings.HasPrefix(line, "    ") {
+			continue
+		}
+		if value, ok := strings.CutPrefix(trimmed, "version"); ok && (strings.HasPrefix(value, " ") || strings.HasPrefix(value, ":")) {
+			version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(value, ":")), `"`)
+		}
+	}
+	flush()
+
+	return result, scanner.Err()
+}
+
+// splitYarnSpec splits "name@range" (including scoped names and the berry "npm:" protocol)
+func splitYarnSpec(spec string) (string, string) {
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 {
+		return spec, ""
+	}
+	return spec[:at], strings.TrimPrefix(spec[at+1:], "npm:")
+}
+
+// parsePnpmLock parses pnpm-lock.yaml (v5 to v9). Dependency entries that cannot be
+// decoded are skipped and counted.
+func parsePnpmLock(data []byte) (*lockfileResult, error) {
+	var lock struct {
+		Importers            map[string]map[string]map[string]yaml.Node `yaml:"importers"`
+		Dependencies         map[string]yaml.Node                       `yaml:"dependencies"`
+		DevDependencies      map[string]yaml.Node                       `yaml:"devDependencies"`
+		OptionalDependencies map[string]yaml.Node                       `yaml:"optionalDependencies"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	result := newLockfileResult("pnpm-lock.yaml")
+	result.Direct = make(map[string]bool)
+	addEntries := func(entries map[string]yaml.Node) {
+		for name, node := range entries {
+			result.Direct[name] = true
+			version := pnpmEntryVersion(node)
+			if version == "" {
+				result.skip(name)
+				continue
+			}
+			result.Versions[name] = version
+		}
+	}
+
+	// Workspaces (and lockfile v9) list dependencies per importer; "." is the root project
+	if root, ok := lock.Importers["."]; ok {
+		for _, section := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+			addEntries(root[section])
+		}
+	} else {
+		addEntries(lock.Dependencies)
+		addEntries(lock.DevDependencies)
+		addEntries(lock.OptionalDependencies)
+	}
+
+	return result, nil
+}
+
+// pnpmEntryVersion returns the version of a pnpm dependency entry, given either
+// as a plain version or as a {specifier, version} mapping
+func pnpmEntryVersion(node yaml.Node) string {
+	var version string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		version = node.Value
+	case yaml.MappingNode:
+		var entry struct {
+			Version string `yaml:"version"`
+		}
+		if err := node.Decode(&entry); err != nil {
+			return ""
+		}
+		version = entry.Version
+	}
+
+	// Strip peer dependency suffixes such as 1.0.0(react@18.2.0) or 1.0.0_react@18.2.0
+	if i := strings.IndexAny(version, "(_"); i != -1 {
+		version = version[:i]
+	}
+	return version
+}
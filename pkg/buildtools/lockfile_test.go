@@ -0,0 +1,471 @@
+package buildtools
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestParsePackageLock_CorruptEntry(t *testing.T) {
+	lock := `{
+	"name": "demo",
+	"lockfileVersion": 3,
+	"packages": {
+		"": { "name": "demo", "version": "1.0.0" },
+		"node_modules/express": { "version": "4.18.2" },
+		"node_modules/broken": "not-an-object",
+		"node_modules/@types/node": { "version": "20.8.0" },
+		"node_modules/express/node_modules/debug": { "version": "2.6.9" }
+	}
+}`
+	result, err := parsePackageLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parsePackageLock failed: %v", err)
+	}
+	if result.Skipped != 1 {
+		t.Errorf("Expected 1 skipped entry, got %d", result.Skipped)
+	}
+	if result.Versions["express"] != "4.18.2" || result.Versions["@types/node"] != "20.8.0" {
+		t.Errorf("Expected valid entries to be returned, got %v", result.Versions)
+	}
+	if _, ok := result.Versions["debug"]; ok {
+		t.Error("Expected nested installs to be ignored")
+	}
+}
+
+func TestParsePackageLock_Licenses(t *testing.T) {
+	lock := `{
+	"lockfileVersion": 3,
+	"packages": {
+		"": { "name": "demo", "version": "1.0.0", "license": "ISC" },
+		"node_modules/express": { "version": "4.18.2", "license": "MIT" },
+		"node_modules/legacy": { "version": "0.1.0", "license": { "type": "BSD-3-Clause" } },
+		"node_modules/unlicensed": { "version": "1.0.0" }
+	}
+}`
+	result, err := parsePackageLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parsePackageLock failed: %v", err)
+	}
+	expected := map[string]string{"express": "MIT", "legacy": "BSD-3-Clause"}
+	if len(result.Licenses) != len(expected) {
+		t.Errorf("Expected licenses %v, got %v", expected, result.Licenses)
+	}
+	for name, license := range expected {
+		if result.Licenses[name] != license {
+			t.Errorf("Expected %s to be licensed %s, got %q", name, license, result.Licenses[name])
+		}
+	}
+
+	ns := NewNpmScanner(NewScannableEnvironment(t.TempDir(), ""), &config.ScanConfig{})
+	dependencies := []model.Dependency{{Name: "express", Version: "^4.18.0"}, {Name: "unlicensed", Version: "^1.0.0"}}
+	ns.applyLockfile(dependencies, result)
+	if dependencies[0].License != "MIT" || dependencies[0].Version != "4.18.2" || dependencies[1].License != "" {
+		t.Errorf("Expected express 4.18.2 licensed MIT and no license for unlicensed, got %+v", dependencies)
+	}
+}
+
+func TestParsePackageLock_V1(t *testing.T) {
+	lock := `{"lockfileVersion": 1, "dependencies": {"lodash": {"version": "4.17.21"}, "bad": {"version": 5}}}`
+	result, err := parsePackageLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parsePackageLock failed: %v", err)
+	}
+	if result.Versions["lodash"] != "4.17.21" || result.Skipped != 1 {
+		t.Errorf("Expected lodash and 1 skipped entry, got %v (skipped %d)", result.Versions, result.Skipped)
+	}
+}
+
+func TestParseYarnLock_CorruptEntry(t *testing.T) {
+	lock := `# yarn lockfile v1
+
+"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+  version "7.23.2"
+  dependencies:
+    debug "^4.1.0"
+
+broken-entry-without-colon
+  version "1.0.0"
+
+lodash@^4.17.0:
+  resolved "https://registry.yarnpkg.com/lodash/-/lodash-4.17.21.tgz"
+
+react@^18.2.0:
+  version "18.2.0"
+`
+	result, err := parseYarnLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parseYarnLock failed: %v", err)
+	}
+	if result.Skipped != 2 {
+		t.Errorf("Expected 2 skipped entries, got %d", result.Skipped)
+	}
+	if version, ok := result.resolve("@babel/core", "^7.1.0"); !ok || version != "7.23.2" {
+		t.Errorf("Expected @babel/core to resolve to 7.23.2, got %s", version)
+	}
+	if version, ok := result.resolve("react", "^18.0.0"); !ok || version != "18.2.0" {
+		t.Errorf("Expected react to resolve by name to 18.2.0, got %s", version)
+	}
+}
+
+func TestParseYarnLock_Berry(t *testing.T) {
+	lock := `__metadata:
+  version: 6
+  cacheKey: 8
+
+"typescript@npm:^5.2.0":
+  version: 5.2.2
+  resolution: "typescript@npm:5.2.2"
+`
+	result, err := parseYarnLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parseYarnLock failed: %v", err)
+	}
+	if version, ok := result.resolve("typescript", "^5.2.0"); !ok || version != "5.2.2" {
+		t.Errorf("Expected typescript to resolve to 5.2.2, got %s", version)
+	}
+	if result.Skipped != 0 {
+		t.Errorf("Expected metadata block not to be counted as skipped, got %d", result.Skipped)
+	}
+}
+
+func TestParsePnpmLock_CorruptEntry(t *testing.T) {
+	lock := `lockfileVersion: '6.0'
+
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+      broken:
+        - not
+        - a
+        - version
+    devDependencies:
+      vitest:
+        specifier: ^0.34.0
+        version: 0.34.6
+`
+	result, err := parsePnpmLock([]byte(lock))
+	if err != nil {
+		t.Fatalf("parsePnpmLock failed: %v", err)
+	}
+	if result.Skipped != 1 {
+		t.Errorf("Expected 1 skipped entry, got %d", result.Skipped)
+	}
+	if result.Versions["react-dom"] != "18.2.0" || result.Versions["vitest"] != "0.34.6" {
+		t.Errorf("Expected valid entries to be returned, got %v", result.Versions)
+	}
+}
+
+func TestNpmScanner_ScanExecute_LockfileWarnings(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"express": "^4.18.0", "broken": "^1.0.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"node_modules/express": {"version": "4.18.2"},
+			"node_modules/broken": []
+		}}`,
+	})
+
+	scanner := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	root := roots[0]
+	if len(root.Dependencies) != 2 {
+		t.Fatalf("Expected both dependencies to be returned, got %d", len(root.Dependencies))
+	}
+	for _, dep := range root.Dependencies {
+		switch dep.Name {
+		case "express":
+			if dep.Version != "4.18.2" || dep.ID.Version != "4.18.2" {
+				t.Errorf("Expected express to resolve to locked 4.18.2, got %s", dep.Version)
+			}
+		case "broken":
+			if dep.Version != "^1.0.0" {
+				t.Errorf("Expected broken to keep its declared range, got %s", dep.Version)
+			}
+		}
+	}
+	if len(root.Warnings) != 1 || root.Warnings[0] != "package-lock.json: skipped 1 unparseable entries" {
+		t.Errorf("Expected skipped entry warning, got %v", root.Warnings)
+	}
+}
+
+func TestNpmScanner_ScanExecute_StaleLockfile(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0",
+			"dependencies": {"express": "^4.18.0", "lodash": "^4.17.21"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "demo", "dependencies": {"express": "^4.18.0", "left-pad": "^1.3.0"}},
+			"node_modules/express": {"version": "4.18.2"},
+			"node_modules/left-pad": {"version": "1.3.0"}
+		}}`,
+	})
+
+	scanner := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	expected := "stale lockfile: package-lock.json is missing lodash declared in package.json; lists left-pad not declared in package.json"
+	if warnings := roots[0].Warnings; len(warnings) != 1 || warnings[0] != expected {
+		t.Errorf("Expected stale lockfile warning %q, got %v", expected, warnings)
+	}
+	if !IsStaleLockfileWarning(roots[0].Warnings[0]) {
+		t.Error("Expected warning to be recognized as a stale lockfile warning")
+	}
+}
+
+func TestNpmScanner_ScanExecute_YarnRangeChanged(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "^4.17.21"}}`,
+		"yarn.lock": `lodash@^4.17.0:
+  version "4.17.20"
+`,
+	})
+
+	scanner := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots[0].Warnings) != 1 || !IsStaleLockfileWarning(roots[0].Warnings[0]) {
+		t.Errorf("Expected a stale lockfile warning for a changed range, got %v", roots[0].Warnings)
+	}
+}
+
+func TestNpmScanner_ScanExecute_LockfileInSync(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0", "dependencies": {"express": "^4.18.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "demo", "dependencies": {"express": "^4.18.0"}},
+			"node_modules/express": {"version": "4.18.2"},
+			"node_modules/debug": {"version": "2.6.9"}
+		}}`,
+	})
+
+	scanner := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots[0].Warnings) != 0 {
+		t.Errorf("Expected no warnings for an up-to-date lockfile, got %v", roots[0].Warnings)
+	}
+}
+
+func TestNpmScanner_ScanExecute_Overrides(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0",
+			"dependencies": {"express": "^4.18.0", "lodash": "^4.17.0", "minimist": "^1.2.0", "qs": "^6.11.0"},
+			"overrides": {"lodash": "4.17.21", "minimist@^0.2": "0.2.4", "qs": {".": "$express"}},
+			"resolutions": {"**/express": "4.19.2", "body-parser/qs": "6.0.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "demo"},
+			"node_modules/express": {"version": "4.18.2"},
+			"node_modules/lodash": {"version": "4.17.20"},
+			"node_modules/minimist": {"version": "1.2.8"},
+			"node_modules/qs": {"version": "6.11.0"}
+		}}`,
+	})
+
+	roots, err := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	versions := make(map[string]string)
+	for _, dep := range roots[0].Dependencies {
+		versions[dep.Name] = dep.Version
+		if dep.ID.Version != dep.Version {
+			t.Errorf("Expected %s ID version %s, got %s", dep.Name, dep.Version, dep.ID.Version)
+		}
+	}
+	expected := map[string]string{
+		"express":  "4.19.2", // Resolution wins over the lockfile
+		"lodash":   "4.17.21",
+		"minimist": "1.2.8",  // The override only applies to ^0.2
+		"qs":       "4.18.2", // References the version express is declared and locked at
+	}
+	for name, version := range expected {
+		if versions[name] != version {
+			t.Errorf("Expected %s@%s, got %s", name, version, versions[name])
+		}
+	}
+}
+
+func TestNpmScanner_ScanExecute_RangeOverrides(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0",
+			"dependencies": {"axios": "^1.6.0", "debug": "^4.3.0", "semver": "^7.5.0", "ws": "^8.0.0"},
+			"overrides": {"axios": "^1.2.0", "debug": "^4.3.5", "semver@^7": "7.6.0", "ws@^7": "7.5.10"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "demo"},
+			"node_modules/axios": {"version": "1.7.2"},
+			"node_modules/debug": {"version": "4.3.4"},
+			"node_modules/semver": {"version": "7.5.4"},
+			"node_modules/ws": {"version": "8.17.1"}
+		}}`,
+	})
+
+	roots, err := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	versions := make(map[string]string)
+	for _, dep := range roots[0].Dependencies {
+		versions[dep.Name] = dep.Version
+	}
+	expected := map[string]string{
+		"axios":  "1.7.2",  // The locked version satisfies the override range
+		"debug":  "^4.3.5", // The locked version is outside the override range
+		"semver": "7.6.0",  // The selector ^7 intersects the declared ^7.5.0
+		"ws":     "8.17.1", // The selector ^7 does not intersect the declared ^8.0.0
+	}
+	for name, version := range expected {
+		if versions[name] != version {
+			t.Errorf("Expected %s@%s, got %s", name, version, versions[name])
+		}
+	}
+}
+
+func TestNpmScanner_ScanExecute_Runtime(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0",
+			"engines": {"node": ">=18.0.0", "npm": "^9"},
+			"packageManager": "pnpm@8.6.0+sha256.abc",
+			"dependencies": {"express": "^4.18.0"}}`,
+	})
+
+	roots, err := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	root := roots[0]
+	if root.Engines["node"] != ">=18.0.0" || root.Engines["npm"] != "^9" || len(root.Engines) != 2 {
+		t.Errorf("Expected node and npm engines, got %v", root.Engines)
+	}
+	if root.PackageManager != "pnpm@8.6.0+sha256.abc" {
+		t.Errorf("Expected packageManager pnpm@8.6.0+sha256.abc, got %q", root.PackageManager)
+	}
+	if len(root.Dependencies) != 1 {
+		t.Errorf("Expected engines not to be reported as dependencies, got %v", root.Dependencies)
+	}
+
+	// The deprecated array form of engines carries no constraints and is ignored
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json": `{"name": "demo", "version": "1.0.0", "engines": ["node >=0.8"]}`,
+	})
+	roots, err = NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if roots[0].Engines != nil || roots[0].PackageManager != "" {
+		t.Errorf("Expected no runtime for array engines, got %v and %q", roots[0].Engines, roots[0].PackageManager)
+	}
+}
+
+func TestNpmScanner_ScanExecute_MultipleLockfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json":      `{"name": "demo", "version": "1.0.0", "dependencies": {"lodash": "^4.17.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {"node_modules/lodash": {"version": "4.17.21"}}}`,
+		"yarn.lock":         "lodash@^4.17.0:\n  version \"4.17.20\"\n",
+	})
+
+	tests := []struct {
+		name        string
+		cfg         *config.ScanConfig
+		wantVersion string
+		wantWarning bool
+	}{
+		{"no preference", &config.ScanConfig{}, "4.17.21", true},
+		{"prefer lockfile", &config.ScanConfig{PreferLockfile: true}, "4.17.21", false},
+		{"prefer manifest", &config.ScanConfig{PreferManifest: true}, "^4.17.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, err := NewNpmScanner(NewScannableEnvironment(tempDir, ""), tt.cfg).ScanExecute()
+			if err != nil {
+				t.Fatalf("ScanExecute failed: %v", err)
+			}
+			if version := roots[0].Dependencies[0].Version; version != tt.wantVersion {
+				t.Errorf("Expected lodash version %s, got %s", tt.wantVersion, version)
+			}
+			warned := slices.ContainsFunc(roots[0].Warnings, func(w string) bool {
+				return strings.Contains(w, "Multiple npm lockfiles")
+			})
+			if warned != tt.wantWarning {
+				t.Errorf("Expected multiple lockfile warning %v, got %v", tt.wantWarning, roots[0].Warnings)
+			}
+		})
+	}
+}
+
+func TestGradleScanner_ScanExecute_StaleLockfile(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"build.gradle": `dependencies {
+    implementation 'com.google.guava:guava:32.1.2-jre'
+    implementation 'org.slf4j:slf4j-api:2.0.9'
+}`,
+		"gradle.lockfile": `# This is a Gradle generated file for dependency locking.
+com.google.guava:guava:32.1.2-jre=compileClasspath,runtimeClasspath
+com.google.guava:failureaccess:1.0.1=compileClasspath,runtimeClasspath
+empty=annotationProcessor
+`,
+	})
+
+	scanner := NewGradleScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	expected := "stale lockfile: gradle.lockfile is missing org.slf4j:slf4j-api declared in build.gradle"
+	if warnings := roots[0].Warnings; len(warnings) != 1 || warnings[0] != expected {
+		t.Errorf("Expected stale lockfile warning %q, got %v", expected, warnings)
+	}
+}
+
+func TestPipScanner_lockfileDrift(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"requirements.in":  "Django>=4.2\nrequests\n",
+		"requirements.txt": "django==4.2.7\nasgiref==3.7.2\n",
+	})
+
+	scanner := NewPipScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	reqPath := filepath.Join(tempDir, "requirements.txt")
+	compiled, err := scanner.parseRequirementsFile(reqPath)
+	if err != nil {
+		t.Fatalf("parseRequirementsFile failed: %v", err)
+	}
+
+	expected := "stale lockfile: requirements.txt is missing requests declared in requirements.in"
+	if warning := scanner.lockfileDrift(reqPath, compiled); warning != expected {
+		t.Errorf("Expected %q, got %q", expected, warning)
+	}
+}
@@ -3,29 +3,52 @@ package buildtools
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
-	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
 // MavenScanner handles Maven project scanning
 type MavenScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
 }
 
 // MavenPOM represents a simplified Maven POM structure
 type MavenPOM struct {
-	XMLName      xml.Name `xml:"project"`
-	GroupID      string   `xml:"groupId"`
-	ArtifactID   string   `xml:"artifactId"`
-	Version      string   `xml:"version"`
+	XMLName     xml.Name `xml:"project"`
+	GroupID     string   `xml:"groupId"`
+	ArtifactID  string   `xml:"artifactId"`
+	Version     string   `xml:"version"`
+	Description string   `xml:"description"`
+	Licenses    struct {
+		License []struct {
+			Name string `xml:"name"`
+		} `xml:"license"`
+	} `xml:"licenses"`
+	Dependencies struct {
+		Dependency []MavenDependency `xml:"dependency"`
+	} `xml:"dependencies"`
+	Build struct {
+		Plugins struct {
+			Plugin []MavenPlugin `xml:"plugin"`
+		} `xml:"plugins"`
+	} `xml:"build"`
+}
+
+// MavenPlugin represents a build plugin, whose dependencies are added to its classpath
+type MavenPlugin struct {
+	GroupID      string `xml:"groupId"`
+	ArtifactID   string `xml:"artifactId"`
+	Version      string `xml:"version"`
 	Dependencies struct {
 		Dependency []MavenDependency `xml:"dependency"`
 	} `xml:"dependencies"`
@@ -38,14 +61,22 @@ type MavenDependency struct {
 	Version    string `xml:"version"`
 	Scope      string `xml:"scope"`
 	Type       string `xml:"type"`
+	Classifier string `xml:"classifier"`
 }
 
+const (
+	// mavenPluginScope is the scope of build plugins and their dependencies, which run at build time
+	mavenPluginScope = "plugin"
+	// mavenDefaultPluginGroup is the group of plugins declared without one
+	mavenDefaultPluginGroup = "org.apache.maven.plugins"
+)
+
 // NewMavenScanner creates a new Maven scanner
 func NewMavenScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *MavenScanner {
 	return &MavenScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("maven", env),
 	}
 }
 
@@ -75,11 +106,11 @@ func (ms *MavenScanner) ScanExecute() ([]model.DependencyRoot, error) {
 
 // parsePOM parses a Maven POM.xml file
 func (ms *MavenScanner) parsePOM(pomPath string) (*MavenPOM, error) {
-	file, err := os.Open(pomPath)
+	file, err := utils.OpenTextFile(pomPath)
 	if err != nil {
 		return nil, err
 	}
-	defer func(file *os.File) {
+	defer func(file io.ReadCloser) {
 		_ = file.Close()
 	}(file)
 
@@ -96,38 +127,122 @@ func (ms *MavenScanner) parsePOM(pomPath string) (*MavenPOM, error) {
 // getMavenDependencyTree gets the dependency tree using Maven command
 // Removed external dependency tree parsing for test determinism
 
+// mavenSettingsArgs returns the -s argument pointing Maven at --maven-settings, else at
+// ~/.m2/settings.xml when it exists, so mirrors, proxies and credentials apply
+func mavenSettingsArgs(cfg *config.ScanConfig) []string {
+	if cfg.MavenSettings != "" {
+		return []string{"-s", cfg.MavenSettings}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	settings := filepath.Join(home, ".m2", "settings.xml")
+	if _, err := os.Stat(settings); err == nil {
+		return []string{"-s", settings}
+	}
+	return nil
+}
+
+// mavenRangeVersion reports whether version is a Maven version range such as "[1.0,2.0)" and
+// returns the version to record for it: the pinned version of "[1.0]", the inclusive lower
+// bound of the first range, or "unknown" when the range has no inclusive lower bound
+func mavenRangeVersion(version string) (string, bool) {
+	version = strings.TrimSpace(version)
+	if !strings.HasPrefix(version, "[") && !strings.HasPrefix(version, "(") {
+		return "", false
+	}
+
+	end := strings.IndexAny(version, ")]")
+	if end < 0 {
+		return "unknown", true
+	}
+	lower, _, _ := strings.Cut(version[1:end], ",")
+	if lower = strings.TrimSpace(lower); lower == "" || version[0] != '[' {
+		return "unknown", true
+	}
+	return lower, true
+}
+
 // pomToDepencyRoot converts a POM to a dependency root (fallback method)
 func (ms *MavenScanner) pomToDepencyRoot(pom *MavenPOM) *model.DependencyRoot {
 	var dependencies []model.Dependency
-
 	for _, dep := range pom.Dependencies.Dependency {
+		dependencies = append(dependencies, newMavenDependency(dep))
+	}
+	dependencies = append(dependencies, mavenPluginDependencies(pom)...)
+
+	return &model.DependencyRoot{
+		ProjectName:    pom.ArtifactID,
+		ProjectVersion: pom.Version,
+		BuildTool:      "maven",
+		Dependencies:   dependencies,
+	}
+}
+
+// mavenPluginDependencies returns the build plugins of a POM with the plugin scope, their own
+// dependencies as children. They are not part of the artifact but of its build supply chain.
+func mavenPluginDependencies(pom *MavenPOM) []model.Dependency {
+	var plugins []model.Dependency
+	for _, plugin := range pom.Build.Plugins.Plugin {
+		group := plugin.GroupID
+		if group == "" {
+			group = mavenDefaultPluginGroup
+		}
+		version := plugin.Version
+		if version == "" {
+			version = "unknown" // Taken from the super POM or plugin management
+		}
+
 		dependency := model.Dependency{
 			ID: &model.DependencyID{
-				Group:   dep.GroupID,
-				Name:    dep.ArtifactID,
-				Version: dep.Version,
-				Type:    dep.Type,
+				Group:   group,
+				Name:    plugin.ArtifactID,
+				Version: version,
+				Type:    "jar",
 			},
-			Name:    dep.ArtifactID,
-			Version: dep.Version,
-			Type:    dep.Type,
-			Scope:   dep.Scope,
-		}
-
-		if dependency.Type == "" {
-			dependency.Type = "jar"
+			Name:    plugin.ArtifactID,
+			Version: version,
+			Type:    "jar",
+			Scope:   mavenPluginScope,
 		}
-		if dependency.Scope == "" {
-			dependency.Scope = "compile"
+		for _, dep := range plugin.Dependencies.Dependency {
+			child := newMavenDependency(dep)
+			child.Scope = mavenPluginScope
+			dependency.Children = append(dependency.Children, child)
 		}
+		plugins = append(plugins, dependency)
+	}
+	return plugins
+}
 
-		dependencies = append(dependencies, dependency)
+// newMavenDependency converts a POM dependency, defaulting its type and scope
+func newMavenDependency(dep MavenDependency) model.Dependency {
+	dependency := model.Dependency{
+		ID: &model.DependencyID{
+			Group:   dep.GroupID,
+			Name:    dep.ArtifactID,
+			Version: dep.Version,
+			Type:    dep.Type,
+		},
+		Name:       dep.ArtifactID,
+		Version:    dep.Version,
+		Type:       dep.Type,
+		Classifier: dep.Classifier,
+		Scope:      dep.Scope,
 	}
 
-	return &model.DependencyRoot{
-		ProjectName:    pom.ArtifactID,
-		ProjectVersion: pom.Version,
-		BuildTool:      "maven",
-		Dependencies:   dependencies,
+	if dependency.Type == "" {
+		dependency.Type = "jar"
+	}
+	if dependency.Scope == "" {
+		dependency.Scope = "compile"
+	}
+	// Ranges are resolved by Maven at build time, so only their lower bound is known here
+	if version, ok := mavenRangeVersion(dep.Version); ok {
+		dependency.RawVersion = dep.Version
+		dependency.Version = version
+		dependency.ID.Version = version
 	}
+	return dependency
 }
@@ -0,0 +1,226 @@
+package buildtools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// monorepoConfigs are the configuration files of JavaScript monorepo tools, in order of preference
+var monorepoConfigs = []string{"lerna.json", "workspace.json", "nx.json", "turbo.json"}
+
+// monorepoPackagePatterns returns the monorepo tool configured in dir and the globs of its
+// package directories, relative to dir. Lerna lists its packages itself, Nx its projects in
+// workspace.json or nx.json; otherwise, as always with Turborepo, the packages are the npm, Yarn
+// or pnpm workspaces of the root. The tool is empty when dir holds no monorepo configuration.
+func monorepoPackagePatterns(dir string) (string, []string) {
+	tool := ""
+	for _, name := range monorepoConfigs {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			tool = name
+			break
+		}
+	}
+	if tool == "" {
+		return "", nil
+	}
+
+	var lerna struct {
+		Packages []string `json:"packages"`
+	}
+	if readJSONManifest(filepath.Join(dir, "lerna.json"), &lerna) && len(lerna.Packages) > 0 {
+		return tool, lerna.Packages
+	}
+
+	for _, name := range []string{"workspace.json", "nx.json"} {
+		var nx struct {
+			Projects map[string]json.RawMessage `json:"projects"`
+		}
+		if !readJSONManifest(filepath.Join(dir, name), &nx) {
+			continue
+		}
+		var patterns []string
+		for _, project := range nx.Projects {
+			if root := nxProjectRoot(project); root != "" {
+				patterns = append(patterns, root)
+			}
+		}
+		if len(patterns) > 0 {
+			slices.Sort(patterns)
+			return tool, patterns
+		}
+	}
+
+	if patterns := workspacePatterns(dir); len(patterns) > 0 {
+		return tool, patterns
+	}
+	// Lerna looks in packages/ unless told otherwise
+	if tool == "lerna.json" {
+		return tool, []string{"packages/*"}
+	}
+	return tool, nil
+}
+
+// nxProjectRoot returns the directory of an Nx project, given either as a path or as a
+// configuration with a root
+func nxProjectRoot(raw json.RawMessage) string {
+	var root string
+	if json.Unmarshal(raw, &root) == nil {
+		return root
+	}
+	var project struct {
+		Root string `json:"root"`
+	}
+	if json.Unmarshal(raw, &project) == nil {
+		return project.Root
+	}
+	return ""
+}
+
+// workspacePatterns returns the workspace globs of package.json, given as a list or as
+// {"packages": [...]}, or else those of pnpm-workspace.yaml
+func workspacePatterns(dir string) []string {
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if readJSONManifest(filepath.Join(dir, "package.json"), &manifest) && manifest.Workspaces != nil {
+		var patterns []string
+		if json.Unmarshal(manifest.Workspaces, &patterns) == nil {
+			return patterns
+		}
+		var workspaces struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(manifest.Workspaces, &workspaces) == nil {
+			return workspaces.Packages
+		}
+	}
+
+	data, err := utils.ReadTextFile(filepath.Join(dir, "pnpm-workspace.yaml"))
+	if err != nil {
+		return nil
+	}
+	var pnpm struct {
+		Packages []string `yaml:"packages"`
+	}
+	if yaml.Unmarshal(data, &pnpm) != nil {
+		return nil
+	}
+	return pnpm.Packages
+}
+
+// expandPackagePatterns returns the directories below root matched by package globs that hold
+// a package.json, sorted. Globs starting with ! exclude directories, and ** matches any depth.
+func expandPackagePatterns(root string, patterns []string) []string {
+	included := make(map[string]bool)
+	var excluded []string
+	for _, pattern := range patterns {
+		pattern, negated := strings.CutPrefix(strings.TrimSpace(pattern), "!")
+		pattern = strings.TrimSuffix(strings.TrimSuffix(filepath.ToSlash(pattern), "/package.json"), "/")
+		if pattern == "" || pattern == "." {
+			continue
+		}
+		if negated {
+			excluded = append(excluded, pattern)
+			continue
+		}
+		for _, dir := range globPackageDirs(root, pattern) {
+			included[dir] = true
+		}
+	}
+
+	var dirs []string
+	for dir := range included {
+		rel, _ := filepath.Rel(root, dir)
+		if dir == root || matchesAnyPattern(filepath.ToSlash(rel), excluded) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "package.json")); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// globPackageDirs returns the directories below root matched by one slash separated glob
+func globPackageDirs(root, pattern string) []string {
+	base, _, recursive := strings.Cut(pattern, "**")
+	if !recursive {
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		return matches
+	}
+
+	// Walk below the part before **, then match the whole glob against each directory
+	var dirs []string
+	_ = filepath.Walk(filepath.Join(root, filepath.FromSlash(base)), func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if detectionSkipDirs[info.Name()] || strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(root, path)
+		if matchesAnyPattern(filepath.ToSlash(rel), []string{pattern}) {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// matchesAnyPattern reports whether a slash separated relative path matches one of the globs,
+// where ** matches any number of path segments
+func matchesAnyPattern(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchSegments(strings.Split(path, "/"), strings.Split(pattern, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against glob segments
+func matchSegments(path, pattern []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(path[i:], pattern[1:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(path[1:], pattern[1:])
+}
+
+// monorepoPackages returns the package directories of a lerna, Nx or Turborepo monorepo rooted
+// in the scan directory, or nil without one. Recursive detection registers nested packages
+// itself, so the monorepo is only followed when it is off.
+func (ns *NpmScanner) monorepoPackages() []string {
+	if ns.config.Recursive {
+		return nil
+	}
+	tool, patterns := monorepoPackagePatterns(ns.environment.GetDirectory())
+	if tool == "" {
+		return nil
+	}
+
+	dirs := expandPackagePatterns(ns.environment.GetDirectory(), patterns)
+	ns.log.Infof("Found %d packages of the monorepo configured by %s", len(dirs), tool)
+	return dirs
+}
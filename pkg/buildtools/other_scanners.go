@@ -2,12 +2,16 @@ package buildtools
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -15,20 +19,22 @@ import (
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
 // GoScanner implements scanning for Go projects
 type GoScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
 }
 
 // GradleScanner handles Gradle project scanning
 type GradleScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
+	catalog     *gradleVersionCatalog // Version catalog resolving libs aliases, nil without one
 }
 
 // NewGradleScanner creates a new Gradle scanner
@@ -36,28 +42,24 @@ func NewGradleScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *Gradle
 	return &GradleScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("gradle", env),
 	}
 }
 
 // ExeFind finds the Gradle executable
 func (gs *GradleScanner) ExeFind() error {
 	// Try to find gradle executable in PATH
-	gradleCandidates := []string{"gradle", "gradle.bat", "./gradlew", "./gradlew.bat"}
-	for _, candidate := range gradleCandidates {
-		if candidate == "./gradlew" || candidate == "./gradlew.bat" {
-			// Check for gradle wrapper in project directory
-			wrapperPath := filepath.Join(gs.environment.GetDirectory(), candidate)
-			if _, err := os.Stat(wrapperPath); err == nil {
-				gs.log.Debugf("Found gradle wrapper: %s", wrapperPath)
-				return nil
-			}
-		} else {
-			// Check for gradle in PATH
-			if path, err := exec.LookPath(candidate); err == nil {
-				gs.log.Debugf("Found gradle executable: %s", path)
-				return nil
-			}
+	if path, found := findExecutable(gradleExecutables); found {
+		gs.log.Debugf("Found gradle executable: %s", path)
+		return nil
+	}
+
+	// Check for gradle wrapper in project directory
+	for _, candidate := range []string{"./gradlew", "./gradlew.bat"} {
+		wrapperPath := filepath.Join(gs.environment.GetDirectory(), candidate)
+		if _, err := os.Stat(wrapperPath); err == nil {
+			gs.log.Debugf("Found gradle wrapper: %s", wrapperPath)
+			return nil
 		}
 	}
 	return fmt.Errorf("gradle executable not found in PATH or as wrapper")
@@ -82,14 +84,21 @@ func (gs *GradleScanner) FileFind() error {
 func (gs *GradleScanner) ScanExecute() ([]model.DependencyRoot, error) {
 	gs.log.Info("Scanning Gradle dependencies...")
 
+	// Subprojects share the version catalog of the root project
+	var warnings scanWarnings
+	catalog, err := parseGradleVersionCatalog(gs.environment.GetDirectory())
+	if err != nil {
+		warnings.add(gs.log, "Failed to parse %s: %v", filepath.ToSlash(gradleCatalogFile), err)
+	}
+	gs.catalog = catalog
+
 	// Parse build.gradle for project info and dependencies
-	projectName, projectVersion, dependencies, err := gs.parseBuildGradle()
+	build, err := gs.parseBuildGradleIn(gs.environment.GetDirectory())
 	if err != nil {
-		gs.log.Warnf("Failed to parse build.gradle: %v", err)
-		projectName = "unknown"
-		projectVersion = "unknown"
-		dependencies = []model.Dependency{}
+		warnings.add(gs.log, "Failed to parse build.gradle: %v", err)
+		build = &gradleBuild{Name: "unknown", Version: "unknown", Dependencies: []model.Dependency{}}
 	}
+	projectName, projectVersion, dependencies := gs.rootProjectName(build), build.Version, build.Dependencies
 
 	root := model.DependencyRoot{
 		ProjectName:    projectName,
@@ -98,14 +107,63 @@ func (gs *GradleScanner) ScanExecute() ([]model.DependencyRoot, error) {
 		Dependencies:   dependencies,
 	}
 
-	return []model.DependencyRoot{root}, nil
+	// Compare declared dependencies with gradle.lockfile when dependency locking is enabled
+	if warning := gs.lockfileDrift(dependencies); warning != "" {
+		warnings.add(gs.log, "%s", warning)
+	}
+	root.Warnings = warnings
+
+	// Multi-module builds declare their subprojects in settings.gradle
+	return append([]model.DependencyRoot{root}, gs.scanSubprojects(projectVersion)...), nil
+}
+
+// lockfileDrift reports declared dependencies missing from gradle.lockfile. The lockfile also
+// lists transitive dependencies, so entries no longer declared cannot be told apart.
+func (gs *GradleScanner) lockfileDrift(dependencies []model.Dependency) string {
+	file, err := utils.OpenTextFile(filepath.Join(gs.environment.GetDirectory(), "gradle.lockfile"))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+
+	// Entries look like group:artifact:version=configuration,...
+	locked := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coordinates, _, _ := strings.Cut(line, "=")
+		if parts := strings.Split(coordinates, ":"); len(parts) >= 2 {
+			locked[parts[0]+":"+parts[1]] = true
+		}
+	}
+	if scanner.Err() != nil {
+		return ""
+	}
+
+	var missing []string
+	for _, dep := range dependencies {
+		if dep.ID == nil {
+			continue
+		}
+		if coordinates := dep.ID.Group + ":" + dep.Name; !locked[coordinates] && !slices.Contains(missing, coordinates) {
+			missing = append(missing, coordinates)
+		}
+	}
+	manifest := "build.gradle"
+	if _, err := os.Stat(filepath.Join(gs.environment.GetDirectory(), manifest)); err != nil {
+		manifest = "build.gradle.kts"
+	}
+	return staleLockfileWarning("gradle.lockfile", manifest, missing, nil)
 }
 
 // PipenvScanner handles Python pipenv project scanning
 type PipenvScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
 }
 
 // NewPipenvScanner creates a new pipenv scanner
@@ -113,19 +171,16 @@ func NewPipenvScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *Pipenv
 	return &PipenvScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("pipenv", env),
 	}
 }
 
 // ExeFind finds the pipenv executable
 func (ps *PipenvScanner) ExeFind() error {
 	// Try to find pipenv executable in PATH
-	pipenvCandidates := []string{"pipenv", "pipenv.exe"}
-	for _, candidate := range pipenvCandidates {
-		if path, err := exec.LookPath(candidate); err == nil {
-			ps.log.Debugf("Found pipenv executable: %s", path)
-			return nil
-		}
+	if path, found := findExecutable(pipenvExecutables); found {
+		ps.log.Debugf("Found pipenv executable: %s", path)
+		return nil
 	}
 	return fmt.Errorf("pipenv executable not found in PATH")
 }
@@ -144,9 +199,10 @@ func (ps *PipenvScanner) ScanExecute() ([]model.DependencyRoot, error) {
 	ps.log.Info("Scanning pipenv dependencies...")
 
 	// Parse Pipfile for project info
+	var warnings scanWarnings
 	projectName, projectVersion, err := ps.parsePipfile()
 	if err != nil {
-		ps.log.Warnf("Failed to parse Pipfile: %v", err)
+		warnings.add(ps.log, "Failed to parse Pipfile: %v", err)
 		projectName = "unknown"
 		projectVersion = "unknown"
 	}
@@ -162,6 +218,7 @@ func (ps *PipenvScanner) ScanExecute() ([]model.DependencyRoot, error) {
 		ProjectVersion: projectVersion,
 		BuildTool:      "pipenv",
 		Dependencies:   dependencies,
+		Warnings:       warnings,
 	}
 
 	return []model.DependencyRoot{root}, nil
@@ -171,7 +228,7 @@ func (ps *PipenvScanner) ScanExecute() ([]model.DependencyRoot, error) {
 type NpmScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
 }
 
 // NewNpmScanner creates a new npm scanner
@@ -179,19 +236,16 @@ func NewNpmScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *NpmScanne
 	return &NpmScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("npm", env),
 	}
 }
 
 // ExeFind finds the npm executable
 func (ns *NpmScanner) ExeFind() error {
 	// Try to find npm executable in PATH
-	npmCandidates := []string{"npm", "npm.cmd"}
-	for _, candidate := range npmCandidates {
-		if path, err := exec.LookPath(candidate); err == nil {
-			ns.log.Debugf("Found npm executable: %s", path)
-			return nil
-		}
+	if path, found := findExecutable(npmExecutables); found {
+		ns.log.Debugf("Found npm executable: %s", path)
+		return nil
 	}
 	return fmt.Errorf("npm executable not found in PATH")
 }
@@ -205,8 +259,34 @@ func (ns *NpmScanner) FileFind() error {
 	return nil
 }
 
-// ScanExecute executes the npm dependency scan
+// ScanExecute executes the npm dependency scan, adding one root per package of a lerna, Nx or
+// Turborepo monorepo
 func (ns *NpmScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	root, err := ns.scanPackage()
+	if err != nil {
+		return nil, err
+	}
+	roots := []model.DependencyRoot{*root}
+
+	var warnings scanWarnings
+	for _, dir := range ns.monorepoPackages() {
+		scanner := NewNpmScanner(NewScannableEnvironment(dir, ""), ns.config)
+		if !scanner.IsApplicable() {
+			continue
+		}
+		pkg, err := scanner.scanPackage()
+		if err != nil {
+			warnings.add(ns.log, "Failed to scan monorepo package %s: %v", dir, err)
+			continue
+		}
+		roots = append(roots, *pkg)
+	}
+	roots[0].Warnings = append(roots[0].Warnings, warnings...)
+	return roots, nil
+}
+
+// scanPackage scans the npm package in the scan directory
+func (ns *NpmScanner) scanPackage() (*model.DependencyRoot, error) {
 	ns.log.Info("Scanning npm dependencies...")
 
 	// Parse package.json for project info and dependencies
@@ -222,7 +302,151 @@ func (ns *NpmScanner) ScanExecute() ([]model.DependencyRoot, error) {
 		Dependencies:   dependencies,
 	}
 
-	return []model.DependencyRoot{root}, nil
+	// Keep the declared ranges, which scoped overrides such as "foo@^2" are matched against
+	ranges := make([]string, len(root.Dependencies))
+	for i, dep := range root.Dependencies {
+		ranges[i] = dep.Version
+	}
+
+	// Resolve declared ranges to locked versions when a lockfile is present
+	var warnings scanWarnings
+	if lock := ns.parseLockfile(&warnings); lock != nil {
+		// Compare before resolving, while dependencies still carry their declared ranges
+		stale := ns.lockfileDrift(root.Dependencies, lock)
+		ns.applyLockfile(root.Dependencies, lock)
+		for _, warning := range []string{lock.warning(), stale} {
+			if warning != "" {
+				warnings.add(ns.log, "%s", warning)
+			}
+		}
+	}
+	root.Warnings = warnings
+	root.Engines, root.PackageManager = ns.readRuntime()
+
+	// Versions forced by overrides or resolutions win over the lockfile
+	if overrides := ns.readOverrides(); len(overrides) > 0 {
+		ns.applyOverrides(root.Dependencies, ranges, overrides)
+	}
+
+	return &root, nil
+}
+
+// npmLockfiles maps lockfile names to their parsers, in order of preference
+var npmLockfiles = []struct {
+	name  string
+	parse func([]byte) (*lockfileResult, error)
+}{
+	{"package-lock.json", parsePackageLock},
+	{"npm-shrinkwrap.json", parsePackageLock},
+	{"yarn.lock", parseYarnLock},
+	{"pnpm-lock.yaml", parsePnpmLock},
+}
+
+// parseLockfile parses the first lockfile found, returning nil when there is none, it is unreadable
+// or --prefer-manifest asks for the declared ranges. Several lockfiles may disagree, so finding
+// more than one is warned about unless a source preference is set.
+func (ns *NpmScanner) parseLockfile(warnings *scanWarnings) *lockfileResult {
+	var found []string
+	for _, lockfile := range npmLockfiles {
+		if _, err := os.Stat(filepath.Join(ns.environment.GetDirectory(), lockfile.name)); err == nil {
+			found = append(found, lockfile.name)
+		}
+	}
+	switch {
+	case len(found) > 0 && ns.config.PreferManifest:
+		ns.log.Infof("Ignoring %s, using package.json ranges (--prefer-manifest)", strings.Join(found, ", "))
+		return nil
+	case len(found) > 1 && ns.config.PreferLockfile:
+		ns.log.Infof("Multiple npm lockfiles found, using %s (--prefer-lockfile)", found[0])
+	case len(found) > 1:
+		warnings.add(ns.log, "Multiple npm lockfiles found (%s), using %s; choose a source with --prefer-lockfile or --prefer-manifest",
+			strings.Join(found, ", "), found[0])
+	}
+
+	for _, lockfile := range npmLockfiles {
+		if !slices.Contains(found, lockfile.name) {
+			continue
+		}
+		data, err := utils.ReadTextFile(filepath.Join(ns.environment.GetDirectory(), lockfile.name))
+		if err != nil {
+			continue
+		}
+
+		result, err := lockfile.parse(data)
+		if err != nil {
+			warnings.add(ns.log.WithField(logger.FieldFile, lockfile.name), "Failed to parse %s, using declared versions: %v", lockfile.name, err)
+			return nil
+		}
+		result.File = lockfile.name
+		return result
+	}
+	return nil
+}
+
+// lockfileDrift reports declared dependencies missing from the lockfile and, when the lockfile
+// records the project's own dependencies, those it lists that package.json no longer declares
+func (ns *NpmScanner) lockfileDrift(dependencies []model.Dependency, lock *lockfileResult) string {
+	declared := make(map[string]bool)
+	var missing, undeclared []string
+	for _, dep := range dependencies {
+		if declared[dep.Name] {
+			continue
+		}
+		declared[dep.Name] = true
+		if !lock.locks(dep.Name, dep.Version) {
+			missing = append(missing, dep.Name)
+		}
+	}
+	for name := range lock.Direct {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	return staleLockfileWarning(lock.File, "package.json", missing, undeclared)
+}
+
+// applyLockfile replaces declared version ranges with the versions resolved by the lockfile,
+// and records the licenses the lockfile declares
+func (ns *NpmScanner) applyLockfile(dependencies []model.Dependency, lock *lockfileResult) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if license, ok := lock.Licenses[dep.Name]; ok {
+			dep.License = license
+		}
+		if version, ok := lock.resolve(dep.Name, dep.Version); ok {
+			dep.Version = version
+			if dep.ID != nil {
+				dep.ID.Version = version
+			}
+		}
+	}
+}
+
+// readRuntime returns the engines and packageManager fields of package.json, which record the
+// Node.js and package manager versions the project is meant for. Engines in the long deprecated
+// array form are ignored.
+func (ns *NpmScanner) readRuntime() (map[string]string, string) {
+	data, err := utils.ReadTextFile(filepath.Join(ns.environment.GetDirectory(), "package.json"))
+	if err != nil {
+		return nil, ""
+	}
+
+	var packageInfo struct {
+		Engines        json.RawMessage `json:"engines"`
+		PackageManager string          `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &packageInfo); err != nil {
+		return nil, ""
+	}
+
+	var engines map[string]string
+	if len(packageInfo.Engines) > 0 && json.Unmarshal(packageInfo.Engines, &engines) != nil {
+		engines = nil
+	}
+	if len(engines) == 0 {
+		engines = nil
+	}
+	return engines, strings.TrimSpace(packageInfo.PackageManager)
 }
 
 // NewGoScanner creates a new Go scanner
@@ -230,25 +454,30 @@ func NewGoScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *GoScanner
 	return &GoScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("go", env),
 	}
 }
 
 // ExeFind finds the Go executable
 func (gs *GoScanner) ExeFind() error {
+	// vendor/modules.txt is parsed statically
+	if _, err := os.Stat(gs.vendorModulesPath()); err == nil {
+		return nil
+	}
+
 	// Try to find go executable in PATH
-	goCandidates := []string{"go"}
-	for _, candidate := range goCandidates {
-		if path, err := exec.LookPath(candidate); err == nil {
-			gs.log.Debugf("Found go executable: %s", path)
-			return nil
-		}
+	if path, found := findExecutable(goExecutables); found {
+		gs.log.Debugf("Found go executable: %s", path)
+		return nil
 	}
 	return fmt.Errorf("go executable not found in PATH")
 }
 
 // FileFind checks if required Go files exist
 func (gs *GoScanner) FileFind() error {
+	if _, err := os.Stat(gs.goWorkPath()); err == nil {
+		return nil
+	}
 	goMod := filepath.Join(gs.environment.GetDirectory(), "go.mod")
 	if _, err := os.Stat(goMod); os.IsNotExist(err) {
 		return fmt.Errorf("go.mod not found")
@@ -256,38 +485,85 @@ func (gs *GoScanner) FileFind() error {
 	return nil
 }
 
-// ScanExecute executes the Go dependency scan
+// ScanExecute executes the Go dependency scan, one root per module of a go.work workspace
 func (gs *GoScanner) ScanExecute() ([]model.DependencyRoot, error) {
+	moduleDirs, err := gs.workspaceModules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.work: %w", err)
+	}
+	if len(moduleDirs) == 0 {
+		root, err := gs.scanModule()
+		if err != nil {
+			return nil, err
+		}
+		return []model.DependencyRoot{*root}, nil
+	}
+
+	gs.log.Infof("Scanning %d Go workspace modules...", len(moduleDirs))
+	var warnings scanWarnings
+	var roots []model.DependencyRoot
+	for _, dir := range moduleDirs {
+		root, err := NewGoScanner(NewScannableEnvironment(dir, ""), gs.config).scanModule()
+		if err != nil {
+			warnings.add(gs.log, "Failed to scan Go workspace module %s: %v", dir, err)
+			continue
+		}
+		roots = append(roots, *root)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no Go workspace module could be scanned")
+	}
+	roots[0].Warnings = append(roots[0].Warnings, warnings...)
+	return roots, nil
+}
+
+// scanModule scans the Go module in the scan directory
+func (gs *GoScanner) scanModule() (*model.DependencyRoot, error) {
 	gs.log.Info("Scanning Go modules dependencies...")
 
 	// Get project info from go.mod
+	var warnings scanWarnings
 	projectName, projectVersion, err := gs.parseGoMod()
 	if err != nil {
-		gs.log.Warnf("Failed to parse go.mod: %v", err)
+		warnings.add(gs.log, "Failed to parse go.mod: %v", err)
 		projectName = "unknown"
 		projectVersion = "unknown"
 	}
 
-	// Get dependencies using go list
-	dependencies, err := gs.getGoDependencies()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Go dependencies: %w", err)
+	// Vendored builds record the authoritative module list in vendor/modules.txt
+	var dependencies []model.Dependency
+	if _, statErr := os.Stat(gs.vendorModulesPath()); statErr == nil {
+		dependencies, err = gs.parseVendorModules()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vendor/modules.txt: %w", err)
+		}
+	} else {
+		// Get dependencies using go list, falling back to the requirements in go.mod
+		dependencies, err = gs.getGoDependencies()
+		if err != nil {
+			warnings.add(gs.log, "go list failed, using go.mod requirements: %v", err)
+			dependencies, err = gs.parseGoModRequires()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get Go dependencies: %w", err)
+			}
+		}
 	}
 
-	root := model.DependencyRoot{
+	root := &model.DependencyRoot{
 		ProjectName:    projectName,
 		ProjectVersion: projectVersion,
 		BuildTool:      "go",
 		Dependencies:   dependencies,
+		Warnings:       warnings,
 	}
 
-	return []model.DependencyRoot{root}, nil
+	return root, nil
 }
 
 // parseGoMod parses go.mod file to extract module name and version
 func (gs *GoScanner) parseGoMod() (string, string, error) {
 	goModPath := filepath.Join(gs.environment.GetDirectory(), "go.mod")
-	file, err := os.Open(goModPath)
+	file, err := utils.OpenTextFile(goModPath)
 	if err != nil {
 		return "", "", err
 	}
@@ -316,77 +592,221 @@ func (gs *GoScanner) parseGoMod() (string, string, error) {
 	return moduleName, goVersion, scanner.Err()
 }
 
-// getGoDependencies gets Go module dependencies using go list command
-func (gs *GoScanner) getGoDependencies() ([]model.Dependency, error) {
-	// Use go list -m -json all to get all dependencies
-	cmd := exec.Command("go", "list", "-m", "-json", "all")
-	cmd.Dir = gs.environment.GetDirectory()
+// parseGoModRequires reads the require directives of go.mod, marking modules with an
+// "// indirect" comment as indirect
+func (gs *GoScanner) parseGoModRequires() ([]model.Dependency, error) {
+	file, err := utils.OpenTextFile(filepath.Join(gs.environment.GetDirectory(), "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var dependencies []model.Dependency
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		case !inBlock:
+			continue
+		}
+
+		requirement, comment, _ := strings.Cut(line, "//")
+		fields := strings.Fields(requirement)
+		if len(fields) != 2 {
+			continue
+		}
 
-	output, err := cmd.Output()
+		scope := "runtime"
+		if strings.TrimSpace(comment) == "indirect" {
+			scope = "indirect"
+		}
+		dependencies = append(dependencies, model.Dependency{
+			ID: &model.DependencyID{
+				Group:   "",
+				Name:    fields[0],
+				Version: fields[1],
+				Type:    "go",
+			},
+			Name:    fields[0],
+			Version: fields[1],
+			Type:    "go",
+			Scope:   scope,
+		})
+	}
+
+	return dependencies, scanner.Err()
+}
+
+// vendorModulesPath returns the path of the vendored module list
+func (gs *GoScanner) vendorModulesPath() string {
+	return filepath.Join(gs.environment.GetDirectory(), "vendor", "modules.txt")
+}
+
+// parseVendorModules reads the module list of a vendored build from vendor/modules.txt.
+// Modules marked "## explicit" are required directly by go.mod, all others are indirect.
+func (gs *GoScanner) parseVendorModules() ([]model.Dependency, error) {
+	file, err := utils.OpenTextFile(gs.vendorModulesPath())
 	if err != nil {
-		return nil, fmt.Errorf("failed to run go list: %w", err)
+		return nil, err
 	}
+	defer func() { _ = file.Close() }()
 
 	var dependencies []model.Dependency
-	lines := strings.Split(string(output), "\n")
+	scanner := bufio.NewScanner(file)
 
-	var jsonBuffer strings.Builder
-	for _, line := range lines {
-		jsonBuffer.WriteString(line)
-		if strings.TrimSpace(line) == "}" {
-			// Parse the complete JSON object
-			var moduleInfo struct {
-				Path     string `json:"Path"`
-				Version  string `json:"Version"`
-				Main     bool   `json:"Main"`
-				Indirect bool   `json:"Indirect"`
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// "## explicit" (optionally "## explicit; go 1.21") annotates the preceding module
+		if marker, ok := strings.CutPrefix(line, "## "); ok {
+			if len(dependencies) > 0 && strings.HasPrefix(marker, "explicit") {
+				dependencies[len(dependencies)-1].Scope = "runtime"
 			}
+			continue
+		}
+
+		// "# path version" or "# path version => replacement [version]"
+		header, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			continue // Package lines list the vendored packages of the module
+		}
+		module, replacement, replaced := strings.Cut(header, "=>")
+		fields := strings.Fields(module)
+		if len(fields) == 0 {
+			continue
+		}
 
-			if err := json.Unmarshal([]byte(jsonBuffer.String()), &moduleInfo); err == nil {
-				// Skip the main module
-				if !moduleInfo.Main && moduleInfo.Path != "" {
-					dependency := model.Dependency{
-						ID: &model.DependencyID{
-							Group:   "",
-							Name:    moduleInfo.Path,
-							Version: moduleInfo.Version,
-							Type:    "go",
-						},
-						Name:    moduleInfo.Path,
-						Version: moduleInfo.Version,
-						Type:    "go",
-						Scope:   "runtime",
-					}
-
-					if moduleInfo.Indirect {
-						dependency.Scope = "indirect"
-					}
-
-					dependencies = append(dependencies, dependency)
-				}
+		version := "unknown"
+		if len(fields) > 1 {
+			version = fields[1]
+		}
+		// A versioned replacement is the code actually built
+		if replaced {
+			if replacementFields := strings.Fields(replacement); len(replacementFields) > 1 {
+				version = replacementFields[1]
 			}
-			jsonBuffer.Reset()
 		}
+
+		dependencies = append(dependencies, model.Dependency{
+			ID: &model.DependencyID{
+				Group:   "",
+				Name:    fields[0],
+				Version: version,
+				Type:    "go",
+			},
+			Name:    fields[0],
+			Version: version,
+			Type:    "go",
+			Scope:   "indirect",
+		})
+	}
+
+	return dependencies, scanner.Err()
+}
+
+// getGoDependencies gets Go module dependencies using go list command
+func (gs *GoScanner) getGoDependencies() ([]model.Dependency, error) {
+	// Use go list -m -json all to get all dependencies
+	var dependencies []model.Dependency
+	err := runBuildToolCommand(gs.config, gs.goListCommand, func(stdout io.Reader) error {
+		var err error
+		dependencies, err = parseGoListModules(stdout)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run go list: %w", err)
 	}
 
 	return dependencies, nil
 }
 
+// goListCommand creates the go list command. --go-proxy and --go-flags are added to the
+// inherited environment, so GOPRIVATE, GONOSUMDB and the like still pass through.
+func (gs *GoScanner) goListCommand(ctx context.Context) *exec.Cmd {
+	cmd := buildToolCommand(ctx, gs.environment.GetDirectory(), "go", "list", "-m", "-json", "all")
+
+	var env []string
+	if gs.config.GoProxy != "" {
+		env = append(env, "GOPROXY="+gs.config.GoProxy)
+	}
+	if gs.config.GoFlags != "" {
+		env = append(env, "GOFLAGS="+gs.config.GoFlags)
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// parseGoListModules decodes the stream of module objects printed by go list -m -json,
+// skipping the main module
+func parseGoListModules(r io.Reader) ([]model.Dependency, error) {
+	var dependencies []model.Dependency
+	decoder := json.NewDecoder(r)
+	for {
+		var moduleInfo struct {
+			Path     string `json:"Path"`
+			Version  string `json:"Version"`
+			Main     bool   `json:"Main"`
+			Indirect bool   `json:"Indirect"`
+		}
+		if err := decoder.Decode(&moduleInfo); err == io.EOF {
+			return dependencies, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if moduleInfo.Main || moduleInfo.Path == "" {
+			continue
+		}
+
+		dependency := model.Dependency{
+			ID: &model.DependencyID{
+				Group:   "",
+				Name:    moduleInfo.Path,
+				Version: moduleInfo.Version,
+				Type:    "go",
+			},
+			Name:    moduleInfo.Path,
+			Version: moduleInfo.Version,
+			Type:    "go",
+			Scope:   "runtime",
+		}
+
+		if moduleInfo.Indirect {
+			dependency.Scope = "indirect"
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+}
+
 // parsePackageJson parses package.json file to extract project info and dependencies
 func (ns *NpmScanner) parsePackageJson() (string, string, []model.Dependency, error) {
 	packageJsonPath := filepath.Join(ns.environment.GetDirectory(), "package.json")
-	file, err := os.Open(packageJsonPath)
+	file, err := utils.OpenTextFile(packageJsonPath)
 	if err != nil {
 		return "", "", nil, err
 	}
 	defer func() { _ = file.Close() }()
 
 	var packageInfo struct {
-		Name         string            `json:"name"`
-		Version      string            `json:"version"`
-		Dependencies map[string]string `json:"dependencies"`
-		DevDependencies map[string]string `json:"devDependencies"`
-		PeerDependencies map[string]string `json:"peerDependencies"`
+		Name                 string            `json:"name"`
+		Version              string            `json:"version"`
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
 	}
 
 	decoder := json.NewDecoder(file)
@@ -457,13 +877,30 @@ func (ns *NpmScanner) parsePackageJson() (string, string, []model.Dependency, er
 		dependencies = append(dependencies, dependency)
 	}
 
+	// Parse optionalDependencies
+	for name, version := range packageInfo.OptionalDependencies {
+		dependency := model.Dependency{
+			ID: &model.DependencyID{
+				Group:   "",
+				Name:    name,
+				Version: version,
+				Type:    "npm",
+			},
+			Name:    name,
+			Version: version,
+			Type:    "npm",
+			Scope:   "optional",
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
 	return projectName, projectVersion, dependencies, nil
 }
 
 // parsePipfile parses Pipfile to extract project name and version
 func (ps *PipenvScanner) parsePipfile() (string, string, error) {
 	pipfilePath := filepath.Join(ps.environment.GetDirectory(), "Pipfile")
-	file, err := os.Open(pipfilePath)
+	file, err := utils.OpenTextFile(pipfilePath)
 	if err != nil {
 		return "", "", err
 	}
@@ -495,10 +932,12 @@ func (ps *PipenvScanner) parsePipfile() (string, string, error) {
 // getPipenvDependencies gets pipenv dependencies using pipenv commands
 func (ps *PipenvScanner) getPipenvDependencies() ([]model.Dependency, error) {
 	// Use pipenv run pip freeze to get installed packages
-	cmd := exec.Command("pipenv", "run", "pip", "freeze")
-	cmd.Dir = ps.environment.GetDirectory()
-
-	output, err := cmd.Output()
+	ctx, cancel := context.WithTimeout(context.Background(), ps.config.GetBuildToolTimeout())
+	defer cancel()
+	output, err := buildToolCommand(ctx, ps.environment.GetDirectory(), "pipenv", "run", "pip", "freeze").Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("pipenv timed out after %s", ps.config.GetBuildToolTimeout())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to run pipenv run pip freeze: %w", err)
 	}
@@ -552,11 +991,28 @@ func (ps *PipenvScanner) extractQuotedValue(expr string) string {
 	return ""
 }
 
+// gradleBuild is the project information and dependencies declared in a Gradle build file
+type gradleBuild struct {
+	Name         string
+	Version      string
+	Group        string // Empty when the build file sets no group
+	Dependencies []model.Dependency
+}
+
 // parseBuildGradle parses build.gradle file to extract project info and dependencies
 func (gs *GradleScanner) parseBuildGradle() (string, string, []model.Dependency, error) {
+	build, err := gs.parseBuildGradleIn(gs.environment.GetDirectory())
+	if err != nil {
+		return "", "", nil, err
+	}
+	return build.Name, build.Version, build.Dependencies, nil
+}
+
+// parseBuildGradleIn parses the build.gradle or build.gradle.kts file in dir
+func (gs *GradleScanner) parseBuildGradleIn(dir string) (*gradleBuild, error) {
 	// Try build.gradle first, then build.gradle.kts
-	buildGradlePath := filepath.Join(gs.environment.GetDirectory(), "build.gradle")
-	buildGradleKtsPath := filepath.Join(gs.environment.GetDirectory(), "build.gradle.kts")
+	buildGradlePath := filepath.Join(dir, "build.gradle")
+	buildGradleKtsPath := filepath.Join(dir, "build.gradle.kts")
 
 	var filePath string
 	if _, err := os.Stat(buildGradlePath); err == nil {
@@ -564,22 +1020,38 @@ func (gs *GradleScanner) parseBuildGradle() (string, string, []model.Dependency,
 	} else if _, err := os.Stat(buildGradleKtsPath); err == nil {
 		filePath = buildGradleKtsPath
 	} else {
-		return "", "", nil, fmt.Errorf("no build.gradle or build.gradle.kts found")
+		return nil, fmt.Errorf("no build.gradle or build.gradle.kts found")
 	}
 
-	file, err := os.Open(filePath)
+	file, err := utils.OpenTextFile(filePath)
 	if err != nil {
-		return "", "", nil, err
+		return nil, err
 	}
 	defer func() { _ = file.Close() }()
 
-	var projectName, projectVersion string
+	var projectName, projectVersion, projectGroup string
 	var dependencies []model.Dependency
+	var pluginsDepth int // Brace depth within the plugins block, 0 outside of it
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
+		// The plugins block applies build plugins, which are reported as build dependencies
+		if pluginsDepth > 0 || gradlePluginsBlockPattern.MatchString(line) {
+			declaration := line
+			if pluginsDepth == 0 {
+				declaration = line[strings.Index(line, "{")+1:]
+			}
+			pluginsDepth += strings.Count(line, "{") - strings.Count(line, "}")
+			declaration = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(declaration), "}"))
+			if dep := gs.catalog.pluginDependency(declaration); dep != nil {
+				dependencies = append(dependencies, *dep)
+			}
+			pluginsDepth = max(pluginsDepth, 0)
+			continue
+		}
+
 		// Parse project name
 		if strings.Contains(line, "rootProject.name") || strings.Contains(line, "name =") {
 			if name := gs.extractGradleValue(line, "name"); name != "" {
@@ -587,6 +1059,13 @@ func (gs *GradleScanner) parseBuildGradle() (string, string, []model.Dependency,
 			}
 		}
 
+		// Parse project group
+		if strings.HasPrefix(line, "group") {
+			if group := gs.extractGradleValue(line, "group"); group != "" {
+				projectGroup = group
+			}
+		}
+
 		// Parse project version
 		if strings.Contains(line, "version") && !strings.Contains(line, "dependencies") {
 			if version := gs.extractGradleValue(line, "version"); version != "" {
@@ -594,11 +1073,15 @@ func (gs *GradleScanner) parseBuildGradle() (string, string, []model.Dependency,
 			}
 		}
 
-		// Parse dependencies
+		// Parse dependencies, given as coordinates or as version catalog aliases
+		configuration := gradleConfiguration(line)
 		if strings.Contains(line, "implementation") || strings.Contains(line, "compile") ||
-		   strings.Contains(line, "api") || strings.Contains(line, "testImplementation") {
+			strings.Contains(line, "api") || strings.Contains(line, "testImplementation") ||
+			isGradleDependencyConfiguration(configuration) {
 			if dep := gs.parseGradleDependency(line); dep != nil {
 				dependencies = append(dependencies, *dep)
+			} else {
+				dependencies = append(dependencies, gs.catalog.catalogDependencies(line, gradleDependencyScope(configuration))...)
 			}
 		}
 	}
@@ -610,7 +1093,11 @@ func (gs *GradleScanner) parseBuildGradle() (string, string, []model.Dependency,
 		projectVersion = "unknown"
 	}
 
-	return projectName, projectVersion, dependencies, scanner.Err()
+	build := &gradleBuild{Name: projectName, Version: projectVersion, Group: projectGroup, Dependencies: dependencies}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return build, nil
 }
 
 // extractGradleValue extracts a value from a gradle line
@@ -641,30 +1128,66 @@ func (gs *GradleScanner) parseGradleDependency(line string) *model.Dependency {
 	matches := re.FindStringSubmatch(line)
 
 	if len(matches) >= 4 {
-		group := matches[1]
-		artifact := matches[2]
-		version := matches[3]
+		dep := newGradleDependency(matches[1], matches[2], matches[3], gradleDependencyScope(gradleConfiguration(line)))
+		return &dep
+	}
 
-		scope := "runtime"
-		if strings.Contains(line, "testImplementation") || strings.Contains(line, "testCompile") {
-			scope = "test"
-		} else if strings.Contains(line, "compileOnly") {
-			scope = "provided"
+	return nil
+}
+
+// gradleConfiguration returns the configuration a dependency line declares into, e.g.
+// implementation or kapt, or "" when the line does not start with one
+func gradleConfiguration(line string) string {
+	if matches := gradleConfigurationPattern.FindStringSubmatch(line); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// isGradleDependencyConfiguration reports whether configuration declares dependencies,
+// including variant-specific ones such as debugImplementation or androidTestImplementation
+func isGradleDependencyConfiguration(configuration string) bool {
+	lower := strings.ToLower(configuration)
+	for _, suffix := range []string{"implementation", "api", "compileonly", "runtimeonly", "annotationprocessor", "classpath"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
 		}
+	}
+	return strings.HasPrefix(lower, "kapt") || strings.HasPrefix(lower, "ksp")
+}
 
-		return &model.Dependency{
-			ID: &model.DependencyID{
-				Group:   group,
-				Name:    artifact,
-				Version: version,
-				Type:    "gradle",
-			},
+// gradleDependencyScope returns the scope of a dependency declared in configuration.
+// Annotation processors run by kapt, ksp and annotationProcessor only take part in the build.
+func gradleDependencyScope(configuration string) string {
+	lower := strings.ToLower(configuration)
+	switch {
+	case strings.HasPrefix(lower, "kapt") || strings.HasPrefix(lower, "ksp") || strings.HasSuffix(lower, "annotationprocessor"):
+		return gradleProcessorScope
+	case strings.Contains(lower, "test"):
+		return "test"
+	case strings.HasSuffix(lower, "compileonly"):
+		return "provided"
+	case lower == "classpath":
+		return gradlePluginScope // Buildscript classpath holding the plugins applied with apply plugin
+	}
+	return "runtime"
+}
+
+// newGradleDependency creates a Gradle dependency, with an unknown version when none is declared
+func newGradleDependency(group, artifact, version, scope string) model.Dependency {
+	if version == "" {
+		version = "unknown"
+	}
+	return model.Dependency{
+		ID: &model.DependencyID{
+			Group:   group,
 			Name:    artifact,
 			Version: version,
 			Type:    "gradle",
-			Scope:   scope,
-		}
+		},
+		Name:    artifact,
+		Version: version,
+		Type:    "gradle",
+		Scope:   scope,
 	}
-
-	return nil
 }
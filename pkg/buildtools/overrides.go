@@ -0,0 +1,126 @@
+package buildtools
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+	"github.com/craftslab/cleansource-sca-cli/pkg/resolver"
+)
+
+// npmOverride is a version forced by npm "overrides" or yarn "resolutions" in package.json
+type npmOverride struct {
+	name     string
+	selector string // Declared range the override is limited to, e.g. "^2" for "foo@^2"; empty for any
+	version  string // Forced version, or "$name" for the version of a direct dependency
+}
+
+// parseNpmOverrides reads the npm overrides and yarn resolutions of a package.json. Entries that
+// only apply below another package are skipped, since the npm scanner reports direct dependencies.
+func parseNpmOverrides(data []byte) ([]npmOverride, error) {
+	var manifest struct {
+		Overrides   map[string]json.RawMessage `json:"overrides"`
+		Resolutions map[string]string          `json:"resolutions"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var overrides []npmOverride
+	for key, raw := range manifest.Overrides {
+		var version string
+		if err := json.Unmarshal(raw, &version); err != nil {
+			// A nested override sets the package itself under "." and its dependencies by name
+			var nested map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &nested); err != nil || json.Unmarshal(nested["."], &version) != nil {
+				continue
+			}
+		}
+		name, selector := splitYarnSpec(key)
+		overrides = append(overrides, npmOverride{name: name, selector: selector, version: version})
+	}
+
+	for key, version := range manifest.Resolutions {
+		// "**/foo" applies everywhere, while "parent/foo" only applies below parent
+		name := strings.TrimPrefix(key, "**/")
+		segments := 1
+		if strings.HasPrefix(name, "@") {
+			segments = 2
+		}
+		if strings.Count(name, "/") != segments-1 {
+			continue
+		}
+		overrides = append(overrides, npmOverride{name: name, version: version})
+	}
+
+	// Map order is random, so sort to apply conflicting entries the same way every run
+	slices.SortFunc(overrides, func(a, b npmOverride) int {
+		return strings.Compare(a.name+"@"+a.selector, b.name+"@"+b.selector)
+	})
+	return overrides, nil
+}
+
+// readOverrides returns the overrides and resolutions declared in package.json
+func (ns *NpmScanner) readOverrides() []npmOverride {
+	data, err := utils.ReadTextFile(filepath.Join(ns.environment.GetDirectory(), "package.json"))
+	if err != nil {
+		return nil
+	}
+	overrides, err := parseNpmOverrides(data)
+	if err != nil {
+		return nil
+	}
+	return overrides
+}
+
+// selects reports whether an override applies to a dependency declared with a range and
+// currently at a version. Like npm, a scoped override applies when the declared range intersects
+// its selector or the locked version satisfies it.
+func (o npmOverride) selects(declared, current string) bool {
+	if o.selector == "" || o.selector == declared {
+		return true
+	}
+	if !isConstraint(current) && resolver.SatisfiesNpmRange(current, o.selector) {
+		return true
+	}
+	return declared != "" && resolver.NpmRangesIntersect(declared, o.selector)
+}
+
+// applyOverrides forces the versions set by overrides and resolutions, which take precedence over
+// both the declared ranges and the lockfile. An exact override version always applies, while a
+// range only replaces a locked version outside it. The declared range selects scoped overrides,
+// so it is read from ranges, keyed by dependency index.
+func (ns *NpmScanner) applyOverrides(dependencies []model.Dependency, ranges []string, overrides []npmOverride) {
+	// "$name" references resolve to the locked version of that direct dependency, before overrides
+	direct := make(map[string]string)
+	for _, dep := range dependencies {
+		if _, ok := direct[dep.Name]; !ok {
+			direct[dep.Name] = dep.Version
+		}
+	}
+
+	for i := range dependencies {
+		dep := &dependencies[i]
+		for _, override := range overrides {
+			if override.name != dep.Name || !override.selects(ranges[i], dep.Version) {
+				continue
+			}
+			version := override.version
+			if reference, ok := strings.CutPrefix(version, "$"); ok {
+				if version, ok = direct[reference]; !ok {
+					continue
+				}
+			}
+			if isConstraint(version) && !isConstraint(dep.Version) && resolver.SatisfiesNpmRange(dep.Version, version) {
+				continue
+			}
+			dep.Version = version
+			if dep.ID != nil {
+				dep.ID.Version = version
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package buildtools
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+)
+
+// parseWorkers returns how many manifests or scanners may be processed at once: the configured
+// thread number, capped at the CPU count since parsing is CPU bound and build tools are heavy
+func parseWorkers(cfg *config.ScanConfig) int {
+	return max(min(cfg.GetThreadNum(), runtime.NumCPU()), 1)
+}
+
+// parseConcurrently calls parse for every item with at most workers calls running at once,
+// returning the results in item order so the output does not depend on scheduling
+func parseConcurrently[T, R any](items []T, workers int, parse func(T) R) []R {
+	results := make([]R, len(items))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(workers, len(items)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] = parse(items[index])
+			}
+		}()
+	}
+
+	for index := range items {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
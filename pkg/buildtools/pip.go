@@ -2,24 +2,27 @@ package buildtools
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
-	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
 // PipScanner handles Python pip project scanning
 type PipScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
-	log         *logrus.Logger
+	log         *logrus.Entry
 	pipPath     string
 	pythonPath  string
 }
@@ -29,28 +32,39 @@ func NewPipScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *PipScanne
 	return &PipScanner{
 		environment: env,
 		config:      cfg,
-		log:         logger.GetLogger(),
+		log:         scannerLogger("pip", env),
 	}
 }
 
 // ExeFind finds the pip and python executables
 func (ps *PipScanner) ExeFind() error {
+	// uv.lock is parsed statically
+	if _, err := os.Stat(filepath.Join(ps.environment.GetDirectory(), "uv.lock")); err == nil {
+		return nil
+	}
+
+	// An active virtualenv is read without invoking pip
+	err := ps.findPip()
+	if err != nil && ps.venvPath() != "" {
+		ps.log.Debugf("Using virtualenv %s instead of pip: %v", ps.venvPath(), err)
+		return nil
+	}
+	return err
+}
+
+// findPip finds the pip and python executables
+func (ps *PipScanner) findPip() error {
 	// Find Python executable
 	if ps.config.PipPath != "" {
 		// Extract python path from pip path if configured
 		ps.pythonPath = strings.Replace(ps.config.PipPath, "pip", "python", 1)
 	} else {
 		// Try to find python in PATH
-		pythonCandidates := []string{"python3", "python", "py"}
-		for _, candidate := range pythonCandidates {
-			if path, err := exec.LookPath(candidate); err == nil {
-				ps.pythonPath = path
-				break
-			}
-		}
-		if ps.pythonPath == "" {
+		path, found := findExecutable(pythonExecutables)
+		if !found {
 			return fmt.Errorf("python executable not found")
 		}
+		ps.pythonPath = path
 	}
 
 	// Find pip executable
@@ -63,18 +77,16 @@ func (ps *PipScanner) ExeFind() error {
 	}
 
 	// Try to find pip in PATH
-	pipCandidates := []string{"pip3", "pip"}
-	for _, candidate := range pipCandidates {
-		if path, err := exec.LookPath(candidate); err == nil {
-			ps.pipPath = path
-			ps.log.Debugf("Found pip in PATH: %s", ps.pipPath)
-			return nil
-		}
+	if path, found := findExecutable(pipExecutables); found {
+		ps.pipPath = path
+		ps.log.Debugf("Found pip in PATH: %s", ps.pipPath)
+		return nil
 	}
 
 	// Try using python -m pip
-	cmd := exec.Command(ps.pythonPath, "-m", "pip", "--version")
-	if err := cmd.Run(); err == nil {
+	ctx, cancel := context.WithTimeout(context.Background(), ps.config.GetBuildToolTimeout())
+	defer cancel()
+	if err := buildToolCommand(ctx, "", ps.pythonPath, "-m", "pip", "--version").Run(); err == nil {
 		ps.pipPath = ps.pythonPath
 		ps.log.Debug("Using python -m pip")
 		return nil
@@ -109,13 +121,31 @@ func (ps *PipScanner) FileFind() error {
 		return nil
 	}
 
-	return fmt.Errorf("no pip requirement files found (requirements.txt, setup.py, pyproject.toml)")
+	// Check for uv.lock
+	uvLockPath := filepath.Join(projectDir, "uv.lock")
+	if _, err := os.Stat(uvLockPath); err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("no pip requirement files found (requirements.txt, setup.py, pyproject.toml, uv.lock)")
 }
 
 // ScanExecute executes the pip dependency scan
 func (ps *PipScanner) ScanExecute() ([]model.DependencyRoot, error) {
 	ps.log.Info("Scanning pip dependencies...")
 
+	// uv.lock pins the complete resolution, so it takes precedence over requirements and constraints
+	var warnings scanWarnings
+	uvLockPath := filepath.Join(ps.environment.GetDirectory(), "uv.lock")
+	if _, err := os.Stat(uvLockPath); err == nil {
+		root, err := ps.scanUvLock(uvLockPath)
+		if err == nil {
+			ps.applyPyproject(&root)
+			return []model.DependencyRoot{root}, nil
+		}
+		warnings.add(ps.log, "Failed to parse uv.lock, falling back to requirements: %v", err)
+	}
+
 	var dependencies []model.Dependency
 	var projectName = "unknown"
 	var projectVersion = "unknown"
@@ -130,18 +160,30 @@ func (ps *PipScanner) ScanExecute() ([]model.DependencyRoot, error) {
 		reqDeps, err := ps.parseRequirementsFile(reqPath)
 		if err == nil {
 			dependencies = append(dependencies, reqDeps...)
+			if warning := ps.lockfileDrift(reqPath, reqDeps); warning != "" {
+				warnings.add(ps.log, "%s", warning)
+			}
 		} else {
-			ps.log.Warnf("Failed to parse requirements.txt: %v", err)
+			warnings.add(ps.log, "Failed to parse requirements.txt: %v", err)
 		}
 	}
 
-	// Try to get installed packages using pip list
+	// Try to get installed packages using pip list, or from the virtualenv when pip fails
 	installedDeps, err := ps.getInstalledPackages()
+	if venv := ps.venvPath(); err != nil && venv != "" {
+		ps.log.Infof("pip list failed, reading installed packages from virtualenv %s: %v", venv, err)
+		installedDeps, err = ps.getVenvPackages(venv)
+	}
 	if err == nil {
 		// Merge with requirements, preferring requirements versions
 		dependencies = ps.mergeDependencies(dependencies, installedDeps)
 	} else {
-		ps.log.Warnf("Failed to get installed packages: %v", err)
+		warnings.add(ps.log, "Failed to get installed packages, using declared requirements only: %v", err)
+	}
+
+	// Constraints pin versions of known packages but never add new ones
+	if constraints := ps.loadConstraints(reqPath); len(constraints) > 0 {
+		dependencies = ps.applyConstraints(dependencies, constraints)
 	}
 
 	// Try to get project info from setup.py
@@ -161,18 +203,102 @@ func (ps *PipScanner) ScanExecute() ([]model.DependencyRoot, error) {
 		ProjectVersion: projectVersion,
 		BuildTool:      "pip",
 		Dependencies:   dependencies,
+		Warnings:       warnings,
+		IndexURLs:      ps.findIndexURLs(reqPath),
 	}
+	ps.applyPyproject(&root)
 
 	return []model.DependencyRoot{root}, nil
 }
 
+// applyPyproject adds the build-time requirements declared in pyproject.toml and fills in
+// project info that no other file provided
+func (ps *PipScanner) applyPyproject(root *model.DependencyRoot) {
+	pyprojectPath := filepath.Join(ps.environment.GetDirectory(), "pyproject.toml")
+	if _, err := os.Stat(pyprojectPath); err != nil {
+		return
+	}
+
+	name, version, buildDeps, err := ps.parsePyproject(pyprojectPath)
+	if err != nil {
+		warning := fmt.Sprintf("Failed to parse pyproject.toml: %v", err)
+		ps.log.Warn(warning)
+		root.Warnings = append(root.Warnings, warning)
+		return
+	}
+	if root.ProjectName == "unknown" && name != "" {
+		root.ProjectName = name
+	}
+	if root.ProjectVersion == "unknown" && version != "" {
+		root.ProjectVersion = version
+	}
+	root.Dependencies = append(root.Dependencies, buildDeps...)
+}
+
+// parsePyproject reads the [project] name and version and the [build-system] requires of a
+// pyproject.toml file. Build requirements are returned with the "build" scope.
+func (ps *PipScanner) parsePyproject(pyprojectPath string) (string, string, []model.Dependency, error) {
+	tables, err := parseTomlFile(pyprojectPath)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	var name, version string
+	var buildDeps []model.Dependency
+	for _, table := range tables {
+		switch table.Name {
+		case "project":
+			name = tomlString(table.Values["name"])
+			version = tomlString(table.Values["version"])
+		case "build-system":
+			for _, raw := range tomlArray(table.Values["requires"]) {
+				// Drop environment markers such as "; python_version < '3.11'"
+				requirement, _, _ := strings.Cut(tomlString(raw), ";")
+				if requirement = strings.TrimSpace(requirement); requirement == "" {
+					continue
+				}
+				dep, err := ps.parseRequirementLine(requirement)
+				if err != nil {
+					continue
+				}
+				dep.Scope = "build"
+				buildDeps = append(buildDeps, dep)
+			}
+		}
+	}
+
+	return name, version, buildDeps, nil
+}
+
+// scanUvLock builds the dependency tree recorded in uv.lock
+func (ps *PipScanner) scanUvLock(uvLockPath string) (model.DependencyRoot, error) {
+	lock, err := parseUvLock(uvLockPath)
+	if err != nil {
+		return model.DependencyRoot{}, err
+	}
+
+	root := model.DependencyRoot{
+		ProjectName:    "unknown",
+		ProjectVersion: "unknown",
+		BuildTool:      "pip",
+		Dependencies:   lock.dependencies(),
+	}
+	if lock.root != nil {
+		root.ProjectName = lock.root.name
+		if lock.root.version != "" {
+			root.ProjectVersion = lock.root.version
+		}
+	}
+	return root, nil
+}
+
 // parseRequirementsFile parses a requirements.txt file
 func (ps *PipScanner) parseRequirementsFile(reqPath string) ([]model.Dependency, error) {
-	file, err := os.Open(reqPath)
+	file, err := utils.OpenTextFile(reqPath)
 	if err != nil {
 		return nil, err
 	}
-	defer func(file *os.File) {
+	defer func(file io.ReadCloser) {
 		_ = file.Close()
 	}(file)
 
@@ -180,12 +306,21 @@ func (ps *PipScanner) parseRequirementsFile(reqPath string) ([]model.Dependency,
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
+		line := stripRequirementComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// Editable installs of local packages bring in that package's own requirements
+		if target, ok := editableTarget(line); ok {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(reqPath), target)
+			}
+			dependencies = append(dependencies, ps.editableDependencies(target)...)
 			continue
 		}
 
-		// Skip lines starting with -r, -e, --find-links, etc.
+		// Skip other options such as -r, -c and --find-links
 		if strings.HasPrefix(line, "-") {
 			continue
 		}
@@ -207,6 +342,7 @@ func (ps *PipScanner) parseRequirementLine(line string) (model.Dependency, error
 	// package~=1.0
 	// package
 
+	line = stripRequirementComment(line)
 	var name, version string
 
 	// Split on version specifiers
@@ -243,26 +379,242 @@ func (ps *PipScanner) parseRequirementLine(line string) (model.Dependency, error
 	}, nil
 }
 
+// stripRequirementComment removes the comment of a requirements file line and surrounding
+// whitespace. As in pip, a comment starts with a # at the beginning of the line or after
+// whitespace, so fragments such as git+https://host/repo.git#egg=name are kept.
+func stripRequirementComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// lockfileDrift compares a pip-tools requirements.in with the requirements file compiled from it,
+// reporting packages declared in the .in file that the compiled file does not pin
+func (ps *PipScanner) lockfileDrift(reqPath string, compiled []model.Dependency) string {
+	inPath := strings.TrimSuffix(reqPath, filepath.Ext(reqPath)) + ".in"
+	if inPath == reqPath {
+		return ""
+	}
+	declared, err := ps.parseRequirementsFile(inPath)
+	if err != nil {
+		return ""
+	}
+
+	pinned := make(map[string]bool)
+	for _, dep := range compiled {
+		pinned[normalizePipName(dep.Name)] = true
+	}
+
+	var missing []string
+	for _, dep := range declared {
+		if !pinned[normalizePipName(dep.Name)] {
+			missing = append(missing, dep.Name)
+		}
+	}
+	return staleLockfileWarning(filepath.Base(reqPath), filepath.Base(inPath), missing, nil)
+}
+
+// findConstraintFiles returns constraint files referenced by -c/--constraint lines in a requirements file
+func (ps *PipScanner) findConstraintFiles(reqPath string) []string {
+	file, err := utils.OpenTextFile(reqPath)
+	if err != nil {
+		return nil
+	}
+	defer func(file io.ReadCloser) {
+		_ = file.Close()
+	}(file)
+
+	var constraintFiles []string
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := stripRequirementComment(scanner.Text())
+
+		var path string
+		switch {
+		case strings.HasPrefix(line, "--constraint="):
+			path = strings.TrimPrefix(line, "--constraint=")
+		case strings.HasPrefix(line, "--constraint "):
+			path = strings.TrimPrefix(line, "--constraint ")
+		case strings.HasPrefix(line, "-c"):
+			path = strings.TrimPrefix(line, "-c")
+		default:
+			continue
+		}
+
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(reqPath), path)
+		}
+		constraintFiles = append(constraintFiles, path)
+	}
+
+	return constraintFiles
+}
+
+// findIndexURLs returns the package indexes set by -i/--index-url and --extra-index-url lines in
+// a requirements file, with any embedded credentials redacted
+func (ps *PipScanner) findIndexURLs(reqPath string) []string {
+	file, err := utils.OpenTextFile(reqPath)
+	if err != nil {
+		return nil
+	}
+	defer func(file io.ReadCloser) {
+		_ = file.Close()
+	}(file)
+
+	var indexURLs []string
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := stripRequirementComment(scanner.Text())
+
+		var option, value string
+		if name, rest, ok := strings.Cut(line, "="); ok && strings.HasPrefix(name, "--") && !strings.ContainsAny(name, " \t") {
+			option, value = name, rest
+		} else if fields := strings.Fields(line); len(fields) >= 2 {
+			option, value = fields[0], fields[1]
+		} else {
+			continue
+		}
+
+		switch option {
+		case "-i", "--index-url", "--extra-index-url":
+		default:
+			continue
+		}
+
+		value = redactIndexURL(strings.TrimSpace(value))
+		if value != "" && !slices.Contains(indexURLs, value) {
+			indexURLs = append(indexURLs, value)
+		}
+	}
+
+	return indexURLs
+}
+
+// redactIndexURL masks the user info of a package index URL, which often carries a token
+func redactIndexURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// Drop a URL that cannot be parsed when it may hold credentials
+		if strings.Contains(rawURL, "@") {
+			return ""
+		}
+		return rawURL
+	}
+	if u.User == nil {
+		return rawURL
+	}
+	// url.User would escape the mask, so it is spliced in after the scheme instead
+	u.User = nil
+	return strings.Replace(u.String(), "://", "://***@", 1)
+}
+
+// loadConstraints collects pinned versions from the configured and referenced constraint files
+func (ps *PipScanner) loadConstraints(reqPath string) map[string]string {
+	var constraintFiles []string
+	if ps.config.PipConstraintsPath != "" {
+		constraintFiles = append(constraintFiles, ps.config.PipConstraintsPath)
+	}
+	constraintFiles = append(constraintFiles, ps.findConstraintFiles(reqPath)...)
+
+	constraints := make(map[string]string)
+	for _, constraintFile := range constraintFiles {
+		data, err := utils.ReadTextFile(constraintFile)
+		if err != nil {
+			ps.log.Warnf("Failed to parse constraints file %s: %v", constraintFile, err)
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if name, version, ok := constraintPin(line); ok {
+				constraints[normalizePipName(name)] = version
+			}
+		}
+	}
+
+	return constraints
+}
+
+// constraintPin returns the package and version of a constraints file line pinning an exact
+// version with ==. Ranges such as urllib3<2 or requests>=2.0 name no single version, so they
+// are ignored, as are wildcards, options and lines without a specifier.
+func constraintPin(line string) (string, string, bool) {
+	line, _, _ = strings.Cut(stripRequirementComment(line), ";") // Environment markers
+	name, version, found := strings.Cut(line, "==")
+	version = strings.TrimSpace(version)
+	if !found || strings.HasPrefix(version, "=") || strings.ContainsAny(version, ",<>!~*= \t") ||
+		strings.ContainsAny(name, "<>!~=") || strings.HasPrefix(name, "-") {
+		return "", "", false
+	}
+	if idx := strings.Index(name, "["); idx != -1 {
+		name = name[:idx] // Extras
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || version == "" {
+		return "", "", false
+	}
+	return name, version, true
+}
+
+// applyConstraints overrides the versions of dependencies that have a matching constraint
+func (ps *PipScanner) applyConstraints(dependencies []model.Dependency, constraints map[string]string) []model.Dependency {
+	for i, dep := range dependencies {
+		version, ok := constraints[normalizePipName(dep.Name)]
+		if !ok {
+			continue
+		}
+		dependencies[i].Version = version
+		if dependencies[i].ID != nil {
+			dependencies[i].ID.Version = version
+		}
+	}
+	return dependencies
+}
+
+// normalizePipName normalizes a Python package name for comparison (PEP 503)
+func normalizePipName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
 // getInstalledPackages gets installed packages using pip list
 func (ps *PipScanner) getInstalledPackages() ([]model.Dependency, error) {
-	var cmd *exec.Cmd
+	if ps.pipPath == "" {
+		return nil, fmt.Errorf("pip executable not found")
+	}
+
+	name, args := ps.pipPath, []string{"list", "--format=freeze"}
 	if ps.pipPath == ps.pythonPath {
-		cmd = exec.Command(ps.pythonPath, "-m", "pip", "list", "--format=freeze")
-	} else {
-		cmd = exec.Command(ps.pipPath, "list", "--format=freeze")
+		args = append([]string{"-m", "pip"}, args...)
 	}
 
-	cmd.Dir = ps.environment.GetDirectory()
-	output, err := cmd.Output()
+	var dependencies []model.Dependency
+	err := runBuildTool(ps.config, ps.environment.GetDirectory(), func(stdout io.Reader) error {
+		var err error
+		dependencies, err = parsePipFreeze(stdout)
+		return err
+	}, name, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run pip list: %w", err)
 	}
 
-	var dependencies []model.Dependency
-	lines := strings.Split(string(output), "\n")
+	return dependencies, nil
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+// parsePipFreeze reads name==version lines as printed by pip list --format=freeze
+func parsePipFreeze(r io.Reader) ([]model.Dependency, error) {
+	var dependencies []model.Dependency
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
 			continue
 		}
@@ -289,7 +641,7 @@ func (ps *PipScanner) getInstalledPackages() ([]model.Dependency, error) {
 		})
 	}
 
-	return dependencies, nil
+	return dependencies, scanner.Err()
 }
 
 // mergeDependencies merges requirements and installed packages
@@ -316,11 +668,11 @@ func (ps *PipScanner) mergeDependencies(requirements, installed []model.Dependen
 
 // parseSetupPy tries to extract project name and version from setup.py
 func (ps *PipScanner) parseSetupPy(setupPath string) (string, string) {
-	file, err := os.Open(setupPath)
+	file, err := utils.OpenTextFile(setupPath)
 	if err != nil {
 		return "", ""
 	}
-	defer func(file *os.File) {
+	defer func(file io.ReadCloser) {
 		_ = file.Close()
 	}(file)
 
@@ -0,0 +1,445 @@
+package buildtools
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// DependencyProcessor transforms dependency roots after all scanners have run
+type DependencyProcessor interface {
+	Process(roots []model.DependencyRoot) []model.DependencyRoot
+}
+
+// Canonical dependency scopes shared by all ecosystems
+const (
+	ScopeRuntime     = "runtime"
+	ScopeDevelopment = "development"
+	ScopeTest        = "test"
+	ScopeOptional    = "optional"
+	ScopeProvided    = "provided"
+)
+
+// commonScopes maps scope strings used across ecosystems to canonical scopes
+var commonScopes = map[string]string{
+	"runtime":     ScopeRuntime,
+	"compile":     ScopeRuntime,
+	"development": ScopeDevelopment,
+	"dev":         ScopeDevelopment,
+	"test":        ScopeTest,
+	"optional":    ScopeOptional,
+	"provided":    ScopeProvided,
+}
+
+// ecosystemScopes maps ecosystem-specific scopes to canonical scopes, keyed by build tool
+var ecosystemScopes = map[string]map[string]string{
+	"maven": {
+		"system": ScopeProvided,
+		"import": ScopeProvided,
+		"plugin": ScopeDevelopment, // Build plugins and their dependencies
+	},
+	"gradle": {
+		"implementation":      ScopeRuntime,
+		"api":                 ScopeRuntime,
+		"compileonly":         ScopeProvided,
+		"plugin":              ScopeDevelopment, // Build plugins and the buildscript classpath
+		"annotationprocessor": ScopeDevelopment,
+	},
+	"npm": {
+		"peer":    ScopeProvided,
+		"bundled": ScopeRuntime,
+	},
+	"go": {
+		"indirect": ScopeRuntime,
+	},
+	"pip": {
+		"build": ScopeDevelopment,
+		"local": ScopeRuntime, // Editable install of a local package
+	},
+	"pipenv": {
+		"develop": ScopeDevelopment,
+	},
+	"cargo": {
+		"build": ScopeDevelopment,
+	},
+	"dotnet": {
+		"transitive": ScopeRuntime,
+	},
+}
+
+// ScopeNormalizer maps ecosystem-specific scopes to the canonical scope set,
+// keeping the original value in RawScope
+type ScopeNormalizer struct{}
+
+// NewScopeNormalizer creates a scope normalizer
+func NewScopeNormalizer() *ScopeNormalizer {
+	return &ScopeNormalizer{}
+}
+
+// Process normalizes the scopes of all dependencies, including their children
+func (sn *ScopeNormalizer) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		sn.normalize(roots[i].BuildTool, roots[i].Dependencies)
+	}
+	return roots
+}
+
+// normalize recursively normalizes a dependency list in place
+func (sn *ScopeNormalizer) normalize(buildTool string, dependencies []model.Dependency) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if dep.Scope != "" && dep.RawScope == "" {
+			dep.RawScope = dep.Scope
+			dep.Scope = NormalizeScope(buildTool, dep.Scope)
+		}
+		sn.normalize(buildTool, dep.Children)
+	}
+}
+
+// NormalizeScope returns the canonical scope for a build tool specific scope.
+// Unknown scopes are returned lower-cased.
+func NormalizeScope(buildTool, scope string) string {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if canonical, ok := ecosystemScopes[buildTool][scope]; ok {
+		return canonical
+	}
+	if canonical, ok := commonScopes[scope]; ok {
+		return canonical
+	}
+	return scope
+}
+
+// ScopeFilter drops dependencies whose scope is in the excluded set
+type ScopeFilter struct {
+	excluded map[string]bool
+}
+
+// NewScopeFilter creates a scope filter for the given scopes (case-insensitive)
+func NewScopeFilter(scopes []string) *ScopeFilter {
+	excluded := make(map[string]bool)
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != "" {
+			excluded[scope] = true
+		}
+	}
+	return &ScopeFilter{excluded: excluded}
+}
+
+// Process removes excluded dependencies, including their children.
+// Both the canonical and the original scope are matched.
+func (sf *ScopeFilter) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	if len(sf.excluded) == 0 {
+		return roots
+	}
+
+	for i := range roots {
+		roots[i].Dependencies = sf.filter(roots[i].Dependencies)
+	}
+	return roots
+}
+
+// filter recursively filters a dependency list
+func (sf *ScopeFilter) filter(dependencies []model.Dependency) []model.Dependency {
+	var result []model.Dependency
+	for _, dep := range dependencies {
+		if sf.excluded[strings.ToLower(dep.Scope)] || sf.excluded[strings.ToLower(dep.RawScope)] {
+			continue
+		}
+		dep.Children = sf.filter(dep.Children)
+		result = append(result, dep)
+	}
+	return result
+}
+
+// versionPrefixes lists the operators stripped from a version per build tool. Only operators
+// pinning a single version, or a minimum of it, are listed; ranges are never rewritten.
+var versionPrefixes = map[string][]string{
+	"npm":      {"^", "~", "=", "v"},
+	"composer": {"^", "~", "=", "v"},
+	"cargo":    {"^", "~", "="},
+	"pip":      {"===", "==", "~="},
+	"pipenv":   {"===", "==", "~="},
+	"go":       {"v"},
+}
+
+var (
+	// exactVersionPattern matches a single version such as 1.2.3, 1.0rc1 or 2.0.0-beta.1+build
+	exactVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*([-+][0-9A-Za-z.+-]+|\.?[A-Za-z][0-9A-Za-z.+-]*)?$`)
+	// wildcardVersionPattern matches x-ranges such as 1.x or 2.*, which have no single version
+	wildcardVersionPattern = regexp.MustCompile(`(^|\.)[xX*](\.|$)`)
+)
+
+// VersionNormalizer strips range operators and "v" prefixes from versions that name a single
+// version, keeping the original value in RawVersion
+type VersionNormalizer struct{}
+
+// NewVersionNormalizer creates a version normalizer
+func NewVersionNormalizer() *VersionNormalizer {
+	return &VersionNormalizer{}
+}
+
+// Process normalizes the versions of all dependencies, including their children
+func (vn *VersionNormalizer) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		vn.normalize(roots[i].BuildTool, roots[i].Dependencies)
+	}
+	return roots
+}
+
+// normalize recursively normalizes a dependency list in place
+func (vn *VersionNormalizer) normalize(buildTool string, dependencies []model.Dependency) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if version := NormalizeVersion(buildTool, dep.Version); version != dep.Version && dep.RawVersion == "" {
+			dep.RawVersion = dep.Version
+			if dep.ID != nil && dep.ID.Version == dep.Version {
+				dep.ID.Version = version
+			}
+			dep.Version = version
+		}
+		vn.normalize(buildTool, dep.Children)
+	}
+}
+
+// NormalizeVersion returns the canonical form of a build tool specific version. Versions that
+// do not reduce to a single version, such as "1.x" or ">=1.0,<2.0", are returned unchanged.
+func NormalizeVersion(buildTool, version string) string {
+	trimmed := strings.TrimSpace(version)
+	for _, prefix := range versionPrefixes[buildTool] {
+		if rest, ok := strings.CutPrefix(trimmed, prefix); ok {
+			trimmed = strings.TrimSpace(rest)
+			break
+		}
+	}
+
+	if !exactVersionPattern.MatchString(trimmed) || wildcardVersionPattern.MatchString(trimmed) {
+		return version
+	}
+	return trimmed
+}
+
+// typeEcosystems maps the dependency types reported by scanners to package URL ecosystems.
+// Maven packaging types all belong to the Maven ecosystem, as do Gradle artifacts.
+var typeEcosystems = map[string]string{
+	"maven":        "maven",
+	"jar":          "maven",
+	"war":          "maven",
+	"ear":          "maven",
+	"pom":          "maven",
+	"aar":          "maven",
+	"test-jar":     "maven",
+	"maven-plugin": "maven",
+	"bundle":       "maven",
+	"gradle":       "maven",
+	"npm":          "npm",
+	"go":           "golang",
+	"golang":       "golang",
+	"pip":          "pypi",
+	"pipenv":       "pypi",
+	"pypi":         "pypi",
+	"cargo":        "cargo",
+	"composer":     "composer",
+	"nuget":        "nuget",
+	"conan":        "conan",
+	"vcpkg":        "generic", // No package URL type of its own
+	"cmake":        "generic", // find_package and FetchContent references
+	"system":       "generic", // Heuristic system libraries
+}
+
+// buildToolEcosystems maps build tools to the ecosystem of dependencies with a type not listed
+// in typeEcosystems, such as an uncommon Maven packaging
+var buildToolEcosystems = map[string]string{
+	"maven":  "maven",
+	"gradle": "maven",
+	"npm":    "npm",
+	"go":     "golang",
+	"pip":    "pypi",
+	"pipenv": "pypi",
+	"cargo":  "cargo",
+	"dotnet": "nuget",
+}
+
+// TypeNormalizer maps dependency types to package URL ecosystems, keeping the original value
+// in RawType
+type TypeNormalizer struct{}
+
+// NewTypeNormalizer creates a type normalizer
+func NewTypeNormalizer() *TypeNormalizer {
+	return &TypeNormalizer{}
+}
+
+// Process normalizes the types of all dependencies, including their children
+func (tn *TypeNormalizer) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		tn.normalize(roots[i].BuildTool, roots[i].Dependencies)
+	}
+	return roots
+}
+
+// normalize recursively normalizes a dependency list in place
+func (tn *TypeNormalizer) normalize(buildTool string, dependencies []model.Dependency) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if typ := NormalizeType(buildTool, dep.Type); typ != dep.Type && dep.RawType == "" {
+			dep.RawType = dep.Type
+			if dep.ID != nil && dep.ID.Type == dep.Type {
+				dep.ID.Type = typ
+			}
+			dep.Type = typ
+		}
+		tn.normalize(buildTool, dep.Children)
+	}
+}
+
+// NormalizeType returns the package URL ecosystem of a dependency type, falling back to the
+// ecosystem of the build tool. Types of unknown ecosystems are returned lower-cased.
+func NormalizeType(buildTool, typ string) string {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	if ecosystem, ok := typeEcosystems[typ]; ok {
+		return ecosystem
+	}
+	if ecosystem, ok := buildToolEcosystems[buildTool]; ok {
+		return ecosystem
+	}
+	return typ
+}
+
+// InternalMarker flags first-party dependencies whose group or name matches a configured pattern
+type InternalMarker struct {
+	patterns []*regexp.Regexp
+}
+
+// NewInternalMarker creates an internal marker; invalid patterns, rejected by config
+// validation, are ignored
+func NewInternalMarker(patterns []string) *InternalMarker {
+	im := &InternalMarker{}
+	for _, pattern := range patterns {
+		if re, err := config.CompileInternalPattern(pattern); err == nil {
+			im.patterns = append(im.patterns, re)
+		}
+	}
+	return im
+}
+
+// Process marks internal dependencies, including children
+func (im *InternalMarker) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		im.mark(roots[i].Dependencies)
+	}
+	return roots
+}
+
+// mark recursively flags a dependency list in place
+func (im *InternalMarker) mark(dependencies []model.Dependency) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		dep.Internal = im.IsInternal(*dep)
+		im.mark(dep.Children)
+	}
+}
+
+// IsInternal reports whether a pattern matches the dependency's group, its name, or both
+// joined as group:name
+func (im *InternalMarker) IsInternal(dep model.Dependency) bool {
+	group := dep.GroupID
+	if dep.ID != nil && dep.ID.Group != "" {
+		group = dep.ID.Group
+	}
+
+	candidates := []string{dep.Name}
+	if group != "" {
+		candidates = append(candidates, group, group+":"+dep.Name)
+	}
+	for _, re := range im.patterns {
+		for _, candidate := range candidates {
+			if re.MatchString(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DepthLimiter prunes transitive dependencies below a maximum depth
+type DepthLimiter struct {
+	maxDepth int
+}
+
+// NewDepthLimiter creates a depth limiter; depth 0 keeps direct dependencies only
+func NewDepthLimiter(maxDepth int) *DepthLimiter {
+	return &DepthLimiter{maxDepth: maxDepth}
+}
+
+// Process drops the children of dependencies at the maximum depth
+func (dl *DepthLimiter) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		dl.prune(roots[i].Dependencies, 0)
+	}
+	return roots
+}
+
+// prune recursively truncates a dependency list at the given depth in place
+func (dl *DepthLimiter) prune(dependencies []model.Dependency, depth int) {
+	for i := range dependencies {
+		if depth >= dl.maxDepth {
+			dependencies[i].Children = nil
+			continue
+		}
+		dl.prune(dependencies[i].Children, depth+1)
+	}
+}
+
+// DependencyFlattener replaces dependency trees with flat lists, for consumers that cannot
+// follow nested children
+type DependencyFlattener struct{}
+
+// NewDependencyFlattener creates a dependency flattener
+func NewDependencyFlattener() *DependencyFlattener {
+	return &DependencyFlattener{}
+}
+
+// Process flattens the dependencies of every root, recording their shallowest depth
+func (df *DependencyFlattener) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	return model.FlattenRoots(roots)
+}
+
+// UnresolvedFilter keeps only dependencies whose version is empty or "unknown", to show
+// which ones an incomplete scan failed to resolve
+type UnresolvedFilter struct{}
+
+// NewUnresolvedFilter creates an unresolved version filter
+func NewUnresolvedFilter() *UnresolvedFilter {
+	return &UnresolvedFilter{}
+}
+
+// Process removes resolved dependencies. Unresolved dependencies below a resolved one take its
+// place, so they stay in the output.
+func (uf *UnresolvedFilter) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	for i := range roots {
+		roots[i].Dependencies = uf.filter(roots[i].Dependencies)
+	}
+	return roots
+}
+
+// filter recursively filters a dependency list
+func (uf *UnresolvedFilter) filter(dependencies []model.Dependency) []model.Dependency {
+	var result []model.Dependency
+	for _, dep := range dependencies {
+		children := uf.filter(dep.Children)
+		if !IsUnresolvedVersion(dep.Version) {
+			result = append(result, children...)
+			continue
+		}
+		dep.Children = children
+		result = append(result, dep)
+	}
+	return result
+}
+
+// IsUnresolvedVersion reports whether a version is empty or "unknown"
+func IsUnresolvedVersion(version string) bool {
+	return model.IsUnknownVersion(version)
+}
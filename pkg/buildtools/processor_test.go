@@ -0,0 +1,438 @@
+package buildtools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestScopeFilter_Process(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{
+			ProjectName: "test-project",
+			BuildTool:   "npm",
+			Dependencies: []model.Dependency{
+				{Name: "express", Version: "^4.18.2", Type: "npm", Scope: "runtime"},
+				{Name: "react", Version: "^18.2.0", Type: "npm", Scope: "peer"},
+				{Name: "fsevents", Version: "^2.3.2", Type: "npm", Scope: "optional"},
+			},
+		},
+	}
+
+	filter := NewScopeFilter([]string{"peer", " Optional "})
+	result := filter.Process(roots)
+
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 root, got %d", len(result))
+	}
+	deps := result[0].Dependencies
+	if len(deps) != 1 {
+		t.Fatalf("Expected 1 dependency after filtering, got %d", len(deps))
+	}
+	if deps[0].Name != "express" {
+		t.Errorf("Expected runtime dependency 'express' to remain, got %s", deps[0].Name)
+	}
+}
+
+func TestScopeFilter_Process_Children(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{
+			Dependencies: []model.Dependency{
+				{
+					Name:  "parent",
+					Scope: "runtime",
+					Children: []model.Dependency{
+						{Name: "child-peer", Scope: "peer"},
+						{Name: "child-runtime", Scope: "runtime"},
+					},
+				},
+			},
+		},
+	}
+
+	result := NewScopeFilter([]string{"peer"}).Process(roots)
+
+	children := result[0].Dependencies[0].Children
+	if len(children) != 1 || children[0].Name != "child-runtime" {
+		t.Errorf("Expected only child-runtime to remain, got %v", children)
+	}
+}
+
+func TestScopeFilter_Process_NoExclusions(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{Dependencies: []model.Dependency{{Name: "react", Scope: "peer"}}},
+	}
+
+	result := NewScopeFilter(nil).Process(roots)
+
+	if len(result[0].Dependencies) != 1 {
+		t.Errorf("Expected peer dependency to be kept by default, got %d dependencies", len(result[0].Dependencies))
+	}
+}
+
+func TestBuildScanner_ScanDependencies_ExcludeScope(t *testing.T) {
+	tempDir := t.TempDir()
+
+	packageJsonContent := `{
+	"name": "test-npm-project",
+	"version": "1.0.0",
+	"dependencies": {
+		"express": "^4.18.2"
+	},
+	"peerDependencies": {
+		"react": "^18.2.0"
+	},
+	"optionalDependencies": {
+		"fsevents": "^2.3.2"
+	}
+}`
+	err := os.WriteFile(filepath.Join(tempDir, "package.json"), []byte(packageJsonContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create package.json: %v", err)
+	}
+
+	env := NewScannableEnvironment(tempDir, "")
+	cfg := &config.ScanConfig{ExcludeScopes: []string{"peer", "optional"}}
+	scanner := NewBuildScanner(env, cfg)
+
+	if err := scanner.scanners[0].ExeFind(); err != nil {
+		t.Skipf("npm executable not available: %v", err)
+	}
+
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 1 {
+		t.Fatalf("Expected 1 dependency root, got %d", len(roots))
+	}
+
+	for _, dep := range roots[0].Dependencies {
+		if dep.Scope == "peer" || dep.Scope == "optional" {
+			t.Errorf("Expected %s dependency %s to be excluded", dep.Scope, dep.Name)
+		}
+	}
+	if len(roots[0].Dependencies) != 1 || roots[0].Dependencies[0].Name != "express" {
+		t.Errorf("Expected only runtime dependency 'express' to remain, got %v", roots[0].Dependencies)
+	}
+}
+
+func TestNormalizeScope(t *testing.T) {
+	tests := []struct {
+		buildTool string
+		scope     string
+		expected  string
+	}{
+		{"maven", "compile", ScopeRuntime},
+		{"maven", "runtime", ScopeRuntime},
+		{"maven", "test", ScopeTest},
+		{"maven", "provided", ScopeProvided},
+		{"maven", "system", ScopeProvided},
+		{"maven", "import", ScopeProvided},
+		{"gradle", "runtime", ScopeRuntime},
+		{"gradle", "compileOnly", ScopeProvided},
+		{"gradle", "test", ScopeTest},
+		{"gradle", "annotationProcessor", ScopeDevelopment},
+		{"npm", "runtime", ScopeRuntime},
+		{"npm", "development", ScopeDevelopment},
+		{"npm", "peer", ScopeProvided},
+		{"npm", "optional", ScopeOptional},
+		{"npm", "bundled", ScopeRuntime},
+		{"go", "runtime", ScopeRuntime},
+		{"go", "indirect", ScopeRuntime},
+		{"pip", "runtime", ScopeRuntime},
+		{"pip", "build", ScopeDevelopment},
+		{"pipenv", "develop", ScopeDevelopment},
+		{"cargo", "development", ScopeDevelopment},
+		{"cargo", "build", ScopeDevelopment},
+		{"composer", "development", ScopeDevelopment},
+		{"unknown", "Compile", ScopeRuntime},
+		{"unknown", "custom", "custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.buildTool+"/"+tt.scope, func(t *testing.T) {
+			if got := NormalizeScope(tt.buildTool, tt.scope); got != tt.expected {
+				t.Errorf("NormalizeScope(%s, %s) = %s, want %s", tt.buildTool, tt.scope, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScopeNormalizer_Process(t *testing.T) {
+	roots := []model.DependencyRoot{
+		{
+			BuildTool: "go",
+			Dependencies: []model.Dependency{
+				{
+					Name:     "github.com/gin-gonic/gin",
+					Scope:    "runtime",
+					Children: []model.Dependency{{Name: "golang.org/x/net", Scope: "indirect"}},
+				},
+				{Name: "no-scope"},
+			},
+		},
+	}
+
+	result := NewScopeNormalizer().Process(roots)
+
+	child := result[0].Dependencies[0].Children[0]
+	if child.Scope != ScopeRuntime || child.RawScope != "indirect" {
+		t.Errorf("Expected indirect to normalize to runtime with raw scope kept, got %s (%s)", child.Scope, child.RawScope)
+	}
+	if dep := result[0].Dependencies[1]; dep.Scope != "" || dep.RawScope != "" {
+		t.Errorf("Expected empty scope to stay empty, got %s (%s)", dep.Scope, dep.RawScope)
+	}
+}
+
+func TestScopeFilter_Process_RawScope(t *testing.T) {
+	roots := NewScopeNormalizer().Process([]model.DependencyRoot{
+		{
+			BuildTool: "npm",
+			Dependencies: []model.Dependency{
+				{Name: "express", Scope: "runtime"},
+				{Name: "react", Scope: "peer"},
+			},
+		},
+	})
+
+	result := NewScopeFilter([]string{"peer"}).Process(roots)
+
+	if len(result[0].Dependencies) != 1 || result[0].Dependencies[0].Name != "express" {
+		t.Errorf("Expected peer dependency to be excluded by its raw scope, got %v", result[0].Dependencies)
+	}
+}
+
+func TestDepthLimiter_Process(t *testing.T) {
+	// app -> web -> http -> socket
+	tree := func() []model.DependencyRoot {
+		return []model.DependencyRoot{{
+			BuildTool: "npm",
+			Dependencies: []model.Dependency{{
+				Name: "web",
+				Children: []model.Dependency{{
+					Name:     "http",
+					Children: []model.Dependency{{Name: "socket"}},
+				}},
+			}},
+		}}
+	}
+
+	result := NewDepthLimiter(1).Process(tree())
+	web := result[0].Dependencies[0]
+	if len(web.Children) != 1 || web.Children[0].Name != "http" {
+		t.Fatalf("Expected depth 1 to keep the direct dependency's children, got %v", web.Children)
+	}
+	if len(web.Children[0].Children) != 0 {
+		t.Errorf("Expected depth 1 to drop grandchildren, got %v", web.Children[0].Children)
+	}
+
+	result = NewDepthLimiter(0).Process(tree())
+	if len(result[0].Dependencies) != 1 || len(result[0].Dependencies[0].Children) != 0 {
+		t.Errorf("Expected depth 0 to keep direct dependencies only, got %v", result[0].Dependencies)
+	}
+}
+
+func TestUnresolvedFilter_Process(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "maven",
+		Dependencies: []model.Dependency{
+			{Name: "resolved", Version: "1.0.0"},
+			{Name: "empty", Version: ""},
+			{
+				Name:    "parent",
+				Version: "2.0.0",
+				Children: []model.Dependency{
+					{Name: "ranged", Version: "unknown", Children: []model.Dependency{{Name: "leaf", Version: "3.0.0"}}},
+					{Name: "sibling", Version: "3.1.0"},
+				},
+			},
+		},
+	}}
+
+	result := NewUnresolvedFilter().Process(roots)
+
+	deps := result[0].Dependencies
+	if len(deps) != 2 || deps[0].Name != "empty" || deps[1].Name != "ranged" {
+		t.Fatalf("Expected only the empty and unknown version dependencies, got %v", deps)
+	}
+	if len(deps[1].Children) != 0 {
+		t.Errorf("Expected resolved children to be removed, got %v", deps[1].Children)
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		buildTool string
+		version   string
+		expected  string
+	}{
+		{"npm", "^4.18.2", "4.18.2"},
+		{"npm", "~1.2.0", "1.2.0"},
+		{"npm", "v2.0.0-beta.1", "2.0.0-beta.1"},
+		{"npm", "4.18.2", "4.18.2"},
+		{"npm", "^1.x", "^1.x"},
+		{"npm", ">=1.0.0 <2.0.0", ">=1.0.0 <2.0.0"},
+		{"npm", "1.0.0 || 2.0.0", "1.0.0 || 2.0.0"},
+		{"npm", "*", "*"},
+		{"pip", "~=1.0", "1.0"},
+		{"pip", "==2.31.0", "2.31.0"},
+		{"pip", "==1.0rc1", "1.0rc1"},
+		{"pip", ">=1.0,<2.0", ">=1.0,<2.0"},
+		{"pip", "==2.*", "==2.*"},
+		{"go", "v1.9.1", "1.9.1"},
+		{"go", "v0.0.0-20230101120000-abcdef123456", "0.0.0-20230101120000-abcdef123456"},
+		{"go", "v2.0.0+incompatible", "2.0.0+incompatible"},
+		{"maven", "[1.0,2.0)", "[1.0,2.0)"},
+		{"maven", "v1.0", "v1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.buildTool+"/"+tt.version, func(t *testing.T) {
+			if got := NormalizeVersion(tt.buildTool, tt.version); got != tt.expected {
+				t.Errorf("NormalizeVersion(%s, %s) = %s, want %s", tt.buildTool, tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVersionNormalizer_Process(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "npm",
+		Dependencies: []model.Dependency{{
+			ID:       &model.DependencyID{Name: "express", Version: "^4.18.2"},
+			Name:     "express",
+			Version:  "^4.18.2",
+			Children: []model.Dependency{{Name: "qs", Version: "~6.11.0"}},
+		}, {
+			Name:    "lodash",
+			Version: "4.x",
+		}},
+	}}
+
+	result := NewVersionNormalizer().Process(roots)
+	express := result[0].Dependencies[0]
+	if express.Version != "4.18.2" || express.RawVersion != "^4.18.2" || express.ID.Version != "4.18.2" {
+		t.Errorf("Expected express 4.18.2 with raw version ^4.18.2, got %+v (id %+v)", express, express.ID)
+	}
+	if qs := express.Children[0]; qs.Version != "6.11.0" || qs.RawVersion != "~6.11.0" {
+		t.Errorf("Expected child qs 6.11.0 with raw version ~6.11.0, got %+v", qs)
+	}
+	if lodash := result[0].Dependencies[1]; lodash.Version != "4.x" || lodash.RawVersion != "" {
+		t.Errorf("Expected the 4.x range to stay unchanged, got %+v", lodash)
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		buildTool string
+		typ       string
+		expected  string
+	}{
+		{"maven", "jar", "maven"},
+		{"maven", "war", "maven"},
+		{"maven", "pom", "maven"},
+		{"maven", "test-jar", "maven"},
+		{"maven", "maven-plugin", "maven"},
+		{"maven", "nbm", "maven"},
+		{"gradle", "gradle", "maven"},
+		{"gradle", "aar", "maven"},
+		{"npm", "npm", "npm"},
+		{"go", "go", "golang"},
+		{"pip", "pip", "pypi"},
+		{"pipenv", "pipenv", "pypi"},
+		{"cargo", "cargo", "cargo"},
+		{"composer", "composer", "composer"},
+		{"dotnet", "nuget", "nuget"},
+		{"conan", "conan", "conan"},
+		{"cmake", "vcpkg", "generic"},
+		{"cmake", "cmake", "generic"},
+		{"csystem", "system", "generic"},
+		{"external", " NPM ", "npm"},
+		{"external", "hex", "hex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.buildTool+"/"+tt.typ, func(t *testing.T) {
+			if got := NormalizeType(tt.buildTool, tt.typ); got != tt.expected {
+				t.Errorf("NormalizeType(%q, %q) = %q, expected %q", tt.buildTool, tt.typ, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTypeNormalizer_Process(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "maven",
+		Dependencies: []model.Dependency{{
+			ID:       &model.DependencyID{Group: "org.example", Name: "core", Version: "1.0.0", Type: "jar"},
+			Name:     "core",
+			Version:  "1.0.0",
+			Type:     "jar",
+			Children: []model.Dependency{{Name: "bom", Version: "2.0.0", Type: "pom"}},
+		}},
+	}, {
+		BuildTool:    "npm",
+		Dependencies: []model.Dependency{{Name: "lodash", Version: "4.17.21", Type: "npm"}},
+	}}
+
+	result := NewTypeNormalizer().Process(roots)
+	core := result[0].Dependencies[0]
+	if core.Type != "maven" || core.RawType != "jar" || core.ID.Type != "maven" {
+		t.Errorf("Expected core of type maven with raw type jar, got %+v (id %+v)", core, core.ID)
+	}
+	if bom := core.Children[0]; bom.Type != "maven" || bom.RawType != "pom" {
+		t.Errorf("Expected child bom of type maven with raw type pom, got %+v", bom)
+	}
+	if lodash := result[1].Dependencies[0]; lodash.Type != "npm" || lodash.RawType != "" {
+		t.Errorf("Expected the npm type to stay unchanged, got %+v", lodash)
+	}
+}
+
+func TestInternalMarker_Process(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "maven",
+		Dependencies: []model.Dependency{{
+			ID:      &model.DependencyID{Group: "com.mycorp.platform", Name: "core"},
+			Name:    "core",
+			Version: "1.0.0",
+			Children: []model.Dependency{
+				{ID: &model.DependencyID{Group: "org.slf4j", Name: "slf4j-api"}, Name: "slf4j-api", Version: "2.0.9"},
+			},
+		}, {
+			ID:   &model.DependencyID{Group: "com.mycorporation", Name: "lookalike"},
+			Name: "lookalike",
+		}},
+	}, {
+		BuildTool: "npm",
+		Dependencies: []model.Dependency{
+			{Name: "@myorg/ui-kit", Version: "3.2.0"},
+			{Name: "@myorganization/other", Version: "1.0.0"},
+			{Name: "react", Version: "18.2.0"},
+			{Name: "internal-logger", Version: "0.1.0"},
+		},
+	}}
+
+	result := NewInternalMarker([]string{"com.mycorp.*", "@myorg/*", "re:internal-(logger|metrics)"}).Process(roots)
+
+	tests := []struct {
+		dep      model.Dependency
+		internal bool
+	}{
+		{result[0].Dependencies[0], true},
+		{result[0].Dependencies[0].Children[0], false},
+		{result[0].Dependencies[1], false},
+		{result[1].Dependencies[0], true},
+		{result[1].Dependencies[1], false},
+		{result[1].Dependencies[2], false},
+		{result[1].Dependencies[3], true},
+	}
+	for _, tt := range tests {
+		if tt.dep.Internal != tt.internal {
+			t.Errorf("Expected %s internal=%t, got %t", tt.dep.Name, tt.internal, tt.dep.Internal)
+		}
+	}
+}
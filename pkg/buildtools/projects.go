@@ -0,0 +1,289 @@
+package buildtools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// ProjectDescriber is implemented by scanners that can describe their projects from the build
+// files alone, without running build tools or resolving dependencies
+type ProjectDescriber interface {
+	GetProjectInfo() ([]model.ProjectInfo, error)
+}
+
+// DetectProjects describes the projects of all detected scanners without scanning their
+// dependencies. Scanners that cannot describe their projects, such as the external scanner,
+// are skipped.
+func (bs *BuildScanner) DetectProjects() ([]model.ProjectInfo, error) {
+	var projects []model.ProjectInfo
+	for _, scanner := range bs.scanners {
+		describer, ok := scanner.(ProjectDescriber)
+		if !ok {
+			bs.log.Infof("Skipping %T, it cannot describe projects without scanning", scanner)
+			continue
+		}
+		infos, err := describer.GetProjectInfo()
+		if err != nil {
+			if bs.config.Strict {
+				return nil, err
+			}
+			bs.log.Warnf("Failed to describe project: %v", err)
+			continue
+		}
+		projects = append(projects, infos...)
+	}
+	return projects, nil
+}
+
+// GetProjectInfo describes the Maven project from pom.xml
+func (ms *MavenScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	pom, err := ms.parsePOM(filepath.Join(ms.environment.GetDirectory(), "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var licenses []string
+	for _, license := range pom.Licenses.License {
+		if name := strings.TrimSpace(license.Name); name != "" {
+			licenses = append(licenses, name)
+		}
+	}
+	return []model.ProjectInfo{{
+		Name:        pom.ArtifactID,
+		Version:     pom.Version,
+		Description: strings.TrimSpace(pom.Description),
+		License:     strings.Join(licenses, ", "),
+		BuildTool:   "maven",
+	}}, nil
+}
+
+// GetProjectInfo describes the Gradle root project and the subprojects of settings.gradle
+func (gs *GradleScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	build, err := gs.parseBuildGradleIn(gs.environment.GetDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	projects := []model.ProjectInfo{{Name: gs.rootProjectName(build), Version: build.Version, BuildTool: "gradle"}}
+	for _, root := range gs.scanSubprojects(build.Version) {
+		projects = append(projects, model.ProjectInfo{Name: root.ProjectName, Version: root.ProjectVersion, BuildTool: root.BuildTool})
+	}
+	return projects, nil
+}
+
+// GetProjectInfo describes the Python project from setup.py, falling back to pyproject.toml
+func (ps *PipScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	project := model.ProjectInfo{Name: "unknown", Version: "unknown", BuildTool: "pip"}
+
+	dir := ps.environment.GetDirectory()
+	setupPath := filepath.Join(dir, "setup.py")
+	if _, err := os.Stat(setupPath); err == nil {
+		name, version := ps.parseSetupPy(setupPath)
+		if name != "" {
+			project.Name = name
+		}
+		if version != "" {
+			project.Version = version
+		}
+	}
+
+	pyprojectPath := filepath.Join(dir, "pyproject.toml")
+	if _, err := os.Stat(pyprojectPath); err == nil {
+		name, version, _, err := ps.parsePyproject(pyprojectPath)
+		if err != nil {
+			return nil, err
+		}
+		if project.Name == "unknown" && name != "" {
+			project.Name = name
+		}
+		if project.Version == "unknown" && version != "" {
+			project.Version = version
+		}
+	}
+	return []model.ProjectInfo{project}, nil
+}
+
+// GetProjectInfo describes the Pipenv project from Pipfile
+func (ps *PipenvScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	name, version, err := ps.parsePipfile()
+	if err != nil {
+		return nil, err
+	}
+	return []model.ProjectInfo{{Name: name, Version: version, BuildTool: "pipenv"}}, nil
+}
+
+// GetProjectInfo describes the npm package from package.json, followed by the packages of a
+// lerna, Nx or Turborepo monorepo
+func (ns *NpmScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	project, err := ns.packageInfo()
+	if err != nil {
+		return nil, err
+	}
+	projects := []model.ProjectInfo{*project}
+	for _, dir := range ns.monorepoPackages() {
+		if pkg, err := NewNpmScanner(NewScannableEnvironment(dir, ""), ns.config).packageInfo(); err == nil {
+			projects = append(projects, *pkg)
+		}
+	}
+	return projects, nil
+}
+
+// packageInfo describes the npm package in the scan directory
+func (ns *NpmScanner) packageInfo() (*model.ProjectInfo, error) {
+	data, err := utils.ReadTextFile(filepath.Join(ns.environment.GetDirectory(), "package.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var packageInfo struct {
+		Name        string          `json:"name"`
+		Version     string          `json:"version"`
+		Description string          `json:"description"`
+		License     json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(data, &packageInfo); err != nil {
+		return nil, err
+	}
+
+	return &model.ProjectInfo{
+		Name:        defaultUnknown(packageInfo.Name),
+		Version:     defaultUnknown(packageInfo.Version),
+		Description: packageInfo.Description,
+		License:     npmLicense(packageInfo.License),
+		BuildTool:   "npm",
+	}, nil
+}
+
+// GetProjectInfo describes the Go module, or every module of a go.work workspace
+func (gs *GoScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	moduleDirs, err := gs.workspaceModules()
+	if err != nil {
+		return nil, err
+	}
+
+	scanners := []*GoScanner{gs}
+	if len(moduleDirs) > 0 {
+		scanners = nil
+		for _, dir := range moduleDirs {
+			scanners = append(scanners, NewGoScanner(NewScannableEnvironment(dir, ""), gs.config))
+		}
+	}
+
+	var projects []model.ProjectInfo
+	for _, scanner := range scanners {
+		name, version, err := scanner.parseGoMod()
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, model.ProjectInfo{Name: name, Version: version, BuildTool: "go"})
+	}
+	return projects, nil
+}
+
+// GetProjectInfo describes the Rust package from the [package] table of Cargo.toml
+func (cs *CargoScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	tables, err := parseTomlFile(filepath.Join(cs.environment.GetDirectory(), "Cargo.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	project := model.ProjectInfo{Name: "unknown", Version: "unknown", BuildTool: "cargo"}
+	for _, table := range tables {
+		if table.Name != "package" {
+			continue
+		}
+		project.Name = defaultUnknown(tomlString(table.Values["name"]))
+		// version.workspace = true inherits the workspace version, which is not known here
+		if version := tomlString(table.Values["version"]); version != "" && !strings.HasPrefix(version, "{") {
+			project.Version = version
+		}
+		project.Description = tomlString(table.Values["description"])
+		project.License = tomlString(table.Values["license"])
+	}
+	return []model.ProjectInfo{project}, nil
+}
+
+// GetProjectInfo describes the PHP package from composer.json
+func (cs *ComposerScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	data, err := utils.ReadTextFile(filepath.Join(cs.environment.GetDirectory(), "composer.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var composerInfo struct {
+		Name        string          `json:"name"`
+		Version     string          `json:"version"`
+		Description string          `json:"description"`
+		License     json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(data, &composerInfo); err != nil {
+		return nil, err
+	}
+	return []model.ProjectInfo{{
+		Name:        defaultUnknown(composerInfo.Name),
+		Version:     defaultUnknown(composerInfo.Version),
+		Description: composerInfo.Description,
+		License:     composerLicense(composerInfo.License),
+		BuildTool:   "composer",
+	}}, nil
+}
+
+// GetProjectInfo describes every .NET project from its project file
+func (ds *DotnetScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	var projects []model.ProjectInfo
+	for _, projectFile := range ds.projectFiles() {
+		root, err := parseDotnetProject(projectFile)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, model.ProjectInfo{Name: root.ProjectName, Version: root.ProjectVersion, BuildTool: dotnetBuildTool})
+	}
+	return projects, nil
+}
+
+// GetProjectInfo describes the C/C++ project from vcpkg.json, overridden by the project()
+// command of CMakeLists.txt
+func (cs *CMakeScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	project := model.ProjectInfo{Name: "unknown", Version: "unknown", BuildTool: "cmake"}
+
+	dir := cs.environment.GetDirectory()
+	if content, err := utils.ReadTextFile(filepath.Join(dir, "vcpkg.json")); err == nil {
+		name, version, _, err := parseVcpkgManifest(content)
+		if err != nil {
+			return nil, err
+		}
+		project.Name, project.Version = name, version
+	}
+	if content, err := utils.ReadTextFile(filepath.Join(dir, "CMakeLists.txt")); err == nil {
+		name, version, _ := parseCMakeLists(string(content))
+		if name != "unknown" {
+			project.Name = name
+		}
+		if version != "unknown" {
+			project.Version = version
+		}
+	}
+	return []model.ProjectInfo{project}, nil
+}
+
+// GetProjectInfo describes the C/C++ project by its directory, its build files name no project
+func (cs *CSystemScanner) GetProjectInfo() ([]model.ProjectInfo, error) {
+	return []model.ProjectInfo{{
+		Name:      utils.ProjectNameFromDir(cs.environment.GetDirectory()),
+		Version:   "unknown",
+		BuildTool: cSystemBuildTool,
+	}}, nil
+}
+
+// defaultUnknown returns value, or "unknown" when it is empty
+func defaultUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
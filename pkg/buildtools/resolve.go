@@ -0,0 +1,135 @@
+package buildtools
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/logger"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/pkg/resolver"
+)
+
+// resolvableBuildTools are the build tools whose registries --resolve-versions queries
+var resolvableBuildTools = []string{"npm", "pip", "pipenv"}
+
+// VersionResolver replaces version constraints with the newest matching version published in
+// the ecosystem registry, keeping the constraint in RawVersion. It is meant for dependencies
+// declared without a lockfile; lookups that fail keep the constraint. Internal dependencies and
+// unknown versions are never looked up, and Python projects selecting their own package index
+// are resolved against that index instead of PyPI.
+type VersionResolver struct {
+	resolvers        map[string]resolver.Resolver               // Keyed by build tool
+	newIndexResolver func(indexURLs []string) resolver.Resolver // Resolver for the Python package indexes a project selects
+	resolved         map[string]string                          // Versions already looked up, keyed by registry, name and constraint
+	failed           int
+	log              *logrus.Logger
+}
+
+// NewVersionResolver creates a version resolver querying the public npm registry and PyPI
+func NewVersionResolver() *VersionResolver {
+	resolvers := make(map[string]resolver.Resolver)
+	for _, buildTool := range resolvableBuildTools {
+		resolvers[buildTool] = resolver.New(buildTool, nil)
+	}
+	vr := newVersionResolver(resolvers)
+	vr.newIndexResolver = func(indexURLs []string) resolver.Resolver {
+		return resolver.NewSimpleIndexResolver(indexURLs, &http.Client{Timeout: resolver.DefaultTimeout})
+	}
+	return vr
+}
+
+// newVersionResolver creates a version resolver using the given resolvers per build tool
+func newVersionResolver(resolvers map[string]resolver.Resolver) *VersionResolver {
+	return &VersionResolver{resolvers: resolvers, resolved: make(map[string]string), log: logger.GetLogger()}
+}
+
+// Process resolves the constraints of all dependencies, including their children
+func (vr *VersionResolver) Process(roots []model.DependencyRoot) []model.DependencyRoot {
+	vr.failed = 0
+	for i := range roots {
+		if r, registry := vr.rootResolver(roots[i]); r != nil {
+			vr.resolve(r, registry, roots[i].Dependencies)
+		}
+	}
+	if vr.failed > 0 {
+		vr.log.Warnf("Could not resolve %d version constraint(s), keeping them as declared", vr.failed)
+	}
+	return roots
+}
+
+// rootResolver returns the resolver for a root and the registry it queries, or nil when the root
+// is not resolved. Python roots use the indexes their requirements file selects, or the one
+// PIP_INDEX_URL configures, like pip; a root whose index needs credentials that were redacted
+// is skipped rather than sending its package names to PyPI.
+func (vr *VersionResolver) rootResolver(root model.DependencyRoot) (resolver.Resolver, string) {
+	r, ok := vr.resolvers[root.BuildTool]
+	if !ok {
+		return nil, ""
+	}
+	if root.BuildTool != "pip" && root.BuildTool != "pipenv" {
+		return r, root.BuildTool
+	}
+
+	indexURLs := root.IndexURLs
+	if len(indexURLs) == 0 {
+		if indexURL := os.Getenv("PIP_INDEX_URL"); indexURL != "" {
+			indexURLs = []string{indexURL}
+		}
+	}
+	if len(indexURLs) == 0 || vr.newIndexResolver == nil {
+		return r, root.BuildTool
+	}
+	for _, indexURL := range indexURLs {
+		if strings.Contains(indexURL, "***@") {
+			vr.log.Warnf("Not resolving the constraints of %s: its package index needs credentials", root.ProjectName)
+			return nil, ""
+		}
+	}
+	return vr.newIndexResolver(indexURLs), strings.Join(indexURLs, " ")
+}
+
+// resolve recursively resolves a dependency list in place, skipping internal dependencies so
+// their names never reach a registry
+func (vr *VersionResolver) resolve(r resolver.Resolver, registry string, dependencies []model.Dependency) {
+	for i := range dependencies {
+		dep := &dependencies[i]
+		if !dep.Internal && isConstraint(dep.Version) {
+			if version, ok := vr.lookup(r, registry, dep.Name, dep.Version); ok {
+				if dep.RawVersion == "" {
+					dep.RawVersion = dep.Version
+				}
+				if dep.ID != nil && dep.ID.Version == dep.Version {
+					dep.ID.Version = version
+				}
+				dep.Version = version
+			}
+		}
+		vr.resolve(r, registry, dep.Children)
+	}
+}
+
+// lookup resolves one constraint, asking the registry once per package and constraint
+func (vr *VersionResolver) lookup(r resolver.Resolver, registry, name, constraint string) (string, bool) {
+	key := registry + " " + name + " " + constraint
+	if version, ok := vr.resolved[key]; ok {
+		return version, version != ""
+	}
+
+	version, err := r.Resolve(name, constraint)
+	if err != nil {
+		vr.log.Debugf("Failed to resolve %s %s: %v", name, constraint, err)
+		vr.failed++
+	}
+	vr.resolved[key] = version
+	return version, err == nil
+}
+
+// isConstraint reports whether a version is a constraint rather than a single version. Unknown
+// versions are not: the newest release says nothing about the version actually used.
+func isConstraint(version string) bool {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	return version != "" && version != "unknown" && (!exactVersionPattern.MatchString(version) || wildcardVersionPattern.MatchString(version))
+}
@@ -0,0 +1,132 @@
+package buildtools
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/pkg/resolver"
+)
+
+// stubResolver resolves constraints from a fixed table, failing for everything else
+type stubResolver map[string]string
+
+func (sr stubResolver) Resolve(name, constraint string) (string, error) {
+	if version, ok := sr[name+" "+constraint]; ok {
+		return version, nil
+	}
+	return "", resolver.ErrNoMatch
+}
+
+// recordingResolver records the packages it is asked about
+type recordingResolver struct {
+	versions stubResolver
+	names    []string
+}
+
+func (rr *recordingResolver) Resolve(name, constraint string) (string, error) {
+	rr.names = append(rr.names, name)
+	return rr.versions.Resolve(name, constraint)
+}
+
+func TestVersionResolver_Process(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "npm",
+		Dependencies: []model.Dependency{{
+			ID:       &model.DependencyID{Name: "express", Version: "^4.18.0"},
+			Name:     "express",
+			Version:  "^4.18.0",
+			Children: []model.Dependency{{Name: "qs", Version: "6.11.0"}},
+		}, {
+			Name:    "left-pad",
+			Version: "^9.0.0",
+		}},
+	}, {
+		BuildTool:    "maven",
+		Dependencies: []model.Dependency{{Name: "guava", Version: "[30.0,)"}},
+	}}
+
+	vr := newVersionResolver(map[string]resolver.Resolver{"npm": stubResolver{"express ^4.18.0": "4.21.2"}})
+	result := vr.Process(roots)
+
+	express := result[0].Dependencies[0]
+	if express.Version != "4.21.2" || express.RawVersion != "^4.18.0" || express.ID.Version != "4.21.2" {
+		t.Errorf("Expected express resolved to 4.21.2 with raw version ^4.18.0, got %+v (id %+v)", express, express.ID)
+	}
+	if qs := express.Children[0]; qs.Version != "6.11.0" || qs.RawVersion != "" {
+		t.Errorf("Expected the exact child version to stay unchanged, got %+v", qs)
+	}
+	if leftPad := result[0].Dependencies[1]; leftPad.Version != "^9.0.0" || leftPad.RawVersion != "" {
+		t.Errorf("Expected a failed lookup to keep the constraint, got %+v", leftPad)
+	}
+	if guava := result[1].Dependencies[0]; guava.Version != "[30.0,)" {
+		t.Errorf("Expected build tools without resolver to stay unchanged, got %+v", guava)
+	}
+}
+
+func TestVersionResolver_Process_SkipsInternalAndUnknown(t *testing.T) {
+	roots := []model.DependencyRoot{{
+		BuildTool: "npm",
+		Dependencies: []model.Dependency{
+			{Name: "@acme/ui", Version: "^2.0.0", Internal: true},
+			{Name: "lodash", Version: "unknown"},
+			{Name: "express", Version: "^4.18.0"},
+		},
+	}}
+
+	registry := recordingResolver{versions: stubResolver{"express ^4.18.0": "4.21.2", "@acme/ui ^2.0.0": "2.9.9", "lodash unknown": "4.17.21"}}
+	vr := newVersionResolver(map[string]resolver.Resolver{"npm": &registry})
+	deps := vr.Process(roots)[0].Dependencies
+
+	if !slices.Equal(registry.names, []string{"express"}) {
+		t.Errorf("Expected only express to be looked up, got %v", registry.names)
+	}
+	if deps[0].Version != "^2.0.0" || deps[1].Version != "unknown" || deps[2].Version != "4.21.2" {
+		t.Errorf("Expected internal and unknown versions kept, got %+v", deps)
+	}
+}
+
+func TestVersionResolver_Process_PipIndexURLs(t *testing.T) {
+	t.Setenv("PIP_INDEX_URL", "")
+	roots := []model.DependencyRoot{{
+		BuildTool:    "pip",
+		IndexURLs:    []string{"https://pypi.acme.example/simple"},
+		Dependencies: []model.Dependency{{Name: "acme-utils", Version: ">=1.0"}},
+	}, {
+		BuildTool:    "pip",
+		ProjectName:  "tokened",
+		IndexURLs:    []string{"https://***@pypi.acme.example/simple"},
+		Dependencies: []model.Dependency{{Name: "acme-core", Version: ">=1.0"}},
+	}, {
+		BuildTool:    "pip",
+		Dependencies: []model.Dependency{{Name: "requests", Version: ">=2.0"}},
+	}}
+
+	public := recordingResolver{versions: stubResolver{"requests >=2.0": "2.32.3"}}
+	private := recordingResolver{versions: stubResolver{"acme-utils >=1.0": "1.4.2"}}
+	var indexes [][]string
+	vr := newVersionResolver(map[string]resolver.Resolver{"pip": &public})
+	vr.newIndexResolver = func(indexURLs []string) resolver.Resolver {
+		indexes = append(indexes, indexURLs)
+		return &private
+	}
+	result := vr.Process(roots)
+
+	if len(indexes) != 1 || !slices.Equal(indexes[0], roots[0].IndexURLs) {
+		t.Errorf("Expected one resolver for the declared index, got %v", indexes)
+	}
+	if !slices.Equal(public.names, []string{"requests"}) || !slices.Equal(private.names, []string{"acme-utils"}) {
+		t.Errorf("Expected each project resolved against its own index, got public %v and private %v", public.names, private.names)
+	}
+	if result[0].Dependencies[0].Version != "1.4.2" || result[1].Dependencies[0].Version != ">=1.0" || result[2].Dependencies[0].Version != "2.32.3" {
+		t.Errorf("Unexpected resolution: %+v", result)
+	}
+
+	t.Setenv("PIP_INDEX_URL", "https://pypi.acme.example/simple")
+	public.names, indexes = nil, nil
+	roots[2].Dependencies[0].Version = ">=2.0"
+	vr.Process(roots[2:])
+	if len(public.names) != 0 || len(indexes) != 1 {
+		t.Errorf("Expected PIP_INDEX_URL to replace PyPI, got public lookups %v and indexes %v", public.names, indexes)
+	}
+}
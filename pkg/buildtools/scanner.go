@@ -1,16 +1,37 @@
 package buildtools
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
+// scanWarnings collects the non-fatal problems of a scan, such as a fallback to a less precise
+// source, so they reach the dependency output and not only the log
+type scanWarnings []string
+
+// add logs a warning and records it
+func (w *scanWarnings) add(log logrus.FieldLogger, format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	log.Warn(warning)
+	*w = append(*w, warning)
+}
+
+// scannerLogger returns the logger of a scanner, tagging its entries with the scanner name and
+// directory so the output of concurrent scanners can be told apart
+func scannerLogger(name string, env *ScannableEnvironment) *logrus.Entry {
+	return logger.WithScanner(name, env.GetDirectory())
+}
+
 // ScannableEnvironment represents the scanning environment
 type ScannableEnvironment struct {
 	directory     string
@@ -49,11 +70,48 @@ func (se *ScannableEnvironment) SetBuildFile(buildFile string) {
 	se.buildFile = buildFile
 }
 
+// buildFileTools maps characteristic build files to their build tool
+var buildFileTools = map[string]string{
+	"pom.xml":          "maven",
+	"build.gradle":     "gradle",
+	"build.gradle.kts": "gradle",
+	"requirements.txt": "pip",
+	"setup.py":         "pip",
+	"pyproject.toml":   "pip",
+	"uv.lock":          "pip",
+	"Pipfile":          "pipenv",
+	"package.json":     "npm",
+	"go.mod":           "go",
+	"go.work":          "go",
+	"Cargo.toml":       "cargo",
+	"composer.json":    "composer",
+	"CMakeLists.txt":   "cmake",
+	"vcpkg.json":       "cmake",
+}
+
+// buildFilePatterns maps build file name patterns, for tools whose build files are named after
+// the project, to their build tool
+var buildFilePatterns = map[string]string{
+	"*.csproj": dotnetBuildTool,
+	"*.fsproj": dotnetBuildTool,
+	"*.vbproj": dotnetBuildTool,
+	"*.sln":    dotnetBuildTool,
+}
+
+// detectionSkipDirs lists dependency and build output directories never searched for nested projects
+var detectionSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "target": true, "build": true,
+	"__pycache__": true, "dist": true, "venv": true,
+}
+
 // Scannable represents an interface for build tool scanners
 type Scannable interface {
 	ExeFind() error
 	FileFind() error
 	ScanExecute() ([]model.DependencyRoot, error)
+	// IsApplicable cheaply checks that the detected build files really describe a project of
+	// this tool, logging why when they do not
+	IsApplicable() bool
 }
 
 // BuildScanner manages different build tool scanners
@@ -61,6 +119,8 @@ type BuildScanner struct {
 	environment *ScannableEnvironment
 	config      *config.ScanConfig
 	scanners    []Scannable
+	processors  []DependencyProcessor
+	warnings    scanWarnings // Detection problems, reported on the first dependency root
 	log         *logrus.Logger
 }
 
@@ -74,87 +134,298 @@ func NewBuildScanner(env *ScannableEnvironment, cfg *config.ScanConfig) *BuildSc
 
 	// Initialize scanners based on detected build tools
 	scanner.initializeScanners()
+	scanner.initializeProcessors()
 	return scanner
 }
 
 // initializeScanners initializes the appropriate scanners based on detected build files
 func (bs *BuildScanner) initializeScanners() {
-	scanDir := bs.environment.GetDirectory()
+	bs.registerScanners(bs.environment)
+
+	// Register scanners for nested projects when recursive detection is enabled
+	if bs.config.Recursive {
+		for _, dir := range bs.findProjectDirs() {
+			bs.registerScanners(NewScannableEnvironment(dir, ""))
+		}
+	}
+
+	// The external scanner reports formats no built-in scanner understands, next to their results
+	if bs.config.ExternalScanner != "" {
+		bs.scanners = append(bs.scanners, NewExternalScanner(bs.environment, bs.config))
+		bs.log.Infof("Using external scanner: %s", bs.config.ExternalScanner)
+	}
+
+	if len(bs.scanners) == 0 {
+		bs.log.Warn("No supported build tools detected")
+	}
+}
+
+// registerScanners registers the scanners for build files found directly in the environment directory
+func (bs *BuildScanner) registerScanners(env *ScannableEnvironment) {
+	scanDir := env.GetDirectory()
 
 	// Check for Maven
-	if bs.fileExists(filepath.Join(scanDir, "pom.xml")) {
-		bs.scanners = append(bs.scanners, NewMavenScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Maven project")
+	if bs.config.ToolEnabled("maven") && bs.fileExists(filepath.Join(scanDir, "pom.xml")) {
+		bs.register(NewMavenScanner(env, bs.config), "Maven", scanDir)
 	}
 
 	// Check for Gradle
-	if bs.fileExists(filepath.Join(scanDir, "build.gradle")) ||
-		bs.fileExists(filepath.Join(scanDir, "build.gradle.kts")) {
-		bs.scanners = append(bs.scanners, NewGradleScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Gradle project")
+	if bs.config.ToolEnabled("gradle") && (bs.fileExists(filepath.Join(scanDir, "build.gradle")) ||
+		bs.fileExists(filepath.Join(scanDir, "build.gradle.kts"))) {
+		bs.register(NewGradleScanner(env, bs.config), "Gradle", scanDir)
 	}
 
-	// Check for Python pip
-	if bs.fileExists(filepath.Join(scanDir, "requirements.txt")) ||
+	// Check for Python pip and Pipenv, which report the same packages when both are present
+	pipFound := bs.config.ToolEnabled("pip") && (bs.fileExists(filepath.Join(scanDir, "requirements.txt")) ||
 		bs.fileExists(filepath.Join(scanDir, "setup.py")) ||
-		bs.fileExists(filepath.Join(scanDir, "pyproject.toml")) {
-		bs.scanners = append(bs.scanners, NewPipScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Python pip project")
+		bs.fileExists(filepath.Join(scanDir, "pyproject.toml")) ||
+		bs.fileExists(filepath.Join(scanDir, "uv.lock")))
+	pipenvFound := bs.config.ToolEnabled("pipenv") && bs.fileExists(filepath.Join(scanDir, "Pipfile"))
+	if pipFound && pipenvFound {
+		pipFound, pipenvFound = bs.choosePythonSource(scanDir)
 	}
-
-	// Check for Pipenv
-	if bs.fileExists(filepath.Join(scanDir, "Pipfile")) {
-		bs.scanners = append(bs.scanners, NewPipenvScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Python Pipenv project")
+	if pipFound {
+		bs.register(NewPipScanner(env, bs.config), "Python pip", scanDir)
+	}
+	if pipenvFound {
+		bs.register(NewPipenvScanner(env, bs.config), "Python Pipenv", scanDir)
 	}
 
 	// Check for Node.js
-	if bs.fileExists(filepath.Join(scanDir, "package.json")) {
-		bs.scanners = append(bs.scanners, NewNpmScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Node.js project")
+	if bs.config.ToolEnabled("npm") && bs.fileExists(filepath.Join(scanDir, "package.json")) {
+		bs.register(NewNpmScanner(env, bs.config), "Node.js", scanDir)
 	}
 
-	// Check for Go
-	if bs.fileExists(filepath.Join(scanDir, "go.mod")) {
-		bs.scanners = append(bs.scanners, NewGoScanner(bs.environment, bs.config))
-		bs.log.Info("Detected Go project")
+	// Check for Go modules and workspaces
+	if bs.config.ToolEnabled("go") && (bs.fileExists(filepath.Join(scanDir, "go.mod")) ||
+		(!bs.config.Recursive && bs.fileExists(filepath.Join(scanDir, "go.work")))) {
+		bs.register(NewGoScanner(env, bs.config), "Go", scanDir)
 	}
 
-	if len(bs.scanners) == 0 {
-		bs.log.Warn("No supported build tools detected")
+	// Check for Rust Cargo
+	if bs.config.ToolEnabled("cargo") && bs.fileExists(filepath.Join(scanDir, "Cargo.toml")) {
+		bs.register(NewCargoScanner(env, bs.config), "Cargo", scanDir)
+	}
+
+	// Check for PHP Composer
+	if bs.config.ToolEnabled("composer") && bs.fileExists(filepath.Join(scanDir, "composer.json")) {
+		bs.register(NewComposerScanner(env, bs.config), "Composer", scanDir)
+	}
+
+	// Check for .NET projects and solutions
+	if bs.config.ToolEnabled(dotnetBuildTool) && bs.hasBuildFilePattern(scanDir, dotnetBuildTool) {
+		bs.register(NewDotnetScanner(env, bs.config), ".NET", scanDir)
+	}
+
+	// Check for CMake and vcpkg
+	if bs.config.ToolEnabled("cmake") && (bs.fileExists(filepath.Join(scanDir, "CMakeLists.txt")) ||
+		bs.fileExists(filepath.Join(scanDir, "vcpkg.json"))) {
+		bs.register(NewCMakeScanner(env, bs.config), "CMake", scanDir)
+	}
+
+	// Check for Make/CMake driven C/C++ projects (experimental)
+	if bs.config.ExperimentalCScan && bs.config.ToolEnabled(cSystemBuildTool) {
+		cScanner := NewCSystemScanner(env, bs.config)
+		if cScanner.FileFind() == nil {
+			bs.register(cScanner, "C/C++ (experimental)", scanDir)
+		}
 	}
 }
 
-// ScanDependencies scans dependencies using all detected scanners
-func (bs *BuildScanner) ScanDependencies() ([]model.DependencyRoot, error) {
-	var allDependencies []model.DependencyRoot
+// choosePythonSource decides whether the pip and Pipenv scanners run for a directory holding
+// files of both. --prefer-manifest keeps pip, which reads requirements.txt, and --prefer-lockfile
+// keeps Pipenv unless only pip has a lockfile (uv.lock). Without either both run.
+func (bs *BuildScanner) choosePythonSource(scanDir string) (pip, pipenv bool) {
+	switch {
+	case bs.config.PreferManifest:
+		bs.log.Infof("Both pip and Pipenv files found in %s, using pip (--prefer-manifest)", scanDir)
+		return true, false
+	case bs.config.PreferLockfile:
+		if bs.fileExists(filepath.Join(scanDir, "uv.lock")) && !bs.fileExists(filepath.Join(scanDir, "Pipfile.lock")) {
+			bs.log.Infof("Both pip and Pipenv files found in %s, using pip with uv.lock (--prefer-lockfile)", scanDir)
+			return true, false
+		}
+		bs.log.Infof("Both pip and Pipenv files found in %s, using Pipenv (--prefer-lockfile)", scanDir)
+		return false, true
+	}
+	bs.warnings.add(bs.log, "Both pip and Pipenv files found in %s, packages may be reported twice; "+
+		"choose one source with --prefer-lockfile or --prefer-manifest", scanDir)
+	return true, true
+}
 
-	for _, scanner := range bs.scanners {
-		// Check if executable is available
-		if err := scanner.ExeFind(); err != nil {
-			bs.log.Warnf("Executable not found for scanner: %v", err)
-			continue
+// register adds a scanner for the build files detected in scanDir unless its applicability
+// check rejects them, e.g. a package.json that only holds tool configuration
+func (bs *BuildScanner) register(scanner Scannable, project, scanDir string) {
+	if !scanner.IsApplicable() {
+		bs.log.Infof("Skipping %s scanner for %s", project, scanDir)
+		return
+	}
+	bs.scanners = append(bs.scanners, scanner)
+	bs.log.Infof("Detected %s project: %s", project, scanDir)
+}
+
+// findProjectDirs returns nested directories containing build files, honoring the detection depth limit
+func (bs *BuildScanner) findProjectDirs() []string {
+	rootDir := bs.environment.GetDirectory()
+	maxDepth := bs.config.GetDetectMaxDepth()
+
+	var projectDirs []string
+	_ = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil && utils.IsPathTooLong(err) {
+			bs.warnings.add(bs.log, "Skipped %s during project detection, its path is too long to be read", path)
+			return nil
+		}
+		if err != nil || !info.IsDir() || path == rootDir {
+			return nil
 		}
 
-		// Check if required files exist
-		if err := scanner.FileFind(); err != nil {
-			bs.log.Warnf("Required files not found for scanner: %v", err)
-			continue
+		if detectionSkipDirs[info.Name()] || strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
 		}
 
-		// Execute scan
-		dependencies, err := scanner.ScanExecute()
-		if err != nil {
-			bs.log.Warnf("Scan execution failed: %v", err)
-			continue
+		if utils.PathDepth(rootDir, path) > maxDepth {
+			bs.log.Debugf("Skipping directory beyond max depth %d: %s", maxDepth, path)
+			return filepath.SkipDir
+		}
+
+		for fileName := range buildFileTools {
+			if bs.fileExists(filepath.Join(path, fileName)) {
+				projectDirs = append(projectDirs, path)
+				return nil
+			}
+		}
+		if bs.hasBuildFilePattern(path, "") {
+			projectDirs = append(projectDirs, path)
 		}
+		return nil
+	})
 
-		allDependencies = append(allDependencies, dependencies...)
+	return projectDirs
+}
+
+// initializeProcessors sets up the post-scan dependency processors from the configuration
+func (bs *BuildScanner) initializeProcessors() {
+	bs.processors = newProcessors(bs.config, bs.log)
+}
+
+// ProcessDependencies runs the processors the configuration selects over dependencies that
+// were not scanned, such as those read from an SBOM, as ScanDependencies does for scanned ones
+func ProcessDependencies(cfg *config.ScanConfig, roots []model.DependencyRoot) []model.DependencyRoot {
+	for _, processor := range newProcessors(cfg, logger.GetLogger()) {
+		roots = processor.Process(roots)
+	}
+	return roots
+}
+
+// newProcessors returns the dependency processors selected by the configuration, in the order they run
+func newProcessors(cfg *config.ScanConfig, log *logrus.Logger) []DependencyProcessor {
+	processors := []DependencyProcessor{NewScopeNormalizer()}
+
+	// Internal dependencies are marked before resolution so their names are never sent to a registry
+	if len(cfg.InternalPatterns) > 0 {
+		processors = append(processors, NewInternalMarker(cfg.InternalPatterns))
+	}
+
+	// Resolution has to see the constraints before --normalize-versions strips their operators
+	if cfg.ResolveVersions {
+		processors = append(processors, NewVersionResolver())
+		log.Info("Resolving npm and Python version constraints against their registries")
+	}
+
+	if cfg.NormalizeVersions {
+		processors = append(processors, NewVersionNormalizer())
+	}
+
+	if cfg.NormalizeTypes {
+		processors = append(processors, NewTypeNormalizer())
+	}
+
+	if len(cfg.ExcludeScopes) > 0 {
+		processors = append(processors, NewScopeFilter(cfg.ExcludeScopes))
+		log.Infof("Excluding dependency scopes: %v", cfg.ExcludeScopes)
+	}
+
+	if depth := cfg.GetDependencyDepth(); depth >= 0 {
+		processors = append(processors, NewDepthLimiter(depth))
+		log.Infof("Limiting dependency trees to depth %d", depth)
+	}
+
+	if cfg.ReportUnmatchedOnly {
+		processors = append(processors, NewUnresolvedFilter())
+		log.Info("Reporting only dependencies with unknown versions")
+	}
+
+	// Flattening comes last so the processors above still see the trees
+	if cfg.FlattenDeps {
+		processors = append(processors, NewDependencyFlattener())
+		log.Info("Flattening dependency trees")
+	}
+
+	return processors
+}
+
+// scanResult is the outcome of running one scanner
+type scanResult struct {
+	dependencies []model.DependencyRoot
+	skipped      error // Why the scanner did not run
+	err          error
+}
+
+// ScanDependencies scans dependencies using all detected scanners. Recursive detection may
+// register many scanners, which run concurrently; their roots keep the registration order.
+// Scanners that fail or are skipped are recorded as scan warnings, see Warnings.
+func (bs *BuildScanner) ScanDependencies() ([]model.DependencyRoot, error) {
+	var allDependencies []model.DependencyRoot
+
+	results := parseConcurrently(bs.scanners, parseWorkers(bs.config), bs.runScanner)
+	for _, result := range results {
+		switch {
+		case result.skipped != nil:
+			bs.warnings.add(bs.log, "Scanner skipped: %v", result.skipped)
+		case result.err != nil:
+			if bs.config.Strict {
+				return nil, fmt.Errorf("scan execution failed: %w", result.err)
+			}
+			bs.warnings.add(bs.log, "Scan execution failed: %v", result.err)
+		default:
+			allDependencies = append(allDependencies, result.dependencies...)
+		}
+	}
+
+	if len(bs.warnings) > 0 && len(allDependencies) > 0 {
+		allDependencies[0].Warnings = append(slices.Clone(bs.warnings), allDependencies[0].Warnings...)
+	}
+
+	for _, processor := range bs.processors {
+		allDependencies = processor.Process(allDependencies)
 	}
 
 	return allDependencies, nil
 }
 
+// Warnings returns the problems of the scan as a whole, such as scanners that failed or were
+// skipped. ScanDependencies also attaches them to the first root, when there is one.
+func (bs *BuildScanner) Warnings() []string {
+	return slices.Clone(bs.warnings)
+}
+
+// runScanner runs a single scanner, skipping it when its executable or files are missing
+func (bs *BuildScanner) runScanner(scanner Scannable) scanResult {
+	// Check if executable is available
+	if err := scanner.ExeFind(); err != nil {
+		return scanResult{skipped: fmt.Errorf("executable not found: %w", err)}
+	}
+
+	// Check if required files exist
+	if err := scanner.FileFind(); err != nil {
+		return scanResult{skipped: fmt.Errorf("required files not found: %w", err)}
+	}
+
+	// Execute scan
+	dependencies, err := scanner.ScanExecute()
+	return scanResult{dependencies: dependencies, err: err}
+}
+
 // fileExists checks if a file exists
 func (bs *BuildScanner) fileExists(path string) bool {
 	_, err := os.Stat(path)
@@ -166,48 +437,66 @@ func (bs *BuildScanner) DetectBuildTools() []string {
 	var detectedTools []string
 	scanDir := bs.environment.GetDirectory()
 
-	buildFiles := map[string]string{
-		"pom.xml":          "maven",
-		"build.gradle":     "gradle",
-		"build.gradle.kts": "gradle",
-		"requirements.txt": "pip",
-		"setup.py":         "pip",
-		"pyproject.toml":   "pip",
-		"Pipfile":          "pipenv",
-		"package.json":     "npm",
-		"go.mod":           "go",
-		"Cargo.toml":       "cargo",
-		"composer.json":    "composer",
-	}
-
-	for fileName, toolName := range buildFiles {
+	for fileName, toolName := range buildFileTools {
 		if bs.fileExists(filepath.Join(scanDir, fileName)) {
 			detectedTools = append(detectedTools, toolName)
 		}
 	}
+	for _, toolName := range buildFilePatterns {
+		if bs.hasBuildFilePattern(scanDir, toolName) && !slices.Contains(detectedTools, toolName) {
+			detectedTools = append(detectedTools, toolName)
+		}
+	}
 
 	return detectedTools
 }
 
+// BuildFile is a build file found in the scan directory with the build tool it belongs to
+type BuildFile struct {
+	Name string
+	Tool string
+}
+
+// DetectBuildFiles returns the build files in the environment directory, sorted by name
+func (bs *BuildScanner) DetectBuildFiles() []BuildFile {
+	var buildFiles []BuildFile
+	scanDir := bs.environment.GetDirectory()
+
+	for fileName, toolName := range buildFileTools {
+		if bs.fileExists(filepath.Join(scanDir, fileName)) {
+			buildFiles = append(buildFiles, BuildFile{Name: fileName, Tool: toolName})
+		}
+	}
+	for pattern, toolName := range buildFilePatterns {
+		matches, _ := filepath.Glob(filepath.Join(scanDir, pattern))
+		for _, match := range matches {
+			buildFiles = append(buildFiles, BuildFile{Name: filepath.Base(match), Tool: toolName})
+		}
+	}
+
+	slices.SortFunc(buildFiles, func(a, b BuildFile) int { return strings.Compare(a.Name, b.Name) })
+	return buildFiles
+}
+
+// hasBuildFilePattern reports whether dir holds a build file matching one of buildFilePatterns,
+// limited to the patterns of the given build tool unless it is empty
+func (bs *BuildScanner) hasBuildFilePattern(dir, buildTool string) bool {
+	for pattern, toolName := range buildFilePatterns {
+		if buildTool != "" && toolName != buildTool {
+			continue
+		}
+		if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // detectBuildToolFromFile detects build tool from a specific file
 func detectBuildToolFromFile(filePath string) (string, bool) {
 	baseName := filepath.Base(filePath)
 
-	buildFiles := map[string]string{
-		"pom.xml":          "maven",
-		"build.gradle":     "gradle",
-		"build.gradle.kts": "gradle",
-		"requirements.txt": "pip",
-		"setup.py":         "pip",
-		"pyproject.toml":   "pip",
-		"Pipfile":          "pipenv",
-		"package.json":     "npm",
-		"go.mod":           "go",
-		"Cargo.toml":       "cargo",
-		"composer.json":    "composer",
-	}
-
-	if tool, exists := buildFiles[baseName]; exists {
+	if tool, exists := buildFileTools[baseName]; exists {
 		return tool, true
 	}
 
@@ -1,9 +1,15 @@
 package buildtools
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/craftslab/cleansource-sca-cli/internal/config"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
@@ -88,6 +94,19 @@ require (
 	}
 }
 
+func TestGoScanner_parseGoMod_ByteOrderMark(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{"go.mod": "\ufeffmodule bom-project\n\ngo 1.21\n"})
+
+	name, version, err := NewGoScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).parseGoMod()
+	if err != nil {
+		t.Fatalf("parseGoMod failed: %v", err)
+	}
+	if name != "bom-project" || version != "1.21" {
+		t.Errorf("Expected bom-project 1.21 from a BOM-prefixed go.mod, got %q %q", name, version)
+	}
+}
+
 func TestGoScanner_parseGoMod_Empty(t *testing.T) {
 	tempDir := t.TempDir()
 	env := NewScannableEnvironment(tempDir, "")
@@ -115,6 +134,238 @@ func TestGoScanner_parseGoMod_Empty(t *testing.T) {
 	}
 }
 
+func TestGoScanner_parseVendorModules(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+		"vendor/modules.txt": `# github.com/sirupsen/logrus v1.9.3
+## explicit; go 1.13
+github.com/sirupsen/logrus
+# golang.org/x/sys v0.15.0
+golang.org/x/sys/unix
+# example.com/forked v1.0.0 => example.com/fork v1.0.1
+## explicit
+example.com/forked
+# example.com/local v0.0.0 => ../local
+## explicit
+example.com/local
+`,
+	})
+
+	scanner := NewGoScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	if err := scanner.ExeFind(); err != nil {
+		t.Errorf("ExeFind should not need go in a vendored project, got: %v", err)
+	}
+
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	expected := []struct{ name, version, scope string }{
+		{"github.com/sirupsen/logrus", "v1.9.3", "runtime"},
+		{"golang.org/x/sys", "v0.15.0", "indirect"},
+		{"example.com/forked", "v1.0.1", "runtime"},
+		{"example.com/local", "v0.0.0", "runtime"},
+	}
+	dependencies := roots[0].Dependencies
+	if len(dependencies) != len(expected) {
+		t.Fatalf("Expected %d modules, got %d: %+v", len(expected), len(dependencies), dependencies)
+	}
+	for i, want := range expected {
+		dep := dependencies[i]
+		if dep.Name != want.name || dep.Version != want.version || dep.Scope != want.scope {
+			t.Errorf("Expected %s %s (%s), got %s %s (%s)", want.name, want.version, want.scope, dep.Name, dep.Version, dep.Scope)
+		}
+	}
+}
+
+func TestParseGoListModules(t *testing.T) {
+	// go list prints objects back to back; nested objects put "}" lines inside a module
+	stream := `{
+	"Path": "example.com/app",
+	"Main": true,
+	"Dir": "/src/app"
+}
+{
+	"Path": "github.com/sirupsen/logrus",
+	"Version": "v1.9.3",
+	"Replace": {
+		"Path": "github.com/fork/logrus",
+		"Version": "v1.9.4"
+	}
+}
+{
+	"Path": "golang.org/x/sys",
+	"Version": "v0.15.0",
+	"Indirect": true,
+	"Error": {
+		"Err": "module lookup disabled by GOFLAGS=-mod=vendor"
+	}
+}
+{"Path": "gopkg.in/yaml.v3", "Version": "v3.0.1"}
+`
+	dependencies, err := parseGoListModules(strings.NewReader(stream))
+	if err != nil {
+		t.Fatalf("parseGoListModules failed: %v", err)
+	}
+
+	expected := []struct{ name, version, scope string }{
+		{"github.com/sirupsen/logrus", "v1.9.3", "runtime"},
+		{"golang.org/x/sys", "v0.15.0", "indirect"},
+		{"gopkg.in/yaml.v3", "v3.0.1", "runtime"},
+	}
+	if len(dependencies) != len(expected) {
+		t.Fatalf("Expected %d modules, got %d: %+v", len(expected), len(dependencies), dependencies)
+	}
+	for i, want := range expected {
+		dep := dependencies[i]
+		if dep.Name != want.name || dep.Version != want.version || dep.Scope != want.scope {
+			t.Errorf("Expected %s %s (%s), got %s %s (%s)", want.name, want.version, want.scope, dep.Name, dep.Version, dep.Scope)
+		}
+	}
+
+	if _, err := parseGoListModules(strings.NewReader(`{"Path": "truncated"`)); err == nil {
+		t.Error("Expected an error for a truncated stream")
+	}
+}
+
+func TestGoScanner_ScanExecute_BuildToolTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go command is a shell script")
+	}
+
+	// A go command that hangs; the sleep child keeps stdout open after the shell is killed
+	binDir := t.TempDir()
+	writeTestFiles(t, binDir, map[string]string{"go": "#!/bin/sh\nsleep 5\n"})
+	if err := os.Chmod(filepath.Join(binDir, "go"), 0755); err != nil {
+		t.Fatalf("Failed to make fake go executable: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.21
+
+require github.com/sirupsen/logrus v1.9.3
+
+require (
+	golang.org/x/sys v0.15.0 // indirect
+)
+`,
+	})
+
+	scanner := NewGoScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{BuildToolTimeout: 200 * time.Millisecond})
+	start := time.Now()
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute should fall back to go.mod, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Expected the hung go command to be killed, scan took %s", elapsed)
+	}
+
+	dependencies := roots[0].Dependencies
+	if len(dependencies) != 2 {
+		t.Fatalf("Expected 2 modules from go.mod, got %+v", dependencies)
+	}
+	if dependencies[0].Name != "github.com/sirupsen/logrus" || dependencies[0].Scope != "runtime" {
+		t.Errorf("Expected direct logrus requirement, got %s (%s)", dependencies[0].Name, dependencies[0].Scope)
+	}
+	if dependencies[1].Name != "golang.org/x/sys" || dependencies[1].Version != "v0.15.0" || dependencies[1].Scope != "indirect" {
+		t.Errorf("Expected indirect x/sys v0.15.0, got %s %s (%s)", dependencies[1].Name, dependencies[1].Version, dependencies[1].Scope)
+	}
+}
+
+func TestGoScanner_ScanExecute_Workspace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go command is a shell script")
+	}
+
+	// go list fails, so every module falls back to its go.mod requirements
+	binDir := t.TempDir()
+	writeTestFiles(t, binDir, map[string]string{"go": "#!/bin/sh\nexit 1\n"})
+	if err := os.Chmod(filepath.Join(binDir, "go"), 0755); err != nil {
+		t.Fatalf("Failed to make fake go executable: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"go.work": `go 1.22
+
+use ./api // The public API
+use (
+	./tools/cli
+	"./missing"
+)
+`,
+		"api/go.mod":       "module example.com/api\n\ngo 1.22\n\nrequire github.com/sirupsen/logrus v1.9.3\n",
+		"tools/cli/go.mod": "module example.com/cli\n\ngo 1.21\n\nrequire github.com/spf13/cobra v1.8.0\n",
+	})
+
+	env := NewScannableEnvironment(tempDir, "")
+	scanner := NewGoScanner(env, &config.ScanConfig{})
+	if err := scanner.FileFind(); err != nil {
+		t.Errorf("Expected go.work to be found, got %v", err)
+	}
+	if !scanner.IsApplicable() {
+		t.Error("Expected a workspace without go.mod to be applicable")
+	}
+
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("Expected one root per workspace module, got %d", len(roots))
+	}
+
+	expected := []struct{ module, goVersion, dependency string }{
+		{"example.com/api", "1.22", "github.com/sirupsen/logrus"},
+		{"example.com/cli", "1.21", "github.com/spf13/cobra"},
+	}
+	for i, want := range expected {
+		root := roots[i]
+		if root.ProjectName != want.module || root.ProjectVersion != want.goVersion || root.BuildTool != "go" {
+			t.Errorf("Expected root %s (%s), got %s (%s)", want.module, want.goVersion, root.ProjectName, root.ProjectVersion)
+		}
+		if len(root.Dependencies) != 1 || root.Dependencies[0].Name != want.dependency {
+			t.Errorf("Expected %s to require %s, got %+v", want.module, want.dependency, root.Dependencies)
+		}
+	}
+
+	// Without go.work the single module behavior is unchanged
+	roots, err = NewGoScanner(NewScannableEnvironment(filepath.Join(tempDir, "api"), ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil || len(roots) != 1 || roots[0].ProjectName != "example.com/api" {
+		t.Errorf("Expected a single root for a module, got %+v (%v)", roots, err)
+	}
+}
+
+func TestGoScanner_goListCommand(t *testing.T) {
+	t.Setenv("GOPRIVATE", "example.com/private")
+	env := NewScannableEnvironment(t.TempDir(), "")
+
+	cmd := NewGoScanner(env, &config.ScanConfig{}).goListCommand(context.Background())
+	if cmd.Env != nil {
+		t.Errorf("Expected the environment to be inherited unchanged by default, got %v", cmd.Env)
+	}
+
+	cfg := &config.ScanConfig{GoProxy: "https://proxy.example.com,direct", GoFlags: "-mod=mod"}
+	cmd = NewGoScanner(env, cfg).goListCommand(context.Background())
+	for _, want := range []string{"GOPROXY=https://proxy.example.com,direct", "GOFLAGS=-mod=mod", "GOPRIVATE=example.com/private"} {
+		if !slices.Contains(cmd.Env, want) {
+			t.Errorf("Expected %s in the go list environment", want)
+		}
+	}
+	// exec uses the last value of a duplicated variable, so the flags must follow os.Environ
+	if last := cmd.Env[len(cmd.Env)-1]; last != "GOFLAGS=-mod=mod" {
+		t.Errorf("Expected the configured variables to override the inherited ones, got %s last", last)
+	}
+}
+
 // Test NPM Scanner
 func TestNpmScanner_ExeFind(t *testing.T) {
 	env := NewScannableEnvironment("/tmp", "")
@@ -181,6 +432,9 @@ func TestNpmScanner_parsePackageJson(t *testing.T) {
 	},
 	"peerDependencies": {
 		"react": "^18.2.0"
+	},
+	"optionalDependencies": {
+		"fsevents": "^2.3.2"
 	}
 }`
 	err := os.WriteFile(packageJsonFile, []byte(packageJsonContent), 0644)
@@ -200,9 +454,9 @@ func TestNpmScanner_parsePackageJson(t *testing.T) {
 		t.Errorf("Expected project version '1.0.0', got %s", version)
 	}
 
-	// Check dependencies count (2 deps + 1 dev + 1 peer = 4)
-	if len(dependencies) != 4 {
-		t.Errorf("Expected 4 dependencies, got %d", len(dependencies))
+	// Check dependencies count (2 deps + 1 dev + 1 peer + 1 optional = 5)
+	if len(dependencies) != 5 {
+		t.Errorf("Expected 5 dependencies, got %d", len(dependencies))
 	}
 
 	// Check dependency types
@@ -220,6 +474,126 @@ func TestNpmScanner_parsePackageJson(t *testing.T) {
 	if depTypes["peer"] != 1 {
 		t.Errorf("Expected 1 peer dependency, got %d", depTypes["peer"])
 	}
+	if depTypes["optional"] != 1 {
+		t.Errorf("Expected 1 optional dependency, got %d", depTypes["optional"])
+	}
+}
+
+func TestNpmScanner_ScanExecute_Lerna(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"package.json":              `{"name": "monorepo", "private": true, "devDependencies": {"lerna": "^8.1.0"}}`,
+		"lerna.json":                `{"version": "independent", "packages": ["packages/*", "tools/cli"]}`,
+		"packages/api/package.json": `{"name": "@acme/api", "version": "1.2.0", "dependencies": {"express": "4.18.2"}}`,
+		"packages/docs/README.md":   "Not a package",
+		"tools/cli/package.json":    `{"name": "@acme/cli", "version": "0.3.0", "dependencies": {"commander": "11.1.0"}}`,
+	})
+
+	scanner := NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 3 {
+		t.Fatalf("Expected the root and both lerna packages, got %d roots", len(roots))
+	}
+
+	expected := []struct{ name, version, dependency string }{
+		{"monorepo", "unknown", "lerna"},
+		{"@acme/api", "1.2.0", "express"},
+		{"@acme/cli", "0.3.0", "commander"},
+	}
+	for i, want := range expected {
+		root := roots[i]
+		if root.ProjectName != want.name || root.ProjectVersion != want.version || root.BuildTool != "npm" {
+			t.Errorf("Expected root %s (%s), got %s (%s)", want.name, want.version, root.ProjectName, root.ProjectVersion)
+		}
+		if len(root.Dependencies) != 1 || root.Dependencies[0].Name != want.dependency {
+			t.Errorf("Expected %s to depend on %s, got %+v", want.name, want.dependency, root.Dependencies)
+		}
+	}
+
+	projects, err := scanner.GetProjectInfo()
+	if err != nil || len(projects) != 3 || projects[2].Name != "@acme/cli" {
+		t.Errorf("Expected project info for the root and both packages, got %+v (%v)", projects, err)
+	}
+
+	// Recursive detection registers the packages itself
+	roots, err = NewNpmScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{Recursive: true}).ScanExecute()
+	if err != nil || len(roots) != 1 {
+		t.Errorf("Expected only the root package with --recursive, got %d roots (%v)", len(roots), err)
+	}
+}
+
+func TestMonorepoPackagePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected []string
+	}{
+		{
+			name:     "no monorepo tool",
+			files:    map[string]string{"package.json": `{"workspaces": ["packages/*"]}`},
+			expected: nil,
+		},
+		{
+			name:     "lerna defaults to packages",
+			files:    map[string]string{"lerna.json": `{"version": "1.0.0"}`},
+			expected: []string{"packages/*"},
+		},
+		{
+			name: "lerna using workspaces",
+			files: map[string]string{
+				"lerna.json":   `{"useWorkspaces": true}`,
+				"package.json": `{"workspaces": {"packages": ["libs/*"]}}`,
+			},
+			expected: []string{"libs/*"},
+		},
+		{
+			name: "nx projects",
+			files: map[string]string{
+				"nx.json":        `{"npmScope": "acme"}`,
+				"workspace.json": `{"version": 2, "projects": {"web": "apps/web", "ui": {"root": "libs/ui"}}}`,
+			},
+			expected: []string{"apps/web", "libs/ui"},
+		},
+		{
+			name: "turborepo with pnpm workspaces",
+			files: map[string]string{
+				"turbo.json":          `{"pipeline": {}}`,
+				"pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n  - '!apps/legacy'\n",
+			},
+			expected: []string{"apps/*", "!apps/legacy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tt.files)
+			if _, patterns := monorepoPackagePatterns(dir); !slices.Equal(patterns, tt.expected) {
+				t.Errorf("Expected patterns %v, got %v", tt.expected, patterns)
+			}
+		})
+	}
+}
+
+func TestExpandPackagePatterns(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"package.json":                      `{}`,
+		"apps/web/package.json":             `{}`,
+		"apps/legacy/package.json":          `{}`,
+		"libs/ui/button/package.json":       `{}`,
+		"libs/ui/node_modules/package.json": `{}`,
+		"libs/README.md":                    "",
+	})
+
+	got := expandPackagePatterns(dir, []string{"apps/*", "!apps/legacy", "libs/**", "."})
+	expected := []string{filepath.Join(dir, "apps", "web"), filepath.Join(dir, "libs", "ui", "button")}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected packages %v, got %v", expected, got)
+	}
 }
 
 // Test Pipenv Scanner
@@ -525,6 +899,209 @@ dependencies {
 	}
 }
 
+func TestGradleScanner_ScanExecute_ComposedName(t *testing.T) {
+	tempDir := filepath.Join(t.TempDir(), "myapp")
+	writeTestFiles(t, tempDir, map[string]string{
+		"build.gradle": `group = 'com.example'
+version = '1.0.0'
+
+dependencies {
+    implementation 'com.google.guava:guava:31.1-jre'
+}`,
+	})
+
+	scanner := NewGradleScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if name := roots[0].ProjectName; name != "com.example:myapp" {
+		t.Errorf("Expected name composed from group and directory 'com.example:myapp', got %s", name)
+	}
+}
+
+func TestGradleScanner_ScanExecute_Subprojects(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"settings.gradle": `rootProject.name = 'platform'
+include 'app', ':lib'
+project(':lib').projectDir = new File(settingsDir, 'libs/lib')
+`,
+		"build.gradle": `version = '2.1.0'
+dependencies {
+    implementation 'org.slf4j:slf4j-api:2.0.9'
+}`,
+		"app/build.gradle": `dependencies {
+    implementation 'com.google.guava:guava:32.1.2-jre'
+}`,
+		"libs/lib/build.gradle": `version = '0.3.0'
+dependencies {
+    implementation 'org.apache.commons:commons-lang3:3.13.0'
+    testImplementation 'junit:junit:4.13.2'
+}`,
+	})
+
+	scanner := NewGradleScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 3 {
+		t.Fatalf("Expected root project plus 2 subprojects, got %d roots: %+v", len(roots), roots)
+	}
+
+	expected := []struct {
+		name, version string
+		dependencies  int
+	}{
+		{"platform", "2.1.0", 1},
+		{"app", "2.1.0", 1},
+		{"lib", "0.3.0", 2},
+	}
+	for i, want := range expected {
+		root := roots[i]
+		if root.ProjectName != want.name || root.ProjectVersion != want.version || len(root.Dependencies) != want.dependencies {
+			t.Errorf("Root %d: expected %s@%s with %d dependencies, got %s@%s with %d",
+				i, want.name, want.version, want.dependencies, root.ProjectName, root.ProjectVersion, len(root.Dependencies))
+		}
+	}
+}
+
+func TestParseGradleSettings_MultiLineInclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		expected []string
+	}{
+		{"kotlin parentheses", "rootProject.name = \"platform\"\ninclude(\n    \":app\",\n    \":lib:core\", // shared code\n)\n", []string{":app", ":lib:core"}},
+		{"groovy trailing commas", "include ':app',\n        ':lib'\ninclude ':tools'\n", []string{":app", ":lib", ":tools"}},
+		{"unterminated", "include(\n    \":app\",\n", []string{":app"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			writeTestFiles(t, tempDir, map[string]string{"settings.gradle.kts": tt.settings})
+
+			settings, err := parseGradleSettings(tempDir)
+			if err != nil {
+				t.Fatalf("parseGradleSettings failed: %v", err)
+			}
+			var paths []string
+			for _, subproject := range settings.Subprojects {
+				paths = append(paths, subproject.Path)
+			}
+			if !slices.Equal(paths, tt.expected) {
+				t.Errorf("Expected subprojects %v, got %v", tt.expected, paths)
+			}
+		})
+	}
+}
+
+func TestGradleScanner_ScanExecute_Android(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"gradle/libs.versions.toml": `[versions]
+agp = "8.1.4"
+core = "1.12.0"
+hilt = "2.48"
+compose = { strictly = "1.5.4" }
+
+[libraries]
+androidx-core-ktx = { group = "androidx.core", name = "core-ktx", version.ref = "core" }
+hilt-android = { module = "com.google.dagger:hilt-android", version.ref = "hilt" }
+hilt-compiler = { module = "com.google.dagger:hilt-compiler", version.ref = "hilt" }
+compose-ui = { module = "androidx.compose.ui:ui", version.ref = "compose" }
+compose-material = "androidx.compose.material:material:1.5.4"
+junit = "junit:junit:4.13.2"
+
+[bundles]
+compose = ["compose-ui", "compose_material"]
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+`,
+		"build.gradle.kts": `plugins {
+    alias(libs.plugins.android.application)
+    id("org.jetbrains.kotlin.android") version "1.9.20"
+    kotlin("kapt") version "1.9.20"
+    id("com.google.devtools.ksp")
+}
+
+android {
+    namespace = "com.example.app"
+    compileSdk = 34
+}
+
+dependencies {
+    implementation(libs.androidx.core.ktx)
+    implementation(libs.hilt.android)
+    implementation(libs.bundles.compose)
+    implementation(libs.unknown.library)
+    kapt(libs.hilt.compiler)
+    ksp("androidx.room:room-compiler:2.6.0")
+    annotationProcessor 'com.google.auto.value:auto-value:1.10.4'
+    testImplementation(libs.junit)
+}`,
+	})
+
+	scanner := NewGradleScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots[0].Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", roots[0].Warnings)
+	}
+
+	var got []string
+	for _, dep := range roots[0].Dependencies {
+		got = append(got, dep.ID.Group+":"+dep.Name+":"+dep.Version+" "+dep.Scope)
+	}
+	expected := []string{
+		"com.android.application:com.android.application.gradle.plugin:8.1.4 plugin",
+		"org.jetbrains.kotlin.android:org.jetbrains.kotlin.android.gradle.plugin:1.9.20 plugin",
+		"org.jetbrains.kotlin.kapt:org.jetbrains.kotlin.kapt.gradle.plugin:1.9.20 plugin",
+		"androidx.core:core-ktx:1.12.0 runtime",
+		"com.google.dagger:hilt-android:2.48 runtime",
+		"androidx.compose.ui:ui:1.5.4 runtime",
+		"androidx.compose.material:material:1.5.4 runtime",
+		"com.google.dagger:hilt-compiler:2.48 annotationProcessor",
+		"androidx.room:room-compiler:2.6.0 annotationProcessor",
+		"com.google.auto.value:auto-value:1.10.4 annotationProcessor",
+		"junit:junit:4.13.2 test",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected dependencies:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestGradleScanner_ScanExecute_ManySubprojects(t *testing.T) {
+	tempDir := t.TempDir()
+	settings := "rootProject.name = 'platform'\n"
+	files := map[string]string{"build.gradle": "version = '1.0.0'\n"}
+	for i := 0; i < 50; i++ {
+		settings += fmt.Sprintf("include 'module-%02d'\n", i)
+		files[fmt.Sprintf("module-%02d/build.gradle", i)] = fmt.Sprintf("dependencies {\n    implementation 'org.example:lib-%02d:1.%d.0'\n}\n", i, i)
+	}
+	files["settings.gradle"] = settings
+	writeTestFiles(t, tempDir, files)
+
+	scanner := NewGradleScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{ThreadNum: "8"})
+	roots, err := scanner.ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+	if len(roots) != 51 {
+		t.Fatalf("Expected root project plus 50 subprojects, got %d roots", len(roots))
+	}
+	for i, root := range roots[1:] {
+		if name := fmt.Sprintf("module-%02d", i); root.ProjectName != name || len(root.Dependencies) != 1 ||
+			root.Dependencies[0].Version != fmt.Sprintf("1.%d.0", i) {
+			t.Errorf("Root %d: expected %s with lib-%02d 1.%d.0, got %s %+v", i+1, name, i, i, root.ProjectName, root.Dependencies)
+		}
+	}
+}
+
 func TestGradleScanner_extractGradleValue(t *testing.T) {
 	env := NewScannableEnvironment("/tmp", "")
 	cfg := &config.ScanConfig{}
@@ -643,6 +1220,243 @@ func TestGradleScanner_parseGradleDependency(t *testing.T) {
 }
 
 // Integration tests for BuildScanner with new scanners
+// Test Cargo Scanner
+func TestCargoScanner_ScanExecute(t *testing.T) {
+	tempDir := t.TempDir()
+	cargoToml := `[package]
+name = "test-crate"
+version = "0.3.1"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+regex = "1.10.2" # inline comment
+tokio.workspace = true
+
+[dependencies.rand]
+version = "0.8.5"
+
+[dev-dependencies]
+criterion = "0.5"
+
+[build-dependencies]
+cc = "1.0"
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "Cargo.toml"), []byte(cargoToml), 0644); err != nil {
+		t.Fatalf("Failed to create Cargo.toml: %v", err)
+	}
+
+	env := NewScannableEnvironment(tempDir, "")
+	scanner := NewBuildScanner(env, &config.ScanConfig{})
+
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 1 {
+		t.Fatalf("Expected 1 dependency root for Cargo project, got %d", len(roots))
+	}
+
+	root := roots[0]
+	if root.BuildTool != "cargo" || root.ProjectName != "test-crate" || root.ProjectVersion != "0.3.1" {
+		t.Errorf("Unexpected Cargo root: %s %s %s", root.BuildTool, root.ProjectName, root.ProjectVersion)
+	}
+
+	expected := map[string]string{
+		"serde":     "1.0|runtime",
+		"regex":     "1.10.2|runtime",
+		"tokio":     "unknown|runtime",
+		"rand":      "0.8.5|runtime",
+		"criterion": "0.5|development",
+		"cc":        "1.0|build",
+	}
+	if len(root.Dependencies) != len(expected) {
+		t.Errorf("Expected %d dependencies, got %d", len(expected), len(root.Dependencies))
+	}
+	// Cargo scopes are reported raw; build dependencies normalize to development
+	for _, dep := range root.Dependencies {
+		if got := dep.Version + "|" + dep.RawScope; expected[dep.Name] != got {
+			t.Errorf("Dependency %s: expected %s, got %s", dep.Name, expected[dep.Name], got)
+		}
+	}
+}
+
+// Test Composer Scanner
+func TestComposerScanner_ScanExecute(t *testing.T) {
+	tempDir := t.TempDir()
+	composerJson := `{
+	"name": "acme/test-app",
+	"require": {
+		"php": ">=8.1",
+		"ext-json": "*",
+		"monolog/monolog": "^3.0"
+	},
+	"require-dev": {
+		"phpunit/phpunit": "^10.0"
+	}
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "composer.json"), []byte(composerJson), 0644); err != nil {
+		t.Fatalf("Failed to create composer.json: %v", err)
+	}
+
+	env := NewScannableEnvironment(tempDir, "")
+	scanner := NewBuildScanner(env, &config.ScanConfig{})
+
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 1 {
+		t.Fatalf("Expected 1 dependency root for Composer project, got %d", len(roots))
+	}
+
+	root := roots[0]
+	if root.BuildTool != "composer" || root.ProjectName != "acme/test-app" || root.ProjectVersion != "unknown" {
+		t.Errorf("Unexpected Composer root: %s %s %s", root.BuildTool, root.ProjectName, root.ProjectVersion)
+	}
+	if len(root.Dependencies) != 2 {
+		t.Fatalf("Expected 2 dependencies (platform requirements skipped), got %d", len(root.Dependencies))
+	}
+	for _, dep := range root.Dependencies {
+		switch dep.Name {
+		case "monolog/monolog":
+			if dep.Scope != "runtime" {
+				t.Errorf("Expected monolog/monolog to be runtime, got %s", dep.Scope)
+			}
+		case "phpunit/phpunit":
+			if dep.Scope != "development" {
+				t.Errorf("Expected phpunit/phpunit to be development, got %s", dep.Scope)
+			}
+		default:
+			t.Errorf("Unexpected dependency %s", dep.Name)
+		}
+	}
+}
+
+func TestComposerScanner_ScanExecute_LockLicenses(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"composer.json": `{"name": "acme/app", "require": {"monolog/monolog": "^3.0", "acme/private": "^1.0"}, "require-dev": {"phpunit/phpunit": "^10.0"}}`,
+		"composer.lock": `{
+	"packages": [
+		{"name": "monolog/monolog", "version": "3.5.0", "license": ["MIT"]},
+		{"name": "acme/private", "version": "1.2.0"}
+	],
+	"packages-dev": [
+		{"name": "phpunit/phpunit", "version": "10.5.0", "license": ["BSD-3-Clause", "MIT"]}
+	]
+}`,
+	})
+
+	roots, err := NewComposerScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"monolog/monolog": "MIT",
+		"phpunit/phpunit": "BSD-3-Clause OR MIT",
+		"acme/private":    "",
+	}
+	for _, dep := range roots[0].Dependencies {
+		if want, ok := expected[dep.Name]; !ok || dep.License != want {
+			t.Errorf("Expected %s to be licensed %q, got %q", dep.Name, want, dep.License)
+		}
+	}
+}
+
+// Test CMake Scanner
+func TestParseCMakeLists(t *testing.T) {
+	cmakeLists := `cmake_minimum_required(VERSION 3.16)
+project(demo VERSION 1.4.0 LANGUAGES CXX)
+
+find_package(Boost 1.81 REQUIRED COMPONENTS system) # comment
+find_package(Threads REQUIRED)
+
+include(FetchContent)
+FetchContent_Declare(
+  googletest
+  GIT_REPOSITORY https://github.com/google/googletest.git
+  GIT_TAG        v1.14.0
+)
+ExternalProject_Add(zlib_ext
+  URL https://zlib.net/zlib-1.3.tar.gz
+)
+`
+	name, version, dependencies := parseCMakeLists(cmakeLists)
+	if name != "demo" || version != "1.4.0" {
+		t.Errorf("Expected project demo 1.4.0, got %s %s", name, version)
+	}
+
+	expected := map[string]string{
+		"Boost":      "1.81",
+		"Threads":    "unknown",
+		"googletest": "v1.14.0",
+		"zlib_ext":   "1.3",
+	}
+	if len(dependencies) != len(expected) {
+		t.Fatalf("Expected %d dependencies, got %d", len(expected), len(dependencies))
+	}
+	for _, dep := range dependencies {
+		if dep.Type != "cmake" || expected[dep.Name] != dep.Version {
+			t.Errorf("Dependency %s: expected cmake %s, got %s %s", dep.Name, expected[dep.Name], dep.Type, dep.Version)
+		}
+	}
+}
+
+func TestCMakeScanner_ScanExecute_Vcpkg(t *testing.T) {
+	tempDir := t.TempDir()
+	vcpkgJson := `{
+	"name": "demo",
+	"version": "2.0.0",
+	"dependencies": [
+		"fmt",
+		{ "name": "boost-asio", "version>=": "1.83.0" }
+	],
+	"overrides": [
+		{ "name": "fmt", "version": "10.1.1" }
+	]
+}`
+	cmakeLists := "project(demo)\nfind_package(fmt CONFIG REQUIRED)\nfind_package(OpenSSL 3.0)\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "vcpkg.json"), []byte(vcpkgJson), 0644); err != nil {
+		t.Fatalf("Failed to create vcpkg.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "CMakeLists.txt"), []byte(cmakeLists), 0644); err != nil {
+		t.Fatalf("Failed to create CMakeLists.txt: %v", err)
+	}
+
+	env := NewScannableEnvironment(tempDir, "")
+	scanner := NewBuildScanner(env, &config.ScanConfig{})
+
+	roots, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(roots) != 1 {
+		t.Fatalf("Expected 1 dependency root for CMake project, got %d", len(roots))
+	}
+
+	root := roots[0]
+	if root.BuildTool != "cmake" || root.ProjectName != "demo" || root.ProjectVersion != "2.0.0" {
+		t.Errorf("Unexpected CMake root: %s %s %s", root.BuildTool, root.ProjectName, root.ProjectVersion)
+	}
+
+	// fmt is reported once, from vcpkg.json with its precise version
+	expected := map[string]string{
+		"fmt":        "vcpkg|10.1.1",
+		"boost-asio": "vcpkg|1.83.0",
+		"OpenSSL":    "cmake|3.0",
+	}
+	if len(root.Dependencies) != len(expected) {
+		t.Fatalf("Expected %d dependencies, got %v", len(expected), root.Dependencies)
+	}
+	for _, dep := range root.Dependencies {
+		if got := dep.Type + "|" + dep.Version; expected[dep.Name] != got {
+			t.Errorf("Dependency %s: expected %s, got %s", dep.Name, expected[dep.Name], got)
+		}
+	}
+}
+
 func TestBuildScanner_DetectBuildTools_AllTypes(t *testing.T) {
 	tempDir := t.TempDir()
 	env := NewScannableEnvironment(tempDir, "")
@@ -786,3 +1600,161 @@ dependencies {
 		_, _, _, _ = scanner.parseBuildGradle()
 	}
 }
+
+func TestMavenScanner_ScanExecute_Classifier(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"pom.xml": `<project>
+    <groupId>com.example</groupId>
+    <artifactId>demo</artifactId>
+    <version>1.0.0</version>
+    <dependencies>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>native-lib</artifactId>
+            <version>2.1</version>
+            <classifier>linux-x86_64</classifier>
+        </dependency>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>webapp</artifactId>
+            <version>2.1</version>
+            <type>war</type>
+        </dependency>
+    </dependencies>
+</project>`,
+	})
+
+	roots, err := NewMavenScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	deps := roots[0].Dependencies
+	if len(deps) != 2 {
+		t.Fatalf("Expected 2 dependencies, got %d", len(deps))
+	}
+	if deps[0].Classifier != "linux-x86_64" || deps[0].Type != "jar" {
+		t.Errorf("Expected native-lib jar with classifier linux-x86_64, got type %q classifier %q", deps[0].Type, deps[0].Classifier)
+	}
+	if deps[1].Type != "war" || deps[1].Classifier != "" {
+		t.Errorf("Expected webapp to keep its war type, got type %q classifier %q", deps[1].Type, deps[1].Classifier)
+	}
+}
+
+func TestMavenScanner_ScanExecute_VersionRanges(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"pom.xml": `<project>
+    <groupId>com.example</groupId>
+    <artifactId>demo</artifactId>
+    <version>1.0.0</version>
+    <dependencies>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>ranged</artifactId>
+            <version>[1.0,2.0)</version>
+        </dependency>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>open</artifactId>
+            <version>(,1.5]</version>
+        </dependency>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>plain</artifactId>
+            <version>3.2</version>
+        </dependency>
+    </dependencies>
+</project>`,
+	})
+
+	roots, err := NewMavenScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	deps := roots[0].Dependencies
+	if len(deps) != 3 {
+		t.Fatalf("Expected 3 dependencies, got %d", len(deps))
+	}
+	if ranged := deps[0]; ranged.RawVersion != "[1.0,2.0)" || ranged.Version != "1.0" || ranged.ID.Version != "1.0" {
+		t.Errorf("Expected range [1.0,2.0) recorded apart from version 1.0, got raw %q version %q", ranged.RawVersion, ranged.Version)
+	}
+	if open := deps[1]; open.RawVersion != "(,1.5]" || open.Version != "unknown" {
+		t.Errorf("Expected range (,1.5] with unknown version, got raw %q version %q", open.RawVersion, open.Version)
+	}
+	if plain := deps[2]; plain.RawVersion != "" || plain.Version != "3.2" {
+		t.Errorf("Expected plain version 3.2 without a raw version, got raw %q version %q", plain.RawVersion, plain.Version)
+	}
+}
+
+func TestMavenScanner_ScanExecute_BuildPlugins(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestFiles(t, tempDir, map[string]string{
+		"pom.xml": `<project>
+    <groupId>com.example</groupId>
+    <artifactId>demo</artifactId>
+    <version>1.0.0</version>
+    <dependencies>
+        <dependency>
+            <groupId>org.example</groupId>
+            <artifactId>lib</artifactId>
+            <version>2.1</version>
+        </dependency>
+    </dependencies>
+    <build>
+        <plugins>
+            <plugin>
+                <artifactId>maven-enforcer-plugin</artifactId>
+                <version>3.4.1</version>
+                <dependencies>
+                    <dependency>
+                        <groupId>org.codehaus.mojo</groupId>
+                        <artifactId>extra-enforcer-rules</artifactId>
+                        <version>1.8.0</version>
+                    </dependency>
+                </dependencies>
+            </plugin>
+            <plugin>
+                <groupId>org.codehaus.mojo</groupId>
+                <artifactId>exec-maven-plugin</artifactId>
+            </plugin>
+        </plugins>
+    </build>
+</project>`,
+	})
+
+	roots, err := NewMavenScanner(NewScannableEnvironment(tempDir, ""), &config.ScanConfig{}).ScanExecute()
+	if err != nil {
+		t.Fatalf("ScanExecute failed: %v", err)
+	}
+
+	deps := roots[0].Dependencies
+	if len(deps) != 3 {
+		t.Fatalf("Expected 1 dependency and 2 plugins, got %d", len(deps))
+	}
+	if deps[0].Name != "lib" || deps[0].Scope != "compile" {
+		t.Errorf("Expected lib with the compile scope first, got %s (%s)", deps[0].Name, deps[0].Scope)
+	}
+
+	enforcer := deps[1]
+	if enforcer.ID.Group != "org.apache.maven.plugins" || enforcer.Version != "3.4.1" || enforcer.Scope != "plugin" {
+		t.Errorf("Expected the enforcer plugin in the default group with the plugin scope, got %+v", enforcer)
+	}
+	if len(enforcer.Children) != 1 {
+		t.Fatalf("Expected the plugin dependency as a child, got %+v", enforcer.Children)
+	}
+	if rules := enforcer.Children[0]; rules.Name != "extra-enforcer-rules" || rules.Version != "1.8.0" || rules.Scope != "plugin" {
+		t.Errorf("Expected extra-enforcer-rules 1.8.0 with the plugin scope, got %s %s (%s)", rules.Name, rules.Version, rules.Scope)
+	}
+	if exec := deps[2]; exec.ID.Group != "org.codehaus.mojo" || exec.Version != "unknown" {
+		t.Errorf("Expected exec-maven-plugin with an unknown version, got %+v", exec)
+	}
+
+	// Plugins are build-time dependencies once scopes are normalized
+	roots = NewScopeNormalizer().Process(roots)
+	if scope := roots[0].Dependencies[1].Children[0].Scope; scope != ScopeDevelopment {
+		t.Errorf("Expected plugin dependencies to normalize to %s, got %s", ScopeDevelopment, scope)
+	}
+}
@@ -0,0 +1,201 @@
+package buildtools
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// tomlTable represents a table read from a TOML document
+type tomlTable struct {
+	Name   string            // Table header, e.g. "dependencies" or "package"
+	Array  bool              // True for [[array-of-tables]] entries
+	Values map[string]string // Raw values keyed by their (unquoted) key
+}
+
+// parseTomlFile reads the tables of a simple TOML file
+func parseTomlFile(path string) ([]tomlTable, error) {
+	file, err := utils.OpenTextFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file io.ReadCloser) {
+		_ = file.Close()
+	}(file)
+
+	return parseToml(file)
+}
+
+// parseToml reads tables and raw key/value pairs from TOML content.
+// It covers the subset used by build manifests and lockfiles: tables, arrays of
+// tables, strings, inline tables and (multi-line) arrays. Values are kept raw and
+// decoded on demand with tomlString, tomlArray and tomlInlineTable.
+func parseToml(reader io.Reader) ([]tomlTable, error) {
+	tables := []tomlTable{{Values: make(map[string]string)}}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var pendingKey string
+	var pendingValue strings.Builder
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripTomlComment(scanner.Text()))
+
+		// Continue a multi-line array or inline table
+		if pendingKey != "" {
+			pendingValue.WriteString(" ")
+			pendingValue.WriteString(line)
+			if tomlBalanced(pendingValue.String()) {
+				tables[len(tables)-1].Values[pendingKey] = strings.TrimSpace(pendingValue.String())
+				pendingKey = ""
+				pendingValue.Reset()
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]") {
+			name := strings.TrimSpace(line[2 : len(line)-2])
+			tables = append(tables, tomlTable{Name: name, Array: true, Values: make(map[string]string)})
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			tables = append(tables, tomlTable{Name: name, Values: make(map[string]string)})
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			continue
+		}
+		key := tomlString(strings.TrimSpace(line[:eq]))
+		value := strings.TrimSpace(line[eq+1:])
+
+		if !tomlBalanced(value) {
+			pendingKey = key
+			pendingValue.WriteString(value)
+			continue
+		}
+		tables[len(tables)-1].Values[key] = value
+	}
+
+	return tables, scanner.Err()
+}
+
+// stripTomlComment removes a trailing # comment that is not inside a string
+func stripTomlComment(line string) string {
+	var quote rune
+	for i, ch := range line {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// tomlBalanced reports whether all brackets and braces outside strings are closed
+func tomlBalanced(value string) bool {
+	depth := 0
+	var quote rune
+	for _, ch := range value {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[' || ch == '{':
+			depth++
+		case ch == ']' || ch == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// tomlString decodes a raw TOML string value, returning it unchanged when unquoted
+func tomlString(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if len(raw) >= 2 {
+		if (raw[0] == '"' && raw[len(raw)-1] == '"') || (raw[0] == '\'' && raw[len(raw)-1] == '\'') {
+			return raw[1 : len(raw)-1]
+		}
+	}
+	return raw
+}
+
+// tomlArray splits a raw TOML array into its raw top-level elements
+func tomlArray(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil
+	}
+	return splitTomlElements(raw[1 : len(raw)-1])
+}
+
+// tomlInlineTable decodes a raw TOML inline table into raw values keyed by name
+func tomlInlineTable(raw string) map[string]string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "{") || !strings.HasSuffix(raw, "}") {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, element := range splitTomlElements(raw[1 : len(raw)-1]) {
+		if eq := strings.Index(element, "="); eq != -1 {
+			values[tomlString(element[:eq])] = strings.TrimSpace(element[eq+1:])
+		}
+	}
+	return values
+}
+
+// splitTomlElements splits comma-separated elements, ignoring commas nested in strings, arrays or tables
+func splitTomlElements(content string) []string {
+	var elements []string
+	var current strings.Builder
+	depth := 0
+	var quote rune
+
+	flush := func() {
+		if element := strings.TrimSpace(current.String()); element != "" {
+			elements = append(elements, element)
+		}
+		current.Reset()
+	}
+
+	for _, ch := range content {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[' || ch == '{':
+			depth++
+		case ch == ']' || ch == '}':
+			depth--
+		case ch == ',' && depth == 0:
+			flush()
+			continue
+		}
+		current.WriteRune(ch)
+	}
+	flush()
+
+	return elements
+}
@@ -0,0 +1,71 @@
+package buildtools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseToml(t *testing.T) {
+	content := `# top comment
+title = "root"
+
+[build-system]
+requires = [
+    "setuptools>=61", # comment inside array
+    "wheel",
+]
+
+[[package]]
+name = "requests"
+dependencies = [{ name = "urllib3" }, { name = "idna" }]
+
+[[package]]
+name = 'idna'
+`
+	tables, err := parseToml(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("parseToml failed: %v", err)
+	}
+	if len(tables) != 4 {
+		t.Fatalf("Expected 4 tables (including root), got %d", len(tables))
+	}
+
+	if tomlString(tables[0].Values["title"]) != "root" {
+		t.Errorf("Expected root title, got %q", tables[0].Values["title"])
+	}
+
+	requires := tomlArray(tables[1].Values["requires"])
+	var decoded []string
+	for _, element := range requires {
+		decoded = append(decoded, tomlString(element))
+	}
+	if !reflect.DeepEqual(decoded, []string{"setuptools>=61", "wheel"}) {
+		t.Errorf("Unexpected multi-line array: %v", decoded)
+	}
+
+	if !tables[2].Array || tables[2].Name != "package" {
+		t.Errorf("Expected [[package]] array table, got %+v", tables[2])
+	}
+	deps := tomlArray(tables[2].Values["dependencies"])
+	if len(deps) != 2 || tomlString(tomlInlineTable(deps[0])["name"]) != "urllib3" {
+		t.Errorf("Unexpected inline table array: %v", deps)
+	}
+	if tomlString(tables[3].Values["name"]) != "idna" {
+		t.Errorf("Expected single-quoted name idna, got %q", tables[3].Values["name"])
+	}
+}
+
+func TestStripTomlComment(t *testing.T) {
+	tests := map[string]string{
+		`a = "b" # c`:     `a = "b" `,
+		`a = "b#c"`:       `a = "b#c"`,
+		`# only comment`:  ``,
+		`url = 'x#y' # z`: `url = 'x#y' `,
+	}
+	for input, expected := range tests {
+		if got := stripTomlComment(input); got != expected {
+			t.Errorf("stripTomlComment(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
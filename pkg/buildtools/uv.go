@@ -0,0 +1,153 @@
+package buildtools
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+// uvLock holds the resolution recorded in a uv.lock file
+type uvLock struct {
+	root     *uvPackage              // The project itself, nil when uv.lock has no editable or virtual package
+	packages map[string][]*uvPackage // Locked packages keyed by name, several when versions fork
+}
+
+// uvPackage is a [[package]] entry of uv.lock
+type uvPackage struct {
+	name         string
+	version      string
+	dependencies []uvEdge
+	optional     []uvEdge // [package.optional-dependencies], all extras
+	dev          []uvEdge // [package.dev-dependencies], all dependency groups
+}
+
+// uvEdge references a locked package; version is only set when the name alone is ambiguous
+type uvEdge struct {
+	name    string
+	version string
+}
+
+// parseUvLock parses a uv.lock file
+func parseUvLock(path string) (*uvLock, error) {
+	tables, err := parseTomlFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &uvLock{packages: make(map[string][]*uvPackage)}
+	var current *uvPackage
+	for _, table := range tables {
+		switch {
+		case table.Name == "package" && table.Array:
+			current = &uvPackage{
+				name:         tomlString(table.Values["name"]),
+				version:      tomlString(table.Values["version"]),
+				dependencies: parseUvEdges(table.Values["dependencies"]),
+			}
+			if current.name == "" {
+				current = nil
+				continue
+			}
+			lock.packages[current.name] = append(lock.packages[current.name], current)
+
+			// The project is installed from the lockfile directory
+			source := tomlInlineTable(table.Values["source"])
+			if lock.root == nil && (tomlString(source["editable"]) == "." || tomlString(source["virtual"]) == ".") {
+				lock.root = current
+			}
+		case table.Name == "package.optional-dependencies" && current != nil:
+			for _, group := range slices.Sorted(maps.Keys(table.Values)) {
+				current.optional = append(current.optional, parseUvEdges(table.Values[group])...)
+			}
+		case table.Name == "package.dev-dependencies" && current != nil:
+			for _, group := range slices.Sorted(maps.Keys(table.Values)) {
+				current.dev = append(current.dev, parseUvEdges(table.Values[group])...)
+			}
+		}
+	}
+
+	if len(lock.packages) == 0 {
+		return nil, fmt.Errorf("no packages found")
+	}
+	return lock, nil
+}
+
+// parseUvEdges decodes an array of { name = "...", version = "..." } inline tables
+func parseUvEdges(raw string) []uvEdge {
+	var edges []uvEdge
+	for _, element := range tomlArray(raw) {
+		inline := tomlInlineTable(element)
+		if name := tomlString(inline["name"]); name != "" {
+			edges = append(edges, uvEdge{name: name, version: tomlString(inline["version"])})
+		}
+	}
+	return edges
+}
+
+// dependencies builds the dependency tree of the project. Without a project package every
+// locked package is returned as a direct runtime dependency.
+func (ul *uvLock) dependencies() []model.Dependency {
+	var dependencies []model.Dependency
+	if ul.root == nil {
+		for _, name := range slices.Sorted(maps.Keys(ul.packages)) {
+			for _, pkg := range ul.packages[name] {
+				dependencies = append(dependencies, ul.dependency(pkg, ScopeRuntime, map[*uvPackage]bool{}))
+			}
+		}
+		return dependencies
+	}
+
+	for _, group := range []struct {
+		edges []uvEdge
+		scope string
+	}{
+		{ul.root.dependencies, ScopeRuntime},
+		{ul.root.optional, ScopeOptional},
+		{ul.root.dev, ScopeDevelopment},
+	} {
+		for _, edge := range group.edges {
+			if pkg := ul.resolve(edge); pkg != nil {
+				dependencies = append(dependencies, ul.dependency(pkg, group.scope, map[*uvPackage]bool{ul.root: true}))
+			}
+		}
+	}
+	return dependencies
+}
+
+// dependency converts a locked package and its transitive dependencies, skipping cycles
+func (ul *uvLock) dependency(pkg *uvPackage, scope string, visiting map[*uvPackage]bool) model.Dependency {
+	visiting[pkg] = true
+	defer delete(visiting, pkg)
+
+	dep := model.Dependency{
+		ID: &model.DependencyID{
+			Group:   "",
+			Name:    pkg.name,
+			Version: pkg.version,
+			Type:    "pip",
+		},
+		Name:    pkg.name,
+		Version: pkg.version,
+		Type:    "pip",
+		Scope:   scope,
+	}
+	for _, edge := range pkg.dependencies {
+		if child := ul.resolve(edge); child != nil && !visiting[child] {
+			dep.Children = append(dep.Children, ul.dependency(child, scope, visiting))
+		}
+	}
+	return dep
+}
+
+// resolve returns the locked package an edge refers to
+func (ul *uvLock) resolve(edge uvEdge) *uvPackage {
+	candidates := ul.packages[edge.name]
+	for _, pkg := range candidates {
+		if edge.version == "" || pkg.version == edge.version {
+			return pkg
+		}
+	}
+	return nil
+}
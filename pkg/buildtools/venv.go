@@ -0,0 +1,100 @@
+package buildtools
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
+)
+
+// venvMetadataPatterns locate the metadata of installed distributions in a virtualenv,
+// relative to its root, for POSIX and Windows layouts
+var venvMetadataPatterns = []string{
+	filepath.Join("lib", "python*", "site-packages", "*.dist-info", "METADATA"),
+	filepath.Join("Lib", "site-packages", "*.dist-info", "METADATA"),
+}
+
+// venvPath returns the virtualenv to read installed packages from: the configured path,
+// otherwise the active one from VIRTUAL_ENV
+func (ps *PipScanner) venvPath() string {
+	if ps.config.VenvPath != "" {
+		return ps.config.VenvPath
+	}
+	return os.Getenv("VIRTUAL_ENV")
+}
+
+// getVenvPackages reads the installed packages of a virtualenv from the dist-info metadata in
+// its site-packages, without invoking pip
+func (ps *PipScanner) getVenvPackages(venv string) ([]model.Dependency, error) {
+	seen := make(map[string]bool)
+	var dependencies []model.Dependency
+	for _, pattern := range venvMetadataPatterns {
+		matches, err := filepath.Glob(filepath.Join(venv, pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, metadataPath := range matches {
+			name, version, err := parseDistInfoMetadata(metadataPath)
+			if err != nil || name == "" {
+				ps.log.Debugf("Skipping unreadable package metadata %s: %v", metadataPath, err)
+				continue
+			}
+			// Case-insensitive file systems match both layouts
+			if key := normalizePipName(name); !seen[key] {
+				seen[key] = true
+				dependencies = append(dependencies, model.Dependency{
+					ID: &model.DependencyID{
+						Group:   "",
+						Name:    name,
+						Version: version,
+						Type:    "pip",
+					},
+					Name:    name,
+					Version: version,
+					Type:    "pip",
+					Scope:   "runtime",
+				})
+			}
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return nil, fmt.Errorf("no installed packages found in virtualenv %s", venv)
+	}
+	return dependencies, nil
+}
+
+// parseDistInfoMetadata reads the Name and Version headers of a dist-info METADATA file
+func parseDistInfoMetadata(path string) (string, string, error) {
+	file, err := utils.OpenTextFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	var name, version string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // The headers end at the first blank line, the description follows
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Version":
+			version = strings.TrimSpace(value)
+		}
+	}
+
+	return name, version, scanner.Err()
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Common authentication misconfigurations, matched with errors.Is
+var (
+	ErrAuthEndpointNotFound = errors.New("auth endpoint not found, check the server URL and base path")
+	ErrUnexpectedHTML       = errors.New("server returned HTML instead of JSON, likely a proxy or login page")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+)
+
+// AuthError describes a failed login or token verification. Cause is one of the common
+// misconfigurations above, or nil when the failure was not recognized.
+type AuthError struct {
+	Operation  string // "login" or "token verification"
+	StatusCode int
+	Body       string
+	Cause      error
+}
+
+// Error returns the diagnosis, falling back to the raw response body
+func (e *AuthError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s failed with status %d: %v", e.Operation, e.StatusCode, e.Cause)
+	}
+	return fmt.Sprintf("%s failed with status %d: %s", e.Operation, e.StatusCode, e.Body)
+}
+
+// Unwrap returns the recognized cause
+func (e *AuthError) Unwrap() error {
+	return e.Cause
+}
+
+// checkAuthResponse returns an *AuthError unless resp is a successful, non-HTML response.
+// Transient failures have already been retried by the client when this is called.
+func checkAuthResponse(operation string, resp *resty.Response) error {
+	var cause error
+	switch {
+	case resp.StatusCode() == http.StatusNotFound:
+		cause = ErrAuthEndpointNotFound
+	case resp.StatusCode() == http.StatusUnauthorized:
+		cause = ErrInvalidCredentials
+	case isHTMLResponse(resp):
+		cause = ErrUnexpectedHTML
+	case resp.StatusCode() == http.StatusOK:
+		return nil
+	}
+	return &AuthError{Operation: operation, StatusCode: resp.StatusCode(), Body: resp.String(), Cause: cause}
+}
+
+// isHTMLResponse reports whether a response carries an HTML page rather than an API reply
+func isHTMLResponse(resp *resty.Response) bool {
+	if strings.Contains(strings.ToLower(resp.Header().Get("Content-Type")), "text/html") {
+		return true
+	}
+	body := strings.ToLower(strings.TrimSpace(resp.String()))
+	return strings.HasPrefix(body, "<!doctype html") || strings.HasPrefix(body, "<html")
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/craftslab/cleansource-sca-cli/internal/logger"
 	"github.com/craftslab/cleansource-sca-cli/internal/model"
+	"github.com/craftslab/cleansource-sca-cli/internal/utils"
 )
 
 // RemotingClient handles communication with the remote server
@@ -25,20 +28,86 @@ type RemotingClient struct {
 	log       *logrus.Logger
 	authToken string
 	cookies   []*http.Cookie
+
+	// Sent with every scan upload of this run, so the server can recognize retries of one upload
+	idempotencyKey string
 }
 
 // NewRemotingClient creates a new remoting client
 func NewRemotingClient(serverURL string) *RemotingClient {
 	client := resty.New()
 	client.SetTimeout(30 * time.Minute) // Long timeout for file uploads
-	client.SetRetryCount(3)
-	client.SetRetryWaitTime(5 * time.Second)
+	client.AddRetryCondition(isRetryable)
+
+	rc := &RemotingClient{
+		client:         client,
+		serverURL:      serverURL,
+		log:            logger.GetLogger(),
+		idempotencyKey: newIdempotencyKey(),
+	}
+	rc.SetRetryPolicy(DefaultRetryPolicy())
+	rc.SetTransportPolicy(DefaultTransportPolicy())
+
+	return rc
+}
+
+// newIdempotencyKey returns a random (version 4) UUID
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// IdempotencyKey returns the key sent as the Idempotency-Key header of scan uploads
+func (rc *RemotingClient) IdempotencyKey() string {
+	return rc.idempotencyKey
+}
+
+// SetRetryPolicy configures exponential backoff with jitter for transient failures
+func (rc *RemotingClient) SetRetryPolicy(policy RetryPolicy) {
+	rc.client.SetRetryCount(policy.MaxRetries)
+	rc.client.SetRetryWaitTime(policy.WaitTime)
+	rc.client.SetRetryMaxWaitTime(policy.MaxWaitTime)
+	rc.client.SetRetryAfter(func(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+		return policy.waitTime(resp), nil
+	})
+}
+
+// SetUploadRate limits the rate at which request bodies, such as fingerprint and archive
+// uploads, are sent. A rate of zero or less leaves uploads unlimited.
+func (rc *RemotingClient) SetUploadRate(bytesPerSecond int64) {
+	if bytesPerSecond <= 0 {
+		return
+	}
+	base := rc.client.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rc.client.SetTransport(&throttledTransport{base: base, bytesPerSecond: bytesPerSecond})
+}
+
+// HealthCheck checks that the server is reachable and reports itself healthy
+func (rc *RemotingClient) HealthCheck() error {
+	resp, err := rc.client.R().
+		Get(rc.serverURL + "/api/health")
+
+	if err != nil {
+		return fmt.Errorf("health check request failed: %w", err)
+	}
 
-	return &RemotingClient{
-		client:    client,
-		serverURL: serverURL,
-		log:       logger.GetLogger(),
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("server unhealthy with status %d: %s", resp.StatusCode(), resp.String())
 	}
+
+	rc.log.Info("Server health check successful")
+	return nil
+}
+
+// SetBasicAuth sends username and password as an HTTP Basic Authorization header on every request
+func (rc *RemotingClient) SetBasicAuth(username, password string) {
+	rc.client.SetBasicAuth(username, password)
 }
 
 // Login authenticates with username and password
@@ -57,8 +126,8 @@ func (rc *RemotingClient) Login(username, password string) error {
 		return fmt.Errorf("login request failed: %w", err)
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("login failed with status %d: %s", resp.StatusCode(), resp.String())
+	if err := checkAuthResponse("login", resp); err != nil {
+		return err
 	}
 
 	// Store cookies for future requests
@@ -77,8 +146,8 @@ func (rc *RemotingClient) VerifyToken(token string) error {
 		return fmt.Errorf("token verification request failed: %w", err)
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("token verification failed with status %d: %s", resp.StatusCode(), resp.String())
+	if err := checkAuthResponse("token verification", resp); err != nil {
+		return err
 	}
 
 	rc.authToken = token
@@ -88,6 +157,15 @@ func (rc *RemotingClient) VerifyToken(token string) error {
 
 // UploadData uploads scan data to the server
 func (rc *RemotingClient) UploadData(uploadData *model.UploadData) (bool, error) {
+	result, err := rc.UploadScan(uploadData)
+	if err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
+
+// UploadScan uploads scan data to the server and returns the server's scan result
+func (rc *RemotingClient) UploadScan(uploadData *model.UploadData) (*model.ScanResult, error) {
 	rc.log.Info("Starting data upload...")
 
 	// Create multipart form
@@ -95,19 +173,21 @@ func (rc *RemotingClient) UploadData(uploadData *model.UploadData) (bool, error)
 	writer := multipart.NewWriter(&requestBody)
 
 	// Add files
-	if err := rc.addFileToForm(writer, "wfpFile", uploadData.WfpFile); err != nil {
-		return false, fmt.Errorf("failed to add wfp file: %w", err)
+	if uploadData.WfpFile != "" {
+		if err := rc.addFileToForm(writer, "wfpFile", uploadData.WfpFile); err != nil {
+			return nil, fmt.Errorf("failed to add wfp file: %w", err)
+		}
 	}
 
 	if uploadData.BuildFile != "" {
 		if err := rc.addFileToForm(writer, "buildFile", uploadData.BuildFile); err != nil {
-			return false, fmt.Errorf("failed to add build file: %w", err)
+			return nil, fmt.Errorf("failed to add build file: %w", err)
 		}
 	}
 
 	if uploadData.ArchiveFile != "" {
 		if err := rc.addFileToForm(writer, "archiveFile", uploadData.ArchiveFile); err != nil {
-			return false, fmt.Errorf("failed to add archive file: %w", err)
+			return nil, fmt.Errorf("failed to add archive file: %w", err)
 		}
 	}
 
@@ -115,37 +195,32 @@ func (rc *RemotingClient) UploadData(uploadData *model.UploadData) (bool, error)
 	metadata := rc.createUploadMetadata(uploadData)
 	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
-		return false, fmt.Errorf("failed to serialize metadata: %w", err)
+		return nil, fmt.Errorf("failed to serialize metadata: %w", err)
 	}
 
 	if err := writer.WriteField("metadata", string(metadataJSON)); err != nil {
-		return false, fmt.Errorf("failed to add metadata: %w", err)
+		return nil, fmt.Errorf("failed to add metadata: %w", err)
 	}
 
 	_ = writer.Close()
 
-	// Create request
+	// Create request; retries resend the same key so the server creates a single task
 	req := rc.client.R().
 		SetHeader("Content-Type", writer.FormDataContentType()).
+		SetHeader("Idempotency-Key", rc.idempotencyKey).
 		SetBody(requestBody.Bytes())
 
 	// Add authentication
-	if rc.authToken != "" {
-		req.SetHeader("Authorization", "Bearer "+rc.authToken)
-	} else if len(rc.cookies) > 0 {
-		for _, cookie := range rc.cookies {
-			req.SetCookie(cookie)
-		}
-	}
+	rc.authenticate(req)
 
 	// Send request
 	resp, err := req.Post(rc.serverURL + "/api/scan/upload")
 	if err != nil {
-		return false, fmt.Errorf("upload request failed: %w", err)
+		return nil, fmt.Errorf("upload request failed: %w", err)
 	}
 
 	if resp.StatusCode() != 200 {
-		return false, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode(), resp.String())
+		return nil, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode(), resp.String())
 	}
 
 	// Parse response
@@ -153,11 +228,118 @@ func (rc *RemotingClient) UploadData(uploadData *model.UploadData) (bool, error)
 	if err := json.Unmarshal(resp.Body(), &result); err != nil {
 		rc.log.Warnf("Failed to parse upload response: %v", err)
 		// Assume success if we can't parse the response but got 200
-		return true, nil
+		return &model.ScanResult{Success: true}, nil
 	}
 
 	rc.log.Infof("Upload completed. Task ID: %s", result.TaskID)
-	return result.Success, nil
+	return &result, nil
+}
+
+// FetchUnmatchedPaths returns the paths, relative to the task directory, of the files the
+// server could not identify from the fingerprints of a scan task
+func (rc *RemotingClient) FetchUnmatchedPaths(taskID string) ([]string, error) {
+	req := rc.client.R().
+		SetQueryParam("taskId", taskID)
+	rc.authenticate(req)
+
+	resp, err := req.Get(rc.serverURL + "/api/scan/unmatched")
+	if err != nil {
+		return nil, fmt.Errorf("unmatched paths request failed: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("fetching unmatched paths failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Paths []string `json:"paths"`
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse unmatched paths: %w", err)
+	}
+	return result.Paths, nil
+}
+
+// UploadArchive uploads a source archive for an existing scan task
+func (rc *RemotingClient) UploadArchive(taskID, archiveFile string) error {
+	var requestBody bytes.Buffer
+	writer := multipart.NewWriter(&requestBody)
+
+	if err := writer.WriteField("taskId", taskID); err != nil {
+		return fmt.Errorf("failed to add task ID: %w", err)
+	}
+	if err := rc.addFileToForm(writer, "archiveFile", archiveFile); err != nil {
+		return fmt.Errorf("failed to add archive file: %w", err)
+	}
+	_ = writer.Close()
+
+	req := rc.client.R().
+		SetHeader("Content-Type", writer.FormDataContentType()).
+		SetBody(requestBody.Bytes())
+	rc.authenticate(req)
+
+	resp, err := req.Post(rc.serverURL + "/api/scan/archive")
+	if err != nil {
+		return fmt.Errorf("archive upload request failed: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("archive upload failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	rc.log.Info("Archive upload completed")
+	return nil
+}
+
+// UploadWfpChunks uploads the chunks of a split WFP file for an existing scan task, in order
+// and with their 1-based index so the server can reassemble them
+func (rc *RemotingClient) UploadWfpChunks(taskID string, chunkFiles []string) error {
+	for i, chunkFile := range chunkFiles {
+		var requestBody bytes.Buffer
+		writer := multipart.NewWriter(&requestBody)
+
+		fields := [][2]string{
+			{"taskId", taskID},
+			{"chunkIndex", strconv.Itoa(i + 1)},
+			{"chunkCount", strconv.Itoa(len(chunkFiles))},
+		}
+		for _, field := range fields {
+			if err := writer.WriteField(field[0], field[1]); err != nil {
+				return fmt.Errorf("failed to add %s: %w", field[0], err)
+			}
+		}
+		if err := rc.addFileToForm(writer, "wfpFile", chunkFile); err != nil {
+			return fmt.Errorf("failed to add wfp chunk: %w", err)
+		}
+		_ = writer.Close()
+
+		req := rc.client.R().
+			SetHeader("Content-Type", writer.FormDataContentType()).
+			SetBody(requestBody.Bytes())
+		rc.authenticate(req)
+
+		resp, err := req.Post(rc.serverURL + "/api/scan/wfp-chunk")
+		if err != nil {
+			return fmt.Errorf("wfp chunk %d/%d upload request failed: %w", i+1, len(chunkFiles), err)
+		}
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("wfp chunk %d/%d upload failed with status %d: %s", i+1, len(chunkFiles), resp.StatusCode(), resp.String())
+		}
+	}
+
+	rc.log.Infof("Uploaded %d WFP chunks", len(chunkFiles))
+	return nil
+}
+
+// authenticate adds the token or session cookies obtained at login to a request
+func (rc *RemotingClient) authenticate(req *resty.Request) {
+	if rc.authToken != "" {
+		req.SetHeader("Authorization", "Bearer "+rc.authToken)
+	} else if len(rc.cookies) > 0 {
+		for _, cookie := range rc.cookies {
+			req.SetCookie(cookie)
+		}
+	}
 }
 
 // addFileToForm adds a file to the multipart form
@@ -194,6 +376,13 @@ func (rc *RemotingClient) createUploadMetadata(uploadData *model.UploadData) map
 		"buildDepend": cfg.BuildDepend,
 	}
 
+	if uploadData.ScanMode != "" {
+		metadata["scanMode"] = uploadData.ScanMode
+	}
+	if uploadData.Commit != "" {
+		metadata["commit"] = uploadData.Commit
+		metadata["dirty"] = uploadData.Dirty
+	}
 	if cfg.CustomProject != "" {
 		metadata["customProject"] = cfg.CustomProject
 	}
@@ -206,9 +395,52 @@ func (rc *RemotingClient) createUploadMetadata(uploadData *model.UploadData) map
 	if cfg.LicenseName != "" {
 		metadata["licenseName"] = cfg.LicenseName
 	}
+	if meta := cfg.UserMeta(); len(meta) > 0 {
+		// Nested so user keys cannot clash with the reserved keys above
+		metadata["userMeta"] = meta
+	}
 	if cfg.NotificationEmail != "" {
 		metadata["notificationEmail"] = cfg.NotificationEmail
 	}
+	if cfg.DedupWfp {
+		metadata["wfpFormat"] = "dedup"
+	}
+	if uploadData.DetectedProject != "" {
+		metadata["detectedProject"] = uploadData.DetectedProject
+	}
+	if len(uploadData.Languages) > 0 {
+		metadata["languages"] = uploadData.Languages
+	}
+	if licenses := uploadData.LicenseFiles; licenses != nil {
+		if len(licenses.ProjectLicenseFiles) > 0 {
+			metadata["projectLicenseFiles"] = licenses.ProjectLicenseFiles
+		}
+		if len(licenses.NoticeFiles) > 0 {
+			metadata["noticeFiles"] = licenses.NoticeFiles
+		}
+	}
+	if uploadData.WfpChunks > 0 {
+		// The fingerprints follow in numbered chunks for the server to reassemble
+		metadata["wfpChunks"] = uploadData.WfpChunks
+	}
+	if uploadData.WfpPartial || uploadData.WfpSince != nil {
+		// Only some files were fingerprinted; files missing from the WFP file are unchanged, not deleted
+		metadata["wfpPartial"] = true
+	}
+	if uploadData.WfpSince != nil {
+		metadata["wfpSince"] = uploadData.WfpSince.UTC().Format(time.RFC3339)
+	}
+	if uploadData.WfpUnchanged {
+		// The server reuses the fingerprints of the project's previous scan
+		metadata["wfpUnchanged"] = true
+	}
+	if cfg.ArchiveUnmatchedOnly {
+		// The source archive follows in a second phase with only the unmatched files
+		metadata["archiveMode"] = "unmatched"
+	}
+	if format := cfg.GetArchiveFormat(); format != utils.ArchiveFormatZip {
+		metadata["archiveFormat"] = format
+	}
 
 	return metadata
 }
@@ -219,13 +451,7 @@ func (rc *RemotingClient) VerifyLicense(licenseName string) error {
 		SetQueryParam("licenseName", licenseName)
 
 	// Add authentication
-	if rc.authToken != "" {
-		req.SetHeader("Authorization", "Bearer "+rc.authToken)
-	} else if len(rc.cookies) > 0 {
-		for _, cookie := range rc.cookies {
-			req.SetCookie(cookie)
-		}
-	}
+	rc.authenticate(req)
 
 	resp, err := req.Get(rc.serverURL + "/api/license/verify")
 	if err != nil {
@@ -246,13 +472,7 @@ func (rc *RemotingClient) VerifyEmail(email string) error {
 		SetQueryParam("email", email)
 
 	// Add authentication
-	if rc.authToken != "" {
-		req.SetHeader("Authorization", "Bearer "+rc.authToken)
-	} else if len(rc.cookies) > 0 {
-		for _, cookie := range rc.cookies {
-			req.SetCookie(cookie)
-		}
-	}
+	rc.authenticate(req)
 
 	resp, err := req.Get(rc.serverURL + "/api/email/verify")
 	if err != nil {
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestRemotingClient_HealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/health" {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"status":"UP"}`))
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	if err := rc.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck should succeed against a healthy server, got: %v", err)
+	}
+}
+
+func TestRemotingClient_HealthCheck_Unhealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("maintenance"))
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetRetryPolicy(RetryPolicy{MaxRetries: 0, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	err := rc.HealthCheck()
+	if err == nil {
+		t.Fatal("HealthCheck should fail against an unhealthy server")
+	}
+	if err.Error() != "server unhealthy with status 503: maintenance" {
+		t.Errorf("Expected specific error message, got: %s", err.Error())
+	}
+}
+
+func TestRemotingClient_AuthDiagnostics(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantCause   error
+	}{
+		{"wrong endpoint", http.StatusNotFound, "application/json", `{"error":"not found"}`, ErrAuthEndpointNotFound},
+		{"bad credentials", http.StatusUnauthorized, "application/json", `{"error":"unauthorized"}`, ErrInvalidCredentials},
+		{"proxy login page", http.StatusOK, "text/html; charset=utf-8", "<!DOCTYPE html><html><body>Sign in</body></html>", ErrUnexpectedHTML},
+		{"html without content type", http.StatusForbidden, "", "<html><body>Access denied</body></html>", ErrUnexpectedHTML},
+		{"unrecognized failure", http.StatusForbidden, "application/json", `{"error":"forbidden"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			rc := NewRemotingClient(server.URL)
+			for operation, err := range map[string]error{
+				"login":              rc.Login("user", "pass"),
+				"token verification": rc.VerifyToken("token"),
+			} {
+				var authErr *AuthError
+				if !errors.As(err, &authErr) {
+					t.Fatalf("Expected %s to fail with *AuthError, got %v", operation, err)
+				}
+				if authErr.Operation != operation || authErr.StatusCode != tt.status {
+					t.Errorf("Expected %s failure with status %d, got %+v", operation, tt.status, authErr)
+				}
+				if tt.wantCause != nil && !errors.Is(err, tt.wantCause) {
+					t.Errorf("Expected %s to fail with %v, got %v", operation, tt.wantCause, err)
+				}
+				if tt.wantCause == nil && authErr.Cause != nil {
+					t.Errorf("Expected no diagnosis for %s, got %v", operation, authErr.Cause)
+				}
+			}
+		})
+	}
+}
+
+func TestRemotingClient_SetBasicAuth(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/api/auth/login" {
+			t.Error("Basic auth should not post to the login endpoint")
+		}
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "testuser" || password != "testpass" {
+			t.Errorf("Expected Basic credentials on %s, got %q %q (present: %v)", r.URL.Path, username, password, ok)
+		}
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetBasicAuth("testuser", "testpass")
+
+	if err := rc.HealthCheck(); err != nil {
+		t.Errorf("HealthCheck failed: %v", err)
+	}
+	if err := rc.VerifyLicense("MIT"); err != nil {
+		t.Errorf("VerifyLicense failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestRemotingClient_FetchUnmatchedPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/scan/unmatched" || r.URL.Query().Get("taskId") != "task-1" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Expected token authentication, got %q", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`{"paths": ["src/a.go", "lib/b.c"]}`))
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.authToken = "test-token"
+
+	paths, err := rc.FetchUnmatchedPaths("task-1")
+	if err != nil {
+		t.Fatalf("FetchUnmatchedPaths failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "src/a.go" || paths[1] != "lib/b.c" {
+		t.Errorf("Expected unmatched paths from the server, got %v", paths)
+	}
+}
+
+func TestRemotingClient_UploadArchive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/scan/archive" {
+			t.Errorf("Unexpected request path: %s", r.URL.Path)
+		}
+		if r.FormValue("taskId") != "task-1" {
+			t.Errorf("Expected task ID task-1, got %q", r.FormValue("taskId"))
+		}
+		if _, header, err := r.FormFile("archiveFile"); err != nil || header.Filename != "project-unmatched.zip" {
+			t.Errorf("Expected archive file in the form, got %v", err)
+		}
+	}))
+	defer server.Close()
+
+	archiveFile := filepath.Join(t.TempDir(), "project-unmatched.zip")
+	if err := os.WriteFile(archiveFile, []byte("zip"), 0644); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	if err := NewRemotingClient(server.URL).UploadArchive("task-1", archiveFile); err != nil {
+		t.Errorf("UploadArchive failed: %v", err)
+	}
+}
+
+func TestRemotingClient_createUploadMetadata_UserMeta(t *testing.T) {
+	cfg := &config.ScanConfig{
+		ScanType: "source",
+		Meta:     []string{"branch=main", "build=42", "scanType=spoofed", "commit=abc=def"},
+	}
+
+	metadataJSON, err := json.Marshal(NewRemotingClient("https://example.com").createUploadMetadata(&model.UploadData{Config: cfg}))
+	if err != nil {
+		t.Fatalf("Failed to serialize metadata: %v", err)
+	}
+
+	var metadata struct {
+		ScanType string            `json:"scanType"`
+		UserMeta map[string]string `json:"userMeta"`
+	}
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+
+	if metadata.ScanType != "source" {
+		t.Errorf("User metadata must not override reserved keys, got scanType %q", metadata.ScanType)
+	}
+	expected := map[string]string{"branch": "main", "build": "42", "scanType": "spoofed", "commit": "abc=def"}
+	if len(metadata.UserMeta) != len(expected) {
+		t.Errorf("Expected userMeta %v, got %v", expected, metadata.UserMeta)
+	}
+	for key, value := range expected {
+		if metadata.UserMeta[key] != value {
+			t.Errorf("Expected userMeta[%s] = %q, got %q", key, value, metadata.UserMeta[key])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy configures retries of transient request failures
+type RetryPolicy struct {
+	MaxRetries  int           // Retries after the first attempt
+	WaitTime    time.Duration // Base wait before the first retry
+	MaxWaitTime time.Duration // Cap for backoff and Retry-After waits
+}
+
+// DefaultRetryPolicy returns the retry policy used by new clients
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:  3,
+		WaitTime:    time.Second,
+		MaxWaitTime: 30 * time.Second,
+	}
+}
+
+// isRetryable reports whether a request failed transiently: network errors, 429 and 5xx
+func isRetryable(resp *resty.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= 500
+}
+
+// backoff returns the wait before the given retry attempt (1-based). The wait doubles
+// per attempt and is capped at MaxWaitTime; jitter spreads it over the upper half of
+// that window, so the first retry waits between WaitTime and twice WaitTime.
+func (p RetryPolicy) backoff(attempt int, random func() float64) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	wait := math.Min(float64(p.MaxWaitTime), float64(p.WaitTime)*math.Exp2(float64(attempt)))
+	return time.Duration(wait/2 + random()*wait/2)
+}
+
+// waitTime returns the wait before retrying a response, honoring Retry-After on 429
+func (p RetryPolicy) waitTime(resp *resty.Response) time.Duration {
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(resp.Header().Get("Retry-After"), time.Now()); ok {
+			return min(wait, p.MaxWaitTime)
+		}
+	}
+	return p.backoff(resp.Request.Attempt, rand.Float64)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
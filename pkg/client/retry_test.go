@@ -0,0 +1,167 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/craftslab/cleansource-sca-cli/internal/config"
+	"github.com/craftslab/cleansource-sca-cli/internal/model"
+)
+
+func TestRetryPolicy_backoff_Grows(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, WaitTime: 100 * time.Millisecond, MaxWaitTime: time.Second}
+	upper := func() float64 { return 1 }
+
+	expected := []time.Duration{
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // capped
+		time.Second,
+	}
+	for i, want := range expected {
+		if got := policy.backoff(i+1, upper); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestRetryPolicy_backoff_Jitter(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, WaitTime: 100 * time.Millisecond, MaxWaitTime: time.Second}
+
+	lowest := policy.backoff(2, func() float64 { return 0 })
+	middle := policy.backoff(2, func() float64 { return 0.5 })
+	highest := policy.backoff(2, func() float64 { return 1 })
+
+	if lowest != 200*time.Millisecond || middle != 300*time.Millisecond || highest != 400*time.Millisecond {
+		t.Errorf("Expected jitter over [200ms, 400ms], got %v, %v, %v", lowest, middle, highest)
+	}
+	if lowest < policy.WaitTime {
+		t.Errorf("Jittered wait %v must not drop below the base wait %v", lowest, policy.WaitTime)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"3", 3 * time.Second, true},
+		{"Mon, 01 Jan 2024 12:00:10 GMT", 10 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %t, want %v, %t", tt.value, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestRemotingClient_RetryAfter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetRetryPolicy(RetryPolicy{MaxRetries: 2, WaitTime: 10 * time.Millisecond, MaxWaitTime: 5 * time.Second})
+
+	start := time.Now()
+	if err := rc.HealthCheck(); err != nil {
+		t.Fatalf("HealthCheck should succeed after retry, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected Retry-After of 1s to be honored, retried after %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestRemotingClient_NoRetryOnClientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetRetryPolicy(RetryPolicy{MaxRetries: 3, WaitTime: time.Millisecond, MaxWaitTime: 10 * time.Millisecond})
+
+	if err := rc.HealthCheck(); err == nil {
+		t.Error("HealthCheck should fail for a 400 response")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected no retries for a 400 response, got %d requests", got)
+	}
+}
+
+func TestRemotingClient_UploadScan_IdempotencyKey(t *testing.T) {
+	var requests int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"taskId":"task-1"}`))
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetRetryPolicy(RetryPolicy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: 10 * time.Millisecond})
+
+	result, err := rc.UploadScan(&model.UploadData{Config: &config.ScanConfig{}})
+	if err != nil {
+		t.Fatalf("UploadScan failed: %v", err)
+	}
+	if result.TaskID != "task-1" {
+		t.Errorf("Expected task ID task-1, got %q", result.TaskID)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected the upload to be retried once, got %d requests", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] || keys[0] != rc.IdempotencyKey() {
+		t.Errorf("Expected the same idempotency key %q on every attempt, got %v", rc.IdempotencyKey(), keys)
+	}
+	if NewRemotingClient(server.URL).IdempotencyKey() == rc.IdempotencyKey() {
+		t.Error("Expected every client to generate its own idempotency key")
+	}
+}
+
+func TestRemotingClient_RetryOnServerError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetRetryPolicy(RetryPolicy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: 10 * time.Millisecond})
+
+	if err := rc.HealthCheck(); err == nil {
+		t.Error("HealthCheck should fail when the server keeps returning 502")
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Expected 1 attempt plus 2 retries, got %d requests", got)
+	}
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// throttleRefillsPerSecond sets the bucket size to this fraction of the rate, so data is sent
+// in bursts of about 100ms worth of bandwidth
+const throttleRefillsPerSecond = 10
+
+// throttledTransport limits the rate at which request bodies are sent. Each attempt, including
+// retries, gets a fresh bucket wrapping its own body.
+type throttledTransport struct {
+	base           http.RoundTripper
+	bytesPerSecond int64
+}
+
+// RoundTrip sends the request with its body read through a token bucket
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(req.Context())
+		req.Body = newThrottledReader(req.Body, t.bytesPerSecond)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// throttledReader is a token bucket reader: tokens accrue at the configured rate up to the
+// bucket size, and each byte read spends one
+type throttledReader struct {
+	reader io.ReadCloser
+	rate   float64 // Tokens, i.e. bytes, added per second
+	size   float64 // Bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+// newThrottledReader wraps r to be read at no more than bytesPerSecond after an initial burst
+// of one bucket
+func newThrottledReader(r io.ReadCloser, bytesPerSecond int64) *throttledReader {
+	size := max(float64(bytesPerSecond)/throttleRefillsPerSecond, 1)
+	return &throttledReader{
+		reader: r,
+		rate:   float64(bytesPerSecond),
+		size:   size,
+		tokens: size,
+		last:   time.Now(),
+	}
+}
+
+// Read waits for enough tokens to fill p, up to the bucket size, then reads at most that many bytes
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return tr.reader.Read(p)
+	}
+
+	want := min(float64(len(p)), tr.size)
+	tr.refill()
+	if tr.tokens < want {
+		time.Sleep(time.Duration((want - tr.tokens) / tr.rate * float64(time.Second)))
+		tr.refill()
+	}
+
+	n, err := tr.reader.Read(p[:min(len(p), max(int(tr.tokens), 1))])
+	tr.tokens -= float64(n)
+	return n, err
+}
+
+// Close closes the underlying body
+func (tr *throttledReader) Close() error {
+	return tr.reader.Close()
+}
+
+// refill adds the tokens accrued since the last refill
+func (tr *throttledReader) refill() {
+	now := time.Now()
+	tr.tokens = min(tr.size, tr.tokens+now.Sub(tr.last).Seconds()*tr.rate)
+	tr.last = now
+}
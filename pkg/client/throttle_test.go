@@ -0,0 +1,52 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRemotingClient_SetUploadRate(t *testing.T) {
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	const size = 50 << 10
+	const rate = 100 << 10
+	archiveFile := filepath.Join(t.TempDir(), "project.zip")
+	if err := os.WriteFile(archiveFile, make([]byte, size), 0644); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	rc := NewRemotingClient(server.URL)
+	rc.SetUploadRate(rate)
+
+	start := time.Now()
+	if err := rc.UploadArchive("task-1", archiveFile); err != nil {
+		t.Fatalf("UploadArchive failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// After the initial burst of one bucket (a tenth of the rate), the rest is paced at the rate
+	minimum := time.Duration(float64(size-rate/throttleRefillsPerSecond) / rate * float64(time.Second))
+	if elapsed < minimum {
+		t.Errorf("Expected a throttled upload to take at least %v, took %v", minimum, elapsed)
+	}
+	if received < size {
+		t.Errorf("Expected the full body of at least %d bytes, server received %d", size, received)
+	}
+}
+
+func TestRemotingClient_SetUploadRate_Unlimited(t *testing.T) {
+	rc := NewRemotingClient("http://localhost")
+	rc.SetUploadRate(0)
+	if _, ok := rc.client.GetClient().Transport.(*throttledTransport); ok {
+		t.Error("Expected a zero rate to leave uploads unthrottled")
+	}
+}
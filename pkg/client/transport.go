@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportPolicy tunes connection reuse, so the many small requests of status polling and
+// verification share connections instead of paying a handshake each over high-latency links
+type TransportPolicy struct {
+	MaxIdleConns    int           // Idle connections kept open to the server
+	KeepAlive       time.Duration // Interval of TCP keep-alive probes on open connections
+	IdleConnTimeout time.Duration // Time an idle connection is kept before it is closed
+}
+
+// DefaultTransportPolicy returns the transport policy used by new clients
+func DefaultTransportPolicy() TransportPolicy {
+	return TransportPolicy{
+		MaxIdleConns:    10,
+		KeepAlive:       30 * time.Second,
+		IdleConnTimeout: 90 * time.Second,
+	}
+}
+
+// SetTransportPolicy replaces the transport of the client with one tuned by policy. It resets
+// the transport, so it has to be called before SetUploadRate wraps it.
+func (rc *RemotingClient) SetTransportPolicy(policy TransportPolicy) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: policy.KeepAlive,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.MaxIdleConns = policy.MaxIdleConns
+	// Every request goes to the one server, so all idle connections may be kept for its host
+	transport.MaxIdleConnsPerHost = policy.MaxIdleConns
+	transport.IdleConnTimeout = policy.IdleConnTimeout
+	rc.client.SetTransport(transport)
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRemotingClient_ReusesConnections(t *testing.T) {
+	var accepted int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status": "UP"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&accepted, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	rc := NewRemotingClient(server.URL)
+	for i := 0; i < 5; i++ {
+		if err := rc.HealthCheck(); err != nil {
+			t.Fatalf("Health check %d failed: %v", i, err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&accepted); got != 1 {
+		t.Errorf("Expected the requests to share a single connection, server accepted %d", got)
+	}
+}
+
+func TestRemotingClient_SetTransportPolicy(t *testing.T) {
+	rc := NewRemotingClient("http://localhost")
+	rc.SetTransportPolicy(TransportPolicy{MaxIdleConns: 4, KeepAlive: DefaultTransportPolicy().KeepAlive, IdleConnTimeout: DefaultTransportPolicy().IdleConnTimeout})
+
+	transport, ok := rc.client.GetClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", rc.client.GetClient().Transport)
+	}
+	if transport.MaxIdleConns != 4 || transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("Expected 4 idle connections in total and per host, got %d and %d", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.Proxy == nil {
+		t.Error("Expected the proxy settings of the default transport to be kept")
+	}
+}
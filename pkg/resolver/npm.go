@@ -0,0 +1,57 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NpmResolver resolves npm ranges against the packuments of an npm registry
+type NpmResolver struct {
+	registry string
+	client   *http.Client
+}
+
+// NewNpmResolver creates a resolver querying the npm registry at registryURL
+func NewNpmResolver(registryURL string, client *http.Client) *NpmResolver {
+	return &NpmResolver{registry: strings.TrimSuffix(registryURL, "/"), client: client}
+}
+
+// Resolve returns the newest version of an npm package satisfying a range. Ranges allowing any
+// version resolve to the latest dist-tag.
+func (nr *NpmResolver) Resolve(name, constraint string) (string, error) {
+	var packument struct {
+		DistTags map[string]string          `json:"dist-tags"`
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	// Scoped packages keep their @ but escape the slash: @types%2Fnode
+	if err := getJSON(nr.client, nr.registry+"/"+url.PathEscape(name), &packument); err != nil {
+		return "", err
+	}
+
+	if latest := packument.DistTags["latest"]; anyVersion(constraint) && latest != "" {
+		return latest, nil
+	}
+	r, err := parseNpmRange(constraint)
+	if err != nil {
+		return "", fmt.Errorf("unsupported npm range %q: %w", constraint, err)
+	}
+
+	var best string
+	var bestVersion semver
+	for version := range packument.Versions {
+		v, ok := parseSemver(version)
+		if !ok || !r.matches(v) {
+			continue
+		}
+		if best == "" || v.compare(bestVersion) > 0 {
+			best, bestVersion = version, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("%w: %s@%s", ErrNoMatch, name, constraint)
+	}
+	return best, nil
+}
@@ -0,0 +1,285 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Pattern matches the public PEP 440 versions considered by the resolver: a release
+// with optional pre, post and dev parts
+var pep440Pattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?\d*)?(?:[-_.]?(post|rev|r)[-_.]?(\d*))?(?:[-_.]?(dev)[-_.]?\d*)?$`)
+
+// pep440Version is a PEP 440 version reduced to what ordering stable releases needs
+type pep440Version struct {
+	release []int
+	post    int  // Post release number, -1 without one
+	pre     bool // Pre or development release, never chosen by the resolver
+}
+
+// parsePEP440 parses a PEP 440 version, ignoring its local label
+func parsePEP440(s string) (pep440Version, bool) {
+	s, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(s)), "+")
+	matches := pep440Pattern.FindStringSubmatch(s)
+	if matches == nil {
+		return pep440Version{}, false
+	}
+
+	v := pep440Version{post: -1, pre: matches[2] != "" || matches[5] != ""}
+	for _, part := range strings.Split(matches[1], ".") {
+		n, _ := strconv.Atoi(part)
+		v.release = append(v.release, n)
+	}
+	if matches[3] != "" {
+		v.post, _ = strconv.Atoi(matches[4])
+	}
+	return v, true
+}
+
+// compare orders versions by release, then post release, padding releases with zeros
+func (v pep440Version) compare(other pep440Version) int {
+	for i := 0; i < len(v.release) || i < len(other.release); i++ {
+		var a, b int
+		if i < len(v.release) {
+			a = v.release[i]
+		}
+		if i < len(other.release) {
+			b = other.release[i]
+		}
+		if a != b {
+			return a - b
+		}
+	}
+	return v.post - other.post
+}
+
+// hasPrefix reports whether the release of v starts with the given release segments
+func (v pep440Version) hasPrefix(prefix []int) bool {
+	for i, n := range prefix {
+		if i >= len(v.release) {
+			if n != 0 {
+				return false
+			}
+			continue
+		}
+		if v.release[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// pep440Specifier is one clause of a version specifier, such as >=2.0 or ==1.4.*
+type pep440Specifier struct {
+	op       string
+	version  pep440Version
+	raw      string
+	wildcard bool // ==1.4.* and !=1.4.* match by release prefix
+}
+
+// matches reports whether v satisfies the specifier
+func (s pep440Specifier) matches(v pep440Version, raw string) bool {
+	cmp := v.compare(s.version)
+	switch s.op {
+	case "===":
+		return raw == s.raw
+	case "==":
+		if s.wildcard {
+			return v.hasPrefix(s.version.release)
+		}
+		return cmp == 0
+	case "!=":
+		if s.wildcard {
+			return !v.hasPrefix(s.version.release)
+		}
+		return cmp != 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "~=":
+		// ~=2.2.1 means >=2.2.1 and ==2.2.*
+		return cmp >= 0 && v.hasPrefix(s.version.release[:len(s.version.release)-1])
+	}
+	return false
+}
+
+// parsePEP440Specifiers parses a comma separated specifier set such as >=2.0,<3 or ~=1.4.
+// A bare version is treated as ==.
+func parsePEP440Specifiers(s string) ([]pep440Specifier, error) {
+	var specifiers []pep440Specifier
+	for _, clause := range strings.Split(s, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		spec := pep440Specifier{op: "=="}
+		for _, op := range []string{"===", "~=", "==", "!=", ">=", "<=", ">", "<"} {
+			if rest, ok := strings.CutPrefix(clause, op); ok {
+				spec.op, clause = op, strings.TrimSpace(rest)
+				break
+			}
+		}
+		spec.raw = clause
+		if spec.op == "===" {
+			specifiers = append(specifiers, spec)
+			continue
+		}
+		if rest, ok := strings.CutSuffix(clause, ".*"); ok && (spec.op == "==" || spec.op == "!=") {
+			spec.wildcard, clause = true, rest
+		}
+
+		version, ok := parsePEP440(clause)
+		if !ok || (spec.op == "~=" && len(version.release) < 2) {
+			return nil, fmt.Errorf("invalid version specifier %q", spec.op+spec.raw)
+		}
+		spec.version = version
+		specifiers = append(specifiers, spec)
+	}
+	return specifiers, nil
+}
+
+// pypiFile is a distribution file of a release in the PyPI JSON API
+type pypiFile struct {
+	Yanked bool `json:"yanked"`
+}
+
+// PyPIResolver resolves PEP 440 specifiers against the PyPI JSON API
+type PyPIResolver struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewPyPIResolver creates a resolver querying the JSON API at baseURL, e.g. https://pypi.org/pypi
+func NewPyPIResolver(baseURL string, client *http.Client) *PyPIResolver {
+	return &PyPIResolver{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+// Resolve returns the newest stable release of a Python package satisfying a specifier set.
+// Yanked releases and releases without files are skipped.
+func (pr *PyPIResolver) Resolve(name, constraint string) (string, error) {
+	var project struct {
+		Releases map[string][]pypiFile `json:"releases"`
+	}
+	if err := getJSON(pr.client, pr.baseURL+"/"+url.PathEscape(name)+"/json", &project); err != nil {
+		return "", err
+	}
+
+	var versions []string
+	for version, files := range project.Releases {
+		if releaseAvailable(files) {
+			versions = append(versions, version)
+		}
+	}
+	return newestRelease(name, constraint, versions)
+}
+
+// SimpleIndexResolver resolves PEP 440 specifiers against package indexes serving the JSON
+// form of the simple repository API (PEP 691), such as the private indexes a requirements
+// file selects with --index-url and --extra-index-url
+type SimpleIndexResolver struct {
+	indexURLs []string
+	client    *http.Client
+}
+
+// NewSimpleIndexResolver creates a resolver querying the indexes at indexURLs, e.g.
+// https://pypi.example.com/simple
+func NewSimpleIndexResolver(indexURLs []string, client *http.Client) *SimpleIndexResolver {
+	trimmed := make([]string, 0, len(indexURLs))
+	for _, indexURL := range indexURLs {
+		trimmed = append(trimmed, strings.TrimSuffix(indexURL, "/"))
+	}
+	return &SimpleIndexResolver{indexURLs: trimmed, client: client}
+}
+
+// Resolve returns the newest stable release of a Python package satisfying a specifier set
+// among the versions all indexes list, like pip merging the candidates of every index. Indexes
+// that cannot be read are skipped; the lookup fails only when none can.
+func (sr *SimpleIndexResolver) Resolve(name, constraint string) (string, error) {
+	var versions []string
+	var lastErr error
+	for _, indexURL := range sr.indexURLs {
+		var project struct {
+			Versions []string `json:"versions"` // PEP 700
+		}
+		err := getJSONAs(sr.client, indexURL+"/"+url.PathEscape(normalizeProjectName(name))+"/", simpleJSONType, &project)
+		if err == nil && project.Versions == nil {
+			err = fmt.Errorf("index %s does not list the versions of %s", indexURL, name)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		versions = append(versions, project.Versions...)
+	}
+	if versions == nil && lastErr != nil {
+		return "", lastErr
+	}
+	return newestRelease(name, constraint, versions)
+}
+
+// simpleJSONType is the media type of the JSON simple repository API
+const simpleJSONType = "application/vnd.pypi.simple.v1+json"
+
+// normalizeProjectName normalizes a project name as the simple repository API expects (PEP 503)
+func normalizeProjectName(name string) string {
+	return strings.ToLower(projectNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// projectNameSeparators matches the runs of characters PEP 503 collapses to one dash
+var projectNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// newestRelease returns the newest stable version satisfying a specifier set
+func newestRelease(name, constraint string, versions []string) (string, error) {
+	var specifiers []pep440Specifier
+	if !anyVersion(constraint) {
+		var err error
+		if specifiers, err = parsePEP440Specifiers(constraint); err != nil {
+			return "", err
+		}
+	}
+
+	var best string
+	var bestVersion pep440Version
+	for _, version := range versions {
+		v, ok := parsePEP440(version)
+		if !ok || v.pre || !matchesAll(specifiers, v, version) {
+			continue
+		}
+		if best == "" || v.compare(bestVersion) > 0 {
+			best, bestVersion = version, v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrNoMatch, name, constraint)
+	}
+	return best, nil
+}
+
+// releaseAvailable reports whether a release has a file that is not yanked
+func releaseAvailable(files []pypiFile) bool {
+	for _, file := range files {
+		if !file.Yanked {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAll reports whether v satisfies every specifier
+func matchesAll(specifiers []pep440Specifier, v pep440Version, raw string) bool {
+	for _, spec := range specifiers {
+		if !spec.matches(v, raw) {
+			return false
+		}
+	}
+	return true
+}
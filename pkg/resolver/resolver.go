@@ -0,0 +1,80 @@
+// Package resolver turns version constraints into the newest matching version published in an
+// ecosystem registry, for dependencies declared without a lockfile
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each registry request
+const DefaultTimeout = 10 * time.Second
+
+// Default registries queried by the resolvers
+const (
+	DefaultNpmRegistry = "https://registry.npmjs.org"
+	DefaultPyPIURL     = "https://pypi.org/pypi"
+)
+
+// ErrNoMatch is returned when no published version satisfies a constraint
+var ErrNoMatch = errors.New("no published version matches the constraint")
+
+// Resolver resolves version constraints against the versions a registry publishes
+type Resolver interface {
+	// Resolve returns the newest published version of a package satisfying constraint. An empty
+	// constraint, "*" or "unknown" resolves to the newest release.
+	Resolve(name, constraint string) (string, error)
+}
+
+// New returns the resolver for the registry of a build tool, or nil when the build tool has
+// none. A nil client uses one that honors the HTTP(S)_PROXY environment and DefaultTimeout.
+func New(buildTool string, client *http.Client) Resolver {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	switch buildTool {
+	case "npm":
+		return NewNpmResolver(DefaultNpmRegistry, client)
+	case "pip", "pipenv":
+		return NewPyPIResolver(DefaultPyPIURL, client)
+	}
+	return nil
+}
+
+// anyVersion reports whether a constraint allows every version
+func anyVersion(constraint string) bool {
+	switch strings.TrimSpace(constraint) {
+	case "", "*", "unknown", "latest", "x":
+		return true
+	}
+	return false
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func getJSON(client *http.Client, url string, v any) error {
+	return getJSONAs(client, url, "application/json", v)
+}
+
+// getJSONAs fetches url accepting the given JSON media type and decodes the body into v
+func getJSONAs(client *http.Client, url, mediaType string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", mediaType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned status %d for %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,202 @@
+package resolver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNpmResolver_Resolve(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+	"name": "lodash",
+	"dist-tags": {"latest": "5.0.0"},
+	"versions": {
+		"4.16.6": {}, "4.17.0": {}, "4.17.21": {}, "4.18.2": {},
+		"4.19.0-rc.1": {}, "5.0.0": {}
+	}
+}`))
+	}))
+	defer server.Close()
+
+	nr := NewNpmResolver(server.URL+"/", server.Client())
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^4.17.0", "4.18.2"},
+		{"~4.17.0", "4.17.21"},
+		{">=4.0.0 <4.17.21", "4.17.0"},
+		{"4.16.x || 4.17", "4.17.21"},
+		{"^4.19.0-rc.0", "4.19.0-rc.1"},
+		{"*", "5.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			version, err := nr.Resolve("lodash", tt.constraint)
+			if err != nil || version != tt.expected {
+				t.Errorf("Resolve(%q) = %q, %v; expected %q", tt.constraint, version, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := nr.Resolve("lodash", "^6.0.0"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch for a range beyond every version, got %v", err)
+	}
+	_, _ = nr.Resolve("@types/node", "*")
+	if path := requested[len(requested)-1]; path != "/@types%2Fnode" {
+		t.Errorf("Expected the scoped package to be requested as /@types%%2Fnode, got %s", path)
+	}
+}
+
+func TestNpmResolver_Resolve_RegistryError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := NewNpmResolver(server.URL, server.Client()).Resolve("missing", "^1.0.0"); err == nil {
+		t.Error("Expected an error for a package the registry does not know")
+	}
+}
+
+func TestPyPIResolver_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pypi/requests/json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+	"releases": {
+		"2.27.0": [{"yanked": false}],
+		"2.28.1": [{"yanked": false}],
+		"2.31.0": [{"yanked": false}],
+		"2.31.1": [{"yanked": true}],
+		"2.32.0rc1": [{"yanked": false}],
+		"2.33.0": [],
+		"3.0.0": [{"yanked": false}]
+	}
+}`))
+	}))
+	defer server.Close()
+
+	pr := NewPyPIResolver(server.URL+"/pypi", server.Client())
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"~=2.28", "2.31.0"},
+		{">=2.0,<3", "2.31.0"},
+		{"==2.27.*", "2.27.0"},
+		{">=2.27,!=2.31.0,<3", "2.28.1"},
+		{"", "3.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			version, err := pr.Resolve("requests", tt.constraint)
+			if err != nil || version != tt.expected {
+				t.Errorf("Resolve(%q) = %q, %v; expected %q", tt.constraint, version, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := pr.Resolve("requests", ">=4"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch, got %v", err)
+	}
+}
+
+func TestSimpleIndexResolver_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != simpleJSONType {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+			return
+		}
+		switch r.URL.Path {
+		case "/private/simple/acme-utils/":
+			_, _ = w.Write([]byte(`{"name": "acme-utils", "versions": ["1.0.0", "1.4.2", "2.0.0b1"]}`))
+		case "/mirror/simple/acme-utils/":
+			_, _ = w.Write([]byte(`{"name": "acme-utils", "versions": ["1.5.0", "2.0.0"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sr := NewSimpleIndexResolver([]string{server.URL + "/private/simple/", server.URL + "/missing/simple"}, server.Client())
+	if version, err := sr.Resolve("Acme_Utils", ">=1.0,<2"); err != nil || version != "1.4.2" {
+		t.Errorf("Resolve = %q, %v; expected 1.4.2 from the readable index", version, err)
+	}
+
+	sr = NewSimpleIndexResolver([]string{server.URL + "/private/simple", server.URL + "/mirror/simple"}, server.Client())
+	if version, err := sr.Resolve("acme.utils", "<2"); err != nil || version != "1.5.0" {
+		t.Errorf("Resolve = %q, %v; expected 1.5.0 across both indexes", version, err)
+	}
+
+	sr = NewSimpleIndexResolver([]string{server.URL + "/missing/simple"}, server.Client())
+	if _, err := sr.Resolve("acme-utils", ">=1"); err == nil {
+		t.Error("Expected an error when no index can be read")
+	}
+}
+
+func TestParseNpmRange(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		matches    bool
+	}{
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2", "1.2.9", true},
+		{"~1.2", "1.3.0", false},
+		{"1.2.3 - 2.3", "2.3.9", true},
+		{"1.2.3 - 2.3", "2.4.0", false},
+		{">= 1.0.0 < 2", "1.5.0", true},
+		{">1.2", "1.2.9", false},
+		{"<=1.2", "1.2.9", true},
+		{"1.x", "1.0.0-beta", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			r, err := parseNpmRange(tt.constraint)
+			if err != nil {
+				t.Fatalf("parseNpmRange(%q) failed: %v", tt.constraint, err)
+			}
+			v, _ := parseSemver(tt.version)
+			if got := r.matches(v); got != tt.matches {
+				t.Errorf("Expected %s matching %s to be %v", tt.version, tt.constraint, tt.matches)
+			}
+		})
+	}
+}
+
+func TestNpmRangesIntersect(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"^2.0.0", "^2", true},
+		{"^2.1.0", "2.x", true},
+		{"~2.1.0", ">=2.1.5 <3", true},
+		{"^1.2.0", "^2", false},
+		{"<2.0.0", ">=2.0.0", false},
+		{"<=2.0.0", ">=2.0.0", true},
+		{"1.5.0", "^1.2.0", true},
+		{"1.0.0 || ^3.0.0", "^3.1", true},
+		{"*", "^0.2", true},
+		{"not-a-range!", "^1", false},
+	}
+	for _, tt := range tests {
+		if got := NpmRangesIntersect(tt.a, tt.b); got != tt.expected {
+			t.Errorf("NpmRangesIntersect(%q, %q) = %v; expected %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+
+	if !SatisfiesNpmRange("2.3.1", "^2") || SatisfiesNpmRange("1.2.8", "^0.2") || SatisfiesNpmRange("unknown", "*") {
+		t.Error("Unexpected SatisfiesNpmRange result")
+	}
+}
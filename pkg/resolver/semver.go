@@ -0,0 +1,365 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// semver is a semantic version as used by npm
+type semver struct {
+	major, minor, patch int
+	pre                 string // Prerelease identifiers, e.g. "beta.1"; empty for releases
+}
+
+// parseSemver parses a full version such as 1.2.3, v1.2.3-beta.1 or 1.2.3+build
+func parseSemver(s string) (semver, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "="), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		numbers[i] = n
+	}
+	return semver{major: numbers[0], minor: numbers[1], patch: numbers[2], pre: pre}, true
+}
+
+// compare orders versions by precedence, a prerelease coming before its release
+func (v semver) compare(other semver) int {
+	for _, d := range []int{v.major - other.major, v.minor - other.minor, v.patch - other.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case v.pre == other.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case other.pre == "":
+		return -1
+	}
+
+	// Prerelease identifiers compare numerically when both are numbers, numbers first
+	ids, otherIDs := strings.Split(v.pre, "."), strings.Split(other.pre, ".")
+	for i := 0; i < len(ids) && i < len(otherIDs); i++ {
+		n, errN := strconv.Atoi(ids[i])
+		m, errM := strconv.Atoi(otherIDs[i])
+		switch {
+		case errN == nil && errM == nil && n != m:
+			return n - m
+		case errN == nil && errM != nil:
+			return -1
+		case errN != nil && errM == nil:
+			return 1
+		case ids[i] != otherIDs[i]:
+			return strings.Compare(ids[i], otherIDs[i])
+		}
+	}
+	return len(ids) - len(otherIDs)
+}
+
+// comparator is a single condition of an npm range, such as >=1.2.3
+type comparator struct {
+	op      string // One of <, <=, >, >=, =
+	version semver
+}
+
+// matches reports whether v satisfies the comparator
+func (c comparator) matches(v semver) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+// npmRange is an npm version range: alternatives separated by ||, each a set of comparators
+// that all have to match
+type npmRange struct {
+	sets       [][]comparator
+	prerelease bool // The range names a prerelease, which opts in to prerelease versions
+}
+
+// matches reports whether v satisfies the range. Prereleases only match ranges naming one.
+func (r npmRange) matches(v semver) bool {
+	if v.pre != "" && !r.prerelease {
+		return false
+	}
+	for _, set := range r.sets {
+		matched := true
+		for _, c := range set {
+			if !c.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// SatisfiesNpmRange reports whether an npm version satisfies a range. Versions or ranges that
+// cannot be parsed satisfy nothing.
+func SatisfiesNpmRange(version, constraint string) bool {
+	v, ok := parseSemver(version)
+	if !ok {
+		return false
+	}
+	r, err := parseNpmRange(constraint)
+	return err == nil && r.matches(v)
+}
+
+// NpmRangesIntersect reports whether some version satisfies both npm ranges, as npm requires
+// of a declared range for an override scoped to another range to apply. Ranges that cannot be
+// parsed intersect nothing.
+func NpmRangesIntersect(a, b string) bool {
+	ra, err := parseNpmRange(a)
+	if err != nil {
+		return false
+	}
+	rb, err := parseNpmRange(b)
+	if err != nil {
+		return false
+	}
+	for _, setA := range ra.sets {
+		for _, setB := range rb.sets {
+			if satisfiable(append(slices.Clone(setA), setB...)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// satisfiable reports whether some version meets every comparator of a set, comparing its
+// tightest lower bound with its tightest upper bound
+func satisfiable(set []comparator) bool {
+	var lower, upper *comparator
+	for _, c := range set {
+		bounds := []comparator{c}
+		if c.op == "=" {
+			bounds = []comparator{{">=", c.version}, {"<=", c.version}}
+		}
+		for _, bound := range bounds {
+			switch bound.op {
+			case ">", ">=":
+				if lower == nil || bound.version.compare(lower.version) > 0 || (bound.version == lower.version && bound.op == ">") {
+					lower = &bound
+				}
+			case "<", "<=":
+				if upper == nil || bound.version.compare(upper.version) < 0 || (bound.version == upper.version && bound.op == "<") {
+					upper = &bound
+				}
+			}
+		}
+	}
+	if lower == nil || upper == nil {
+		return true
+	}
+	cmp := lower.version.compare(upper.version)
+	return cmp < 0 || (cmp == 0 && lower.op == ">=" && upper.op == "<=")
+}
+
+// parseNpmRange parses an npm range with caret, tilde, x-range, hyphen and comparison syntax
+func parseNpmRange(s string) (npmRange, error) {
+	r := npmRange{prerelease: strings.Contains(s, "-") && !strings.Contains(s, " - ")}
+	for _, alternative := range strings.Split(s, "||") {
+		tokens := joinOperators(strings.Fields(alternative))
+		var set []comparator
+		if len(tokens) == 0 {
+			tokens = []string{"*"}
+		}
+		for i := 0; i < len(tokens); i++ {
+			// Hyphen range: 1.2.3 - 2.3.4
+			if i+2 < len(tokens) && tokens[i+1] == "-" {
+				lower, err := parsePartial(tokens[i])
+				if err != nil {
+					return npmRange{}, err
+				}
+				upper, err := parsePartial(tokens[i+2])
+				if err != nil {
+					return npmRange{}, err
+				}
+				set = append(set, comparator{">=", lower.floor()})
+				set = append(set, upper.upperBound(true)...)
+				i += 2
+				continue
+			}
+			comparators, err := parseNpmComparator(tokens[i])
+			if err != nil {
+				return npmRange{}, err
+			}
+			set = append(set, comparators...)
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+// joinOperators joins operators written apart from their version, as in ">= 1.2.3"
+func joinOperators(tokens []string) []string {
+	var joined []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if strings.Trim(token, "<>=^~") == "" && token != "-" && i+1 < len(tokens) {
+			token += tokens[i+1]
+			i++
+		}
+		joined = append(joined, token)
+	}
+	return joined
+}
+
+// parseNpmComparator desugars one range token, such as ^1.2.3, ~1.2, >=1.0 or 1.x, into
+// plain comparators
+func parseNpmComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(token, candidate); ok {
+			op, token = candidate, rest
+			break
+		}
+	}
+	p, err := parsePartial(token)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		return []comparator{{">=", p.floor()}, {"<", p.caretCeiling()}}, nil
+	case "~":
+		if p.specified < 2 {
+			return p.xRange(), nil
+		}
+		return []comparator{{">=", p.floor()}, {"<", semver{major: p.major, minor: p.minor + 1}}}, nil
+	case ">=":
+		return []comparator{{">=", p.floor()}}, nil
+	case ">":
+		if p.specified == 3 {
+			return []comparator{{">", p.floor()}}, nil
+		}
+		return []comparator{{">=", p.next()}}, nil
+	case "<":
+		return []comparator{{"<", p.floor()}}, nil
+	case "<=":
+		return p.upperBound(true), nil
+	}
+	return p.xRange(), nil
+}
+
+// partialVersion is a possibly incomplete version such as 1, 1.2, 1.x or 1.2.3
+type partialVersion struct {
+	major, minor, patch int
+	pre                 string
+	specified           int // Leading parts given, 0 for * or x
+}
+
+// parsePartial parses a partial version, where parts may be missing or x, X or *
+func parsePartial(s string) (partialVersion, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+
+	var p partialVersion
+	p.pre = pre
+	if s == "" {
+		return p, nil
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+		switch i {
+		case 0:
+			p.major = n
+		case 1:
+			p.minor = n
+		case 2:
+			p.patch = n
+		}
+		p.specified = i + 1
+	}
+	return p, nil
+}
+
+// floor returns the lowest version the partial version covers
+func (p partialVersion) floor() semver {
+	return semver{major: p.major, minor: p.minor, patch: p.patch, pre: p.pre}
+}
+
+// next returns the lowest version above everything the partial version covers
+func (p partialVersion) next() semver {
+	switch p.specified {
+	case 1:
+		return semver{major: p.major + 1}
+	case 2:
+		return semver{major: p.major, minor: p.minor + 1}
+	}
+	return semver{major: p.major, minor: p.minor, patch: p.patch + 1}
+}
+
+// xRange returns the comparators of a bare partial version, matching every version it covers
+func (p partialVersion) xRange() []comparator {
+	switch p.specified {
+	case 0:
+		return []comparator{{">=", semver{}}}
+	case 3:
+		return []comparator{{"=", p.floor()}}
+	}
+	return []comparator{{">=", p.floor()}, {"<", p.next()}}
+}
+
+// upperBound returns the comparators capping a range at the partial version, including it
+// when inclusive
+func (p partialVersion) upperBound(inclusive bool) []comparator {
+	switch {
+	case p.specified == 0:
+		return nil
+	case p.specified < 3:
+		return []comparator{{"<", p.next()}}
+	case inclusive:
+		return []comparator{{"<=", p.floor()}}
+	}
+	return []comparator{{"<", p.floor()}}
+}
+
+// caretCeiling returns the exclusive upper bound of a caret range, which allows changes that
+// do not modify the leftmost non-zero part
+func (p partialVersion) caretCeiling() semver {
+	switch {
+	case p.major > 0 || p.specified < 2:
+		return semver{major: p.major + 1}
+	case p.minor > 0 || p.specified < 3:
+		return semver{minor: p.minor + 1}
+	}
+	return semver{patch: p.patch + 1}
+}
@@ -57,15 +57,7 @@ func (ms *MavenScanner) ScanExecute() ([]model.DependencyRoot, error) {
 	}
 	deps := make([]model.Dependency, 0, len(pom.Dependencies))
 	for _, d := range pom.Dependencies {
-		dep := model.Dependency{
-			ID:      &model.DependencyID{Group: d.GroupID, Name: d.ArtifactID, Version: d.Version, Type: "jar"},
-			Name:    d.ArtifactID,
-			GroupID: d.GroupID,
-			Version: d.Version,
-			Type:    "jar",
-			Scope:   d.Scope, // keep empty if missing to satisfy tests
-		}
-		deps = append(deps, dep)
+		deps = append(deps, d.toDependency())
 	}
 	root := model.DependencyRoot{ProjectName: pom.ArtifactID, ProjectVersion: pom.Version, BuildTool: "maven", Dependencies: deps}
 	return []model.DependencyRoot{root}, nil
@@ -125,15 +117,7 @@ func (ms *MavenScanner) ScanDependencies() ([]model.Dependency, error) {
 	}
 	deps := make([]model.Dependency, 0, len(pom.Dependencies))
 	for _, d := range pom.Dependencies {
-		dep := model.Dependency{
-			ID:      &model.DependencyID{Group: d.GroupID, Name: d.ArtifactID, Version: d.Version, Type: "jar"},
-			Name:    d.ArtifactID,
-			GroupID: d.GroupID,
-			Version: d.Version,
-			Type:    "jar",
-			Scope:   d.Scope, // empty if missing
-		}
-		deps = append(deps, dep)
+		deps = append(deps, d.toDependency())
 	}
 	return deps, nil
 }
@@ -159,6 +143,7 @@ type pomXMLInternal struct {
 			Version    string `xml:"version"`
 			Scope      string `xml:"scope"`
 			Type       string `xml:"type"`
+			Classifier string `xml:"classifier"`
 		} `xml:"dependency"`
 	} `xml:"dependencies"`
 }
@@ -170,12 +155,33 @@ type ParsedPom struct {
 	Version      string
 	Description  string
 	License      string
-	Dependencies []struct {
-		GroupID    string
-		ArtifactID string
-		Version    string
-		Scope      string
-		Type       string
+	Dependencies []ParsedDependency
+}
+
+// ParsedDependency is a dependency declared in a parsed POM
+type ParsedDependency struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Scope      string
+	Type       string
+	Classifier string
+}
+
+// toDependency converts a declared dependency, defaulting to Maven's jar packaging
+func (d ParsedDependency) toDependency() model.Dependency {
+	depType := d.Type
+	if depType == "" {
+		depType = "jar"
+	}
+	return model.Dependency{
+		ID:         &model.DependencyID{Group: d.GroupID, Name: d.ArtifactID, Version: d.Version, Type: depType},
+		Name:       d.ArtifactID,
+		GroupID:    d.GroupID,
+		Version:    d.Version,
+		Type:       depType,
+		Classifier: d.Classifier,
+		Scope:      d.Scope, // empty if missing
 	}
 }
 
@@ -196,13 +202,14 @@ func (ms *MavenScanner) parsePomXML(content []byte) (*ParsedPom, error) {
 		p.License = raw.Licenses.License[0].Name
 	}
 	for _, d := range raw.Dependencies.Dependency {
-		p.Dependencies = append(p.Dependencies, struct {
-			GroupID    string
-			ArtifactID string
-			Version    string
-			Scope      string
-			Type       string
-		}{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Version: d.Version, Scope: d.Scope, Type: d.Type})
+		p.Dependencies = append(p.Dependencies, ParsedDependency{
+			GroupID:    d.GroupID,
+			ArtifactID: d.ArtifactID,
+			Version:    d.Version,
+			Scope:      d.Scope,
+			Type:       d.Type,
+			Classifier: d.Classifier,
+		})
 	}
 	return p, nil
 }
@@ -285,6 +285,52 @@ func TestMavenScanner_ScanDependencies_NoDependencies(t *testing.T) {
 	}
 }
 
+func TestMavenScanner_ScanDependencies_ClassifierAndType(t *testing.T) {
+	tempDir := t.TempDir()
+
+	pomContent := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+    <modelVersion>4.0.0</modelVersion>
+    <groupId>com.example</groupId>
+    <artifactId>test-project</artifactId>
+    <version>1.0.0</version>
+
+    <dependencies>
+        <dependency>
+            <groupId>net.sf.json-lib</groupId>
+            <artifactId>json-lib</artifactId>
+            <version>2.4</version>
+            <classifier>jdk15</classifier>
+        </dependency>
+        <dependency>
+            <groupId>com.example</groupId>
+            <artifactId>platform-bom</artifactId>
+            <version>3.0.0</version>
+            <type>pom</type>
+        </dependency>
+    </dependencies>
+</project>`
+	if err := os.WriteFile(filepath.Join(tempDir, "pom.xml"), []byte(pomContent), 0644); err != nil {
+		t.Fatalf("Failed to create pom.xml: %v", err)
+	}
+
+	scanner := NewMavenScanner(buildtools.NewScannableEnvironment(tempDir, "pom.xml"), &config.ScanConfig{})
+	dependencies, err := scanner.ScanDependencies()
+	if err != nil {
+		t.Fatalf("ScanDependencies failed: %v", err)
+	}
+	if len(dependencies) != 2 {
+		t.Fatalf("Expected 2 dependencies, got %d", len(dependencies))
+	}
+
+	if jsonLib := dependencies[0]; jsonLib.Classifier != "jdk15" || jsonLib.Type != "jar" {
+		t.Errorf("Expected json-lib jar with classifier jdk15, got type %q classifier %q", jsonLib.Type, jsonLib.Classifier)
+	}
+	if bom := dependencies[1]; bom.Type != "pom" || bom.ID.Type != "pom" || bom.Classifier != "" {
+		t.Errorf("Expected platform-bom to keep its pom type, got type %q classifier %q", bom.Type, bom.Classifier)
+	}
+}
+
 func TestMavenScanner_ScanDependencies_InvalidPom(t *testing.T) {
 	tempDir := t.TempDir()
 